@@ -6,6 +6,7 @@ package bundler
 
 import (
 	"errors"
+	"io"
 	"strings"
 
 	"github.com/pb33f/libopenapi"
@@ -54,7 +55,26 @@ func BundleDocument(model *v3.Document) ([]byte, error) {
 	return bundle(model, false)
 }
 
+// BundleTo will take a v3.Document, bundle it in the same way as BundleDocument, and write the bundled document to w,
+// rendered using opts (a nil opts renders YAML using the defaults, see v3.Document.RenderTo). The bundled node tree is
+// built in memory, as it is by BundleDocument, only the rendered bytes are written to w as the tree is encoded, rather
+// than being returned as a byte slice.
+func BundleTo(w io.Writer, model *v3.Document, opts *v3.RenderOptions) error {
+	if model == nil || model.Rolodex == nil {
+		return ErrInvalidModel
+	}
+	compactReferences(model, false)
+	return model.RenderTo(w, opts)
+}
+
 func bundle(model *v3.Document, inline bool) ([]byte, error) {
+	compactReferences(model, inline)
+	return model.Render()
+}
+
+// compactReferences replaces the node of every reference in the model with the node it references, so the model
+// renders as a single document.
+func compactReferences(model *v3.Document, inline bool) {
 	rolodex := model.Rolodex
 	compact := func(idx *index.SpecIndex, root bool) {
 		mappedReferences := idx.GetMappedReferences()
@@ -92,5 +112,4 @@ func bundle(model *v3.Document, inline bool) ([]byte, error) {
 		compact(idx, false)
 	}
 	compact(rolodex.GetRootIndex(), true)
-	return model.Render()
 }
@@ -5,6 +5,7 @@ package bundler
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"log"
 	"log/slog"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/pb33f/libopenapi"
 	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -100,6 +102,32 @@ func TestBundleDocument_Circular(t *testing.T) {
 	assert.Len(t, logEntries, 0)
 }
 
+func TestBundleTo(t *testing.T) {
+	spec, _ := os.ReadFile("../test_specs/circular-tests.yaml")
+	build := func() *v3.Document {
+		doc, err := libopenapi.NewDocumentWithConfiguration(spec, &datamodel.DocumentConfiguration{
+			ExtractRefsSequentially: true,
+		})
+		require.NoError(t, err)
+		v3Doc, _ := doc.BuildV3Model()
+		require.NotNil(t, v3Doc)
+		return &v3Doc.Model
+	}
+
+	bundled, err := BundleDocument(build())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, BundleTo(&buf, build(), nil))
+	assert.Equal(t, string(bundled), buf.String())
+
+	buf.Reset()
+	require.NoError(t, BundleTo(&buf, build(), &v3.RenderOptions{JSON: true, JSONIndent: 2}))
+	assert.True(t, json.Valid(buf.Bytes()))
+
+	assert.ErrorIs(t, BundleTo(&buf, nil, nil), ErrInvalidModel)
+}
+
 func TestBundleBytes(t *testing.T) {
 
 	digi, _ := os.ReadFile("../test_specs/circular-tests.yaml")
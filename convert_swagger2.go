@@ -0,0 +1,668 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package libopenapi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// ConvertSwagger2 will parse a Swagger (OpenAPI 2) specification and convert it into an OpenAPI 3 high level model.
+//
+// The conversion is best-effort:
+//   - 'definitions' become 'components/schemas' (and references to them are rewritten).
+//   - 'parameters' become 'components/parameters', body parameters become 'components/requestBodies'.
+//   - 'responses' become 'components/responses'.
+//   - 'securityDefinitions' become 'components/securitySchemes'.
+//   - 'host', 'basePath' and 'schemes' become 'servers'.
+//   - Body and form parameters become request bodies, with a media type for each type in 'consumes'.
+//   - Response schemas and examples become content, with a media type for each type in 'produces'.
+//
+// Anything that can't be converted is left out, and reported in the returned error. The Document is returned whenever
+// it can be built, even when the error is not nil.
+func ConvertSwagger2(data []byte) (*v3high.Document, error) {
+	info, err := datamodel.ExtractSpecInfo(data)
+	if err != nil {
+		return nil, err
+	}
+	if info.SpecFormat != datamodel.OAS2 {
+		return nil, fmt.Errorf("unable to convert document, supplied spec is not a Swagger document (%v)", info.SpecFormat)
+	}
+
+	c := &swagger2Converter{root: info.RootNode.Content[0]}
+	converted, err := yaml.Marshal(c.convert())
+	if err != nil {
+		return nil, err
+	}
+	doc, err := NewDocument(converted)
+	if err != nil {
+		return nil, err
+	}
+	m, errs := doc.BuildV3Model()
+	errs = append(c.errs, errs...)
+	if m == nil {
+		return nil, errors.Join(errs...)
+	}
+	return &m.Model, errors.Join(errs...)
+}
+
+// swagger2Converter converts the root node of a Swagger document into the root node of an OpenAPI 3 document.
+type swagger2Converter struct {
+	root     *yaml.Node
+	produces []string
+	consumes []string
+	errs     []error
+}
+
+// parameter schema keywords of Swagger, that are moved into the 'schema' of an OpenAPI 3 parameter.
+var swagger2SchemaKeys = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum",
+	"exclusiveMinimum", "maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+var swagger2Methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+func (c *swagger2Converter) convert() *yaml.Node {
+	c.consumes = stringValues(mapValue(c.root, "consumes"))
+	c.produces = stringValues(mapValue(c.root, "produces"))
+
+	out := utils.CreateEmptyMapNode()
+	serversDone, componentsDone := false, false
+	for i := 0; i+1 < len(c.root.Content); i += 2 {
+		key, value := c.root.Content[i], c.root.Content[i+1]
+		switch key.Value {
+		case "swagger":
+			setValue(out, "openapi", utils.CreateStringNode("3.0.3"))
+		case "host", "basePath", "schemes":
+			if !serversDone {
+				serversDone = true
+				setValue(out, "servers", c.servers(stringValues(mapValue(c.root, "schemes"))))
+			}
+		case "consumes", "produces":
+		case "paths":
+			setValue(out, "paths", c.paths(value))
+		case "definitions", "parameters", "responses", "securityDefinitions":
+			if !componentsDone {
+				componentsDone = true
+				setValue(out, "components", c.components())
+			}
+		default:
+			setValue(out, key.Value, copyNode(value))
+		}
+	}
+	return out
+}
+
+// servers creates the servers of the document from the host and base path, with a server for each scheme.
+func (c *swagger2Converter) servers(schemes []string) *yaml.Node {
+	host := stringValue(mapValue(c.root, "host"))
+	basePath := stringValue(mapValue(c.root, "basePath"))
+	servers := utils.CreateEmptySequenceNode()
+	addServer := func(url string) {
+		server := utils.CreateEmptyMapNode()
+		setValue(server, "url", utils.CreateStringNode(url))
+		servers.Content = append(servers.Content, server)
+	}
+	switch {
+	case host == "" && basePath == "":
+		addServer("/")
+	case host == "":
+		addServer(basePath)
+	case len(schemes) == 0:
+		addServer("//" + host + basePath)
+	default:
+		for _, scheme := range schemes {
+			addServer(scheme + "://" + host + basePath)
+		}
+	}
+	return servers
+}
+
+func (c *swagger2Converter) components() *yaml.Node {
+	components := utils.CreateEmptyMapNode()
+	if definitions := mapValue(c.root, "definitions"); definitions != nil {
+		schemas := utils.CreateEmptyMapNode()
+		for i := 0; i+1 < len(definitions.Content); i += 2 {
+			setValue(schemas, definitions.Content[i].Value, c.schema(definitions.Content[i+1]))
+		}
+		setValue(components, "schemas", schemas)
+	}
+
+	if responses := mapValue(c.root, "responses"); responses != nil {
+		converted := utils.CreateEmptyMapNode()
+		for i := 0; i+1 < len(responses.Content); i += 2 {
+			setValue(converted, responses.Content[i].Value, c.response(responses.Content[i+1], c.produces))
+		}
+		setValue(components, "responses", converted)
+	}
+
+	if parameters := mapValue(c.root, "parameters"); parameters != nil {
+		converted, requestBodies := utils.CreateEmptyMapNode(), utils.CreateEmptyMapNode()
+		for i := 0; i+1 < len(parameters.Content); i += 2 {
+			name, param := parameters.Content[i].Value, parameters.Content[i+1]
+			switch stringValue(mapValue(param, "in")) {
+			case "body":
+				setValue(requestBodies, name, c.requestBody([]*yaml.Node{param}, c.consumes))
+			case "formData":
+				// form parameters are converted where they are used, as they are only part of a request body.
+			default:
+				setValue(converted, name, c.parameter(param, "#/parameters/"+name))
+			}
+		}
+		if len(converted.Content) > 0 {
+			setValue(components, "parameters", converted)
+		}
+		if len(requestBodies.Content) > 0 {
+			setValue(components, "requestBodies", requestBodies)
+		}
+	}
+
+	if definitions := mapValue(c.root, "securityDefinitions"); definitions != nil {
+		schemes := utils.CreateEmptyMapNode()
+		for i := 0; i+1 < len(definitions.Content); i += 2 {
+			if scheme := c.securityScheme(definitions.Content[i].Value, definitions.Content[i+1]); scheme != nil {
+				setValue(schemes, definitions.Content[i].Value, scheme)
+			}
+		}
+		setValue(components, "securitySchemes", schemes)
+	}
+	return components
+}
+
+func (c *swagger2Converter) securityScheme(name string, definition *yaml.Node) *yaml.Node {
+	scheme := utils.CreateEmptyMapNode()
+	switch t := stringValue(mapValue(definition, "type")); t {
+	case "basic":
+		setValue(scheme, "type", utils.CreateStringNode("http"))
+		setValue(scheme, "scheme", utils.CreateStringNode("basic"))
+	case "apiKey":
+		setValue(scheme, "type", utils.CreateStringNode("apiKey"))
+		setValue(scheme, "name", copyNode(mapValue(definition, "name")))
+		setValue(scheme, "in", copyNode(mapValue(definition, "in")))
+	case "oauth2":
+		flowName := map[string]string{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}[stringValue(mapValue(definition, "flow"))]
+		if flowName == "" {
+			c.report("security definition '%s' has an unknown oauth2 flow", name)
+			return nil
+		}
+		flow := utils.CreateEmptyMapNode()
+		for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+			setValue(flow, key, copyNode(mapValue(definition, key)))
+		}
+		scopes := copyNode(mapValue(definition, "scopes"))
+		if scopes == nil {
+			scopes = utils.CreateEmptyMapNode()
+		}
+		setValue(flow, "scopes", scopes)
+		flows := utils.CreateEmptyMapNode()
+		setValue(flows, flowName, flow)
+		setValue(scheme, "type", utils.CreateStringNode("oauth2"))
+		setValue(scheme, "flows", flows)
+	default:
+		c.report("security definition '%s' has an unknown type '%s'", name, t)
+		return nil
+	}
+	setValue(scheme, "description", copyNode(mapValue(definition, "description")))
+	copyExtensions(scheme, definition)
+	return scheme
+}
+
+func (c *swagger2Converter) paths(paths *yaml.Node) *yaml.Node {
+	out := utils.CreateEmptyMapNode()
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		path, item := paths.Content[i].Value, paths.Content[i+1]
+		if strings.HasPrefix(path, "x-") || item.Kind != yaml.MappingNode {
+			setValue(out, path, copyNode(item))
+			continue
+		}
+
+		// body and form parameters of the path item become part of the request body of each operation.
+		var pathParams []*yaml.Node
+		if params := mapValue(item, "parameters"); params != nil {
+			pathParams = params.Content
+		}
+
+		converted := utils.CreateEmptyMapNode()
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			key, value := item.Content[j].Value, item.Content[j+1]
+			switch {
+			case key == "$ref":
+				c.report("path '%s' is a reference, which is not converted", path)
+				setValue(converted, key, copyNode(value))
+			case key == "parameters":
+				if params, _ := c.parameters(value.Content, path); params != nil {
+					setValue(converted, key, params)
+				}
+			case slices.Contains(swagger2Methods, key):
+				setValue(converted, key, c.operation(value, pathParams, path+" "+key))
+			default:
+				setValue(converted, key, copyNode(value))
+			}
+		}
+		setValue(out, path, converted)
+	}
+	return out
+}
+
+func (c *swagger2Converter) operation(op *yaml.Node, pathParams []*yaml.Node, location string) *yaml.Node {
+	consumes, produces := c.consumes, c.produces
+	if n := mapValue(op, "consumes"); n != nil {
+		consumes = stringValues(n)
+	}
+	if n := mapValue(op, "produces"); n != nil {
+		produces = stringValues(n)
+	}
+
+	// operation parameters override the path item parameters with the same name and location.
+	var opParams []*yaml.Node
+	if n := mapValue(op, "parameters"); n != nil {
+		opParams = n.Content
+	}
+	var inherited []*yaml.Node
+	for _, p := range pathParams {
+		if in := c.parameterIn(p); in == "body" || in == "formData" {
+			overridden := slices.ContainsFunc(opParams, func(o *yaml.Node) bool {
+				return c.parameterIn(o) == in && stringValue(mapValue(c.resolveParameter(o), "name")) ==
+					stringValue(mapValue(c.resolveParameter(p), "name"))
+			})
+			if !overridden {
+				inherited = append(inherited, p)
+			}
+		}
+	}
+	params, bodyParams := c.parameters(append(slices.Clone(opParams), inherited...), location)
+	var body *yaml.Node
+	if len(bodyParams) > 0 {
+		body = c.requestBody(bodyParams, consumes)
+	}
+
+	out := utils.CreateEmptyMapNode()
+	bodyDone := false
+	addBody := func() {
+		if !bodyDone && body != nil {
+			setValue(out, "requestBody", body)
+		}
+		bodyDone = true
+	}
+	for i := 0; i+1 < len(op.Content); i += 2 {
+		key, value := op.Content[i].Value, op.Content[i+1]
+		switch key {
+		case "consumes", "produces":
+		case "parameters":
+			if params != nil {
+				setValue(out, key, params)
+			}
+			addBody()
+		case "responses":
+			addBody()
+			responses := utils.CreateEmptyMapNode()
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				code, response := value.Content[j].Value, value.Content[j+1]
+				if strings.HasPrefix(code, "x-") {
+					setValue(responses, code, copyNode(response))
+					continue
+				}
+				setValue(responses, code, c.response(response, produces))
+			}
+			setValue(out, key, responses)
+		case "schemes":
+			setValue(out, "servers", c.servers(stringValues(value)))
+		default:
+			setValue(out, key, copyNode(value))
+		}
+	}
+	addBody()
+	return out
+}
+
+// parameters converts a list of parameters, returning the parameters that are not part of the request body, and
+// the body and form parameters that make up the request body.
+func (c *swagger2Converter) parameters(params []*yaml.Node, location string) (*yaml.Node, []*yaml.Node) {
+	out := utils.CreateEmptySequenceNode()
+	var bodyParams []*yaml.Node
+	for _, p := range params {
+		switch c.parameterIn(p) {
+		case "body":
+			bodyParams = append(bodyParams, p)
+		case "formData":
+			bodyParams = append(bodyParams, c.resolveParameter(p))
+		default:
+			out.Content = append(out.Content, c.parameter(p, location))
+		}
+	}
+	if len(out.Content) == 0 {
+		out = nil
+	}
+	return out, bodyParams
+}
+
+// parameterIn returns the location of a parameter, looking up references to the parameters of the document.
+func (c *swagger2Converter) parameterIn(p *yaml.Node) string {
+	return stringValue(mapValue(c.resolveParameter(p), "in"))
+}
+
+// resolveParameter returns the parameter of the document that p references, or p if it's not a local reference.
+func (c *swagger2Converter) resolveParameter(p *yaml.Node) *yaml.Node {
+	if name, ok := strings.CutPrefix(stringValue(mapValue(p, "$ref")), "#/parameters/"); ok {
+		if resolved := mapValue(mapValue(c.root, "parameters"), name); resolved != nil {
+			return resolved
+		}
+	}
+	return p
+}
+
+func (c *swagger2Converter) parameter(p *yaml.Node, location string) *yaml.Node {
+	if ref := mapValue(p, "$ref"); ref != nil {
+		return utils.CreateRefNode(c.ref(ref.Value))
+	}
+	out := utils.CreateEmptyMapNode()
+	for i := 0; i+1 < len(p.Content); i += 2 {
+		key := p.Content[i].Value
+		if key == "collectionFormat" || slices.Contains(swagger2SchemaKeys, key) {
+			continue
+		}
+		setValue(out, key, copyNode(p.Content[i+1]))
+	}
+
+	// arrays are comma separated unless there is a collection format.
+	in := stringValue(mapValue(p, "in"))
+	if stringValue(mapValue(p, "type")) == "array" {
+		format := stringValue(mapValue(p, "collectionFormat"))
+		switch {
+		case format == "multi" && in == "query":
+			setValue(out, "style", utils.CreateStringNode("form"))
+			setValue(out, "explode", utils.CreateBoolNode("true"))
+		case (format == "" || format == "csv") && in == "query":
+			setValue(out, "style", utils.CreateStringNode("form"))
+			setValue(out, "explode", utils.CreateBoolNode("false"))
+		case format == "" || format == "csv":
+		case format == "ssv" && in == "query":
+			setValue(out, "style", utils.CreateStringNode("spaceDelimited"))
+			setValue(out, "explode", utils.CreateBoolNode("false"))
+		case format == "pipes" && in == "query":
+			setValue(out, "style", utils.CreateStringNode("pipeDelimited"))
+			setValue(out, "explode", utils.CreateBoolNode("false"))
+		default:
+			c.report("collectionFormat '%s' of parameter '%s' (%s) can't be converted", format,
+				stringValue(mapValue(p, "name")), location)
+		}
+	}
+	setValue(out, "schema", c.parameterSchema(p))
+	return out
+}
+
+// parameterSchema creates a schema from the schema keywords of a (non-body) parameter, header or items object.
+func (c *swagger2Converter) parameterSchema(p *yaml.Node) *yaml.Node {
+	schema := utils.CreateEmptyMapNode()
+	for i := 0; i+1 < len(p.Content); i += 2 {
+		key, value := p.Content[i].Value, p.Content[i+1]
+		switch {
+		case key == "items":
+			setValue(schema, key, c.parameterSchema(value))
+		case key == "type" && value.Value == "file":
+			setValue(schema, key, utils.CreateStringNode("string"))
+			if mapValue(p, "format") == nil {
+				setValue(schema, "format", utils.CreateStringNode("binary"))
+			}
+		case slices.Contains(swagger2SchemaKeys, key):
+			setValue(schema, key, copyNode(value))
+		}
+	}
+	return schema
+}
+
+// requestBody creates a request body from either a body parameter (or a reference to one), or form parameters.
+func (c *swagger2Converter) requestBody(params []*yaml.Node, consumes []string) *yaml.Node {
+	body := utils.CreateEmptyMapNode()
+	if len(params) == 1 && c.parameterIn(params[0]) != "formData" {
+		p := params[0]
+		if ref := mapValue(p, "$ref"); ref != nil {
+			return utils.CreateRefNode(c.ref(ref.Value))
+		}
+		setValue(body, "description", copyNode(mapValue(p, "description")))
+		setValue(body, "content", c.content(c.schema(mapValue(p, "schema")), nil, consumes, "application/json"))
+		setValue(body, "required", copyNode(mapValue(p, "required")))
+		copyExtensions(body, p)
+		return body
+	}
+
+	// form parameters are the properties of an object.
+	schema := utils.CreateEmptyMapNode()
+	properties := utils.CreateEmptyMapNode()
+	required := utils.CreateEmptySequenceNode()
+	hasFile := false
+	for _, p := range params {
+		if c.parameterIn(p) != "formData" {
+			c.report("body parameter '%s' can't be used with form parameters", stringValue(mapValue(p, "name")))
+			continue
+		}
+		name := stringValue(mapValue(p, "name"))
+		property := c.parameterSchema(p)
+		setValue(property, "description", copyNode(mapValue(p, "description")))
+		setValue(properties, name, property)
+		if stringValue(mapValue(p, "required")) == "true" {
+			required.Content = append(required.Content, utils.CreateStringNode(name))
+		}
+		hasFile = hasFile || stringValue(mapValue(p, "type")) == "file"
+	}
+	setValue(schema, "type", utils.CreateStringNode("object"))
+	setValue(schema, "properties", properties)
+	if len(required.Content) > 0 {
+		setValue(schema, "required", required)
+	}
+
+	var mediaTypes []string
+	for _, mt := range consumes {
+		if mt == "multipart/form-data" || mt == "application/x-www-form-urlencoded" {
+			mediaTypes = append(mediaTypes, mt)
+		}
+	}
+	defaultType := "application/x-www-form-urlencoded"
+	if hasFile {
+		defaultType = "multipart/form-data"
+	}
+	setValue(body, "content", c.content(schema, nil, mediaTypes, defaultType))
+	return body
+}
+
+func (c *swagger2Converter) response(response *yaml.Node, produces []string) *yaml.Node {
+	if ref := mapValue(response, "$ref"); ref != nil {
+		return utils.CreateRefNode(c.ref(ref.Value))
+	}
+	out := utils.CreateEmptyMapNode()
+	for i := 0; i+1 < len(response.Content); i += 2 {
+		key, value := response.Content[i].Value, response.Content[i+1]
+		switch key {
+		case "schema":
+			setValue(out, "content", c.content(c.schema(value), mapValue(response, "examples"), produces, "application/json"))
+		case "examples":
+			if mapValue(response, "schema") == nil {
+				setValue(out, "content", c.content(nil, value, produces, "application/json"))
+			}
+		case "headers":
+			headers := utils.CreateEmptyMapNode()
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				header := value.Content[j+1]
+				converted := utils.CreateEmptyMapNode()
+				setValue(converted, "description", copyNode(mapValue(header, "description")))
+				setValue(converted, "schema", c.parameterSchema(header))
+				copyExtensions(converted, header)
+				setValue(headers, value.Content[j].Value, converted)
+			}
+			setValue(out, key, headers)
+		default:
+			setValue(out, key, copyNode(value))
+		}
+	}
+	return out
+}
+
+// content creates a media type for each media type in mediaTypes (or defaultType when there are none), using
+// schema and any matching example in examples. Examples for other media types get their own media type.
+func (c *swagger2Converter) content(schema, examples *yaml.Node, mediaTypes []string, defaultType string) *yaml.Node {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{defaultType}
+	}
+	if examples != nil {
+		for i := 0; i+1 < len(examples.Content); i += 2 {
+			if !slices.Contains(mediaTypes, examples.Content[i].Value) {
+				mediaTypes = append(mediaTypes, examples.Content[i].Value)
+			}
+		}
+	}
+	content := utils.CreateEmptyMapNode()
+	for _, mt := range mediaTypes {
+		mediaType := utils.CreateEmptyMapNode()
+		if schema != nil {
+			setValue(mediaType, "schema", copyNode(schema))
+		}
+		setValue(mediaType, "example", copyNode(mapValue(examples, mt)))
+		setValue(content, mt, mediaType)
+	}
+	return content
+}
+
+// schema copies a Swagger schema, converting the keywords that are different in OpenAPI 3.
+func (c *swagger2Converter) schema(schema *yaml.Node) *yaml.Node {
+	if schema == nil {
+		return nil
+	}
+	if schema.Kind == yaml.SequenceNode {
+		out := utils.CreateEmptySequenceNode()
+		for _, item := range schema.Content {
+			out.Content = append(out.Content, c.schema(item))
+		}
+		return out
+	}
+	if schema.Kind != yaml.MappingNode {
+		return copyNode(schema)
+	}
+	out := utils.CreateEmptyMapNode()
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		key, value := schema.Content[i].Value, schema.Content[i+1]
+		switch {
+		case key == "$ref" && value.Kind == yaml.ScalarNode:
+			setValue(out, key, utils.CreateStringNode(c.ref(value.Value)))
+		case key == "x-nullable" && value.Kind == yaml.ScalarNode:
+			setValue(out, "nullable", copyNode(value))
+		case key == "discriminator" && value.Kind == yaml.ScalarNode:
+			discriminator := utils.CreateEmptyMapNode()
+			setValue(discriminator, "propertyName", copyNode(value))
+			setValue(out, key, discriminator)
+		case key == "type" && value.Value == "file":
+			setValue(out, key, utils.CreateStringNode("string"))
+			if mapValue(schema, "format") == nil {
+				setValue(out, "format", utils.CreateStringNode("binary"))
+			}
+		case key == "example" || key == "default" || key == "enum" || strings.HasPrefix(key, "x-"):
+			setValue(out, key, copyNode(value))
+		case key == "properties" && value.Kind == yaml.MappingNode:
+			properties := utils.CreateEmptyMapNode()
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				setValue(properties, value.Content[j].Value, c.schema(value.Content[j+1]))
+			}
+			setValue(out, key, properties)
+		default:
+			setValue(out, key, c.schema(value))
+		}
+	}
+	return out
+}
+
+// ref rewrites a reference to a definition, parameter or response of the document to the matching component.
+func (c *swagger2Converter) ref(ref string) string {
+	if !strings.HasPrefix(ref, "#/") {
+		c.report("reference '%s' is to another document, which is not converted", ref)
+		return ref
+	}
+	if name, ok := strings.CutPrefix(ref, "#/definitions/"); ok {
+		return "#/components/schemas/" + name
+	}
+	if name, ok := strings.CutPrefix(ref, "#/responses/"); ok {
+		return "#/components/responses/" + name
+	}
+	if name, ok := strings.CutPrefix(ref, "#/parameters/"); ok {
+		if c.parameterIn(utils.CreateRefNode(ref)) == "body" {
+			return "#/components/requestBodies/" + name
+		}
+		return "#/components/parameters/" + name
+	}
+	c.report("reference '%s' can't be converted", ref)
+	return ref
+}
+
+func (c *swagger2Converter) report(format string, args ...any) {
+	c.errs = append(c.errs, fmt.Errorf("unable to convert swagger document, "+format, args...))
+}
+
+// mapValue returns the value of key in mapping node m (or nil).
+func mapValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setValue adds key to mapping node m, unless value is nil.
+func setValue(m *yaml.Node, key string, value *yaml.Node) {
+	if value != nil {
+		m.Content = append(m.Content, utils.CreateStringNode(key), value)
+	}
+}
+
+func copyExtensions(to, from *yaml.Node) {
+	for i := 0; i+1 < len(from.Content); i += 2 {
+		if strings.HasPrefix(from.Content[i].Value, "x-") {
+			setValue(to, from.Content[i].Value, copyNode(from.Content[i+1]))
+		}
+	}
+}
+
+func stringValue(n *yaml.Node) string {
+	if n == nil || n.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return n.Value
+}
+
+func stringValues(n *yaml.Node) []string {
+	if n == nil {
+		return nil
+	}
+	values := []string{}
+	for _, v := range n.Content {
+		values = append(values, v.Value)
+	}
+	return values
+}
+
+// copyNode returns a deep copy of n, so the converted document doesn't share nodes with the original.
+func copyNode(n *yaml.Node) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = copyNode(child)
+	}
+	return &c
+}
@@ -0,0 +1,199 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package libopenapi
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertSwagger2(t *testing.T) {
+	spec := `swagger: "2.0"
+info:
+  title: pets
+  version: "1.0"
+host: pets.example.com
+basePath: /v1
+schemes: [https, http]
+consumes: [application/json, application/xml]
+produces: [application/json]
+paths:
+  /pets:
+    parameters:
+      - $ref: '#/parameters/limit'
+    get:
+      operationId: listPets
+      parameters:
+        - name: tags
+          in: query
+          type: array
+          items:
+            type: string
+        - name: ids
+          in: query
+          type: array
+          collectionFormat: tsv
+          items:
+            type: integer
+      responses:
+        "200":
+          description: the pets
+          headers:
+            X-Total:
+              type: integer
+          schema:
+            type: array
+            items:
+              $ref: '#/definitions/Pet'
+          examples:
+            application/json: [{name: fido}]
+        default:
+          $ref: '#/responses/Error'
+    post:
+      operationId: createPet
+      parameters:
+        - $ref: '#/parameters/pet'
+      responses:
+        "201":
+          description: created
+  /pets/{id}/photo:
+    put:
+      operationId: uploadPhoto
+      consumes: [multipart/form-data]
+      parameters:
+        - name: id
+          in: path
+          required: true
+          type: string
+        - name: photo
+          in: formData
+          required: true
+          type: file
+        - name: caption
+          in: formData
+          type: string
+      responses:
+        "204":
+          description: uploaded
+definitions:
+  Pet:
+    type: object
+    discriminator: kind
+    properties:
+      name:
+        type: string
+        x-nullable: true
+      kind:
+        type: string
+parameters:
+  limit:
+    name: limit
+    in: query
+    type: integer
+  pet:
+    name: pet
+    in: body
+    required: true
+    schema:
+      $ref: '#/definitions/Pet'
+responses:
+  Error:
+    description: an error
+securityDefinitions:
+  basic:
+    type: basic
+  oauth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://example.com/auth
+    tokenUrl: https://example.com/token
+    scopes:
+      read: read pets`
+
+	doc, err := ConvertSwagger2([]byte(spec))
+	require.NotNil(t, doc)
+	assert.EqualError(t, err, "unable to convert swagger document, collectionFormat 'tsv' of parameter 'ids' "+
+		"(/pets get) can't be converted")
+
+	assert.Equal(t, "3.0.3", doc.Version)
+	assert.Equal(t, "pets", doc.Info.Title)
+	if assert.Len(t, doc.Servers, 2) {
+		assert.Equal(t, "https://pets.example.com/v1", doc.Servers[0].URL)
+		assert.Equal(t, "http://pets.example.com/v1", doc.Servers[1].URL)
+	}
+
+	// components
+	pet := doc.Components.Schemas.GetOrZero("Pet").Schema()
+	assert.Equal(t, "kind", pet.Discriminator.PropertyName)
+	assert.True(t, *pet.Properties.GetOrZero("name").Schema().Nullable)
+	assert.Equal(t, "limit", doc.Components.Parameters.GetOrZero("limit").Name)
+	assert.Nil(t, doc.Components.Parameters.GetOrZero("pet"))
+	petBody := doc.Components.RequestBodies.GetOrZero("pet")
+	assert.True(t, *petBody.Required)
+	assert.Equal(t, 2, orderedmap.Len(petBody.Content))
+	assert.NotNil(t, petBody.Content.GetOrZero("application/xml"))
+	assert.Equal(t, "an error", doc.Components.Responses.GetOrZero("Error").Description)
+	assert.Equal(t, "http", doc.Components.SecuritySchemes.GetOrZero("basic").Type)
+	assert.Equal(t, "basic", doc.Components.SecuritySchemes.GetOrZero("basic").Scheme)
+	oauth := doc.Components.SecuritySchemes.GetOrZero("oauth")
+	assert.Equal(t, "https://example.com/token", oauth.Flows.AuthorizationCode.TokenUrl)
+	assert.Equal(t, "read pets", oauth.Flows.AuthorizationCode.Scopes.GetOrZero("read"))
+
+	// operations
+	pets := doc.Paths.PathItems.GetOrZero("/pets")
+	assert.Equal(t, "#/components/parameters/limit", pets.Parameters[0].GoLow().GetReference())
+	list := pets.Get
+	if assert.Len(t, list.Parameters, 2) {
+		assert.Equal(t, "form", list.Parameters[0].Style)
+		assert.False(t, *list.Parameters[0].Explode)
+		assert.Equal(t, []string{"array"}, list.Parameters[0].Schema.Schema().Type)
+	}
+	ok := list.Responses.Codes.GetOrZero("200")
+	assert.Equal(t, []string{"integer"}, ok.Headers.GetOrZero("X-Total").Schema.Schema().Type)
+	media := ok.Content.GetOrZero("application/json")
+	assert.Equal(t, "#/components/schemas/Pet", media.Schema.Schema().Items.A.GetReference())
+	assert.Equal(t, "fido", media.Example.Content[0].Content[1].Value)
+	assert.Equal(t, "an error", list.Responses.Default.Description)
+
+	create := pets.Post
+	assert.Empty(t, create.Parameters)
+	assert.Equal(t, "#/components/requestBodies/pet", create.RequestBody.GoLow().GetReference())
+
+	upload := doc.Paths.PathItems.GetOrZero("/pets/{id}/photo").Put
+	assert.Len(t, upload.Parameters, 1)
+	form := upload.RequestBody.Content.GetOrZero("multipart/form-data").Schema.Schema()
+	assert.Equal(t, []string{"photo"}, form.Required)
+	photo := form.Properties.GetOrZero("photo").Schema()
+	assert.Equal(t, "binary", photo.Format)
+	assert.Equal(t, []string{"string"}, photo.Type)
+}
+
+func TestConvertSwagger2_Petstore(t *testing.T) {
+	spec, _ := os.ReadFile("test_specs/petstorev2-complete.yaml")
+	doc, err := ConvertSwagger2(spec)
+	assert.EqualError(t, err, "unable to convert swagger document, path '/ref' is a reference, which is not converted")
+	require.NotNil(t, doc)
+	assert.Equal(t, 15, orderedmap.Len(doc.Paths.PathItems))
+	assert.Equal(t, "https://petstore.swagger.io/v2", doc.Servers[0].URL)
+
+	// the converted document renders as valid OpenAPI 3.
+	rendered, err := doc.Render()
+	assert.NoError(t, err)
+	converted, err := NewDocument(rendered)
+	require.NoError(t, err)
+	_, errs := converted.BuildV3Model()
+	assert.Empty(t, errs)
+}
+
+func TestConvertSwagger2_NotSwagger(t *testing.T) {
+	_, err := ConvertSwagger2([]byte(`openapi: 3.1.0`))
+	assert.EqualError(t, err, "unable to convert document, supplied spec is not a Swagger document (oas3_1)")
+
+	_, err = ConvertSwagger2(nil)
+	assert.Error(t, err)
+}
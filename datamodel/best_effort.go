@@ -0,0 +1,247 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package datamodel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SectionError is an error found in a section of a specification when DocumentConfiguration.BestEffort is set. When
+// a section can't be parsed it is dropped from the document, and the rest of the document is kept.
+type SectionError struct {
+	// Path is a JSON pointer to the section, for example '/paths/~1pets'. Empty when the section isn't known.
+	Path string
+
+	// Line and Column are the position of the section in the specification.
+	Line   int
+	Column int
+
+	// Err is the error found in the section.
+	Err error
+}
+
+func (e *SectionError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("[%d:%d]: %s", e.Line, e.Column, e.Err.Error())
+	}
+	return fmt.Sprintf("section '%s' [%d:%d]: %s", e.Path, e.Line, e.Column, e.Err.Error())
+}
+
+// aliasPattern matches a YAML alias (like '*name') and what comes before it.
+var aliasPattern = regexp.MustCompile(`(^|[\s\[{,])\*([^\s,\[\]{}]+)`)
+
+// aliasPlaceholder replaces the aliases of anchors defined in other sections, when a section is parsed on its own.
+const aliasPlaceholder = "__libopenapi_alias__"
+
+// bestEffortParser keeps the anchors of the sections that have been parsed, so the sections that come after them
+// can use them.
+type bestEffortParser struct {
+	anchors map[string]*yaml.Node
+}
+
+func (e *SectionError) Unwrap() error {
+	return e.Err
+}
+
+// parseBestEffort parses a YAML specification that can't be parsed in one go, section by section. Every mapping
+// entry (or sequence item) that can't be parsed is split into the entries it contains, and parsed again, until the
+// entries that are broken are found. Those are dropped, and returned as a *SectionError each. Line and column
+// numbers of the nodes that are kept are the same as they are in the specification.
+//
+// Anchors defined by a section can be used by the sections after it. An alias of an anchor defined later in the
+// specification, or in a section that is dropped, can't be parsed, so the section it's in is dropped.
+//
+// Only block style YAML can be recovered, nil is returned if nothing could be recovered at all.
+func parseBestEffort(spec []byte) (*yaml.Node, []error) {
+	p := &bestEffortParser{anchors: make(map[string]*yaml.Node)}
+	lines := strings.Split(string(spec), "\n")
+	start := 0
+	for start < len(lines) && skipLine(lines[start], true) {
+		start++
+	}
+	root, errs := p.parseBlock(lines[start:], start, "")
+	if root == nil {
+		return nil, errs
+	}
+	return &yaml.Node{Kind: yaml.DocumentNode, Line: root.Line, Column: 1, Content: []*yaml.Node{root}}, errs
+}
+
+// skipLine returns true for a blank line or comment, and for document markers and directives at the top.
+func skipLine(line string, top bool) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return true
+	}
+	return top && (trimmed == "---" || strings.HasPrefix(line, "%"))
+}
+
+// parseLines parses lines as YAML, the first line being line start (zero based) of the specification. Aliases of
+// anchors defined by the sections parsed before are linked to their anchors.
+func (p *bestEffortParser) parseLines(lines []string, start int) (*yaml.Node, error) {
+	text := strings.Repeat("\n", start) + strings.Join(lines, "\n")
+	var n yaml.Node
+	err := yaml.Unmarshal([]byte(text), &n)
+	if err != nil && strings.Contains(err.Error(), "unknown anchor") && len(p.anchors) > 0 {
+		// the anchor may be in another section, so its aliases are swapped for placeholders, and linked back.
+		text = aliasPattern.ReplaceAllStringFunc(text, func(alias string) string {
+			m := aliasPattern.FindStringSubmatch(alias)
+			if p.anchors[m[2]] == nil {
+				return alias
+			}
+			return m[1] + aliasPlaceholder + m[2]
+		})
+		n = yaml.Node{}
+		if err = yaml.Unmarshal([]byte(text), &n); err == nil {
+			p.linkAliases(&n)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(n.Content) == 0 {
+		return nil, nil
+	}
+	return n.Content[0], nil
+}
+
+// linkAliases turns the placeholders of aliases under node back into aliases, linked to their anchors.
+func (p *bestEffortParser) linkAliases(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && strings.Contains(node.Value, aliasPlaceholder) {
+		name, isAlias := strings.CutPrefix(node.Value, aliasPlaceholder)
+		if isAlias && node.Style == 0 && p.anchors[name] != nil {
+			node.Kind, node.Tag, node.Value, node.Alias = yaml.AliasNode, "", name, p.anchors[name]
+			return
+		}
+		// not an alias, but text that looks like one (in a quoted string for example).
+		node.Value = strings.ReplaceAll(node.Value, aliasPlaceholder, "*")
+	}
+	for _, child := range node.Content {
+		p.linkAliases(child)
+	}
+}
+
+// keep records the anchors of a node that is kept.
+func (p *bestEffortParser) keep(node *yaml.Node) {
+	if node == nil || node.Kind == yaml.AliasNode {
+		return
+	}
+	if node.Anchor != "" {
+		p.anchors[node.Anchor] = node
+	}
+	for _, child := range node.Content {
+		p.keep(child)
+	}
+}
+
+// parseBlock parses the entries of a block mapping (or sequence), recovering the ones that can be parsed. All the
+// entries are indented the same as the first line.
+func (p *bestEffortParser) parseBlock(lines []string, start int, path string) (*yaml.Node, []error) {
+	var entries []int
+	indent := -1
+	for i, line := range lines {
+		if skipLine(line, false) {
+			continue
+		}
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent < 0 {
+			indent = lineIndent
+		}
+		item := strings.HasPrefix(line[lineIndent:], "-")
+		// items of a sequence value can be indented the same as the key of the mapping they belong to.
+		if lineIndent == indent && (len(entries) == 0 || item == isItem(lines[entries[0]])) {
+			entries = append(entries, i)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	sequence := isItem(lines[entries[0]])
+	block := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: start + entries[0] + 1, Column: indent + 1}
+	if sequence {
+		block.Kind, block.Tag = yaml.SequenceNode, "!!seq"
+	}
+	var errs []error
+	for i, first := range entries {
+		last := len(lines)
+		if i+1 < len(entries) {
+			last = entries[i+1]
+		}
+		entry := lines[first:last]
+		entryStart := start + first
+		if sequence {
+			item, err := p.parseLines(entry, entryStart)
+			if err != nil {
+				errs = append(errs, &SectionError{Path: fmt.Sprintf("%s/%d", path, i), Line: entryStart + 1,
+					Column: indent + 1, Err: err})
+				continue
+			}
+			if item != nil && len(item.Content) > 0 {
+				block.Content = append(block.Content, item.Content...)
+				p.keep(item)
+			}
+			continue
+		}
+		key, value, entryErrs := p.parseEntry(entry, entryStart, indent, path)
+		errs = append(errs, entryErrs...)
+		if key != nil {
+			block.Content = append(block.Content, key, value)
+			p.keep(value)
+		}
+	}
+	if len(block.Content) == 0 {
+		return nil, errs
+	}
+	return block, errs
+}
+
+// parseEntry parses a single mapping entry, and if it can't be parsed, the entries of its value.
+func (p *bestEffortParser) parseEntry(lines []string, start, indent int, path string) (*yaml.Node, *yaml.Node, []error) {
+	parsed, err := p.parseLines(lines, start)
+	if err == nil {
+		if parsed == nil || parsed.Kind != yaml.MappingNode || len(parsed.Content) != 2 {
+			return nil, nil, nil
+		}
+		return parsed.Content[0], parsed.Content[1], nil
+	}
+
+	sectionErr := &SectionError{Path: path, Line: start + 1, Column: indent + 1, Err: err}
+	header, headerErr := p.parseLines(lines[:1], start)
+	if headerErr != nil || header == nil || header.Kind != yaml.MappingNode || len(header.Content) != 2 {
+		// the key can't be parsed, but it's still the best way to find the section.
+		if key, _, found := strings.Cut(strings.TrimSpace(lines[0]), ":"); found && key != "" {
+			sectionErr.Path = path + "/" + escapeSegment(strings.Trim(key, `"'`))
+		}
+		return nil, nil, []error{sectionErr}
+	}
+	key := header.Content[0]
+	sectionErr.Path = path + "/" + escapeSegment(key.Value)
+	if header.Content[1].Tag != "!!null" || header.Content[1].Value != "" || len(lines) == 1 {
+		// the value is on the same line as the key, so there is nothing to split.
+		return nil, nil, []error{sectionErr}
+	}
+	value, errs := p.parseBlock(lines[1:], start+1, sectionErr.Path)
+	if value == nil {
+		// nothing is left of the entry, the errors of its own entries are more precise, when there are any.
+		if len(errs) == 0 {
+			errs = []error{sectionErr}
+		}
+		return nil, nil, errs
+	}
+	return key, value, errs
+}
+
+// escapeSegment escapes a key for use as a JSON pointer segment.
+func escapeSegment(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// isItem returns true if line is an item of a block sequence.
+func isItem(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " "), "-")
+}
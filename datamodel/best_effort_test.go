@@ -0,0 +1,117 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package datamodel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExtractSpecInfoWithConfig_BestEffort(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+tags:
+  - name: pets
+  - name: [broken
+paths:
+  /pets:
+    get:
+      summary: list pets
+  /owners:
+    get:
+      summary: list owners: all of them
+    post:
+      summary: add an owner
+components:
+  schemas:
+    Pet:
+      type: object`
+
+	_, err := ExtractSpecInfoWithConfig([]byte(spec), &DocumentConfiguration{})
+	assert.Error(t, err)
+
+	info, err := ExtractSpecInfoWithConfig([]byte(spec), &DocumentConfiguration{BestEffort: true})
+	require.NoError(t, err)
+	assert.Equal(t, "3.1.0", info.Version)
+	require.Len(t, info.ParseErrors, 2)
+
+	var sErr *SectionError
+	require.True(t, errors.As(info.ParseErrors[0], &sErr))
+	assert.Equal(t, "/tags/1", sErr.Path)
+	assert.Equal(t, 7, sErr.Line)
+	assert.Equal(t, 3, sErr.Column)
+
+	require.True(t, errors.As(info.ParseErrors[1], &sErr))
+	assert.Equal(t, "/paths/~1owners/get/summary", sErr.Path)
+	assert.Equal(t, 14, sErr.Line)
+	assert.Equal(t, 7, sErr.Column)
+	assert.Contains(t, sErr.Error(), "section '/paths/~1owners/get/summary' [14:7]: yaml: line 14")
+
+	// everything else is kept, with the original line numbers.
+	root := info.RootNode.Content[0]
+	assert.Equal(t, []any{"openapi", "info", "tags", "paths", "components"}, mappingKeys(root.Content))
+	tags := root.Content[5]
+	assert.Len(t, tags.Content, 1)
+	paths := root.Content[7]
+	assert.Equal(t, []any{"/pets", "/owners"}, mappingKeys(paths.Content))
+	assert.Equal(t, 9, paths.Content[0].Line)
+	assert.Equal(t, []any{"post"}, mappingKeys(paths.Content[3].Content))
+	assert.Equal(t, 19, root.Content[9].Content[1].Content[0].Line)
+
+	// JSON can't be recovered.
+	_, err = ExtractSpecInfoWithConfig([]byte(`{"openapi": "3.1.0", "info": {]}`), &DocumentConfiguration{BestEffort: true})
+	assert.Error(t, err)
+}
+
+func TestExtractSpecInfoWithConfig_BestEffort_Anchors(t *testing.T) {
+	spec := `openapi: 3.1.0
+info: &i
+  title: pets
+  version: 1.0.0
+x-info: *i
+x-text: "a *i that is not an alias"
+x-later: *later
+paths:
+  /pets: &later
+    get:
+      summary: list pets
+  /broken:
+    get:
+      summary: [broken`
+
+	info, err := ExtractSpecInfoWithConfig([]byte(spec), &DocumentConfiguration{BestEffort: true})
+	require.NoError(t, err)
+	require.Len(t, info.ParseErrors, 2)
+
+	// an alias of an anchor defined later can't be recovered.
+	var sErr *SectionError
+	require.True(t, errors.As(info.ParseErrors[0], &sErr))
+	assert.Equal(t, "/x-later", sErr.Path)
+	assert.Equal(t, 7, sErr.Line)
+
+	root := info.RootNode.Content[0]
+	assert.Equal(t, []any{"openapi", "info", "x-info", "x-text", "paths"}, mappingKeys(root.Content))
+	alias := root.Content[5]
+	assert.Equal(t, yaml.AliasNode, alias.Kind)
+	assert.Same(t, root.Content[3], alias.Alias)
+	assert.Equal(t, "a *i that is not an alias", root.Content[7].Value)
+
+	// errors that are not about a section still say where they are.
+	assert.Equal(t, "[3:4]: oops", (&SectionError{Line: 3, Column: 4, Err: errors.New("oops")}).Error())
+}
+
+// mappingKeys returns the value of every key in the content of a mapping node.
+func mappingKeys(content []*yaml.Node) []any {
+	var k []any
+	for i := 0; i < len(content); i += 2 {
+		k = append(k, content[i].Value)
+	}
+	return k
+}
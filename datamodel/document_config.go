@@ -4,9 +4,11 @@
 package datamodel
 
 import (
+	"context"
 	"github.com/pb33f/libopenapi/utils"
 	"io/fs"
 	"log/slog"
+	"net/http"
 	"net/url"
 	"os"
 )
@@ -21,6 +23,12 @@ type DocumentConfiguration struct {
 	// Schema must be set to "http/https".
 	BaseURL *url.URL
 
+	// SpecURL is the location of the root specification, when it has been fetched from a remote location (see
+	// libopenapi.NewDocumentFromURL). Relative references in the root specification are resolved against the
+	// directory of the URL, and the index of the root specification records it as the absolute path of the spec.
+	// Remote lookups are allowed when a SpecURL is set.
+	SpecURL *url.URL
+
 	// RemoteURLHandler is a function that will be used to retrieve remote documents. If not set, the default
 	// remote document getter will be used.
 	//
@@ -30,6 +38,48 @@ type DocumentConfiguration struct {
 	// Resolves [#132]: https://github.com/pb33f/libopenapi/issues/132
 	RemoteURLHandler utils.RemoteURLHandler
 
+	// HTTPClient is the client used to retrieve remote documents, use it to configure authentication, proxies, mTLS
+	// or timeouts for private schema registries. If a RemoteURLHandler is set, it takes precedence over the HTTPClient.
+	// If neither is set, a default client (with a 120-second timeout) is used.
+	HTTPClient *http.Client
+
+	// Context is used to cancel (or set a deadline on) building a document. It is handed to the index, the resolver
+	// and to any remote lookups made by the default remote file system. If the context is cancelled, the document
+	// will not be built and ctx.Err() is returned.
+	Context context.Context
+
+	// AllowedRemoteHosts is a list of hosts that remote references are allowed to be fetched from, any other host is
+	// refused. DeniedRemoteHosts is a list of hosts that remote references will never be fetched from, it takes
+	// precedence over AllowedRemoteHosts. Use these to prevent user-supplied specifications from making requests to
+	// arbitrary hosts. Wildcard patterns are supported, for example '*.internal.example.com'.
+	AllowedRemoteHosts []string
+	DeniedRemoteHosts  []string
+
+	// LazyRemoteResolution will stop remote references from being fetched while the document is indexed. A remote
+	// schema is fetched when it's first built (when Schema() is called on a SchemaProxy backed by the reference),
+	// anything else is fetched when the model is built. Useful for specifications with many remote references that
+	// are rarely used. Remote references are not checked for circular references when this is enabled.
+	LazyRemoteResolution bool
+
+	// MaxDocumentBytes is the maximum size (in bytes) of any document that will be parsed, this includes the root
+	// specification and any local or remote documents it references. Documents that are larger are refused with
+	// ErrDocumentTooLarge, rather than being parsed. Zero (the default) means there is no limit.
+	MaxDocumentBytes int64
+
+	// MaxDocumentNodes is the maximum number of YAML nodes a parsed document may contain, it's checked straight after
+	// parsing and before anything else is done with the document. Zero (the default) means there is no limit.
+	MaxDocumentNodes int
+
+	// MaxYAMLAliasExpansions is the maximum number of YAML alias dereferences a document may make once fully
+	// expanded. Heavily nested anchors and aliases (a 'billion laughs' attack) are refused with
+	// ErrTooManyAliasExpansions. Zero (the default) means there is no limit.
+	MaxYAMLAliasExpansions int
+
+	// DetectDuplicateKeys will scan the specification for mappings that define the same key more than once (for
+	// example, two 'get' operations under the same path) when it's parsed. YAML parsers quietly keep only one of
+	// them, so the specification is refused with an ErrDuplicateKey error for every duplicate found instead.
+	DetectDuplicateKeys bool
+
 	// If resolving locally, the BasePath will be the root from which relative references will be resolved from.
 	// It's usually the location of the root specification.
 	//
@@ -39,10 +89,19 @@ type DocumentConfiguration struct {
 	// To avoid sucking in all the files, set the FileFilter to a list of specific files to be included.
 	BasePath string // set the Base Path for resolving relative references if the spec is exploded.
 
+	// SpecFilePath is the path of the root specification file, relative to the BasePath (or absolute). Set it when the
+	// root specification is not in the BasePath directory itself (for example 'specs/api/openapi.yaml'), so the
+	// relative references it makes are resolved from the directory it lives in, rather than from the BasePath.
+	SpecFilePath string
+
 	// FileFilter is a list of specific files to be included by the rolodex when looking up references. If this value
 	// is set, then only these specific files will be included. If this value is not set, then all files will be included.
 	FileFilter []string
 
+	// RestrictToBaseDirectory will prevent the rolodex from loading any local file that resolves to a location outside
+	// the BasePath (for example a reference to '../../../../etc/passwd'). Use this when indexing untrusted specifications.
+	RestrictToBaseDirectory bool
+
 	// RemoteFS is a filesystem that will be used to retrieve remote documents. If not set, then the rolodex will
 	// use its own internal remote filesystem implementation. The RemoteURLHandler will be used to retrieve remote
 	// documents if it has been set. The default is to use the internal remote filesystem loader.
@@ -98,6 +157,32 @@ type DocumentConfiguration struct {
 	// indexed later on.
 	SkipCircularReferenceCheck bool
 
+	// MergeRefSiblings will keep the keywords next to a $ref in an OpenAPI 3.1 document (e.g. a 'description' that
+	// overrides the description of the schema it references), merging them into the referenced schema when it's
+	// built. The keywords of the referenced schema are overridden by its siblings, and the referenced schema itself
+	// is not changed. OpenAPI 3.0 does not allow siblings, so they are always discarded. This is disabled by default.
+	MergeRefSiblings bool
+
+	// DisableSchemaCache will stop built schemas from being shared. By default, every SchemaProxy that references the
+	// same schema returns the same *Schema, which is only built once. Enable this if each reference needs its own
+	// isolated copy of the schema.
+	DisableSchemaCache bool
+
+	// StrictUnknownFields will report every field of an OpenAPI 3 document (and of the objects in it, except schemas)
+	// that is not one of the fixed fields the specification defines for the object, and is not an extension (x-),
+	// as an error when the document is built. This catches typos like 'component' instead of 'components', which
+	// would otherwise be ignored. This is disabled by default, which means unknown fields are ignored.
+	StrictUnknownFields bool
+
+	// BestEffort will keep as much of a specification as possible when parts of it are broken, instead of failing.
+	// A YAML specification that can't be parsed is parsed again section by section, and only the sections that
+	// can't be parsed are dropped. Errors found when building a section of the model no longer prevent the rest
+	// of the model from being built. Every error is reported with its location in the specification, and is
+	// available from the GetBuildErrors method of the document. This is useful for editors, that need a model even
+	// when part of the specification is broken. JSON specifications can't be recovered when they can't be parsed.
+	// This is disabled by default.
+	BestEffort bool
+
 	// Logger is a structured logger that will be used for logging errors and warnings. If not set, a default logger
 	// will be used, set to the Error level.
 	Logger *slog.Logger
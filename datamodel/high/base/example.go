@@ -5,9 +5,14 @@ package base
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/pb33f/libopenapi/datamodel/high"
 	lowmodel "github.com/pb33f/libopenapi/datamodel/low"
 	low "github.com/pb33f/libopenapi/datamodel/low/base"
+	"github.com/pb33f/libopenapi/index"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"gopkg.in/yaml.v3"
 )
@@ -66,6 +71,57 @@ func (e *Example) MarshalJSON() ([]byte, error) {
 	return json.Marshal(g)
 }
 
+// ResolveValue will return the value of the Example, decoded into a generic Go value. If the Example has an inline
+// 'value', it's returned. Otherwise, the document at 'externalValue' is opened using the rolodex of the document
+// (the high-level v3 Document exposes it as Rolodex), so it's subject to the same configuration as references are,
+// including the allowed and denied remote hosts. The document must be JSON or YAML. A relative 'externalValue' is
+// resolved from the root document, when the root document is remote.
+//
+// If the Example has no value at all, nil is returned with no error.
+func (e *Example) ResolveValue(rolodex *index.Rolodex) (any, error) {
+	if e.Value != nil {
+		var value any
+		if err := e.Value.Decode(&value); err != nil {
+			return nil, fmt.Errorf("unable to decode example value: %w", err)
+		}
+		return value, nil
+	}
+	if e.ExternalValue == "" {
+		return nil, nil
+	}
+	if rolodex == nil {
+		return nil, fmt.Errorf("unable to resolve example external value '%s', no rolodex is available",
+			e.ExternalValue)
+	}
+
+	location := e.ExternalValue
+	if root := rolodex.GetRootIndex(); root != nil && !strings.HasPrefix(location, "http") {
+		if base, err := url.Parse(root.GetSpecAbsolutePath()); err == nil && strings.HasPrefix(base.Scheme, "http") {
+			if ref, rErr := url.Parse(location); rErr == nil {
+				location = base.ResolveReference(ref).String()
+			}
+		}
+	}
+
+	// the rolodex may return errors from other file systems, along with the file.
+	f, err := rolodex.Open(location)
+	if f == nil {
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve example external value '%s': %w", e.ExternalValue, err)
+		}
+		return nil, fmt.Errorf("unable to resolve example external value '%s', it can't be found", e.ExternalValue)
+	}
+	node, err := f.GetContentAsYAMLNode()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse example external value '%s': %w", e.ExternalValue, err)
+	}
+	var value any
+	if err = node.Decode(&value); err != nil {
+		return nil, fmt.Errorf("unable to decode example external value '%s': %w", e.ExternalValue, err)
+	}
+	return value, nil
+}
+
 // ExtractExamples will convert a low-level example map, into a high level one that is simple to navigate.
 // no fidelity is lost, everything is still available via GoLow()
 func ExtractExamples(elements *orderedmap.Map[lowmodel.KeyReference[string], lowmodel.ValueReference[*low.Example]]) *orderedmap.Map[string, *Example] {
@@ -7,11 +7,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	lowmodel "github.com/pb33f/libopenapi/datamodel/low"
 	lowbase "github.com/pb33f/libopenapi/datamodel/low/base"
+	"github.com/pb33f/libopenapi/index"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
@@ -117,3 +122,73 @@ x-hack: code`
 	fmt.Print(highExample.ExternalValue)
 	// Output: https://pb33f.io
 }
+
+func TestExample_ResolveValue(t *testing.T) {
+	e := &Example{Value: &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "42"}}
+	value, err := e.ResolveValue(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, value)
+
+	value, err = (&Example{}).ResolveValue(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	_, err = (&Example{ExternalValue: "pizza.json"}).ResolveValue(nil)
+	assert.EqualError(t, err, "unable to resolve example external value 'pizza.json', no rolodex is available")
+}
+
+func TestExample_ResolveValue_Local(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "pizza.json"), []byte(`{"name": "pepperoni", "slices": 8}`), 0o644))
+
+	config := index.CreateOpenAPIIndexConfig()
+	config.BasePath = dir
+	localFS, err := index.NewLocalFSWithConfig(&index.LocalFSConfig{
+		BaseDirectory: dir,
+		DirFS:         os.DirFS(dir),
+		IndexConfig:   config,
+	})
+	assert.NoError(t, err)
+	rolodex := index.NewRolodex(config)
+	rolodex.AddLocalFS(dir, localFS)
+
+	value, err := (&Example{ExternalValue: "pizza.json"}).ResolveValue(rolodex)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "pepperoni", "slices": 8}, value)
+
+	_, err = (&Example{ExternalValue: "burger.json"}).ResolveValue(rolodex)
+	assert.Error(t, err)
+}
+
+func TestExample_ResolveValue_Remote(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("name: margherita"))
+	}))
+	defer server.Close()
+
+	config := index.CreateOpenAPIIndexConfig()
+	config.AllowRemoteLookup = true
+	remoteFS, err := index.NewRemoteFSWithConfig(config)
+	assert.NoError(t, err)
+	rolodex := index.NewRolodex(config)
+	rolodex.AddRemoteFS(server.URL, remoteFS)
+
+	value, err := (&Example{ExternalValue: server.URL + "/pizza.yaml"}).ResolveValue(rolodex)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "margherita"}, value)
+
+	// the allowed hosts are respected.
+	config = index.CreateOpenAPIIndexConfig()
+	config.AllowRemoteLookup = true
+	config.AllowedRemoteHosts = []string{"pb33f.io"}
+	remoteFS, err = index.NewRemoteFSWithConfig(config)
+	assert.NoError(t, err)
+	rolodex = index.NewRolodex(config)
+	rolodex.AddRemoteFS(server.URL, remoteFS)
+
+	_, err = (&Example{ExternalValue: server.URL + "/pizza.yaml"}).ResolveValue(rolodex)
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests)
+}
@@ -73,6 +73,12 @@ type Schema struct {
 	// 3.1 only, part of the JSON Schema spec provides a way to identify a sub-schema
 	Anchor string `json:"$anchor,omitempty" yaml:"$anchor,omitempty"`
 
+	// 3.1 only, part of the JSON Schema spec, used for recursive schemas that can be extended.
+	// A SchemaProxy follows a DynamicRef the index can resolve (see index.SpecIndex.FindDynamicRef) like a $ref,
+	// so a Schema only has a DynamicRef if it could not be resolved.
+	DynamicAnchor string `json:"$dynamicAnchor,omitempty" yaml:"$dynamicAnchor,omitempty"`
+	DynamicRef    string `json:"$dynamicRef,omitempty" yaml:"$dynamicRef,omitempty"`
+
 	// Compatible with all versions
 	Not                  *SchemaProxy                          `json:"not,omitempty" yaml:"not,omitempty"`
 	Properties           *orderedmap.Map[string, *SchemaProxy] `json:"properties,omitempty" yaml:"properties,omitempty"`
@@ -93,6 +99,8 @@ type Schema struct {
 	Enum                 []*yaml.Node                          `json:"enum,omitempty" yaml:"enum,omitempty"`
 	AdditionalProperties *DynamicValue[*SchemaProxy, bool]     `json:"additionalProperties,renderZero,omitempty" yaml:"additionalProperties,renderZero,omitempty"`
 	Description          string                                `json:"description,omitempty" yaml:"description,omitempty"`
+	ContentEncoding      string                                `json:"contentEncoding,omitempty" yaml:"contentEncoding,omitempty"`
+	ContentMediaType     string                                `json:"contentMediaType,omitempty" yaml:"contentMediaType,omitempty"`
 	Default              *yaml.Node                            `json:"default,omitempty" yaml:"default,renderZero,omitempty"`
 	Const                *yaml.Node                            `json:"const,omitempty" yaml:"const,renderZero,omitempty"`
 	Nullable             *bool                                 `json:"nullable,omitempty" yaml:"nullable,omitempty"`
@@ -103,7 +111,11 @@ type Schema struct {
 	Example              *yaml.Node                            `json:"example,omitempty" yaml:"example,omitempty"`
 	Deprecated           *bool                                 `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
 	Extensions           *orderedmap.Map[string, *yaml.Node]   `json:"-" yaml:"-"`
-	low                  *base.Schema
+
+	// UnknownKeywords are keywords that are not modeled by the Schema and are not extensions, for example '$comment'
+	// or the keywords of a custom JSON Schema vocabulary. They are rendered verbatim.
+	UnknownKeywords *orderedmap.Map[string, *yaml.Node] `json:"-" yaml:"-"`
+	low             *base.Schema
 
 	// Parent Proxy refers back to the low level SchemaProxy that is proxying this schema.
 	ParentProxy *SchemaProxy `json:"-" yaml:"-"`
@@ -264,6 +276,8 @@ func NewSchema(schema *base.Schema) *Schema {
 	s.AdditionalProperties = additionalProperties
 
 	s.Description = schema.Description.Value
+	s.ContentEncoding = schema.ContentEncoding.Value
+	s.ContentMediaType = schema.ContentMediaType.Value
 	s.Default = schema.Default.Value
 	s.Const = schema.Const.Value
 	if !schema.Nullable.IsEmpty() {
@@ -287,6 +301,9 @@ func NewSchema(schema *base.Schema) *Schema {
 		s.Examples = examples
 	}
 	s.Extensions = high.ExtractExtensions(schema.Extensions)
+	if schema.UnknownKeywords != nil {
+		s.UnknownKeywords = high.ExtractExtensions(schema.UnknownKeywords)
+	}
 	if !schema.Discriminator.IsEmpty() {
 		s.Discriminator = NewDiscriminator(schema.Discriminator.Value)
 	}
@@ -305,6 +322,12 @@ func NewSchema(schema *base.Schema) *Schema {
 	if !schema.Anchor.IsEmpty() {
 		s.Anchor = schema.Anchor.Value
 	}
+	if !schema.DynamicAnchor.IsEmpty() {
+		s.DynamicAnchor = schema.DynamicAnchor.Value
+	}
+	if !schema.DynamicRef.IsEmpty() {
+		s.DynamicRef = schema.DynamicRef.Value
+	}
 
 	var enum []*yaml.Node
 	for i := range schema.Enum.Value {
@@ -465,6 +488,54 @@ func (s *Schema) GoLowUntyped() any {
 	return s.low
 }
 
+// OrderedPropertyNames will return the names of the properties of the Schema, in the order they are defined by the
+// specification (the low-level model). Properties that have been added to the high-level model follow, in the order
+// they were added. Properties removed from the high-level model are not returned.
+//
+// This is not named PropertyNames, because that is the 'propertyNames' keyword of the Schema.
+func (s *Schema) OrderedPropertyNames() []string {
+	if s.Properties == nil {
+		return nil
+	}
+	names := make([]string, 0, orderedmap.Len(s.Properties))
+	seen := make(map[string]bool)
+	if s.low != nil {
+		for pair := orderedmap.First(s.low.Properties.Value); pair != nil; pair = pair.Next() {
+			name := pair.Key().Value
+			if _, ok := s.Properties.Get(name); ok && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	for pair := orderedmap.First(s.Properties); pair != nil; pair = pair.Next() {
+		if !seen[pair.Key()] {
+			names = append(names, pair.Key())
+		}
+	}
+	return names
+}
+
+// inSourceOrder returns the Schema ready to be rendered, with properties in the order of OrderedPropertyNames.
+// If the properties are already in that order, the Schema itself is returned, otherwise a copy is.
+func (s *Schema) inSourceOrder() *Schema {
+	names := s.OrderedPropertyNames()
+	i := 0
+	for pair := orderedmap.First(s.Properties); pair != nil; pair = pair.Next() {
+		if pair.Key() != names[i] {
+			ordered := orderedmap.New[string, *SchemaProxy]()
+			for _, name := range names {
+				ordered.Set(name, s.Properties.GetOrZero(name))
+			}
+			c := *s
+			c.Properties = ordered
+			return &c
+		}
+		i++
+	}
+	return s
+}
+
 // Render will return a YAML representation of the Schema object as a byte slice.
 func (s *Schema) Render() ([]byte, error) {
 	return yaml.Marshal(s)
@@ -481,7 +552,7 @@ func (s *Schema) RenderInline() ([]byte, error) {
 
 // MarshalYAML will create a ready to render YAML representation of the ExternalDoc object.
 func (s *Schema) MarshalYAML() (interface{}, error) {
-	nb := high.NewNodeBuilder(s, s.low)
+	nb := high.NewNodeBuilder(s.inSourceOrder(), s.low)
 
 	// determine index version
 	idx := s.GoLow().Index
@@ -495,7 +566,7 @@ func (s *Schema) MarshalYAML() (interface{}, error) {
 
 // MarshalJSON will create a ready to render JSON representation of the Schema object.
 func (s *Schema) MarshalJSON() ([]byte, error) {
-	nb := high.NewNodeBuilder(s, s.low)
+	nb := high.NewNodeBuilder(s.inSourceOrder(), s.low)
 
 	// determine index version
 	idx := s.GoLow().Index
@@ -517,7 +588,7 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 
 // MarshalYAMLInline will render out the Schema pointer as YAML, and all refs will be inlined fully
 func (s *Schema) MarshalYAMLInline() (interface{}, error) {
-	nb := high.NewNodeBuilder(s, s.low)
+	nb := high.NewNodeBuilder(s.inSourceOrder(), s.low)
 	nb.Resolve = true
 	// determine index version
 	idx := s.GoLow().Index
@@ -531,7 +602,7 @@ func (s *Schema) MarshalYAMLInline() (interface{}, error) {
 
 // MarshalJSONInline will render out the Schema pointer as JSON, and all refs will be inlined fully
 func (s *Schema) MarshalJSONInline() ([]byte, error) {
-	nb := high.NewNodeBuilder(s, s.low)
+	nb := high.NewNodeBuilder(s.inSourceOrder(), s.low)
 	nb.Resolve = true
 	// determine index version
 	idx := s.GoLow().Index
@@ -550,3 +621,39 @@ func (s *Schema) MarshalJSONInline() ([]byte, error) {
 	// return JSON bytes
 	return json.Marshal(renderedJSON)
 }
+
+// subSchemas returns every SchemaProxy directly contained by the Schema, composition, array, object and
+// conditional keywords are all included. The schemas are not built or resolved, only collected.
+func (s *Schema) subSchemas() []*SchemaProxy {
+	var proxies []*SchemaProxy
+	add := func(sp ...*SchemaProxy) {
+		for _, p := range sp {
+			if p != nil {
+				proxies = append(proxies, p)
+			}
+		}
+	}
+	addMap := func(m *orderedmap.Map[string, *SchemaProxy]) {
+		for pair := orderedmap.First(m); pair != nil; pair = pair.Next() {
+			add(pair.Value())
+		}
+	}
+	add(s.AllOf...)
+	add(s.OneOf...)
+	add(s.AnyOf...)
+	add(s.PrefixItems...)
+	add(s.Contains, s.If, s.Else, s.Then, s.PropertyNames, s.UnevaluatedItems, s.Not)
+	if s.Items != nil && s.Items.IsA() {
+		add(s.Items.A)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.IsA() {
+		add(s.AdditionalProperties.A)
+	}
+	if s.UnevaluatedProperties != nil && s.UnevaluatedProperties.IsA() {
+		add(s.UnevaluatedProperties.A)
+	}
+	addMap(s.Properties)
+	addMap(s.DependentSchemas)
+	addMap(s.PatternProperties)
+	return proxies
+}
@@ -0,0 +1,429 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// FlattenAllOf will merge every 'allOf' member of the Schema (and every 'allOf' member of those members) into a
+// single Schema that has no 'allOf'. Members that are references are resolved using their SchemaProxy first.
+//
+// Properties are combined (a property defined by more than one member is merged in the same way), 'required' is
+// the union of all members, and constraints are tightened (the smallest maximum, the largest minimum etc.). Metadata
+// such as 'title' and 'description' is taken from the Schema first, then from each member in order.
+//
+// Members that cannot be combined are reported as errors, for example incompatible 'type' values, different
+// 'pattern' or 'format' values, enums with no values in common, or constraints that can never be satisfied (such as
+// a 'minimum' that is greater than the 'maximum'). If any errors are found, no Schema is returned. The Schema
+// itself is not modified.
+func (s *Schema) FlattenAllOf() (*Schema, error) {
+	f := &allOfFlattener{stack: make(map[any]bool)}
+	flat := f.flatten(s, "")
+	if len(f.errs) > 0 {
+		return nil, errors.Join(f.errs...)
+	}
+	return flat, nil
+}
+
+type allOfFlattener struct {
+	stack map[any]bool
+	errs  []error
+}
+
+func (f *allOfFlattener) fail(path, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if path != "" {
+		msg = fmt.Sprintf("%s: %s", path, msg)
+	}
+	f.errs = append(f.errs, fmt.Errorf("unable to flatten allOf, %s", msg))
+}
+
+// flatten returns a copy of s with all allOf members merged in.
+func (f *allOfFlattener) flatten(s *Schema, path string) *Schema {
+	if s == nil {
+		return nil
+	}
+	key := schemaKey(s)
+	if f.stack[key] {
+		f.fail(path, "allOf is circular")
+		return &Schema{}
+	}
+	f.stack[key] = true
+	defer delete(f.stack, key)
+
+	merged := *s
+	merged.AllOf = nil
+	merged.low = nil
+	merged.ParentProxy = nil
+	merged.Type = slices.Clone(s.Type)
+	merged.Required = slices.Clone(s.Required)
+	merged.Examples = slices.Clone(s.Examples)
+	merged.Properties = cloneProxyMap(s.Properties)
+	merged.PatternProperties = cloneProxyMap(s.PatternProperties)
+	merged.DependentSchemas = cloneProxyMap(s.DependentSchemas)
+	merged.Extensions = cloneNodeMap(s.Extensions)
+
+	for i, sp := range s.AllOf {
+		memberPath := joinFlattenPath(path, fmt.Sprintf("allOf[%d]", i))
+		member := sp.Schema()
+		if member == nil {
+			err := sp.GetBuildError()
+			if err == nil {
+				err = errors.New("schema is empty")
+			}
+			f.fail(memberPath, "unable to build schema: %s", err.Error())
+			continue
+		}
+		f.merge(&merged, f.flatten(member, memberPath), memberPath)
+	}
+	return &merged
+}
+
+// merge combines the (already flattened) schema b into a.
+func (f *allOfFlattener) merge(a, b *Schema, path string) {
+	a.Type = f.mergeTypes(a.Type, b.Type, path)
+
+	// metadata, the first value wins.
+	a.Title = firstString(a.Title, b.Title)
+	a.Description = firstString(a.Description, b.Description)
+	a.SchemaTypeRef = firstString(a.SchemaTypeRef, b.SchemaTypeRef)
+	a.Anchor = firstString(a.Anchor, b.Anchor)
+	a.DynamicAnchor = firstString(a.DynamicAnchor, b.DynamicAnchor)
+	a.DynamicRef = firstString(a.DynamicRef, b.DynamicRef)
+	a.Default = firstValue(a.Default, b.Default)
+	a.Example = firstValue(a.Example, b.Example)
+	a.Examples = append(a.Examples, b.Examples...)
+	a.XML = firstValue(a.XML, b.XML)
+	a.ExternalDocs = firstValue(a.ExternalDocs, b.ExternalDocs)
+	a.Discriminator = firstValue(a.Discriminator, b.Discriminator)
+	for pair := orderedmap.First(b.Extensions); pair != nil; pair = pair.Next() {
+		if a.Extensions == nil {
+			a.Extensions = orderedmap.New[string, *yaml.Node]()
+		}
+		if _, ok := a.Extensions.Get(pair.Key()); !ok {
+			a.Extensions.Set(pair.Key(), pair.Value())
+		}
+	}
+
+	// values that must agree.
+	a.Format = f.mergeString(a.Format, b.Format, "format", path)
+	a.Pattern = f.mergeString(a.Pattern, b.Pattern, "pattern", path)
+	a.MultipleOf = f.mergeEqual(a.MultipleOf, b.MultipleOf, "multipleOf", path)
+	a.Const = f.mergeConst(a.Const, b.Const, path)
+	a.Enum = f.mergeEnum(a.Enum, b.Enum, path)
+
+	// flags.
+	a.ReadOnly = eitherTrue(a.ReadOnly, b.ReadOnly)
+	a.WriteOnly = eitherTrue(a.WriteOnly, b.WriteOnly)
+	a.Deprecated = eitherTrue(a.Deprecated, b.Deprecated)
+	a.UniqueItems = eitherTrue(a.UniqueItems, b.UniqueItems)
+	if a.Nullable == nil {
+		a.Nullable = b.Nullable
+	} else if b.Nullable != nil {
+		n := *a.Nullable && *b.Nullable
+		a.Nullable = &n
+	}
+
+	// constraints are tightened.
+	a.Maximum = smallest(a.Maximum, b.Maximum)
+	a.Minimum = largest(a.Minimum, b.Minimum)
+	a.MaxLength = smallest(a.MaxLength, b.MaxLength)
+	a.MinLength = largest(a.MinLength, b.MinLength)
+	a.MaxItems = smallest(a.MaxItems, b.MaxItems)
+	a.MinItems = largest(a.MinItems, b.MinItems)
+	a.MaxProperties = smallest(a.MaxProperties, b.MaxProperties)
+	a.MinProperties = largest(a.MinProperties, b.MinProperties)
+	a.MaxContains = smallest(a.MaxContains, b.MaxContains)
+	a.MinContains = largest(a.MinContains, b.MinContains)
+	a.ExclusiveMaximum = f.mergeExclusive(a.ExclusiveMaximum, b.ExclusiveMaximum, smallest[float64], "exclusiveMaximum", path)
+	a.ExclusiveMinimum = f.mergeExclusive(a.ExclusiveMinimum, b.ExclusiveMinimum, largest[float64], "exclusiveMinimum", path)
+	checkRange(f, a.Minimum, a.Maximum, "minimum", "maximum", path)
+	checkRange(f, a.MinLength, a.MaxLength, "minLength", "maxLength", path)
+	checkRange(f, a.MinItems, a.MaxItems, "minItems", "maxItems", path)
+	checkRange(f, a.MinProperties, a.MaxProperties, "minProperties", "maxProperties", path)
+	checkRange(f, a.MinContains, a.MaxContains, "minContains", "maxContains", path)
+
+	// object keywords.
+	for _, r := range b.Required {
+		if !slices.Contains(a.Required, r) {
+			a.Required = append(a.Required, r)
+		}
+	}
+	a.Properties = f.mergeProxyMap(a.Properties, b.Properties, joinFlattenPath(path, "properties"))
+	a.PatternProperties = f.mergeProxyMap(a.PatternProperties, b.PatternProperties, joinFlattenPath(path, "patternProperties"))
+	a.DependentSchemas = f.mergeProxyMap(a.DependentSchemas, b.DependentSchemas, joinFlattenPath(path, "dependentSchemas"))
+	a.AdditionalProperties = f.mergeDynamic(a.AdditionalProperties, b.AdditionalProperties, joinFlattenPath(path, "additionalProperties"))
+	a.UnevaluatedProperties = f.mergeDynamic(a.UnevaluatedProperties, b.UnevaluatedProperties, joinFlattenPath(path, "unevaluatedProperties"))
+	a.PropertyNames = f.mergeProxy(a.PropertyNames, b.PropertyNames, joinFlattenPath(path, "propertyNames"))
+
+	// array keywords.
+	a.Items = f.mergeDynamic(a.Items, b.Items, joinFlattenPath(path, "items"))
+	a.Contains = f.mergeProxy(a.Contains, b.Contains, joinFlattenPath(path, "contains"))
+	a.UnevaluatedItems = f.mergeProxy(a.UnevaluatedItems, b.UnevaluatedItems, joinFlattenPath(path, "unevaluatedItems"))
+	if len(a.PrefixItems) > 0 && len(b.PrefixItems) > 0 {
+		f.fail(path, "members cannot both define 'prefixItems'")
+	} else if len(a.PrefixItems) == 0 {
+		a.PrefixItems = b.PrefixItems
+	}
+
+	// composition and conditional keywords cannot be merged, only one member may define them.
+	a.OneOf = f.mergeOnlyOne(a.OneOf, b.OneOf, "oneOf", path)
+	a.AnyOf = f.mergeOnlyOne(a.AnyOf, b.AnyOf, "anyOf", path)
+	for _, kw := range []struct {
+		name string
+		a    **SchemaProxy
+		b    *SchemaProxy
+	}{{"not", &a.Not, b.Not}, {"if", &a.If, b.If}, {"then", &a.Then, b.Then}, {"else", &a.Else, b.Else}} {
+		if *kw.a != nil && kw.b != nil && *kw.a != kw.b {
+			f.fail(path, "members cannot both define '%s'", kw.name)
+		} else if *kw.a == nil {
+			*kw.a = kw.b
+		}
+	}
+}
+
+// mergeTypes returns the types allowed by both a and b, an empty type means any type is allowed.
+func (f *allOfFlattener) mergeTypes(a, b []string, path string) []string {
+	if len(a) == 0 {
+		return slices.Clone(b)
+	}
+	if len(b) == 0 {
+		return a
+	}
+	var types []string
+	for _, t := range a {
+		switch {
+		case slices.Contains(b, t):
+			types = append(types, t)
+		case t == "integer" && slices.Contains(b, "number"), t == "number" && slices.Contains(b, "integer"):
+			types = append(types, "integer") // every integer is a number.
+		}
+	}
+	types = slices.Compact(types)
+	if len(types) == 0 {
+		f.fail(path, "incompatible types %v and %v", a, b)
+		return a
+	}
+	return types
+}
+
+func (f *allOfFlattener) mergeString(a, b, name, path string) string {
+	if a != "" && b != "" && a != b {
+		f.fail(path, "conflicting '%s' values '%s' and '%s'", name, a, b)
+	}
+	return firstString(a, b)
+}
+
+func (f *allOfFlattener) mergeEqual(a, b *float64, name, path string) *float64 {
+	if a != nil && b != nil && *a != *b {
+		f.fail(path, "conflicting '%s' values %v and %v", name, *a, *b)
+	}
+	return firstValue(a, b)
+}
+
+func (f *allOfFlattener) mergeConst(a, b *yaml.Node, path string) *yaml.Node {
+	if a != nil && b != nil && !nodesEqual(a, b) {
+		f.fail(path, "conflicting 'const' values '%s' and '%s'", a.Value, b.Value)
+	}
+	return firstValue(a, b)
+}
+
+// mergeEnum returns the enum values common to a and b.
+func (f *allOfFlattener) mergeEnum(a, b []*yaml.Node, path string) []*yaml.Node {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	var common []*yaml.Node
+	for _, av := range a {
+		if slices.ContainsFunc(b, func(bv *yaml.Node) bool { return nodesEqual(av, bv) }) {
+			common = append(common, av)
+		}
+	}
+	if len(common) == 0 {
+		f.fail(path, "enums have no values in common")
+		return a
+	}
+	return common
+}
+
+func (f *allOfFlattener) mergeExclusive(a, b *DynamicValue[bool, float64],
+	pick func(x, y *float64) *float64, name, path string,
+) *DynamicValue[bool, float64] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.IsA() != b.IsA() {
+		f.fail(path, "'%s' is a boolean in one member and a number in another", name)
+		return a
+	}
+	if a.IsA() {
+		return &DynamicValue[bool, float64]{A: a.A || b.A}
+	}
+	return &DynamicValue[bool, float64]{N: 1, B: *pick(&a.B, &b.B)}
+}
+
+func (f *allOfFlattener) mergeOnlyOne(a, b []*SchemaProxy, name, path string) []*SchemaProxy {
+	if len(a) > 0 && len(b) > 0 {
+		f.fail(path, "members cannot both define '%s'", name)
+		return a
+	}
+	if len(a) == 0 {
+		return b
+	}
+	return a
+}
+
+// mergeProxy merges two schemas that both apply to the same value, by flattening an allOf of the two.
+func (f *allOfFlattener) mergeProxy(a, b *SchemaProxy, path string) *SchemaProxy {
+	if a == nil || a == b {
+		return firstValue(a, b)
+	}
+	if b == nil {
+		return a
+	}
+	merged := f.flatten(&Schema{AllOf: []*SchemaProxy{a, b}}, path)
+	return CreateSchemaProxy(merged)
+}
+
+func (f *allOfFlattener) mergeProxyMap(a, b *orderedmap.Map[string, *SchemaProxy],
+	path string,
+) *orderedmap.Map[string, *SchemaProxy] {
+	for pair := orderedmap.First(b); pair != nil; pair = pair.Next() {
+		if a == nil {
+			a = orderedmap.New[string, *SchemaProxy]()
+		}
+		existing, _ := a.Get(pair.Key())
+		a.Set(pair.Key(), f.mergeProxy(existing, pair.Value(), joinFlattenPath(path, pair.Key())))
+	}
+	return a
+}
+
+// mergeDynamic merges a keyword that is either a schema or a boolean, a false value always wins.
+func (f *allOfFlattener) mergeDynamic(a, b *DynamicValue[*SchemaProxy, bool], path string) *DynamicValue[*SchemaProxy, bool] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.IsB() && !a.B {
+		return a
+	}
+	if b.IsB() && !b.B {
+		return b
+	}
+	if a.IsB() {
+		return b // true allows anything, so the other value is at least as strict.
+	}
+	if b.IsB() {
+		return a
+	}
+	return &DynamicValue[*SchemaProxy, bool]{A: f.mergeProxy(a.A, b.A, path)}
+}
+
+func checkRange[T int64 | float64](f *allOfFlattener, lower, upper *T, lowerName, upperName, path string) {
+	if lower != nil && upper != nil && *lower > *upper {
+		f.fail(path, "'%s' (%v) is greater than '%s' (%v)", lowerName, *lower, upperName, *upper)
+	}
+}
+
+func smallest[T int64 | float64](a, b *T) *T {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
+
+func largest[T int64 | float64](a, b *T) *T {
+	if a == nil || (b != nil && *b > *a) {
+		return b
+	}
+	return a
+}
+
+// schemaKey returns a key that identifies a Schema when looking for loops. References build a new schema every time
+// they are followed, so the backing node is used (if there is one) rather than the Schema itself.
+func schemaKey(s *Schema) any {
+	if s.low != nil && s.low.RootNode != nil {
+		return s.low.RootNode
+	}
+	return s
+}
+
+func firstString(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
+
+func firstValue[T any](a, b *T) *T {
+	if a != nil {
+		return a
+	}
+	return b
+}
+
+func eitherTrue(a, b *bool) *bool {
+	if a != nil && *a {
+		return a
+	}
+	if b != nil && *b {
+		return b
+	}
+	return firstValue(a, b)
+}
+
+func nodesEqual(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !nodesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func cloneProxyMap(m *orderedmap.Map[string, *SchemaProxy]) *orderedmap.Map[string, *SchemaProxy] {
+	if m == nil {
+		return nil
+	}
+	c := orderedmap.New[string, *SchemaProxy]()
+	for pair := orderedmap.First(m); pair != nil; pair = pair.Next() {
+		c.Set(pair.Key(), pair.Value())
+	}
+	return c
+}
+
+func cloneNodeMap(m *orderedmap.Map[string, *yaml.Node]) *orderedmap.Map[string, *yaml.Node] {
+	if m == nil {
+		return nil
+	}
+	c := orderedmap.New[string, *yaml.Node]()
+	for pair := orderedmap.First(m); pair != nil; pair = pair.Next() {
+		c.Set(pair.Key(), pair.Value())
+	}
+	return c
+}
+
+func joinFlattenPath(path, segment string) string {
+	if path == "" {
+		return segment
+	}
+	return path + "." + segment
+}
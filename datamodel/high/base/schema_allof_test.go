@@ -0,0 +1,174 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/low"
+	lowbase "github.com/pb33f/libopenapi/datamodel/low/base"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// getHighSchemaWithComponents builds a high-level (3.1) schema that can reference '#/components/schemas/...'
+func getHighSchemaWithComponents(t *testing.T, components, yml string) *Schema {
+	return getHighSchemaWithVersion(t, 3.1, components, yml)
+}
+
+func getHighSchemaWithVersion(t *testing.T, version float32, components, yml string) *Schema {
+	var idxNode, schemaNode yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(components), &idxNode))
+	cfg := index.CreateOpenAPIIndexConfig()
+	cfg.SpecInfo = &datamodel.SpecInfo{VersionNumeric: version}
+	idx := index.NewSpecIndexWithConfig(&idxNode, cfg)
+
+	assert.NoError(t, yaml.Unmarshal([]byte(yml), &schemaNode))
+	sp := new(lowbase.SchemaProxy)
+	assert.NoError(t, sp.Build(context.Background(), nil, schemaNode.Content[0], idx))
+	return NewSchemaProxy(&low.NodeReference[*lowbase.SchemaProxy]{
+		Value:     sp,
+		ValueNode: schemaNode.Content[0],
+	}).Schema()
+}
+
+func TestSchema_FlattenAllOf(t *testing.T) {
+	components := `components:
+  schemas:
+    Pet:
+      type: object
+      description: a pet
+      required: [name]
+      properties:
+        name:
+          type: string
+          maxLength: 50
+        age:
+          type: number
+          minimum: 0`
+
+	yml := `title: Dog
+allOf:
+  - $ref: '#/components/schemas/Pet'
+  - type: object
+    required: [name, breed]
+    properties:
+      name:
+        minLength: 1
+        maxLength: 20
+      age:
+        type: integer
+        maximum: 30
+      breed:
+        type: string
+        enum: [pug, beagle, poodle]
+  - allOf:
+      - properties:
+          breed:
+            enum: [pug, beagle]`
+
+	s := getHighSchemaWithComponents(t, components, yml)
+	flat, err := s.FlattenAllOf()
+	assert.NoError(t, err)
+
+	assert.Nil(t, flat.AllOf)
+	assert.Equal(t, "Dog", flat.Title)
+	assert.Equal(t, "a pet", flat.Description)
+	assert.Equal(t, []string{"object"}, flat.Type)
+	assert.Equal(t, []string{"name", "breed"}, flat.Required)
+	assert.Equal(t, 3, flat.Properties.Len())
+
+	name := flat.Properties.GetOrZero("name").Schema()
+	assert.Equal(t, []string{"string"}, name.Type)
+	assert.Equal(t, int64(1), *name.MinLength)
+	assert.Equal(t, int64(20), *name.MaxLength)
+
+	age := flat.Properties.GetOrZero("age").Schema()
+	assert.Equal(t, []string{"integer"}, age.Type)
+	assert.Equal(t, 0.0, *age.Minimum)
+	assert.Equal(t, 30.0, *age.Maximum)
+
+	breed := flat.Properties.GetOrZero("breed").Schema()
+	assert.Len(t, breed.Enum, 2)
+	assert.Equal(t, "pug", breed.Enum[0].Value)
+	assert.Equal(t, "beagle", breed.Enum[1].Value)
+
+	// the original is untouched.
+	assert.Len(t, s.AllOf, 3)
+	assert.Nil(t, s.Properties)
+}
+
+func TestSchema_FlattenAllOf_Conflicts(t *testing.T) {
+	yml := `allOf:
+  - type: string
+    format: date
+    minimum: 10
+  - type: integer
+    format: date-time
+    maximum: 5
+  - enum: [a, b]
+  - enum: [c]`
+
+	flat, err := getHighSchema(t, yml).FlattenAllOf()
+	assert.Nil(t, flat)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "allOf[1]: incompatible types [string] and [integer]")
+	assert.Contains(t, err.Error(), "allOf[1]: conflicting 'format' values 'date' and 'date-time'")
+	assert.Contains(t, err.Error(), "allOf[1]: 'minimum' (10) is greater than 'maximum' (5)")
+	assert.Contains(t, err.Error(), "allOf[3]: enums have no values in common")
+}
+
+func TestSchema_FlattenAllOf_PropertyConflict(t *testing.T) {
+	yml := `allOf:
+  - properties:
+      id:
+        type: string
+  - properties:
+      id:
+        type: boolean`
+
+	_, err := getHighSchema(t, yml).FlattenAllOf()
+	assert.EqualError(t, err, "unable to flatten allOf, allOf[1].properties.id.allOf[1]: "+
+		"incompatible types [string] and [boolean]")
+}
+
+func TestSchema_FlattenAllOf_AdditionalProperties(t *testing.T) {
+	yml := `allOf:
+  - additionalProperties:
+      type: string
+  - additionalProperties: false`
+
+	flat, err := getHighSchema(t, yml).FlattenAllOf()
+	assert.NoError(t, err)
+	assert.True(t, flat.AdditionalProperties.IsB())
+	assert.False(t, flat.AdditionalProperties.B)
+}
+
+func TestSchema_FlattenAllOf_Circular(t *testing.T) {
+	components := `components:
+  schemas:
+    A:
+      allOf:
+        - $ref: '#/components/schemas/B'
+    B:
+      allOf:
+        - $ref: '#/components/schemas/A'`
+
+	yml := `allOf:
+  - $ref: '#/components/schemas/A'`
+
+	s := getHighSchemaWithComponents(t, components, yml)
+	_, err := s.FlattenAllOf()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "allOf is circular")
+}
+
+func TestSchema_FlattenAllOf_NoAllOf(t *testing.T) {
+	flat, err := getHighSchema(t, `type: string`).FlattenAllOf()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"string"}, flat.Type)
+}
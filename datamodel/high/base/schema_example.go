@@ -0,0 +1,326 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// GenerateExample will synthesize a sample value for the Schema, useful for mock servers and documentation.
+// The value is built from nested map[string]any and []any values, so it marshals cleanly to JSON and YAML.
+//
+// A declared 'example' (or the first of 'examples') is used as-is, then 'default', 'const' and the first 'enum'
+// value. Otherwise, a value is generated from the 'type' and 'format', honoring constraints such as 'minimum',
+// 'minLength' and 'minItems'. Every property of an object is generated, unless it is optional and circular. 'allOf'
+// members are merged using FlattenAllOf, for 'oneOf' and 'anyOf' the first branch is used.
+//
+// References are followed, a reference that loops back on itself is skipped if it's not required, otherwise an
+// error is returned. The output is deterministic, the same Schema will always generate the same value.
+func (s *Schema) GenerateExample() (any, error) {
+	g := &exampleGenerator{stack: make(map[any]bool)}
+	return g.generate(s, "")
+}
+
+// AllExamples returns the example values declared by the Schema, whatever the version of the specification: the
+// singular 'example' (OpenAPI 3.0) first, followed by every value of 'examples' (OpenAPI 3.1 / JSON Schema) in
+// order. Values are decoded into Go values (nested map[string]any and []any), values that can't be decoded are
+// skipped. Returns nil if the Schema declares no examples.
+func (s *Schema) AllExamples() []any {
+	if s == nil {
+		return nil
+	}
+	var examples []any
+	for _, node := range append([]*yaml.Node{s.Example}, s.Examples...) {
+		if node == nil {
+			continue
+		}
+		if value, err := decodeExampleNode(node); err == nil {
+			examples = append(examples, value)
+		}
+	}
+	return examples
+}
+
+var errCircularExample = errors.New("circular reference")
+
+type exampleGenerator struct {
+	stack map[any]bool
+}
+
+func (g *exampleGenerator) generate(s *Schema, path string) (any, error) {
+	if s == nil {
+		return nil, nil
+	}
+	key := schemaKey(s)
+	if g.stack[key] {
+		return nil, fmt.Errorf("unable to generate example, %s: %w", examplePath(path), errCircularExample)
+	}
+	g.stack[key] = true
+	defer delete(g.stack, key)
+
+	if s.Example != nil {
+		return decodeExampleNode(s.Example)
+	}
+	if len(s.Examples) > 0 && s.Examples[0] != nil {
+		return decodeExampleNode(s.Examples[0])
+	}
+	if s.Default != nil {
+		return decodeExampleNode(s.Default)
+	}
+	if s.Const != nil {
+		return decodeExampleNode(s.Const)
+	}
+	if len(s.Enum) > 0 && s.Enum[0] != nil {
+		return decodeExampleNode(s.Enum[0])
+	}
+
+	if len(s.AllOf) > 0 {
+		flat, err := s.FlattenAllOf()
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate example, %s: %w", examplePath(path), err)
+		}
+		return g.generate(flat, path)
+	}
+	if len(s.OneOf) > 0 {
+		return g.generateProxy(s.OneOf[0], path+".oneOf[0]")
+	}
+	if len(s.AnyOf) > 0 {
+		return g.generateProxy(s.AnyOf[0], path+".anyOf[0]")
+	}
+
+	types, _ := s.GetEffectiveType()
+	var schemaType string
+	if len(types) > 0 {
+		schemaType = types[0]
+	} else if s.Properties != nil || s.AdditionalProperties != nil {
+		schemaType = "object"
+	} else if s.Items != nil || len(s.PrefixItems) > 0 {
+		schemaType = "array"
+	} else if len(s.Type) > 0 {
+		return nil, nil // the only type is 'null'.
+	}
+
+	switch schemaType {
+	case "object":
+		return g.generateObject(s, path)
+	case "array":
+		return g.generateArray(s, path)
+	case "string":
+		return generateString(s), nil
+	case "integer":
+		return int64(generateNumber(s, true)), nil
+	case "number":
+		return generateNumber(s, false), nil
+	case "boolean":
+		return true, nil
+	}
+	return nil, nil
+}
+
+func (g *exampleGenerator) generateProxy(sp *SchemaProxy, path string) (any, error) {
+	if sp == nil {
+		return nil, nil
+	}
+	s := sp.Schema()
+	if s == nil {
+		err := sp.GetBuildError()
+		if err == nil {
+			err = errors.New("schema is empty")
+		}
+		return nil, fmt.Errorf("unable to generate example, %s: %w", examplePath(path), err)
+	}
+	return g.generate(s, path)
+}
+
+func (g *exampleGenerator) generateObject(s *Schema, path string) (any, error) {
+	obj := make(map[string]any)
+	for pair := orderedmap.First(s.Properties); pair != nil; pair = pair.Next() {
+		value, err := g.generateProxy(pair.Value(), path+"."+pair.Key())
+		if err != nil {
+			if errors.Is(err, errCircularExample) && !slices.Contains(s.Required, pair.Key()) {
+				continue
+			}
+			return nil, err
+		}
+		obj[pair.Key()] = value
+	}
+	// required properties that are not defined still need a value.
+	for _, r := range s.Required {
+		if _, ok := obj[r]; !ok {
+			obj[r] = nil
+			if s.AdditionalProperties != nil && s.AdditionalProperties.IsA() {
+				value, err := g.generateProxy(s.AdditionalProperties.A, path+"."+r)
+				if err != nil {
+					return nil, err
+				}
+				obj[r] = value
+			}
+		}
+	}
+	minProps := 0
+	if s.MinProperties != nil {
+		minProps = int(*s.MinProperties)
+	}
+	for i := 1; len(obj) < minProps; i++ {
+		name := fmt.Sprintf("property%d", i)
+		if _, ok := obj[name]; ok {
+			continue
+		}
+		obj[name] = nil
+		if s.AdditionalProperties != nil && s.AdditionalProperties.IsA() {
+			value, err := g.generateProxy(s.AdditionalProperties.A, path+"."+name)
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = value
+		}
+	}
+	return obj, nil
+}
+
+func (g *exampleGenerator) generateArray(s *Schema, path string) (any, error) {
+	arr := make([]any, 0)
+	for i, sp := range s.PrefixItems {
+		value, err := g.generateProxy(sp, fmt.Sprintf("%s.prefixItems[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, value)
+	}
+	count := 1
+	if s.MinItems != nil {
+		count = int(*s.MinItems)
+	}
+	if s.MaxItems != nil && int(*s.MaxItems) < count {
+		count = int(*s.MaxItems)
+	}
+	if s.Items == nil || !s.Items.IsA() || s.Items.A == nil {
+		return arr, nil
+	}
+	for len(arr) < count {
+		value, err := g.generateProxy(s.Items.A, path+".items")
+		if err != nil {
+			if errors.Is(err, errCircularExample) && (s.MinItems == nil || *s.MinItems == 0) {
+				break
+			}
+			return nil, err
+		}
+		arr = append(arr, value)
+	}
+	return arr, nil
+}
+
+// generateString returns a sample string for the format of the schema, padded or trimmed to fit the length limits.
+func generateString(s *Schema) string {
+	var str string
+	switch s.Format {
+	case "date":
+		str = "2024-01-01"
+	case "date-time":
+		str = "2024-01-01T00:00:00Z"
+	case "time":
+		str = "00:00:00Z"
+	case "email":
+		str = "user@example.com"
+	case "hostname":
+		str = "example.com"
+	case "ipv4":
+		str = "192.168.0.1"
+	case "ipv6":
+		str = "::1"
+	case "uri", "url":
+		str = "https://example.com"
+	case "uri-reference":
+		str = "/example"
+	case "uuid":
+		str = "00000000-0000-0000-0000-000000000000"
+	case "byte":
+		str = base64.StdEncoding.EncodeToString([]byte("example"))
+	case "password":
+		str = "password"
+	default:
+		str = "string"
+	}
+	if s.MinLength != nil && int64(len(str)) < *s.MinLength {
+		str += strings.Repeat("x", int(*s.MinLength)-len(str))
+	}
+	if s.MaxLength != nil && int64(len(str)) > *s.MaxLength {
+		str = str[:*s.MaxLength]
+	}
+	return str
+}
+
+// generateNumber returns zero, or the number closest to zero that satisfies the minimum, maximum and multipleOf
+// of the schema.
+func generateNumber(s *Schema, integer bool) float64 {
+	lower, upper := math.Inf(-1), math.Inf(1)
+	lowerExclusive, upperExclusive := false, false
+	if s.Minimum != nil {
+		lower = *s.Minimum
+		lowerExclusive = s.ExclusiveMinimum != nil && s.ExclusiveMinimum.IsA() && s.ExclusiveMinimum.A
+	}
+	if s.ExclusiveMinimum != nil && s.ExclusiveMinimum.IsB() && s.ExclusiveMinimum.B >= lower {
+		lower, lowerExclusive = s.ExclusiveMinimum.B, true
+	}
+	if s.Maximum != nil {
+		upper = *s.Maximum
+		upperExclusive = s.ExclusiveMaximum != nil && s.ExclusiveMaximum.IsA() && s.ExclusiveMaximum.A
+	}
+	if s.ExclusiveMaximum != nil && s.ExclusiveMaximum.IsB() && s.ExclusiveMaximum.B <= upper {
+		upper, upperExclusive = s.ExclusiveMaximum.B, true
+	}
+
+	step := 1.0
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		step = *s.MultipleOf
+	}
+
+	// start at zero if it's allowed, otherwise the first multiple above the lower limit.
+	value := 0.0
+	if value < lower || (value == lower && lowerExclusive) {
+		value = math.Ceil(lower/step) * step
+		if value == lower && lowerExclusive {
+			value += step
+		}
+	} else if value > upper || (value == upper && upperExclusive) {
+		value = math.Floor(upper/step) * step
+		if value == upper && upperExclusive {
+			value -= step
+		}
+	}
+	if integer {
+		value = math.Ceil(value)
+	}
+	if value < lower || value > upper || (value == lower && lowerExclusive) || (value == upper && upperExclusive) {
+		if !integer && !math.IsInf(lower, 0) && !math.IsInf(upper, 0) {
+			return (lower + upper) / 2 // a narrow range of numbers, the middle is always valid.
+		}
+		return 0
+	}
+	return value
+}
+
+func decodeExampleNode(node *yaml.Node) (any, error) {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, fmt.Errorf("unable to generate example, cannot decode value at line %d, col %d: %w",
+			node.Line, node.Column, err)
+	}
+	return value, nil
+}
+
+func examplePath(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return "$" + path
+}
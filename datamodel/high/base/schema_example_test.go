@@ -0,0 +1,166 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchema_GenerateExample(t *testing.T) {
+	components := `components:
+  schemas:
+    Owner:
+      type: object
+      required: [email]
+      properties:
+        email:
+          type: string
+          format: email
+        pets:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+    Pet:
+      type: object
+      required: [name, owner]
+      properties:
+        name:
+          type: string
+          example: fido
+        owner:
+          $ref: '#/components/schemas/Owner'`
+
+	yml := `type: object
+required: [id, tags]
+properties:
+  id:
+    type: integer
+    minimum: 10
+    multipleOf: 3
+  price:
+    type: number
+    exclusiveMinimum: 0
+    maximum: 0.5
+  status:
+    type: string
+    enum: [available, sold]
+  code:
+    type: string
+    minLength: 8
+  created:
+    type: string
+    format: date-time
+  active:
+    type: boolean
+    default: false
+  tags:
+    type: array
+    minItems: 2
+    items:
+      type: string
+      format: uuid
+  owner:
+    $ref: '#/components/schemas/Owner'
+  choice:
+    oneOf:
+      - type: integer
+      - type: string
+  merged:
+    allOf:
+      - type: object
+        properties:
+          a:
+            type: string
+      - properties:
+          b:
+            type: ["null", "boolean"]`
+
+	s := getHighSchemaWithComponents(t, components, yml)
+	example, err := s.GenerateExample()
+	assert.NoError(t, err)
+
+	obj := example.(map[string]any)
+	assert.Equal(t, int64(12), obj["id"])
+	assert.Equal(t, 0.25, obj["price"])
+	assert.Equal(t, "available", obj["status"])
+	assert.Equal(t, "stringxx", obj["code"])
+	assert.Equal(t, "2024-01-01T00:00:00Z", obj["created"])
+	assert.Equal(t, false, obj["active"])
+	assert.Equal(t, []any{"00000000-0000-0000-0000-000000000000", "00000000-0000-0000-0000-000000000000"}, obj["tags"])
+	assert.Equal(t, int64(0), obj["choice"])
+	assert.Equal(t, map[string]any{"a": "string", "b": true}, obj["merged"])
+
+	// the pets array is optional and circular (pet -> owner -> pet), so it's left empty.
+	assert.Equal(t, map[string]any{"email": "user@example.com", "pets": []any{}}, obj["owner"])
+
+	_, err = json.Marshal(example)
+	assert.NoError(t, err)
+	_, err = yaml.Marshal(example)
+	assert.NoError(t, err)
+}
+
+func TestSchema_GenerateExample_UsesExample(t *testing.T) {
+	yml := `type: object
+example:
+  name: pizza
+  toppings: [cheese]`
+
+	example, err := getHighSchema(t, yml).GenerateExample()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "pizza", "toppings": []any{"cheese"}}, example)
+}
+
+func TestSchema_AllExamples(t *testing.T) {
+	yml := `type: object
+example:
+  name: pizza
+examples:
+  - name: burger
+  - [1, 2]
+  - 3`
+
+	assert.Equal(t, []any{
+		map[string]any{"name": "pizza"},
+		map[string]any{"name": "burger"},
+		[]any{1, 2},
+		3,
+	}, getHighSchema(t, yml).AllExamples())
+
+	assert.Equal(t, []any{"cake"}, getHighSchema(t, "example: cake").AllExamples())
+	assert.Equal(t, []any{"cake", "pie"}, getHighSchema(t, "examples: [cake, pie]").AllExamples())
+	assert.Nil(t, getHighSchema(t, "type: string").AllExamples())
+	assert.Nil(t, (*Schema)(nil).AllExamples())
+}
+
+func TestSchema_GenerateExample_RequiredCircular(t *testing.T) {
+	components := `components:
+  schemas:
+    Node:
+      type: object
+      required: [next]
+      properties:
+        next:
+          $ref: '#/components/schemas/Node'`
+
+	yml := `$ref: '#/components/schemas/Node'`
+
+	// the root reference and 'next' share the cached Node schema, so the cycle is found the first time Node is
+	// visited again, at '$.next'. Without the schema cache, the root is a separate copy of Node and the cycle is
+	// only found one level deeper, at '$.next.next'.
+	_, err := getHighSchemaWithComponents(t, components, yml).GenerateExample()
+	assert.EqualError(t, err, "unable to generate example, $.next: circular reference")
+}
+
+func TestSchema_GenerateExample_AllOfConflict(t *testing.T) {
+	yml := `allOf:
+  - type: string
+  - type: integer`
+
+	_, err := getHighSchema(t, yml).GenerateExample()
+	assert.Error(t, err)
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var (
+	formatValidatorsLock sync.RWMutex
+	formatValidators     = map[string]func(value any) error{
+		"int32":     validateInt32,
+		"int64":     validateInt64,
+		"date":      validateDate,
+		"date-time": validateDateTime,
+		"uuid":      validateUUID,
+		"email":     validateEmail,
+		"ipv4":      validateIPv4,
+		"ipv6":      validateIPv6,
+		"byte":      validateByte,
+	}
+)
+
+// RegisterFormatValidator will register fn as the validator of the 'format' name, replacing any validator already
+// registered for it (including a built-in one). Registering a nil fn removes the validator. Validators are used by
+// ValidateValue and ValidateEnum, formats with no validator are not checked (they are only annotations).
+//
+// The value passed to fn is decoded from YAML, so it's a string, bool, int, float64, []any or map[string]any. A
+// validator should return nil for values of a type the format doesn't apply to. Validators for 'int32', 'int64',
+// 'date', 'date-time', 'uuid', 'email', 'ipv4', 'ipv6' and 'byte' are registered by default.
+func RegisterFormatValidator(name string, fn func(value any) error) {
+	formatValidatorsLock.Lock()
+	defer formatValidatorsLock.Unlock()
+	if fn == nil {
+		delete(formatValidators, name)
+		return
+	}
+	formatValidators[name] = fn
+}
+
+// checkFormat returns the error of the validator registered for format, if value is not valid.
+func checkFormat(format string, value *yaml.Node) error {
+	if format == "" {
+		return nil
+	}
+	formatValidatorsLock.RLock()
+	fn := formatValidators[format]
+	formatValidatorsLock.RUnlock()
+	if fn == nil {
+		return nil
+	}
+	var decoded any
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	return fn(decoded)
+}
+
+// formatNumber returns value as a float64, if it's a number.
+func formatNumber(value any) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func validateInt32(value any) error {
+	n, ok := formatNumber(value)
+	if ok && (n != math.Trunc(n) || n < math.MinInt32 || n > math.MaxInt32) {
+		return errors.New("not a 32 bit integer")
+	}
+	return nil
+}
+
+func validateInt64(value any) error {
+	switch n := value.(type) {
+	case int, int64:
+		return nil
+	case uint64:
+		if n > math.MaxInt64 {
+			return errors.New("not a 64 bit integer")
+		}
+	case float64:
+		// a float with no fraction (e.g. 1.0) is still an integer.
+		if n != math.Trunc(n) || n < math.MinInt64 || n > math.MaxInt64 {
+			return errors.New("not a 64 bit integer")
+		}
+	}
+	return nil
+}
+
+func validateDate(value any) error {
+	if s, ok := value.(string); ok {
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return errors.New("not a valid date")
+		}
+	}
+	return nil
+}
+
+func validateDateTime(value any) error {
+	if s, ok := value.(string); ok {
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return errors.New("not a valid date-time")
+		}
+	}
+	return nil
+}
+
+func validateUUID(value any) error {
+	if s, ok := value.(string); ok && !uuidPattern.MatchString(s) {
+		return errors.New("not a valid uuid")
+	}
+	return nil
+}
+
+func validateEmail(value any) error {
+	if s, ok := value.(string); ok {
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			return errors.New("not a valid email address")
+		}
+	}
+	return nil
+}
+
+func validateIPv4(value any) error {
+	if s, ok := value.(string); ok {
+		if ip := net.ParseIP(s); ip == nil || ip.To4() == nil || strings.Contains(s, ":") {
+			return errors.New("not a valid ipv4 address")
+		}
+	}
+	return nil
+}
+
+func validateIPv6(value any) error {
+	if s, ok := value.(string); ok && (net.ParseIP(s) == nil || !strings.Contains(s, ":")) {
+		return errors.New("not a valid ipv6 address")
+	}
+	return nil
+}
+
+func validateByte(value any) error {
+	if s, ok := value.(string); ok {
+		if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+			return fmt.Errorf("not valid base64: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFormatValidator(t *testing.T) {
+	s := getHighSchema(t, `type: string
+format: pizza`)
+
+	// unknown formats are annotations only.
+	assert.Empty(t, s.ValidateValue("burger"))
+
+	RegisterFormatValidator("pizza", func(value any) error {
+		if value != "pizza" {
+			return errors.New("not a pizza")
+		}
+		return nil
+	})
+	defer RegisterFormatValidator("pizza", nil)
+
+	assert.Empty(t, s.ValidateValue("pizza"))
+	errs := s.ValidateValue("burger")
+	require.Len(t, errs, 1)
+	assert.Equal(t, "$: value does not match the format 'pizza': not a pizza", errs[0].Error())
+}
+
+func TestRegisterFormatValidator_ReplaceBuiltIn(t *testing.T) {
+	s := getHighSchema(t, `type: string
+format: email`)
+	require.Len(t, s.ValidateValue("nope"), 1)
+
+	RegisterFormatValidator("email", nil)
+	defer RegisterFormatValidator("email", validateEmail)
+	assert.Empty(t, s.ValidateValue("nope"))
+}
+
+func TestFormatValidators_BuiltIn(t *testing.T) {
+	tests := []struct {
+		format string
+		valid  []any
+		bad    []any
+	}{
+		{"int32", []any{1, 2.0, "not a number"}, []any{2147483648, 1.5}},
+		{"int64", []any{1, 2.0, int64(-9223372036854775808)}, []any{1.5, uint64(9223372036854775808)}},
+		{"date", []any{"2024-01-31", 12}, []any{"2024-01-32"}},
+		{"date-time", []any{"2024-01-31T12:00:00Z"}, []any{"2024-01-31"}},
+		{"uuid", []any{"123e4567-e89b-12d3-a456-426614174000"}, []any{"123e4567"}},
+		{"email", []any{"pizza@pb33f.io"}, []any{"Pizza <pizza@pb33f.io>", "pizza"}},
+		{"ipv4", []any{"127.0.0.1"}, []any{"::1", "localhost"}},
+		{"ipv6", []any{"::1"}, []any{"127.0.0.1"}},
+		{"byte", []any{"cGl6emE="}, []any{"pizza!"}},
+	}
+	for _, tc := range tests {
+		fn := formatValidators[tc.format]
+		require.NotNil(t, fn, tc.format)
+		for _, v := range tc.valid {
+			assert.NoError(t, fn(v), "%s %v", tc.format, v)
+		}
+		for _, v := range tc.bad {
+			assert.Error(t, fn(v), "%s %v", tc.format, v)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pb33f/libopenapi/json"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultJSONSchemaDialect is the '$schema' declared by ToJSONSchema when no dialect is provided.
+const DefaultJSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ToJSONSchema will render the Schema as a standalone JSON Schema document, declaring dialect as its '$schema'
+// (DefaultJSONSchemaDialect when dialect is empty).
+//
+// Every component schema the Schema references, directly or transitively ('#/components/schemas/X', or
+// '#/definitions/X' for Swagger), is added to the '$defs' of the document, and the references are rewritten to
+// point to them ('#/$defs/X'). An error is returned if the Schema makes a reference that is not to a component
+// schema (for example to another file), or if a referenced schema can't be built.
+//
+// The OpenAPI 3.0 keywords that JSON Schema doesn't have are translated: 'nullable: true' adds "null" to the 'type',
+// 'example' becomes a single item 'examples' array, and a boolean 'exclusiveMinimum' or 'exclusiveMaximum' becomes
+// the numeric bound of the 'minimum' or 'maximum' it applies to.
+func (s *Schema) ToJSONSchema(dialect string) ([]byte, error) {
+	if dialect == "" {
+		dialect = DefaultJSONSchemaDialect
+	}
+
+	// collect every referenced component schema, in the order they are found.
+	defs := orderedmap.New[string, *Schema]()
+	seen := make(map[*Schema]bool)
+	var walk func(sch *Schema) error
+	walk = func(sch *Schema) error {
+		if sch == nil || seen[sch] {
+			return nil
+		}
+		seen[sch] = true
+		for _, sp := range sch.subSchemas() {
+			if !sp.IsReference() {
+				if err := walk(sp.Schema()); err != nil {
+					return err
+				}
+				continue
+			}
+			ref := sp.GetReference()
+			name, ok := componentSchemaName(ref)
+			if !ok {
+				return fmt.Errorf("unable to export schema, reference '%s' is not to a component schema", ref)
+			}
+			if _, found := defs.Get(name); found {
+				continue
+			}
+			def, err := sp.BuildSchema()
+			if err != nil {
+				return fmt.Errorf("unable to export schema, reference '%s' can't be built: %w", ref, err)
+			}
+			if def == nil {
+				return fmt.Errorf("unable to export schema, reference '%s' can't be built", ref)
+			}
+			defs.Set(name, def)
+			if err = walk(def); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(s); err != nil {
+		return nil, err
+	}
+
+	rendered, err := s.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	root := rewriteComponentRefs(rendered.(*yaml.Node))
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("unable to export schema, it does not render as an object")
+	}
+	translateKeywords(root)
+
+	// the dialect comes first, the definitions last.
+	doc := utils.CreateEmptyMapNode()
+	doc.Content = append(doc.Content, utils.CreateStringNode("$schema"), utils.CreateStringNode(dialect))
+	var defsNode *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		switch root.Content[i].Value {
+		case "$schema":
+			continue
+		case "$defs":
+			defsNode = root.Content[i+1]
+		}
+		doc.Content = append(doc.Content, root.Content[i], root.Content[i+1])
+	}
+	if defs.Len() > 0 && defsNode == nil {
+		defsNode = utils.CreateEmptyMapNode()
+		doc.Content = append(doc.Content, utils.CreateStringNode("$defs"), defsNode)
+	}
+	for pair := orderedmap.First(defs); pair != nil; pair = pair.Next() {
+		if _, existing := utils.FindKeyNodeTop(pair.Key(), defsNode.Content); existing != nil {
+			return nil, fmt.Errorf("unable to export schema, '$defs' already contains '%s'", pair.Key())
+		}
+		def, err := pair.Value().MarshalYAML()
+		if err != nil {
+			return nil, err
+		}
+		defNode := rewriteComponentRefs(def.(*yaml.Node))
+		translateKeywords(defNode)
+		defsNode.Content = append(defsNode.Content, utils.CreateStringNode(pair.Key()), defNode)
+	}
+	return json.YAMLNodeToJSONWithOptions(doc, json.Options{Indent: 2})
+}
+
+// componentSchemaName returns the (unescaped) name of the component schema ref points to, if it's a local reference
+// to a component schema.
+func componentSchemaName(ref string) (string, bool) {
+	for _, prefix := range []string{"#/components/schemas/", "#/definitions/"} {
+		if name, ok := strings.CutPrefix(ref, prefix); ok && name != "" && !strings.Contains(name, "/") {
+			return strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~"), true
+		}
+	}
+	return "", false
+}
+
+// rewriteComponentRefs returns a copy of node, with every reference to a component schema rewritten to point to
+// '$defs'. The rendered nodes may belong to the specification, so they are never changed.
+func rewriteComponentRefs(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return rewriteComponentRefs(node.Content[0])
+	}
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		return rewriteComponentRefs(node.Alias)
+	}
+	c := *node
+	c.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		c.Content[i] = rewriteComponentRefs(child)
+		if node.Kind == yaml.MappingNode && i%2 == 1 && node.Content[i-1].Value == "$ref" {
+			if name, ok := componentSchemaName(child.Value); ok {
+				escaped := strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+				c.Content[i].Value = "#/$defs/" + escaped
+			}
+		}
+	}
+	return &c
+}
+
+// jsonSchemaKeywords are the schema keywords that hold a single schema, a list of schemas, or a map of schemas.
+var (
+	jsonSchemaKeywords = map[string]bool{
+		"items": true, "not": true, "additionalProperties": true, "contains": true, "if": true, "then": true,
+		"else": true, "propertyNames": true, "unevaluatedItems": true, "unevaluatedProperties": true,
+	}
+	jsonSchemaListKeywords = map[string]bool{"allOf": true, "oneOf": true, "anyOf": true, "prefixItems": true}
+	jsonSchemaMapKeywords  = map[string]bool{"properties": true, "patternProperties": true, "dependentSchemas": true}
+)
+
+// translateKeywords replaces the OpenAPI 3.0 keywords of a rendered schema (and of every schema it contains) with
+// their JSON Schema equivalents. The node must be a copy, as it is changed.
+func translateKeywords(node *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	translateNullable(node)
+	translateExample(node)
+	translateExclusive(node, "exclusiveMinimum", "minimum")
+	translateExclusive(node, "exclusiveMaximum", "maximum")
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch {
+		case jsonSchemaKeywords[key]:
+			translateKeywords(value)
+		case jsonSchemaListKeywords[key] && value.Kind == yaml.SequenceNode:
+			for _, n := range value.Content {
+				translateKeywords(n)
+			}
+		case jsonSchemaMapKeywords[key] && value.Kind == yaml.MappingNode:
+			for j := 1; j < len(value.Content); j += 2 {
+				translateKeywords(value.Content[j])
+			}
+		}
+	}
+}
+
+// translateNullable removes 'nullable', adding "null" to the 'type' when it is true. A schema without a 'type' is
+// not changed by 'nullable', so it is just removed.
+func translateNullable(node *yaml.Node) {
+	_, nullable := utils.FindKeyNodeTop("nullable", node.Content)
+	if nullable == nil {
+		return
+	}
+	utils.RemoveKeyNodes(node, "nullable")
+	_, types := utils.FindKeyNodeTop("type", node.Content)
+	if nullable.Value != "true" || types == nil {
+		return
+	}
+	null := utils.CreateStringNode("null")
+	switch types.Kind {
+	case yaml.ScalarNode:
+		t := *types
+		types.Kind, types.Tag, types.Value, types.Style = yaml.SequenceNode, "!!seq", "", yaml.FlowStyle
+		types.Content = []*yaml.Node{&t, null}
+	case yaml.SequenceNode:
+		for _, t := range types.Content {
+			if t.Value == "null" {
+				return
+			}
+		}
+		types.Content = append(types.Content, null)
+	}
+}
+
+// translateExample renames 'example' to 'examples', as a single item array. It is removed if the schema already has
+// 'examples'.
+func translateExample(node *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "example" {
+			continue
+		}
+		if _, examples := utils.FindKeyNodeTop("examples", node.Content); examples != nil {
+			utils.RemoveKeyNodes(node, "example")
+			return
+		}
+		node.Content[i].Value = "examples"
+		node.Content[i+1] = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{node.Content[i+1]}}
+		return
+	}
+}
+
+// translateExclusive replaces a boolean exclusive keyword with the value of the bound it applies to, which is removed.
+// A numeric exclusive keyword is left as it is, and a boolean one without a bound to take the value of is removed.
+func translateExclusive(node *yaml.Node, exclusiveKey, boundKey string) {
+	_, exclusive := utils.FindKeyNodeTop(exclusiveKey, node.Content)
+	if exclusive == nil || exclusive.Tag != "!!bool" || ConvertExclusiveBound(node, exclusiveKey, boundKey) {
+		return
+	}
+	utils.RemoveKeyNodes(node, exclusiveKey)
+}
+
+// ConvertExclusiveBound converts the OpenAPI 3.0 (boolean) form of an exclusive keyword in a schema node into its
+// OpenAPI 3.1 and JSON Schema (numeric) form: 'minimum: 5' and 'exclusiveMinimum: true' become 'exclusiveMinimum: 5'.
+// The exclusive keyword takes the value of the bound, which is removed. Returns true if the node was changed, nothing
+// is changed if the exclusive keyword is not 'true', or if the schema has no bound.
+func ConvertExclusiveBound(node *yaml.Node, exclusiveKey, boundKey string) bool {
+	_, exclusive := utils.FindKeyNodeTop(exclusiveKey, node.Content)
+	if exclusive == nil || exclusive.Tag != "!!bool" || exclusive.Value != "true" {
+		return false
+	}
+	_, bound := utils.FindKeyNodeTop(boundKey, node.Content)
+	if bound == nil {
+		return false
+	}
+	*exclusive = *bound
+	utils.RemoveKeyNodes(node, boundKey)
+	return true
+}
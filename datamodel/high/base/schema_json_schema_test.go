@@ -0,0 +1,211 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_ToJSONSchema(t *testing.T) {
+	components := `components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+        weight:
+          type: number
+          maximum: 100.0
+    Owner:
+      type: object
+      properties:
+        pets:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+    Unused:
+      type: string`
+
+	yml := `type: object
+properties:
+  pet:
+    $ref: '#/components/schemas/Pet'`
+
+	s := getHighSchemaWithComponents(t, components, yml)
+	out, err := s.ToJSONSchema("")
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "pet": {
+      "$ref": "#/$defs/Pet"
+    }
+  },
+  "$defs": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "owner": {
+          "$ref": "#/$defs/Owner"
+        },
+        "weight": {
+          "type": "number",
+          "maximum": 100.0
+        }
+      }
+    },
+    "Owner": {
+      "type": "object",
+      "properties": {
+        "pets": {
+          "type": "array",
+          "items": {
+            "$ref": "#/$defs/Pet"
+          }
+        }
+      }
+    }
+  }
+}`, string(out))
+
+	// the references of the specification are not changed.
+	assert.Equal(t, "#/components/schemas/Pet", s.Properties.GetOrZero("pet").GetReference())
+}
+
+func TestSchema_ToJSONSchema_Dialect(t *testing.T) {
+	yml := `$schema: https://example.com/dialect
+type: string`
+
+	s := getHighSchemaWithComponents(t, "components: {}", yml)
+	out, err := s.ToJSONSchema("http://json-schema.org/draft-07/schema#")
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "string"
+}`, string(out))
+}
+
+func TestSchema_ToJSONSchema_NotAComponent(t *testing.T) {
+	components := `components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string`
+
+	yml := `type: object
+properties:
+  name:
+    $ref: '#/components/schemas/Pet/properties/name'`
+
+	s := getHighSchemaWithComponents(t, components, yml)
+	_, err := s.ToJSONSchema("")
+	assert.EqualError(t, err, "unable to export schema, reference "+
+		"'#/components/schemas/Pet/properties/name' is not to a component schema")
+}
+
+func TestSchema_ToJSONSchema_Unresolved(t *testing.T) {
+	components := `components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Missing'`
+
+	yml := `type: object
+properties:
+  pet:
+    $ref: '#/components/schemas/Pet'`
+
+	s := getHighSchemaWithComponents(t, components, yml)
+	_, err := s.ToJSONSchema("")
+	assert.ErrorContains(t, err, "unable to export schema, reference '#/components/schemas/Pet' can't be built")
+}
+
+func TestSchema_ToJSONSchema_OpenAPI30(t *testing.T) {
+	components := `components:
+  schemas:
+    Weight:
+      type: number
+      nullable: true
+      minimum: 0
+      exclusiveMinimum: true
+      maximum: 100
+      exclusiveMaximum: false
+      example: 12.5`
+
+	yml := `type: object
+nullable: false
+properties:
+  weight:
+    $ref: '#/components/schemas/Weight'
+  tags:
+    type: [array]
+    nullable: true
+    items:
+      type: string
+      example: small
+      examples: [large]
+  owner:
+    nullable: true
+    example:
+      nullable: true`
+
+	s := getHighSchemaWithVersion(t, 3.0, components, yml)
+	out, err := s.ToJSONSchema("")
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "weight": {
+      "$ref": "#/$defs/Weight"
+    },
+    "tags": {
+      "type": [
+        "array",
+        "null"
+      ],
+      "items": {
+        "type": "string",
+        "examples": [
+          "large"
+        ]
+      }
+    },
+    "owner": {
+      "examples": [
+        {
+          "nullable": true
+        }
+      ]
+    }
+  },
+  "$defs": {
+    "Weight": {
+      "type": [
+        "number",
+        "null"
+      ],
+      "exclusiveMinimum": 0,
+      "maximum": 100,
+      "examples": [
+        12.5
+      ]
+    }
+  }
+}`, string(out))
+
+	// the schema is not changed.
+	weight, _ := s.Properties.GetOrZero("weight").Schema().Render()
+	assert.Contains(t, string(weight), "nullable: true")
+	assert.Contains(t, string(weight), "exclusiveMinimum: true")
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateNullableUsage will check a Schema for nullability constructs that are not appropriate for the supplied
+// OpenAPI version (e.g. '3.0.3' or '3.1.0').
+//
+//   - OpenAPI 3.0 does not support 'type' arrays (e.g. type: [string, "null"]), or a 'null' type.
+//   - OpenAPI 3.1 does not support 'nullable', it has been replaced by adding 'null' to the 'type' array.
+//
+// Every inline schema contained by the supplied schema (properties, items, polymorphic schemas etc.) is also checked.
+// References are not followed, the schemas they point to are expected to be checked where they are defined
+// (for example when walking the components of a document).
+//
+// If the version is not 3.0 or 3.1, then there are no rules to apply and nothing is returned.
+func ValidateNullableUsage(schema *Schema, version string) []error {
+	is30, is31 := versionIs30(version), versionIs31(version)
+	if schema == nil || (!is30 && !is31) {
+		return nil
+	}
+	var errs []error
+	seen := make(map[*Schema]bool)
+	var check func(s *Schema)
+	check = func(s *Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+
+		if is30 {
+			if s.low != nil && !s.low.Type.IsEmpty() && s.low.Type.Value.IsB() {
+				line, col := nodePosition(s.low.Type.KeyNode)
+				errs = append(errs, fmt.Errorf("type arrays are not supported by OpenAPI %s, "+
+					"use 'nullable: true' to allow null values, line %d, col %d", version, line, col))
+			} else if slices.Contains(s.Type, "null") {
+				var line, col int
+				if s.low != nil {
+					line, col = nodePosition(s.low.Type.KeyNode)
+				}
+				errs = append(errs, fmt.Errorf("the 'null' type is not supported by OpenAPI %s, "+
+					"use 'nullable: true' to allow null values, line %d, col %d", version, line, col))
+			}
+		}
+		if is31 && s.Nullable != nil {
+			var line, col int
+			if s.low != nil {
+				line, col = nodePosition(s.low.Nullable.KeyNode)
+			}
+			errs = append(errs, fmt.Errorf("'nullable' is not supported by OpenAPI %s, "+
+				"add 'null' to the type array to allow null values, line %d, col %d", version, line, col))
+		}
+
+		for _, sp := range s.subSchemas() {
+			if sp.IsReference() {
+				continue
+			}
+			check(sp.Schema())
+		}
+	}
+	check(schema)
+	return errs
+}
+
+// GetEffectiveType returns the types of the Schema with any "null" type removed, and a flag indicating if the
+// Schema allows null values. A Schema allows null if the 3.1 'type' array contains "null" or if the 3.0
+// 'nullable' property is true. This means callers don't need to care which version of OpenAPI defined the schema.
+func (s *Schema) GetEffectiveType() ([]string, bool) {
+	var types []string
+	nullable := s.Nullable != nil && *s.Nullable
+	for _, t := range s.Type {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		types = append(types, t)
+	}
+	return types, nullable
+}
+
+// IsNullable returns true if the Schema accepts null values under the supplied OpenAPI version (e.g. '3.0.3' or
+// '3.1.0'), following the nullability rules of that version:
+//
+//   - OpenAPI 3.0: 'nullable: true' is set.
+//   - OpenAPI 3.1: the 'type' array contains "null", or 'const' is null, or 'enum' contains null.
+//
+// For any other version, the rules of both are accepted. A Schema that has none of these is still nullable if it
+// has no 'type' of its own (or its 'enum' doesn't rule null out) and any of its 'oneOf' or 'anyOf' schemas are
+// nullable, or all of its 'allOf' schemas are, for example 'oneOf: [{type: "null"}, {$ref: ...}]'. References are
+// followed, circular references are not considered nullable.
+func (s *Schema) IsNullable(version string) bool {
+	return s.isNullable(versionIs30(version), versionIs31(version), make(map[string]bool))
+}
+
+func (s *Schema) isNullable(is30, is31 bool, refs map[string]bool) bool {
+	if s == nil {
+		return false
+	}
+	if !is31 && s.Nullable != nil && *s.Nullable {
+		return true
+	}
+	if !is30 {
+		if slices.Contains(s.Type, "null") || isNullNode(s.Const) || slices.ContainsFunc(s.Enum, isNullNode) {
+			return true
+		}
+		if len(s.Enum) > 0 {
+			return false // the enum does not contain null.
+		}
+	}
+	if len(s.Type) > 0 {
+		return false // the type does not allow null.
+	}
+
+	nullable := func(sp *SchemaProxy) bool {
+		if sp == nil {
+			return false
+		}
+		if sp.IsReference() {
+			ref := sp.GetReference()
+			if refs[ref] {
+				return false // circular.
+			}
+			refs[ref] = true
+			defer delete(refs, ref)
+		}
+		return sp.Schema().isNullable(is30, is31, refs)
+	}
+	if slices.ContainsFunc(s.OneOf, nullable) || slices.ContainsFunc(s.AnyOf, nullable) {
+		return true
+	}
+	if len(s.AllOf) > 0 {
+		return !slices.ContainsFunc(s.AllOf, func(sp *SchemaProxy) bool { return !nullable(sp) })
+	}
+	return false
+}
+
+// isNullNode returns true if the node is a YAML/JSON null value.
+func isNullNode(n *yaml.Node) bool {
+	return n != nil && n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
+
+// versionIs30 returns true if the supplied version string is an OpenAPI 3.0.x version.
+func versionIs30(version string) bool {
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	return v == "3.0" || strings.HasPrefix(v, "3.0.")
+}
+
+// versionIs31 returns true if the supplied version string is an OpenAPI 3.1.x version.
+func versionIs31(version string) bool {
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	return v == "3.1" || strings.HasPrefix(v, "3.1.")
+}
+
+// nodePosition returns the line and column of a node, or zeros if the node is nil.
+func nodePosition(node *yaml.Node) (int, int) {
+	if node == nil {
+		return 0, 0
+	}
+	return node.Line, node.Column
+}
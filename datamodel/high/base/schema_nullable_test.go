@@ -0,0 +1,132 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNullableUsage_30_TypeArray(t *testing.T) {
+	yml := `type: object
+properties:
+  name:
+    type:
+      - string
+      - "null"
+  age:
+    type: integer
+    nullable: true`
+
+	errs := ValidateNullableUsage(getHighSchema(t, yml), "3.0.3")
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "type arrays are not supported by OpenAPI 3.0.3, use 'nullable: true' "+
+		"to allow null values, line 4, col 5", errs[0].Error())
+}
+
+func TestValidateNullableUsage_30_NullType(t *testing.T) {
+	yml := `type: "null"`
+
+	errs := ValidateNullableUsage(getHighSchema(t, yml), "3.0.0")
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "the 'null' type is not supported by OpenAPI 3.0.0, use 'nullable: true' "+
+		"to allow null values, line 1, col 1", errs[0].Error())
+}
+
+func TestValidateNullableUsage_31_Nullable(t *testing.T) {
+	yml := `type: array
+items:
+  type: string
+  nullable: true
+oneOf:
+  - type: [string, "null"]`
+
+	errs := ValidateNullableUsage(getHighSchema(t, yml), "3.1.0")
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "'nullable' is not supported by OpenAPI 3.1.0, add 'null' to the type array "+
+		"to allow null values, line 4, col 3", errs[0].Error())
+}
+
+func TestValidateNullableUsage_Valid(t *testing.T) {
+	assert.Empty(t, ValidateNullableUsage(getHighSchema(t, `type: string
+nullable: true`), "3.0.1"))
+	assert.Empty(t, ValidateNullableUsage(getHighSchema(t, `type: [string, "null"]`), "3.1.0"))
+}
+
+func TestValidateNullableUsage_UnknownVersion(t *testing.T) {
+	assert.Nil(t, ValidateNullableUsage(getHighSchema(t, `type: [string, "null"]`), "2.0"))
+	assert.Nil(t, ValidateNullableUsage(nil, "3.1.0"))
+}
+
+func TestSchema_GetEffectiveType(t *testing.T) {
+	tests := []struct {
+		yml      string
+		types    []string
+		nullable bool
+	}{
+		{`type: string`, []string{"string"}, false},
+		{"type: string\nnullable: true", []string{"string"}, true},
+		{"type: string\nnullable: false", []string{"string"}, false},
+		{`type: [string, "null"]`, []string{"string"}, true},
+		{`type: [integer, number]`, []string{"integer", "number"}, false},
+		{`type: "null"`, nil, true},
+		{`nullable: true`, nil, true},
+		{`description: anything`, nil, false},
+	}
+	for _, tc := range tests {
+		types, nullable := getHighSchema(t, tc.yml).GetEffectiveType()
+		assert.Equal(t, tc.types, types, tc.yml)
+		assert.Equal(t, tc.nullable, nullable, tc.yml)
+	}
+}
+
+func TestSchema_IsNullable(t *testing.T) {
+	tests := []struct {
+		yml    string
+		is30   bool
+		is31   bool
+		either bool
+	}{
+		{`type: string`, false, false, false},
+		{"type: string\nnullable: true", true, false, true},
+		{`type: [string, "null"]`, false, true, true},
+		{`type: "null"`, false, true, true},
+		{`const: null`, false, true, true},
+		{`enum: [a, null]`, false, true, true},
+		{`enum: [a, b]`, false, false, false},
+		{"oneOf:\n  - type: \"null\"\n  - type: string", false, true, true},
+		{"anyOf:\n  - type: string\n  - type: string\n    nullable: true", true, false, true},
+		{"type: object\noneOf:\n  - type: \"null\"", false, false, false},
+		{"allOf:\n  - type: [string, \"null\"]\n  - enum: [a, null]", false, true, true},
+		{"allOf:\n  - type: [string, \"null\"]\n  - type: string", false, false, false},
+		{`description: anything`, false, false, false},
+	}
+	for _, tc := range tests {
+		s := getHighSchema(t, tc.yml)
+		assert.Equal(t, tc.is30, s.IsNullable("3.0.3"), "3.0: "+tc.yml)
+		assert.Equal(t, tc.is31, s.IsNullable("3.1.0"), "3.1: "+tc.yml)
+		assert.Equal(t, tc.either, s.IsNullable(""), "any: "+tc.yml)
+	}
+	assert.False(t, (*Schema)(nil).IsNullable("3.1.0"))
+}
+
+func TestSchema_IsNullable_References(t *testing.T) {
+	components := `components:
+  schemas:
+    Null:
+      type: "null"
+    Loop:
+      oneOf:
+        - $ref: '#/components/schemas/Loop'`
+
+	s := getHighSchemaWithComponents(t, components, `oneOf:
+  - $ref: '#/components/schemas/Null'
+  - type: string`)
+	assert.True(t, s.IsNullable("3.1.0"))
+
+	s = getHighSchemaWithComponents(t, components, `anyOf:
+  - $ref: '#/components/schemas/Loop'`)
+	assert.False(t, s.IsNullable("3.1.0"))
+}
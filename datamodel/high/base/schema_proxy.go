@@ -4,6 +4,9 @@
 package base
 
 import (
+	"context"
+	"path"
+	"strings"
 	"sync"
 
 	"github.com/pb33f/libopenapi/datamodel/high"
@@ -75,6 +78,10 @@ func CreateSchemaProxyRef(ref string) *SchemaProxy {
 // Schema will create a new Schema instance using NewSchema from the low-level SchemaProxy backing this high-level one.
 // If there is a problem building the Schema, then this method will return nil. Use GetBuildError to gain access
 // to that building error.
+//
+// Every SchemaProxy that references the same schema returns the same *Schema, unless the schema cache has been
+// disabled (see datamodel.DocumentConfiguration.DisableSchemaCache). The ParentProxy of a shared Schema is the
+// SchemaProxy that built it first.
 func (sp *SchemaProxy) Schema() *Schema {
 	if sp == nil || sp.lock == nil {
 		return nil
@@ -88,8 +95,24 @@ func (sp *SchemaProxy) Schema() *Schema {
 			sp.lock.Unlock()
 			return nil
 		}
+		// references to the same schema share the low-level schema, so share the high-level one too.
+		var cache *sync.Map
+		if sp.schema.Value.IsReference() {
+			cache = sp.schema.Value.GetIndex().GetHighSchemaCache()
+		}
+		if cache != nil {
+			if cached, ok := cache.Load(s); ok {
+				sp.rendered = cached.(*Schema)
+				sp.lock.Unlock()
+				return sp.rendered
+			}
+		}
 		sch := NewSchema(s)
 		sch.ParentProxy = sp
+		if cache != nil {
+			actual, _ := cache.LoadOrStore(s, sch)
+			sch = actual.(*Schema)
+		}
 
 		sp.rendered = sch
 		sp.lock.Unlock()
@@ -117,12 +140,36 @@ func (sp *SchemaProxy) IsReference() bool {
 
 // GetReference returns the location of the $ref if this SchemaProxy is a reference to another Schema.
 func (sp *SchemaProxy) GetReference() string {
+	if sp == nil {
+		return ""
+	}
 	if sp.refStr != "" {
 		return sp.refStr
 	}
+	if sp.schema == nil || sp.schema.Value == nil {
+		return ""
+	}
 	return sp.schema.GetValue().GetReference()
 }
 
+// ReferenceName returns the name of the Schema this SchemaProxy references, which is the last segment of the $ref
+// (for example 'Pet' for '#/components/schemas/Pet'). If the $ref points to a whole file, the name of the file
+// without its extension is returned (for example 'Pet' for './models/Pet.yaml'). The Schema is not built. Returns an
+// empty string if the SchemaProxy is not a reference.
+func (sp *SchemaProxy) ReferenceName() string {
+	ref := sp.GetReference()
+	if ref == "" {
+		return ""
+	}
+	file, fragment, found := strings.Cut(ref, "#")
+	if !found || strings.Trim(fragment, "/") == "" {
+		name := path.Base(file)
+		return strings.TrimSuffix(name, path.Ext(name))
+	}
+	name := fragment[strings.LastIndex(fragment, "/")+1:]
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+}
+
 func (sp *SchemaProxy) GetSchemaKeyNode() *yaml.Node {
 	if sp.schema != nil {
 		return sp.GoLow().GetKeyNode()
@@ -148,16 +195,83 @@ func (sp *SchemaProxy) GetReferenceOrigin() *index.NodeOrigin {
 
 // BuildSchema operates the same way as Schema, except it will return any error along with the *Schema
 func (sp *SchemaProxy) BuildSchema() (*Schema, error) {
-	if sp.rendered != nil {
-		return sp.rendered, sp.buildError
-	}
 	schema := sp.Schema()
-	er := sp.buildError
+	er := sp.GetBuildError()
 	return schema, er
 }
 
+// SchemaResult is the result of an asynchronous schema build, created by BuildSchemaAsync.
+type SchemaResult struct {
+	// Proxy is the SchemaProxy that was built.
+	Proxy *SchemaProxy
+
+	// Schema is the built Schema, it will be nil if the build failed.
+	Schema *Schema
+
+	// Error is the build error, or the context error if the build was cancelled.
+	Error error
+}
+
+// BuildSchemaAsync operates the same way as BuildSchema, except the schema is built on a new goroutine and
+// the result is delivered on the returned channel. The channel receives a single SchemaResult, and is then closed.
+//
+// If the context is cancelled before the build starts, the result will contain the context error.
+func (sp *SchemaProxy) BuildSchemaAsync(ctx context.Context) <-chan SchemaResult {
+	results := make(chan SchemaResult, 1)
+	go func() {
+		defer close(results)
+		if err := ctx.Err(); err != nil {
+			results <- SchemaResult{Proxy: sp, Error: err}
+			return
+		}
+		schema, err := sp.BuildSchema()
+		results <- SchemaResult{Proxy: sp, Schema: schema, Error: err}
+	}()
+	return results
+}
+
+// BuildSchemaWithChildrenAsync builds the schema on a new goroutine (the same as BuildSchemaAsync), and once
+// built, every immediate child schema (properties, items, allOf etc.) is then built concurrently.
+//
+// The result for this SchemaProxy is always delivered first, followed by results for each child in the order
+// they complete. Children are only built if this schema builds successfully. The channel is closed once
+// every build has completed, or the context is cancelled.
+func (sp *SchemaProxy) BuildSchemaWithChildrenAsync(ctx context.Context) <-chan SchemaResult {
+	results := make(chan SchemaResult)
+	send := func(r SchemaResult) bool {
+		select {
+		case results <- r:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	go func() {
+		defer close(results)
+		r := <-sp.BuildSchemaAsync(ctx)
+		if !send(r) || r.Schema == nil {
+			return
+		}
+		var wg sync.WaitGroup
+		for _, child := range r.Schema.subSchemas() {
+			wg.Add(1)
+			go func(child *SchemaProxy) {
+				defer wg.Done()
+				send(<-child.BuildSchemaAsync(ctx))
+			}(child)
+		}
+		wg.Wait()
+	}()
+	return results
+}
+
 // GetBuildError returns any error that was thrown when calling Schema()
 func (sp *SchemaProxy) GetBuildError() error {
+	if sp == nil || sp.lock == nil {
+		return nil
+	}
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
 	return sp.buildError
 }
 
@@ -99,6 +99,19 @@ func TestSchemaProxy_IsReference_Nil(t *testing.T) {
 	assert.False(t, sp.IsReference())
 }
 
+func TestSchemaProxy_ReferenceName(t *testing.T) {
+	assert.Equal(t, "Pet", CreateSchemaProxyRef("#/components/schemas/Pet").ReferenceName())
+	assert.Equal(t, "Pet", CreateSchemaProxyRef("models.yaml#/components/schemas/Pet").ReferenceName())
+	assert.Equal(t, "a/b", CreateSchemaProxyRef("#/components/schemas/a~1b").ReferenceName())
+	assert.Equal(t, "Pet", CreateSchemaProxyRef("./models/Pet.yaml").ReferenceName())
+	assert.Equal(t, "Pet", CreateSchemaProxyRef("https://pb33f.io/models/Pet.json#").ReferenceName())
+
+	var sp *SchemaProxy
+	assert.Equal(t, "", sp.GetReference())
+	assert.Equal(t, "", sp.ReferenceName())
+	assert.Equal(t, "", (&SchemaProxy{}).ReferenceName())
+}
+
 func TestSchemaProxy_NoSchema_GetOrigin(t *testing.T) {
 	sp := &SchemaProxy{}
 	assert.Nil(t, sp.GetReferenceOrigin())
@@ -174,3 +187,83 @@ components:
 	rend, _ := sp.MarshalYAMLInline()
 	assert.NotNil(t, rend)
 }
+
+func buildSchemaProxyAsyncTest(t *testing.T, yml string) *SchemaProxy {
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(yml), &node))
+	idx := index.NewSpecIndexWithConfig(&node, index.CreateOpenAPIIndexConfig())
+	lowProxy := new(lowbase.SchemaProxy)
+	require.NoError(t, lowProxy.Build(context.Background(), nil, node.Content[0], idx))
+	return NewSchemaProxy(&low.NodeReference[*lowbase.SchemaProxy]{Value: lowProxy})
+}
+
+func TestSchemaProxy_BuildSchemaAsync(t *testing.T) {
+	sp := buildSchemaProxyAsyncTest(t, `type: object
+description: pizza`)
+
+	// build the same proxy from many goroutines, they must all get the same schema.
+	var results []<-chan SchemaResult
+	for i := 0; i < 20; i++ {
+		results = append(results, sp.BuildSchemaAsync(context.Background()))
+	}
+	first := <-results[0]
+	assert.NoError(t, first.Error)
+	assert.Equal(t, "pizza", first.Schema.Description)
+	assert.Equal(t, sp, first.Proxy)
+	for _, r := range results[1:] {
+		res := <-r
+		assert.Same(t, first.Schema, res.Schema)
+	}
+	_, open := <-results[0]
+	assert.False(t, open)
+}
+
+func TestSchemaProxy_BuildSchemaAsync_Cancelled(t *testing.T) {
+	sp := buildSchemaProxyAsyncTest(t, `type: string`)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res := <-sp.BuildSchemaAsync(ctx)
+	assert.ErrorIs(t, res.Error, context.Canceled)
+	assert.Nil(t, res.Schema)
+}
+
+func TestSchemaProxy_BuildSchemaAsync_Error(t *testing.T) {
+	sp := buildSchemaProxyAsyncTest(t, `type: object
+properties:
+  bad:
+    $ref: '#/nowhere'`)
+
+	res := <-sp.BuildSchemaAsync(context.Background())
+	assert.Error(t, res.Error)
+	assert.Nil(t, res.Schema)
+}
+
+func TestSchemaProxy_BuildSchemaWithChildrenAsync(t *testing.T) {
+	sp := buildSchemaProxyAsyncTest(t, `type: object
+properties:
+  name:
+    type: string
+  tags:
+    type: array
+    items:
+      type: string
+allOf:
+  - description: one
+  - description: two`)
+
+	var descriptions []string
+	var count int
+	for res := range sp.BuildSchemaWithChildrenAsync(context.Background()) {
+		require.NoError(t, res.Error)
+		if count == 0 {
+			assert.Equal(t, sp, res.Proxy)
+		}
+		if res.Schema.Description != "" {
+			descriptions = append(descriptions, res.Schema.Description)
+		}
+		count++
+	}
+	assert.Equal(t, 5, count)
+	assert.ElementsMatch(t, []string{"one", "two"}, descriptions)
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+// ReferenceInventory will return every distinct '$ref' reachable from the Schema, in the order they are first found.
+// Properties, items, composition keywords ('allOf', 'oneOf', 'anyOf', 'not'), 'additionalProperties' and every other
+// keyword holding a schema are walked. Referenced schemas are resolved and walked as well, so references they make
+// are included. Each schema is only visited once, so circular references are safe.
+func (s *Schema) ReferenceInventory() []string {
+	return s.collectReferences(true)
+}
+
+// DirectReferences will return every distinct '$ref' made by the Schema and its inline schemas, in the order they
+// are first found. Unlike ReferenceInventory, referenced schemas are not walked.
+func (s *Schema) DirectReferences() []string {
+	return s.collectReferences(false)
+}
+
+// ContainsRef will return true if the Schema reaches the reference ref, directly or transitively. See
+// ReferenceInventory for the keywords that are walked.
+func (s *Schema) ContainsRef(ref string) bool {
+	for _, r := range s.ReferenceInventory() {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// collectReferences returns the unique references found walking the Schema, following them when follow is true.
+func (s *Schema) collectReferences(follow bool) []string {
+	var refs []string
+	seenRefs := make(map[string]bool)
+	seen := make(map[*Schema]bool)
+
+	var walk func(sch *Schema)
+	walk = func(sch *Schema) {
+		if sch == nil || seen[sch] {
+			return
+		}
+		seen[sch] = true
+		for _, sp := range sch.subSchemas() {
+			if sp.IsReference() {
+				ref := sp.GetReference()
+				found := seenRefs[ref]
+				if !found {
+					seenRefs[ref] = true
+					refs = append(refs, ref)
+				}
+				if found || !follow {
+					continue
+				}
+			}
+			walk(sp.Schema())
+		}
+	}
+	walk(s)
+	return refs
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_ReferenceInventory(t *testing.T) {
+	components := `components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+        tags:
+          type: array
+          items:
+            $ref: '#/components/schemas/Tag'
+    Owner:
+      type: object
+      additionalProperties:
+        $ref: '#/components/schemas/Tag'
+    Tag:
+      type: string
+    Error:
+      type: object`
+
+	yml := `type: object
+properties:
+  pet:
+    $ref: '#/components/schemas/Pet'
+oneOf:
+  - $ref: '#/components/schemas/Error'
+  - type: object
+    not:
+      $ref: '#/components/schemas/Tag'`
+
+	s := getHighSchemaWithComponents(t, components, yml)
+	assert.Equal(t, []string{
+		"#/components/schemas/Error",
+		"#/components/schemas/Tag",
+		"#/components/schemas/Pet",
+		"#/components/schemas/Owner",
+	}, s.ReferenceInventory())
+	assert.True(t, s.ContainsRef("#/components/schemas/Owner"))
+	assert.False(t, s.ContainsRef("#/components/schemas/Nope"))
+}
+
+func TestSchema_ReferenceInventory_Circular(t *testing.T) {
+	components := `components:
+  schemas:
+    A:
+      properties:
+        b:
+          $ref: '#/components/schemas/B'
+    B:
+      items:
+        $ref: '#/components/schemas/A'`
+
+	yml := `allOf:
+  - $ref: '#/components/schemas/A'`
+
+	s := getHighSchemaWithComponents(t, components, yml)
+	assert.Equal(t, []string{"#/components/schemas/A", "#/components/schemas/B"}, s.ReferenceInventory())
+}
+
+func TestSchema_ReferenceInventory_None(t *testing.T) {
+	s := getHighSchema(t, `type: object
+properties:
+  name:
+    type: string`)
+	assert.Empty(t, s.ReferenceInventory())
+	assert.False(t, s.ContainsRef("#/components/schemas/Pet"))
+}
+
+func TestSchema_DirectReferences(t *testing.T) {
+	components := `components:
+  schemas:
+    Pet:
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object`
+
+	yml := `properties:
+  pet:
+    $ref: '#/components/schemas/Pet'
+  pets:
+    type: array
+    items:
+      $ref: '#/components/schemas/Pet'`
+
+	s := getHighSchemaWithComponents(t, components, yml)
+	assert.Equal(t, []string{"#/components/schemas/Pet"}, s.DirectReferences())
+	assert.Equal(t, []string{"#/components/schemas/Pet", "#/components/schemas/Owner"}, s.ReferenceInventory())
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// RequiredProperties will return the properties of the Schema that are required, with their schemas. Properties and
+// 'required' lists contributed by 'allOf' schemas (including referenced ones, and their own 'allOf' schemas) are
+// included, so a property required by one member and defined by another is returned.
+//
+// If a property is defined more than once, the first definition found is used (the Schema itself, then the 'allOf'
+// schemas in order). Required properties that are not defined anywhere are not returned, use
+// MissingRequiredProperties to find them.
+func (s *Schema) RequiredProperties() map[string]*SchemaProxy {
+	required, properties := s.collectRequired()
+	result := make(map[string]*SchemaProxy)
+	for _, name := range required {
+		if sp, ok := properties.Get(name); ok {
+			result[name] = sp
+		}
+	}
+	return result
+}
+
+// MissingRequiredProperties will return the names listed as 'required' by the Schema (or its 'allOf' schemas) that
+// are not defined as a property by any of them, in the order they are listed. An empty slice means every required
+// property is defined.
+func (s *Schema) MissingRequiredProperties() []string {
+	required, properties := s.collectRequired()
+	var missing []string
+	for _, name := range required {
+		if _, ok := properties.Get(name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// collectRequired returns the unique names that are required by the Schema and its 'allOf' schemas, and every
+// property they define.
+func (s *Schema) collectRequired() ([]string, *orderedmap.Map[string, *SchemaProxy]) {
+	var required []string
+	properties := orderedmap.New[string, *SchemaProxy]()
+	seenRequired := make(map[string]bool)
+	seen := make(map[*Schema]bool)
+
+	var walk func(sch *Schema)
+	walk = func(sch *Schema) {
+		if sch == nil || seen[sch] {
+			return
+		}
+		seen[sch] = true
+		for _, name := range sch.Required {
+			if !seenRequired[name] {
+				seenRequired[name] = true
+				required = append(required, name)
+			}
+		}
+		for pair := orderedmap.First(sch.Properties); pair != nil; pair = pair.Next() {
+			if _, ok := properties.Get(pair.Key()); !ok {
+				properties.Set(pair.Key(), pair.Value())
+			}
+		}
+		for _, sp := range sch.AllOf {
+			if sp != nil {
+				walk(sp.Schema())
+			}
+		}
+	}
+	walk(s)
+	return required, properties
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_RequiredProperties(t *testing.T) {
+	yml := `type: object
+required: [name, colour]
+properties:
+  name:
+    type: string
+  age:
+    type: integer
+allOf:
+  - required: [size]
+    properties:
+      colour:
+        type: string
+  - properties:
+      size:
+        type: integer
+      name:
+        type: integer
+    allOf:
+      - required: [age, weight]`
+
+	s := getHighSchema(t, yml)
+	required := s.RequiredProperties()
+	assert.Len(t, required, 4)
+	assert.Equal(t, []string{"string"}, required["name"].Schema().Type) // the first definition wins.
+	assert.Equal(t, []string{"string"}, required["colour"].Schema().Type)
+	assert.Equal(t, []string{"integer"}, required["size"].Schema().Type)
+	assert.Equal(t, []string{"integer"}, required["age"].Schema().Type)
+	assert.Equal(t, []string{"weight"}, s.MissingRequiredProperties())
+}
+
+func TestSchema_RequiredProperties_None(t *testing.T) {
+	s := getHighSchema(t, `type: object
+properties:
+  name:
+    type: string`)
+	assert.Empty(t, s.RequiredProperties())
+	assert.Empty(t, s.MissingRequiredProperties())
+}
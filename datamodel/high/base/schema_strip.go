@@ -0,0 +1,77 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high"
+	"github.com/pb33f/libopenapi/datamodel/low/base"
+	"github.com/pb33f/libopenapi/utils"
+)
+
+// StripExamples will remove 'example' and 'examples' from the Schema, and every inline schema it contains
+// (properties, items, polymorphic schemas etc.). The values are removed from both the high-level Schema and the
+// low-level yaml nodes, so they are not rendered. Referenced schemas are not changed.
+func (s *Schema) StripExamples() {
+	s.walkInline(func(sch *Schema) {
+		sch.Example = nil
+		sch.Examples = nil
+		if sch.low != nil {
+			utils.RemoveKeyNodes(sch.low.RootNode, base.ExampleLabel, base.ExamplesLabel)
+		}
+	})
+}
+
+// StripDescriptions will remove 'description' from the Schema (and its 'externalDocs'), and every inline schema
+// it contains. The values are removed from both the high-level Schema and the low-level yaml nodes, so they are not
+// rendered. Referenced schemas are not changed.
+func (s *Schema) StripDescriptions() {
+	s.walkInline(func(sch *Schema) {
+		sch.Description = ""
+		if sch.low != nil {
+			utils.RemoveKeyNodes(sch.low.RootNode, base.DescriptionLabel)
+		}
+		if sch.ExternalDocs != nil {
+			sch.ExternalDocs.Description = ""
+			if sch.ExternalDocs.low != nil {
+				utils.RemoveKeyNodes(sch.ExternalDocs.low.RootNode, base.DescriptionLabel)
+			}
+		}
+	})
+}
+
+// StripExtensions will remove the extensions that start with any of the prefixes (or every extension if no
+// prefixes are provided) from the Schema, and every inline schema it contains (including 'xml' and 'externalDocs').
+// Returns the number of extensions removed. Referenced schemas are not changed.
+func (s *Schema) StripExtensions(prefixes ...string) int {
+	removed := 0
+	s.walkInline(func(sch *Schema) {
+		removed += high.RemoveExtensions(sch, prefixes...)
+		if sch.XML != nil {
+			removed += high.RemoveExtensions(sch.XML, prefixes...)
+		}
+		if sch.ExternalDocs != nil {
+			removed += high.RemoveExtensions(sch.ExternalDocs, prefixes...)
+		}
+	})
+	return removed
+}
+
+// walkInline calls visit for the Schema, and every inline schema it contains.
+func (s *Schema) walkInline(visit func(sch *Schema)) {
+	seen := make(map[*Schema]bool)
+	var walk func(sch *Schema)
+	walk = func(sch *Schema) {
+		if sch == nil || seen[sch] {
+			return
+		}
+		seen[sch] = true
+		visit(sch)
+		for _, sp := range sch.subSchemas() {
+			if !sp.IsReference() {
+				walk(sp.Schema())
+			}
+		}
+	}
+	walk(s)
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_StripExamples(t *testing.T) {
+	yml := `type: object
+example:
+  name: fido
+properties:
+  name:
+    type: string
+    examples: [fido, rex]
+  tags:
+    type: array
+    items:
+      type: string
+      example: friendly`
+
+	s := getHighSchema(t, yml)
+	s.StripExamples()
+	assert.Nil(t, s.Example)
+	assert.Nil(t, s.Properties.GetOrZero("name").Schema().Examples)
+	assert.Nil(t, s.Properties.GetOrZero("tags").Schema().Items.A.Schema().Example)
+
+	rend, err := s.Render()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(rend), "fido")
+	assert.NotContains(t, string(rend), "friendly")
+}
+
+func TestSchema_StripDescriptions(t *testing.T) {
+	yml := `type: object
+description: a pet
+externalDocs:
+  url: https://pb33f.io
+  description: more about pets
+properties:
+  name:
+    type: string
+    description: the name of the pet`
+
+	s := getHighSchema(t, yml)
+	s.StripDescriptions()
+	assert.Empty(t, s.Description)
+	assert.Empty(t, s.ExternalDocs.Description)
+	assert.Empty(t, s.Properties.GetOrZero("name").Schema().Description)
+
+	rend, err := s.Render()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(rend), "description")
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/pb33f/libopenapi/datamodel/low"
 	lowbase "github.com/pb33f/libopenapi/datamodel/low/base"
 	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/pb33f/libopenapi/utils"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
@@ -1372,3 +1373,87 @@ func TestSchema_RenderProxyWithConfig_Corrected_3(t *testing.T) {
 	schemaBytes, _ = compiled.RenderInline()
 	assert.Equal(t, testSpecCorrect, strings.TrimSpace(string(schemaBytes)))
 }
+
+func TestSchema_OrderedPropertyNames(t *testing.T) {
+	yml := `type: object
+properties:
+  zebra:
+    type: string
+  apple:
+    type: integer
+  mango:
+    type: boolean`
+
+	s := getHighSchema(t, yml)
+	assert.Equal(t, []string{"zebra", "apple", "mango"}, s.OrderedPropertyNames())
+
+	// moving a property to the end of the high-level map does not change the source order.
+	apple := s.Properties.GetOrZero("apple")
+	s.Properties.Delete("apple")
+	s.Properties.Set("apple", apple)
+	s.Properties.Set("kiwi", CreateSchemaProxy(&Schema{Type: []string{"string"}}))
+	assert.Equal(t, []string{"zebra", "apple", "mango", "kiwi"}, s.OrderedPropertyNames())
+
+	rend, err := s.Render()
+	assert.NoError(t, err)
+	r := string(rend)
+	assert.Less(t, strings.Index(r, "zebra:"), strings.Index(r, "apple:"))
+	assert.Less(t, strings.Index(r, "apple:"), strings.Index(r, "mango:"))
+	assert.Less(t, strings.Index(r, "mango:"), strings.Index(r, "kiwi:"))
+
+	// rendering does not change the high-level map.
+	var keys []string
+	for pair := orderedmap.First(s.Properties); pair != nil; pair = pair.Next() {
+		keys = append(keys, pair.Key())
+	}
+	assert.Equal(t, []string{"zebra", "mango", "apple", "kiwi"}, keys)
+
+	assert.Nil(t, (&Schema{}).OrderedPropertyNames())
+}
+
+func TestSchema_UnknownKeywords(t *testing.T) {
+	yml := `$comment: pets are great
+type: object
+x-pizza: cake
+properties:
+  name:
+    type: string
+    myVocab:units: letters
+unitSystem:
+  metric: true`
+
+	s := getHighSchema(t, yml)
+	assert.Equal(t, 2, orderedmap.Len(s.UnknownKeywords))
+	assert.Equal(t, "pets are great", s.UnknownKeywords.GetOrZero("$comment").Value)
+	assert.NotNil(t, s.UnknownKeywords.GetOrZero("unitSystem"))
+	assert.Nil(t, s.UnknownKeywords.GetOrZero("x-pizza"))
+	assert.Nil(t, s.UnknownKeywords.GetOrZero("type"))
+	name := s.Properties.GetOrZero("name").Schema()
+	assert.Equal(t, "letters", name.UnknownKeywords.GetOrZero("myVocab:units").Value)
+
+	rend, err := s.Render()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.ReplaceAll(yml, "  ", "    "), strings.TrimSpace(string(rend)))
+
+	// new keywords are rendered at the end.
+	s.UnknownKeywords.Set("$id", utils.CreateStringNode("https://example.com/pet"))
+	rend, _ = s.Render()
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(string(rend)), "$id: https://example.com/pet"))
+
+	assert.Nil(t, getHighSchema(t, "type: string").UnknownKeywords)
+}
+
+func TestSchema_ContentKeywords(t *testing.T) {
+	yml := `type: string
+contentEncoding: base64
+contentMediaType: image/png`
+
+	s := getHighSchema(t, yml)
+	assert.Equal(t, "base64", s.ContentEncoding)
+	assert.Equal(t, "image/png", s.ContentMediaType)
+	assert.Nil(t, s.UnknownKeywords)
+
+	rend, err := s.Render()
+	assert.NoError(t, err)
+	assert.Equal(t, yml, strings.TrimSpace(string(rend)))
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateEnum will check that every value of the 'enum' declared by the Schema is compatible with its 'type' (any
+// of them, if the 'type' is an array) and 'format'. Every error names the offending value, and the line and column
+// it's defined on. A null value is only allowed if the Schema allows null values (see GetEffectiveType).
+//
+// Formats are checked by the validators registered with RegisterFormatValidator, other formats are not checked.
+// The enums of every inline schema contained by the Schema are also
+// checked, references are not followed.
+func (s *Schema) ValidateEnum() []error {
+	var errs []error
+	seen := make(map[*Schema]bool)
+	var check func(sch *Schema)
+	check = func(sch *Schema) {
+		if sch == nil || seen[sch] {
+			return
+		}
+		seen[sch] = true
+		for _, value := range sch.Enum {
+			if reason := enumViolation(sch, value); reason != "" {
+				errs = append(errs, fmt.Errorf("enum value '%s' is not valid, line %d, col %d: %s",
+					describeNode(value), value.Line, value.Column, reason))
+			}
+		}
+		for _, sp := range sch.subSchemas() {
+			if sp.IsReference() {
+				continue
+			}
+			check(sp.Schema())
+		}
+	}
+	check(s)
+	return errs
+}
+
+// enumViolation returns the reason value is not compatible with the type and format of the schema, or an empty
+// string if it is.
+func enumViolation(s *Schema, value *yaml.Node) string {
+	if value == nil {
+		return ""
+	}
+	if value.Kind == yaml.AliasNode && value.Alias != nil {
+		value = value.Alias
+	}
+	types, nullable := s.GetEffectiveType()
+	if len(types) == 0 {
+		return ""
+	}
+	valueType := nodeValueType(value)
+	if valueType == "null" {
+		if !nullable {
+			return fmt.Sprintf("null is not allowed, expected %s", strings.Join(types, " or "))
+		}
+		return ""
+	}
+	if !slices.ContainsFunc(types, func(t string) bool { return typeAllows(t, valueType, value) }) {
+		return fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), valueType)
+	}
+	if checkFormat(s.Format, value) != nil {
+		return fmt.Sprintf("value does not match the format '%s'", s.Format)
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_ValidateEnum(t *testing.T) {
+	yml := `type: object
+properties:
+  code:
+    type: integer
+    enum: [1, "2", 3.0, 4.5, null]
+  small:
+    type: integer
+    format: int32
+    enum: [1, 3000000000]
+  day:
+    type: string
+    format: date
+    enum: [2024-01-01, tomorrow, 7]
+  id:
+    type: string
+    format: uuid
+    enum: [00000000-0000-0000-0000-000000000000, abc]
+  mixed:
+    type: [string, integer, "null"]
+    enum: [a, 1, null, true]
+  old:
+    type: string
+    nullable: true
+    enum: [a, null]
+  untyped:
+    enum: [a, 1, true]`
+
+	var msgs []string
+	for _, err := range getHighSchema(t, yml).ValidateEnum() {
+		msgs = append(msgs, err.Error())
+	}
+	assert.Equal(t, []string{
+		"enum value '2' is not valid, line 5, col 15: expected integer, got string",
+		"enum value '4.5' is not valid, line 5, col 25: expected integer, got number",
+		"enum value 'null' is not valid, line 5, col 30: null is not allowed, expected integer",
+		"enum value '3000000000' is not valid, line 9, col 15: value does not match the format 'int32'",
+		"enum value 'tomorrow' is not valid, line 13, col 24: value does not match the format 'date'",
+		"enum value '7' is not valid, line 13, col 34: expected string, got integer",
+		"enum value 'abc' is not valid, line 17, col 50: value does not match the format 'uuid'",
+		"enum value 'true' is not valid, line 20, col 24: expected string or integer, got boolean",
+	}, msgs)
+}
+
+func TestSchema_ValidateEnum_Valid(t *testing.T) {
+	yml := `type: string
+format: email
+enum: [a@example.com, b@example.com]`
+	assert.Empty(t, getHighSchema(t, yml).ValidateEnum())
+	assert.Empty(t, getHighSchema(t, `type: string`).ValidateEnum())
+}
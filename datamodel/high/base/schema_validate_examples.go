@@ -0,0 +1,357 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateExamples will check the 'example', 'examples' and 'default' values declared by the Schema against the
+// constraints of the Schema itself ('type', 'enum', 'required', 'minimum', 'maxLength', 'items' etc.), object and
+// array values are checked all the way down. Every error names the offending value, and the line and column it's
+// defined on.
+//
+// The values declared by every inline schema contained by the Schema (properties, items, polymorphic schemas etc.)
+// are also checked. References are followed when checking a value, but the examples declared by the referenced
+// schemas are expected to be checked where they are defined (for example when walking the components of a document).
+func (s *Schema) ValidateExamples() []error {
+	var errs []error
+	seen := make(map[*Schema]bool)
+	var check func(sch *Schema)
+	check = func(sch *Schema) {
+		if sch == nil || seen[sch] {
+			return
+		}
+		seen[sch] = true
+		if sch.Example != nil {
+			errs = append(errs, validateExampleValue(sch, sch.Example, "example")...)
+		}
+		for i, ex := range sch.Examples {
+			if ex != nil {
+				errs = append(errs, validateExampleValue(sch, ex, fmt.Sprintf("examples[%d]", i))...)
+			}
+		}
+		if sch.Default != nil {
+			errs = append(errs, validateExampleValue(sch, sch.Default, "default")...)
+		}
+		for _, sp := range sch.subSchemas() {
+			if sp.IsReference() {
+				continue
+			}
+			check(sp.Schema())
+		}
+	}
+	check(s)
+	return errs
+}
+
+func validateExampleValue(s *Schema, node *yaml.Node, source string) []error {
+	var errs []error
+	for _, v := range new(valueValidator).validate(s, node, "$", 0) {
+		errs = append(errs, fmt.Errorf("%s value '%s' (%s) is not valid, line %d, col %d: %s",
+			source, v.value, v.path, v.line, v.col, v.reason))
+	}
+	return errs
+}
+
+// valueViolation is a single reason a value is not valid against a schema.
+type valueViolation struct {
+	path      string
+	value     string
+	reason    string
+	line, col int
+}
+
+// maxValidationDepth stops validation of values that are (somehow) infinitely deep.
+const maxValidationDepth = 100
+
+// valueValidator validates values against schemas. A schema that is already validating a value (through a
+// reference back to itself) is not checked again, so circular schemas can't loop.
+type valueValidator struct {
+	formats bool // check the 'format' of strings and numbers.
+	active  map[validationKey]bool
+}
+
+type validationKey struct {
+	schema any
+	node   *yaml.Node
+}
+
+func (vv *valueValidator) validate(s *Schema, node *yaml.Node, path string, depth int) []valueViolation {
+	if s == nil || node == nil || depth > maxValidationDepth {
+		return nil
+	}
+	for node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	// every reference to the same schema builds a new Schema, so the low-level node identifies it.
+	key := validationKey{schema: s, node: node}
+	if low := s.GoLow(); low != nil && low.RootNode != nil {
+		key.schema = low.RootNode
+	}
+	if vv.active == nil {
+		vv.active = make(map[validationKey]bool)
+	}
+	if vv.active[key] {
+		return nil
+	}
+	vv.active[key] = true
+	defer delete(vv.active, key)
+
+	var v []valueViolation
+	fail := func(format string, args ...any) {
+		v = append(v, valueViolation{
+			path: path, value: describeNode(node), reason: fmt.Sprintf(format, args...),
+			line: node.Line, col: node.Column,
+		})
+	}
+
+	// type
+	types, nullable := s.GetEffectiveType()
+	valueType := nodeValueType(node)
+	if valueType == "null" {
+		if !nullable && len(types) > 0 {
+			fail("null is not allowed, expected %s", strings.Join(types, " or "))
+		}
+		return v
+	}
+	if len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool { return typeAllows(t, valueType, node) }) {
+		fail("expected %s, got %s", strings.Join(types, " or "), valueType)
+		return v
+	}
+
+	// enum and const
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e *yaml.Node) bool { return valuesEqual(e, node) }) {
+		fail("value is not one of the enum values")
+	}
+	if s.Const != nil && !valuesEqual(s.Const, node) {
+		fail("value does not match the const value '%s'", describeNode(s.Const))
+	}
+	if vv.formats {
+		if err := checkFormat(s.Format, node); err != nil {
+			fail("value does not match the format '%s': %s", s.Format, err)
+		}
+	}
+
+	switch valueType {
+	case "integer", "number":
+		n, _ := strconv.ParseFloat(node.Value, 64)
+		if s.Minimum != nil {
+			exclusive := s.ExclusiveMinimum != nil && s.ExclusiveMinimum.IsA() && s.ExclusiveMinimum.A
+			if n < *s.Minimum || (exclusive && n == *s.Minimum) {
+				fail("value is less than the minimum of %v", *s.Minimum)
+			}
+		}
+		if s.ExclusiveMinimum != nil && s.ExclusiveMinimum.IsB() && n <= s.ExclusiveMinimum.B {
+			fail("value must be greater than %v", s.ExclusiveMinimum.B)
+		}
+		if s.Maximum != nil {
+			exclusive := s.ExclusiveMaximum != nil && s.ExclusiveMaximum.IsA() && s.ExclusiveMaximum.A
+			if n > *s.Maximum || (exclusive && n == *s.Maximum) {
+				fail("value is greater than the maximum of %v", *s.Maximum)
+			}
+		}
+		if s.ExclusiveMaximum != nil && s.ExclusiveMaximum.IsB() && n >= s.ExclusiveMaximum.B {
+			fail("value must be less than %v", s.ExclusiveMaximum.B)
+		}
+		if s.MultipleOf != nil && *s.MultipleOf > 0 {
+			q := n / *s.MultipleOf
+			if math.Abs(q-math.Round(q)) > 1e-9 {
+				fail("value is not a multiple of %v", *s.MultipleOf)
+			}
+		}
+	case "string":
+		length := int64(utf8.RuneCountInString(node.Value))
+		if s.MinLength != nil && length < *s.MinLength {
+			fail("length %d is less than the minLength of %d", length, *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail("length %d is greater than the maxLength of %d", length, *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(node.Value) {
+				fail("value does not match the pattern '%s'", s.Pattern)
+			}
+		}
+	case "array":
+		count := int64(len(node.Content))
+		if s.MinItems != nil && count < *s.MinItems {
+			fail("%d items is less than the minItems of %d", count, *s.MinItems)
+		}
+		if s.MaxItems != nil && count > *s.MaxItems {
+			fail("%d items is greater than the maxItems of %d", count, *s.MaxItems)
+		}
+		if s.UniqueItems != nil && *s.UniqueItems {
+			for i := range node.Content {
+				if slices.ContainsFunc(node.Content[:i], func(n *yaml.Node) bool { return valuesEqual(n, node.Content[i]) }) {
+					fail("items are not unique")
+					break
+				}
+			}
+		}
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if i < len(s.PrefixItems) {
+				v = append(v, vv.validate(s.PrefixItems[i].Schema(), item, itemPath, depth+1)...)
+			} else if s.Items != nil && s.Items.IsA() {
+				v = append(v, vv.validate(s.Items.A.Schema(), item, itemPath, depth+1)...)
+			} else if s.Items != nil && s.Items.IsB() && !s.Items.B {
+				fail("additional items are not allowed")
+			}
+		}
+	case "object":
+		count := int64(len(node.Content) / 2)
+		if s.MinProperties != nil && count < *s.MinProperties {
+			fail("%d properties is less than the minProperties of %d", count, *s.MinProperties)
+		}
+		if s.MaxProperties != nil && count > *s.MaxProperties {
+			fail("%d properties is greater than the maxProperties of %d", count, *s.MaxProperties)
+		}
+		present := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name, value := node.Content[i].Value, node.Content[i+1]
+			present[name] = true
+			propPath := path + "." + name
+			if s.Properties != nil {
+				if prop, ok := s.Properties.Get(name); ok {
+					v = append(v, vv.validate(prop.Schema(), value, propPath, depth+1)...)
+					continue
+				}
+			}
+			matched := false
+			for pair := orderedmap.First(s.PatternProperties); pair != nil; pair = pair.Next() {
+				if re, err := regexp.Compile(pair.Key()); err == nil && re.MatchString(name) {
+					matched = true
+					v = append(v, vv.validate(pair.Value().Schema(), value, propPath, depth+1)...)
+				}
+			}
+			if matched || s.AdditionalProperties == nil {
+				continue
+			}
+			if s.AdditionalProperties.IsA() {
+				v = append(v, vv.validate(s.AdditionalProperties.A.Schema(), value, propPath, depth+1)...)
+			} else if !s.AdditionalProperties.B {
+				fail("property '%s' is not allowed", name)
+			}
+		}
+		for _, r := range s.Required {
+			if !present[r] {
+				fail("required property '%s' is missing", r)
+			}
+		}
+	}
+
+	// composition
+	for _, sp := range s.AllOf {
+		v = append(v, vv.validate(sp.Schema(), node, path, depth+1)...)
+	}
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for _, sp := range s.OneOf {
+			if len(vv.validate(sp.Schema(), node, path, depth+1)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			fail("value must match exactly one oneOf schema, it matches %d", matches)
+		}
+	}
+	if len(s.AnyOf) > 0 && !slices.ContainsFunc(s.AnyOf, func(sp *SchemaProxy) bool {
+		return len(vv.validate(sp.Schema(), node, path, depth+1)) == 0
+	}) {
+		fail("value does not match any anyOf schema")
+	}
+	if s.Not != nil && len(vv.validate(s.Not.Schema(), node, path, depth+1)) == 0 {
+		fail("value must not match the 'not' schema")
+	}
+	return v
+}
+
+// nodeValueType returns the JSON schema type of a yaml node.
+func nodeValueType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+	return "string"
+}
+
+// typeAllows returns true if a value of valueType is allowed by the schema type, every integer is a number and
+// a number with no fractional part is an integer.
+func typeAllows(schemaType, valueType string, node *yaml.Node) bool {
+	switch {
+	case schemaType == valueType:
+		return true
+	case schemaType == "number" && valueType == "integer":
+		return true
+	case schemaType == "integer" && valueType == "number":
+		n, err := strconv.ParseFloat(node.Value, 64)
+		return err == nil && n == math.Trunc(n)
+	}
+	return false
+}
+
+// valuesEqual compares two yaml values, ignoring style and position.
+func valuesEqual(a, b *yaml.Node) bool {
+	if a.Kind == yaml.AliasNode && a.Alias != nil {
+		a = a.Alias
+	}
+	if b.Kind == yaml.AliasNode && b.Alias != nil {
+		b = b.Alias
+	}
+	if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+	if a.Kind == yaml.ScalarNode {
+		at, bt := nodeValueType(a), nodeValueType(b)
+		if (at == "integer" || at == "number") && (bt == "integer" || bt == "number") {
+			an, _ := strconv.ParseFloat(a.Value, 64)
+			bn, _ := strconv.ParseFloat(b.Value, 64)
+			return an == bn
+		}
+		return at == bt && a.Value == b.Value
+	}
+	for i := range a.Content {
+		if !valuesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeNode returns a short, single line description of a value for use in an error.
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "{...}"
+	case yaml.SequenceNode:
+		return "[...]"
+	}
+	return node.Value
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_ValidateExamples(t *testing.T) {
+	yml := `type: object
+required: [name]
+properties:
+  name:
+    type: string
+    maxLength: 5
+    example: pizza
+  age:
+    type: integer
+    minimum: 0
+    default: -1
+  tags:
+    type: array
+    uniqueItems: true
+    items:
+      type: string
+      enum: [hot, cold]
+additionalProperties: false
+example:
+  name: burger
+  age: 3.5
+  tags: [hot, warm, hot]
+  extra: true`
+
+	errs := getHighSchema(t, yml).ValidateExamples()
+	assert.Len(t, errs, 6)
+	assert.Equal(t, "example value 'burger' ($.name) is not valid, line 20, col 9: "+
+		"length 6 is greater than the maxLength of 5", errs[0].Error())
+	assert.Equal(t, "example value '3.5' ($.age) is not valid, line 21, col 8: "+
+		"expected integer, got number", errs[1].Error())
+	assert.Equal(t, "example value '[...]' ($.tags) is not valid, line 22, col 9: "+
+		"items are not unique", errs[2].Error())
+	assert.Equal(t, "example value 'warm' ($.tags[1]) is not valid, line 22, col 15: "+
+		"value is not one of the enum values", errs[3].Error())
+	assert.Equal(t, "example value '{...}' ($) is not valid, line 20, col 3: "+
+		"property 'extra' is not allowed", errs[4].Error())
+	assert.Equal(t, "default value '-1' ($) is not valid, line 11, col 14: "+
+		"value is less than the minimum of 0", errs[5].Error())
+}
+
+func TestSchema_ValidateExamples_Valid(t *testing.T) {
+	yml := `type: [object, "null"]
+required: [id]
+properties:
+  id:
+    type: number
+    multipleOf: 0.5
+  kind:
+    oneOf:
+      - type: string
+      - type: integer
+examples:
+  - id: 1.5
+    kind: thing
+  - null
+default:
+  id: 2`
+
+	assert.Empty(t, getHighSchema(t, yml).ValidateExamples())
+}
+
+func TestSchema_ValidateExamples_Composition(t *testing.T) {
+	yml := `allOf:
+  - required: [id]
+anyOf:
+  - type: string
+  - type: object
+not:
+  type: object
+  required: [secret]
+examples:
+  - {}
+  - {id: 1, secret: true}
+  - true`
+
+	errs := getHighSchema(t, yml).ValidateExamples()
+	assert.Len(t, errs, 3)
+	assert.Contains(t, errs[0].Error(), "examples[0] value '{...}' ($) is not valid")
+	assert.Contains(t, errs[0].Error(), "required property 'id' is missing")
+	assert.Contains(t, errs[1].Error(), "value must not match the 'not' schema")
+
+	// 'required' only applies to objects.
+	assert.Contains(t, errs[2].Error(), "examples[2] value 'true' ($) is not valid")
+	assert.Contains(t, errs[2].Error(), "value does not match any anyOf schema")
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError is a single reason a value is not valid against a Schema, see ValidateValue.
+type ValidationError struct {
+	Path    string // the location of the value that is not valid, for example '$.pets[0].name'
+	Value   string // a short description of the value that is not valid.
+	Message string
+}
+
+// Error returns a single line description of the ValidationError.
+func (v ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Message)
+}
+
+// ValidateValue will check value against the Schema, and return every reason it's not valid. The value can be
+// anything that can be encoded to YAML, such as the result of decoding a JSON request payload into an 'any', or a
+// *yaml.Node.
+//
+// The core JSON Schema keywords are supported: 'type', 'enum', 'const', 'required', 'properties',
+// 'patternProperties', 'additionalProperties', 'items', 'prefixItems', the numeric, length and count constraints,
+// 'pattern', 'format' (see RegisterFormatValidator), 'allOf', 'oneOf', 'anyOf' and 'not'. References are
+// followed, a schema that refers back to itself is not checked again against the same value.
+func (s *Schema) ValidateValue(value any) []ValidationError {
+	node, ok := value.(*yaml.Node)
+	if !ok {
+		node = new(yaml.Node)
+		if err := node.Encode(value); err != nil {
+			return []ValidationError{{Path: "$", Message: fmt.Sprintf("unable to encode value: %s", err)}}
+		}
+	}
+	var errs []ValidationError
+	for _, v := range (&valueValidator{formats: true}).validate(s, node, "$", 0) {
+		errs = append(errs, ValidationError{Path: v.path, Value: v.value, Message: v.reason})
+	}
+	return errs
+}
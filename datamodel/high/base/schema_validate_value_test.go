@@ -0,0 +1,108 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_ValidateValue(t *testing.T) {
+	s := getHighSchema(t, `type: object
+required:
+  - name
+  - id
+properties:
+  id:
+    type: string
+    format: uuid
+  name:
+    type: string
+    minLength: 2
+    pattern: '^[a-z]+$'
+  size:
+    type: integer
+    minimum: 1
+    maximum: 10
+  toppings:
+    type: array
+    items:
+      type: string
+      enum: [cheese, ham]
+  contact:
+    oneOf:
+      - type: string
+        format: email
+      - type: integer`)
+
+	var value any
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "id": "not-a-uuid",
+  "name": "X",
+  "size": 11,
+  "toppings": ["cheese", "pineapple"],
+  "contact": "nope"
+}`), &value))
+
+	errs := s.ValidateValue(value)
+	require.Len(t, errs, 6)
+	assert.Equal(t, "$.contact: value must match exactly one oneOf schema, it matches 0", errs[0].Error())
+	assert.Equal(t, "$.id: value does not match the format 'uuid': not a valid uuid", errs[1].Error())
+	assert.Equal(t, "$.name: length 1 is less than the minLength of 2", errs[2].Error())
+	assert.Equal(t, "$.name: value does not match the pattern '^[a-z]+$'", errs[3].Error())
+	assert.Equal(t, "$.size: value is greater than the maximum of 10", errs[4].Error())
+	assert.Equal(t, "$.toppings[1]: value is not one of the enum values", errs[5].Error())
+	assert.Equal(t, "pineapple", errs[5].Value)
+
+	value = map[string]any{
+		"id":       "123e4567-e89b-12d3-a456-426614174000",
+		"name":     "pizza",
+		"size":     3.0,
+		"toppings": []string{"ham"},
+		"contact":  42,
+	}
+	assert.Empty(t, s.ValidateValue(value))
+
+	errs = s.ValidateValue(map[string]any{"name": "pizza"})
+	require.Len(t, errs, 1)
+	assert.Equal(t, "$: required property 'id' is missing", errs[0].Error())
+
+	errs = s.ValidateValue("pizza")
+	require.Len(t, errs, 1)
+	assert.Equal(t, "$: expected object, got string", errs[0].Error())
+}
+
+func TestSchema_ValidateValue_Circular(t *testing.T) {
+	components := `components:
+  schemas:
+    Node:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'
+    Loop:
+      allOf:
+        - $ref: '#/components/schemas/Loop'`
+
+	s := getHighSchemaWithComponents(t, components, `$ref: '#/components/schemas/Node'`)
+	errs := s.ValidateValue(map[string]any{
+		"name": "root",
+		"children": []any{
+			map[string]any{"name": "child", "children": []any{map[string]any{}}},
+		},
+	})
+	require.Len(t, errs, 1)
+	assert.Equal(t, "$.children[0].children[0]: required property 'name' is missing", errs[0].Error())
+
+	s = getHighSchemaWithComponents(t, components, `$ref: '#/components/schemas/Loop'`)
+	assert.Empty(t, s.ValidateValue("anything"))
+}
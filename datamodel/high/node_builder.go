@@ -89,8 +89,11 @@ func (n *NodeBuilder) add(key string, i int) {
 			nodeEntry := &nodes.NodeEntry{Tag: pair.Key(), Key: pair.Key(), Value: pair.Value(), Line: j}
 
 			if lowExtensions != nil {
-				lowItem := low.FindItemInOrderedMap(pair.Key(), lowExtensions)
+				lowKey, lowItem := low.FindItemInOrderedMapWithKey(pair.Key(), lowExtensions)
 				nodeEntry.LowValue = lowItem
+				if lowKey != nil {
+					nodeEntry.LowKeyNode = lowKey.KeyNode
+				}
 			}
 			n.Nodes = append(n.Nodes, nodeEntry)
 			j++
@@ -99,6 +102,12 @@ func (n *NodeBuilder) add(key string, i int) {
 		return
 	}
 
+	// unknown keywords (of a schema) are rendered verbatim, in the place they were originally defined.
+	if key == "UnknownKeywords" {
+		n.addUnknownKeywords(i)
+		return
+	}
+
 	// find the field with the tag supplied.
 	field, _ := reflect.TypeOf(n.High).Elem().FieldByName(key)
 	tag := string(field.Tag.Get("yaml"))
@@ -221,6 +230,31 @@ func (n *NodeBuilder) add(key string, i int) {
 	}
 }
 
+// addUnknownKeywords adds a node entry for every unknown keyword of the high level object. New keywords (that have no
+// low level equivalent) are weighted to the bottom of the rendered object.
+func (n *NodeBuilder) addUnknownKeywords(i int) {
+	keywords, _ := reflect.ValueOf(n.High).Elem().FieldByName("UnknownKeywords").Interface().(*orderedmap.Map[string, *yaml.Node])
+
+	var lowKeywords *orderedmap.Map[low.KeyReference[string], low.ValueReference[*yaml.Node]]
+	if n.Low != nil && !reflect.ValueOf(n.Low).IsZero() {
+		if f := reflect.ValueOf(n.Low).Elem().FieldByName("UnknownKeywords"); f.IsValid() {
+			lowKeywords, _ = f.Interface().(*orderedmap.Map[low.KeyReference[string], low.ValueReference[*yaml.Node]])
+		}
+	}
+
+	for pair := orderedmap.First(keywords); pair != nil; pair = pair.Next() {
+		nodeEntry := &nodes.NodeEntry{Tag: pair.Key(), Key: pair.Key(), Value: pair.Value(), Line: 9999 + i}
+		if lowKey, lowItem := low.FindItemInOrderedMapWithKey(pair.Key(), lowKeywords); lowKey != nil {
+			if lowKey.KeyNode != nil {
+				nodeEntry.Line = lowKey.KeyNode.Line
+			}
+			nodeEntry.LowValue = lowItem
+			nodeEntry.LowKeyNode = lowKey.KeyNode
+		}
+		n.Nodes = append(n.Nodes, nodeEntry)
+	}
+}
+
 func (n *NodeBuilder) renderReference(fg low.IsReferenced) *yaml.Node {
 	origNode := fg.GetReferenceNode()
 	if origNode == nil {
@@ -261,6 +295,26 @@ func (n *NodeBuilder) Render() *yaml.Node {
 	return m
 }
 
+// originalNumberNode returns a copy of the low level value node of lowValue, if it's a number with the value v.
+func originalNumberNode(lowValue any, v float64) *yaml.Node {
+	lv, ok := lowValue.(low.HasValueNodeUntyped)
+	if !ok {
+		return nil
+	}
+	vn := lv.GetValueNode()
+	if vn == nil || vn.Kind != yaml.ScalarNode {
+		return nil
+	}
+	if tag := vn.ShortTag(); tag != "!!int" && tag != "!!float" {
+		return nil
+	}
+	var decoded float64
+	if err := vn.Decode(&decoded); err != nil || decoded != v {
+		return nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: vn.ShortTag(), Value: vn.Value, Style: vn.Style}
+}
+
 // AddYAMLNode will add a new *yaml.Node to the parent node, using the tag, key and value provided.
 // If the value is nil, then the node will not be added. This method is recursive, so it will dig down
 // into any non-scalar types.
@@ -499,7 +553,11 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 			}
 			if b, bok := value.(*float64); bok {
 				encodeSkip = true
-				if *b > 0 || (entry.RenderZero && entry.Line > 0) {
+				if original := originalNumberNode(entry.LowValue, *b); original != nil {
+					// the value is unchanged, so it's rendered the way it's written in the specification.
+					valueNode = original
+					valueNode.Line = line
+				} else if *b > 0 || (entry.RenderZero && entry.Line > 0) {
 					formatFloat := strconv.FormatFloat(*b, 'f', -1, 64)
 					if *b > 0 {
 						if *b == math.Trunc(*b) {
@@ -543,6 +601,7 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 	if valueNode == nil {
 		return parent
 	}
+	keepComments(l, valueNode, entry)
 	if l != nil {
 		parent.Content = append(parent.Content, l, valueNode)
 	} else {
@@ -551,6 +610,64 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 	return parent
 }
 
+// keepComments copies the comments of the original key node of entry to keyNode, and the comments of the original
+// value node to valueNode, so comments in the specification are rendered. Comments of scalar values (and of the
+// scalar items of a sequence) are only kept when the value has not changed.
+func keepComments(keyNode, valueNode *yaml.Node, entry *nodes.NodeEntry) {
+	lowKey := entry.LowKeyNode
+	var lowValue *yaml.Node
+	if entry.LowValue != nil {
+		if hk, ok := entry.LowValue.(low.HasKeyNode); ok && lowKey == nil {
+			lowKey = hk.GetKeyNode()
+		}
+		if hv, ok := entry.LowValue.(low.HasValueNodeUntyped); ok {
+			lowValue = hv.GetValueNode()
+		}
+	}
+	if keyNode != nil && lowKey != nil && lowKey != keyNode {
+		copyComments(keyNode, lowKey)
+	}
+	if lowValue == nil || lowValue == valueNode || valueNode.Kind != lowValue.Kind {
+		return
+	}
+	switch valueNode.Kind {
+	case yaml.ScalarNode:
+		if valueNode.Value == lowValue.Value {
+			copyComments(valueNode, lowValue)
+		}
+	case yaml.SequenceNode:
+		for i, item := range valueNode.Content {
+			if i < len(lowValue.Content) && item.Kind == yaml.ScalarNode && item != lowValue.Content[i] &&
+				item.Value == lowValue.Content[i].Value {
+				copyComments(item, lowValue.Content[i])
+			}
+		}
+	}
+}
+
+// KeepKeyFormatting copies the style and comments of the original key node lowKey to keyNode, so a key created for a
+// rendered map looks the way it was written in the specification. Nothing happens if there is no lowKey.
+func KeepKeyFormatting(keyNode, lowKey *yaml.Node) {
+	if keyNode == nil || lowKey == nil {
+		return
+	}
+	keyNode.Style = lowKey.Style
+	copyComments(keyNode, lowKey)
+}
+
+// copyComments copies any comments of from to to, without removing the comments to already has.
+func copyComments(to, from *yaml.Node) {
+	if from.HeadComment != "" {
+		to.HeadComment = from.HeadComment
+	}
+	if from.LineComment != "" {
+		to.LineComment = from.LineComment
+	}
+	if from.FootComment != "" {
+		to.FootComment = from.FootComment
+	}
+}
+
 // Renderable is an interface that can be implemented by types that provide a custom MarshalYAML method.
 type Renderable interface {
 	MarshalYAML() (interface{}, error)
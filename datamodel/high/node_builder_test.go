@@ -991,6 +991,38 @@ func TestNewNodeBuilder_TestRenderZero_Float_NotZero(t *testing.T) {
 	assert.Equal(t, desired, strings.TrimSpace(string(data)))
 }
 
+type numberLow struct {
+	Minimum low.NodeReference[float64]
+	Maximum low.NodeReference[float64]
+}
+
+type numberHigh struct {
+	Minimum *float64 `yaml:"minimum,omitempty"`
+	Maximum *float64 `yaml:"maximum,omitempty"`
+}
+
+func TestNewNodeBuilder_OriginalNumbers(t *testing.T) {
+	var lowNode yaml.Node
+	_ = yaml.Unmarshal([]byte(`minimum: -999.990
+maximum: 1.0`), &lowNode)
+	minimum, maximum := lowNode.Content[0].Content[1], lowNode.Content[0].Content[3]
+	l := numberLow{
+		Minimum: low.NodeReference[float64]{Value: -999.99, ValueNode: minimum},
+		Maximum: low.NodeReference[float64]{Value: 1, ValueNode: maximum},
+	}
+
+	// unchanged numbers are rendered as they are written, changed numbers are formatted.
+	minValue, maxValue := -999.99, 2.0
+	nb := NewNodeBuilder(&numberHigh{Minimum: &minValue, Maximum: &maxValue}, &l)
+	data, _ := yaml.Marshal(nb.Render())
+	assert.Equal(t, `minimum: -999.990
+maximum: 2`, strings.TrimSpace(string(data)))
+
+	var decoded map[string]float64
+	assert.NoError(t, yaml.Unmarshal(data, &decoded))
+	assert.Equal(t, -999.99, decoded["minimum"])
+}
+
 func TestNewNodeBuilder_TestRenderServerVariableSimulation(t *testing.T) {
 	thrig := orderedmap.New[string, *plug]()
 	thrig.Set("pork", &plug{Name: []string{"gammon", "bacon"}})
@@ -1109,3 +1141,20 @@ func TestNodeBuilder_GetStringLowValue(t *testing.T) {
 
 	assert.Equal(t, `thing: "thing"`, strings.TrimSpace(string(data)))
 }
+
+func TestKeepKeyFormatting(t *testing.T) {
+	lowKey := &yaml.Node{Kind: yaml.ScalarNode, Value: "200", Style: yaml.DoubleQuotedStyle,
+		HeadComment: "# ok", LineComment: "# line", FootComment: "# foot"}
+	key := utils.CreateStringNode("200")
+	KeepKeyFormatting(key, lowKey)
+	assert.Equal(t, yaml.DoubleQuotedStyle, key.Style)
+	assert.Equal(t, "# ok", key.HeadComment)
+	assert.Equal(t, "# line", key.LineComment)
+	assert.Equal(t, "# foot", key.FootComment)
+
+	// nothing to copy.
+	key = utils.CreateStringNode("200")
+	KeepKeyFormatting(key, nil)
+	KeepKeyFormatting(nil, lowKey)
+	assert.Empty(t, key.HeadComment)
+}
@@ -13,4 +13,6 @@ type NodeEntry struct {
 	// ValueStyle  yaml.Style
 	RenderZero bool
 	LowValue   any
+	// LowKeyNode is the original key node, when it can't be found from the LowValue. Its comments are rendered.
+	LowKeyNode *yaml.Node
 }
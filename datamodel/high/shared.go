@@ -14,8 +14,16 @@
 package high
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
 	"github.com/pb33f/libopenapi/datamodel/low"
+	"github.com/pb33f/libopenapi/index"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
 	"gopkg.in/yaml.v3"
 )
 
@@ -76,3 +84,154 @@ func UnpackExtensions[T any, R low.HasExtensions[T]](low GoesLow[R]) (*orderedma
 	}
 	return m, nil
 }
+
+// RemoveExtensions will remove the extensions of a high-level model that start with any of the prefixes (or every
+// extension, starting with 'x-', if no prefixes are provided). Extensions are removed from the high-level model,
+// and the low-level model and yaml nodes backing it, so they are not rendered. Returns the number of extensions
+// that were removed.
+//
+// Only the object itself is changed, not any of the objects it contains.
+func RemoveExtensions(h any, prefixes ...string) int {
+	if len(prefixes) == 0 {
+		prefixes = []string{"x-"}
+	}
+	match := func(key string) bool {
+		return slices.ContainsFunc(prefixes, func(p string) bool { return strings.HasPrefix(key, p) })
+	}
+	removed := make(map[string]bool)
+
+	// high-level extensions
+	hv := reflect.ValueOf(h)
+	if hv.Kind() != reflect.Pointer || hv.IsNil() {
+		return 0
+	}
+	if f := hv.Elem().FieldByName("Extensions"); f.IsValid() {
+		if ext, ok := f.Interface().(*orderedmap.Map[string, *yaml.Node]); ok && ext != nil {
+			var keys []string
+			for pair := orderedmap.First(ext); pair != nil; pair = pair.Next() {
+				if match(pair.Key()) {
+					keys = append(keys, pair.Key())
+				}
+			}
+			for _, k := range keys {
+				ext.Delete(k)
+				removed[k] = true
+			}
+		}
+	}
+
+	// low-level extensions and nodes.
+	gl, ok := h.(GoesLowUntyped)
+	if !ok {
+		return len(removed)
+	}
+	l := gl.GoLowUntyped()
+	if l == nil || reflect.ValueOf(l).IsNil() {
+		return len(removed)
+	}
+	if le, ok := l.(low.HasExtensionsUntyped); ok && le.GetExtensions() != nil {
+		var keys []low.KeyReference[string]
+		for pair := orderedmap.First(le.GetExtensions()); pair != nil; pair = pair.Next() {
+			if match(pair.Key().Value) {
+				keys = append(keys, pair.Key())
+			}
+		}
+		for _, k := range keys {
+			le.GetExtensions().Delete(k)
+			removed[k.Value] = true
+		}
+	}
+	if rn, ok := l.(low.HasRootNode); ok {
+		for _, k := range utils.RemoveKeyNodesWithPrefix(rn.GetRootNode(), prefixes...) {
+			removed[k] = true
+		}
+	}
+	return len(removed)
+}
+
+// SourceSnippet will return the lines of the specification that define obj, exactly as they are written in the
+// source (including the key the object is defined under, if it has one). obj can be any high-level model, a
+// low-level model, a low-level NodeReference, KeyReference or ValueReference, or a *yaml.Node.
+//
+// The source is read from the SpecInfo of the index (the index of the document obj belongs to), so an error is
+// returned if the index has no source, or if obj has no yaml nodes (for example, it was created in code).
+func SourceSnippet(obj any, idx *index.SpecIndex) (string, error) {
+	if idx == nil || idx.GetConfig() == nil || idx.GetConfig().SpecInfo == nil ||
+		idx.GetConfig().SpecInfo.SpecBytes == nil {
+		return "", errors.New("unable to extract source snippet, the index has no specification source")
+	}
+	keyNode, valueNode := sourceNodes(obj)
+	if valueNode == nil {
+		return "", fmt.Errorf("unable to extract source snippet, %T has no yaml nodes", obj)
+	}
+
+	start := valueNode.Line
+	if keyNode != nil && keyNode.Line > 0 && keyNode.Line < start {
+		start = keyNode.Line
+	}
+	lines := strings.Split(string(*idx.GetConfig().SpecInfo.SpecBytes), "\n")
+	end := lastLine(valueNode, lines)
+	if start < 1 || end > len(lines) {
+		return "", fmt.Errorf("unable to extract source snippet, lines %d to %d are not in the specification", start, end)
+	}
+	return strings.Join(lines[start-1:end], "\n"), nil
+}
+
+// sourceNodes returns the key and value nodes backing obj, if there are any.
+func sourceNodes(obj any) (keyNode, valueNode *yaml.Node) {
+	if obj == nil {
+		return nil, nil
+	}
+	if n, ok := obj.(*yaml.Node); ok {
+		return nil, n
+	}
+	if v := reflect.ValueOf(obj); v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, nil
+	}
+	if gl, ok := obj.(GoesLowUntyped); ok {
+		return sourceNodes(gl.GoLowUntyped())
+	}
+	if kn, ok := obj.(low.HasKeyNode); ok {
+		keyNode = kn.GetKeyNode()
+	}
+	switch o := obj.(type) {
+	case low.HasRootNode:
+		valueNode = o.GetRootNode()
+	case low.HasValueNodeUntyped:
+		valueNode = o.GetValueNode()
+	}
+	if valueNode == nil {
+		// a key on its own (a KeyReference).
+		valueNode = keyNode
+	}
+	return keyNode, valueNode
+}
+
+// lastLine returns the last line of the source occupied by node, including the lines of any block scalar (which are
+// found using the indentation of the source lines).
+func lastLine(node *yaml.Node, lines []string) int {
+	last := node.Line
+	if node.Kind == yaml.ScalarNode && (node.Style&(yaml.LiteralStyle|yaml.FoldedStyle)) != 0 {
+		// block scalars start on the line after the indicator, and continue while lines are blank or are indented
+		// further than the line holding the indicator.
+		indentOf := func(line string) int { return len(line) - len(strings.TrimLeft(line, " ")) }
+		if node.Line >= 1 && node.Line <= len(lines) {
+			indent := indentOf(lines[node.Line-1])
+			for i := node.Line; i < len(lines); i++ {
+				if strings.TrimSpace(lines[i]) == "" {
+					continue
+				}
+				if indentOf(lines[i]) <= indent {
+					break
+				}
+				last = i + 1
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if l := lastLine(child, lines); l > last {
+			last = l
+		}
+	}
+	return last
+}
@@ -6,7 +6,10 @@ package high
 import (
 	"testing"
 
+	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/pb33f/libopenapi/datamodel/low"
+	lowv3 "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"github.com/pb33f/libopenapi/index"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/pb33f/libopenapi/utils"
 	"github.com/stretchr/testify/assert"
@@ -135,3 +138,127 @@ power: hello`
 	assert.Error(t, er)
 	assert.Empty(t, res)
 }
+
+type extensionsHigh struct {
+	Extensions *orderedmap.Map[string, *yaml.Node]
+	low        *extensionsLow
+}
+
+func (e *extensionsHigh) GoLowUntyped() any {
+	return e.low
+}
+
+type extensionsLow struct {
+	root       *yaml.Node
+	extensions *orderedmap.Map[low.KeyReference[string], low.ValueReference[*yaml.Node]]
+}
+
+func (e *extensionsLow) GetRootNode() *yaml.Node {
+	return e.root
+}
+
+func (e *extensionsLow) GetExtensions() *orderedmap.Map[low.KeyReference[string], low.ValueReference[*yaml.Node]] {
+	return e.extensions
+}
+
+func TestRemoveExtensions(t *testing.T) {
+	var root yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`name: burger
+x-internal-owner: team
+x-logo: logo.png`), &root))
+
+	lowExt := low.ExtractExtensions(root.Content[0])
+	h := &extensionsHigh{
+		Extensions: ExtractExtensions(lowExt),
+		low:        &extensionsLow{root: root.Content[0], extensions: lowExt},
+	}
+
+	assert.Equal(t, 1, RemoveExtensions(h, "x-internal-"))
+	assert.Equal(t, 1, h.Extensions.Len())
+	assert.Equal(t, 1, lowExt.Len())
+	assert.Len(t, root.Content[0].Content, 4)
+
+	assert.Equal(t, 1, RemoveExtensions(h))
+	assert.Equal(t, 0, h.Extensions.Len())
+	assert.Equal(t, 0, lowExt.Len())
+	assert.Len(t, root.Content[0].Content, 2)
+
+	var nilHigh *extensionsHigh
+	assert.Equal(t, 0, RemoveExtensions(nilHigh))
+	assert.Equal(t, 0, RemoveExtensions(&extensionsHigh{}))
+}
+
+type highOperation struct {
+	low *lowv3.Operation
+}
+
+func (h *highOperation) GoLowUntyped() any {
+	return h.low
+}
+
+func TestSourceSnippet(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      description: |
+        Lists the pets.
+
+        All of them.
+      responses:
+        "200":
+          description: >
+            some
+            pets
+    post:
+      operationId: addPet`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	pathItem := lDoc.Paths.Value.FindPath("/pets").Value
+
+	// a high-level model.
+	snippet, err := SourceSnippet(&highOperation{low: pathItem.Get.Value}, lDoc.Index)
+	assert.NoError(t, err)
+	assert.Equal(t, `    get:
+      description: |
+        Lists the pets.
+
+        All of them.
+      responses:
+        "200":
+          description: >
+            some
+            pets`, snippet)
+
+	// a low-level reference.
+	snippet, err = SourceSnippet(pathItem.Post, lDoc.Index)
+	assert.NoError(t, err)
+	assert.Equal(t, "    post:\n      operationId: addPet", snippet)
+
+	// a node.
+	snippet, err = SourceSnippet(lDoc.Info.Value.Title.ValueNode, lDoc.Index)
+	assert.NoError(t, err)
+	assert.Equal(t, "  title: pets", snippet)
+}
+
+func TestSourceSnippet_Errors(t *testing.T) {
+	_, err := SourceSnippet(&yaml.Node{Line: 1}, nil)
+	assert.ErrorContains(t, err, "the index has no specification source")
+
+	info, _ := datamodel.ExtractSpecInfo([]byte("openapi: 3.1.0"))
+	cfg := index.CreateOpenAPIIndexConfig()
+	cfg.SpecInfo = info
+	idx := index.NewSpecIndexWithConfig(info.RootNode, cfg)
+
+	_, err = SourceSnippet(&highOperation{}, idx)
+	assert.ErrorContains(t, err, "*high.highOperation has no yaml nodes")
+	_, err = SourceSnippet(nil, idx)
+	assert.Error(t, err)
+	_, err = SourceSnippet(&yaml.Node{Line: 10}, idx)
+	assert.ErrorContains(t, err, "lines 10 to 10 are not in the specification")
+}
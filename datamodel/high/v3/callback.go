@@ -4,6 +4,7 @@
 package v3
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/pb33f/libopenapi/datamodel/high"
@@ -39,6 +40,19 @@ func NewCallback(lowCallback *low.Callback) *Callback {
 	return n
 }
 
+// Validate will check that every key of the Callback is well-formed, either a single runtime expression like
+// '$request.body#/callbackUrl', or a URL with runtime expressions embedded like 'https://pb33f.io?id={$request.query.id}'.
+// An error is returned for every expression that is not valid.
+func (c *Callback) Validate() []error {
+	var errs []error
+	for pair := orderedmap.First(c.Expression); pair != nil; pair = pair.Next() {
+		if _, err := extractRuntimeExpressions(pair.Key()); err != nil {
+			errs = append(errs, fmt.Errorf("callback expression '%s' is not valid: %w", pair.Key(), err))
+		}
+	}
+	return errs
+}
+
 // GoLow returns the low-level Callback instance used to create the high-level one.
 func (c *Callback) GoLow() *low.Callback {
 	return c.low
@@ -68,7 +82,7 @@ func (c *Callback) MarshalYAML() (interface{}, error) {
 		pi       *PathItem
 		path     string
 		line     int
-		style    yaml.Style
+		keyNode  *yaml.Node
 		rendered *yaml.Node
 	}
 	var mapped []*pathItem
@@ -77,7 +91,7 @@ func (c *Callback) MarshalYAML() (interface{}, error) {
 		k := pair.Key()
 		pi := pair.Value()
 		ln := 9999 // default to a high value to weight new content to the bottom.
-		var style yaml.Style
+		var keyNode *yaml.Node
 		if c.low != nil {
 			lpi := c.low.FindExpression(k)
 			if lpi != nil {
@@ -86,12 +100,12 @@ func (c *Callback) MarshalYAML() (interface{}, error) {
 
 			for pair := orderedmap.First(c.low.Expression); pair != nil; pair = pair.Next() {
 				if pair.Key().Value == k {
-					style = pair.Key().KeyNode.Style
+					keyNode = pair.Key().KeyNode
 					break
 				}
 			}
 		}
-		mapped = append(mapped, &pathItem{pi, k, ln, style, nil})
+		mapped = append(mapped, &pathItem{pi, k, ln, keyNode, nil})
 	}
 
 	nb := high.NewNodeBuilder(c, c.low)
@@ -105,7 +119,7 @@ func (c *Callback) MarshalYAML() (interface{}, error) {
 			}
 			mapped = append(mapped, &pathItem{
 				nil, label,
-				extNode.Content[u].Line, 0, extNode.Content[u],
+				extNode.Content[u].Line, nil, extNode.Content[u],
 			})
 		}
 	}
@@ -118,7 +132,7 @@ func (c *Callback) MarshalYAML() (interface{}, error) {
 			rendered, _ := mp.pi.MarshalYAML()
 
 			kn := utils.CreateStringNode(mp.path)
-			kn.Style = mp.style
+			high.KeepKeyFormatting(kn, mp.keyNode)
 
 			m.Content = append(m.Content, kn)
 			m.Content = append(m.Content, rendered.(*yaml.Node))
@@ -139,7 +153,7 @@ func (c *Callback) MarshalYAMLInline() (interface{}, error) {
 		pi       *PathItem
 		path     string
 		line     int
-		style    yaml.Style
+		keyNode  *yaml.Node
 		rendered *yaml.Node
 	}
 	var mapped []*pathItem
@@ -148,7 +162,7 @@ func (c *Callback) MarshalYAMLInline() (interface{}, error) {
 		k := pair.Key()
 		pi := pair.Value()
 		ln := 9999 // default to a high value to weight new content to the bottom.
-		var style yaml.Style
+		var keyNode *yaml.Node
 		if c.low != nil {
 			lpi := c.low.FindExpression(k)
 			if lpi != nil {
@@ -157,12 +171,12 @@ func (c *Callback) MarshalYAMLInline() (interface{}, error) {
 
 			for pair := orderedmap.First(c.low.Expression); pair != nil; pair = pair.Next() {
 				if pair.Key().Value == k {
-					style = pair.Key().KeyNode.Style
+					keyNode = pair.Key().KeyNode
 					break
 				}
 			}
 		}
-		mapped = append(mapped, &pathItem{pi, k, ln, style, nil})
+		mapped = append(mapped, &pathItem{pi, k, ln, keyNode, nil})
 	}
 
 	nb := high.NewNodeBuilder(c, c.low)
@@ -177,7 +191,7 @@ func (c *Callback) MarshalYAMLInline() (interface{}, error) {
 			}
 			mapped = append(mapped, &pathItem{
 				nil, label,
-				extNode.Content[u].Line, 0, extNode.Content[u],
+				extNode.Content[u].Line, nil, extNode.Content[u],
 			})
 		}
 	}
@@ -190,7 +204,7 @@ func (c *Callback) MarshalYAMLInline() (interface{}, error) {
 			rendered, _ := mp.pi.MarshalYAMLInline()
 
 			kn := utils.CreateStringNode(mp.path)
-			kn.Style = mp.style
+			high.KeepKeyFormatting(kn, mp.keyNode)
 
 			m.Content = append(m.Content, kn)
 			m.Content = append(m.Content, rendered.(*yaml.Node))
@@ -108,3 +108,27 @@ func TestCallback_RenderInline(t *testing.T) {
 	rend, _ := cb.RenderInline()
 	assert.Equal(t, "x-burgers: why not?\nhttps://pb33f.io:\n    get:\n        operationId: oneTwoThree\nhttps://pb33f.io/libopenapi:\n    get:\n        operationId: openaypeeeye\n", string(rend))
 }
+
+func TestCallback_Validate(t *testing.T) {
+	cb := &Callback{
+		Expression: orderedmap.ToOrderedMap(map[string]*PathItem{
+			"{$request.body#/callbackUrl}": {},
+			"$request.query.url":           {},
+			"https://pb33f.io/notify?id={$request.query.id}&email={$request.body#/email}": {},
+			"https://pb33f.io/static": {},
+		}),
+	}
+	assert.Empty(t, cb.Validate())
+
+	cb.Expression.Set("{$request.cookie.id}", &PathItem{})
+	cb.Expression.Set("https://pb33f.io/{$url", &PathItem{})
+	cb.Expression.Set("https://pb33f.io/$url}", &PathItem{})
+	errs := cb.Validate()
+	assert.Len(t, errs, 3)
+	assert.EqualError(t, errs[0], "callback expression '{$request.cookie.id}' is not valid: "+
+		"unable to parse runtime expression '{$request.cookie.id}': unknown location 'cookie', expected header, query, path or body")
+	assert.EqualError(t, errs[1], "callback expression 'https://pb33f.io/{$url' is not valid: "+
+		"unable to parse runtime expression in 'https://pb33f.io/{$url': a '{' is not closed")
+	assert.EqualError(t, errs[2], "callback expression 'https://pb33f.io/$url}' is not valid: "+
+		"unable to parse runtime expression in 'https://pb33f.io/$url}': unexpected '}'")
+}
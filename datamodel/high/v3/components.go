@@ -31,6 +31,7 @@ type Components struct {
 	SecuritySchemes *orderedmap.Map[string, *SecurityScheme]       `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
 	Links           *orderedmap.Map[string, *Link]                 `json:"links,omitempty" yaml:"links,omitempty"`
 	Callbacks       *orderedmap.Map[string, *Callback]             `json:"callbacks,omitempty" yaml:"callbacks,omitempty"`
+	PathItems       *orderedmap.Map[string, *PathItem]             `json:"pathItems,omitempty" yaml:"pathItems,omitempty"` // 3.1 only
 	Extensions      *orderedmap.Map[string, *yaml.Node]            `json:"-" yaml:"-"`
 	low             *low.Components
 }
@@ -53,10 +54,11 @@ func NewComponents(comp *low.Components) *Components {
 	headerMap := orderedmap.New[string, *Header]()
 	securitySchemeMap := orderedmap.New[string, *SecurityScheme]()
 	schemas := orderedmap.New[string, *highbase.SchemaProxy]()
+	pathItemMap := orderedmap.New[string, *PathItem]()
 
 	// build all components asynchronously.
 	var wg sync.WaitGroup
-	wg.Add(9)
+	wg.Add(10)
 	go func() {
 		buildComponent[*low.Callback, *Callback](comp.Callbacks.Value, cbMap, NewCallback)
 		wg.Done()
@@ -93,6 +95,10 @@ func NewComponents(comp *low.Components) *Components {
 		buildSchema(comp.Schemas.Value, schemas)
 		wg.Done()
 	}()
+	go func() {
+		buildComponent[*low.PathItem, *PathItem](comp.PathItems.Value, pathItemMap, NewPathItem)
+		wg.Done()
+	}()
 
 	wg.Wait()
 	c.Schemas = schemas
@@ -104,6 +110,7 @@ func NewComponents(comp *low.Components) *Components {
 	c.RequestBodies = requestBodyMap
 	c.Examples = exampleMap
 	c.SecuritySchemes = securitySchemeMap
+	c.PathItems = pathItemMap
 	return c
 }
 
@@ -152,6 +159,126 @@ func (c *Components) GoLowUntyped() any {
 	return c.low
 }
 
+// SchemaCount returns the number of schemas in the Components, zero if Components is nil. The low-level
+// Components is counted when there is one, so no high-level objects are built.
+func (c *Components) SchemaCount() int {
+	if c == nil {
+		return 0
+	}
+	if c.low != nil {
+		return c.low.SchemaCount()
+	}
+	return orderedmap.Len(c.Schemas)
+}
+
+// ResponseCount returns the number of responses in the Components, zero if Components is nil. The low-level
+// Components is counted when there is one, so no high-level objects are built.
+func (c *Components) ResponseCount() int {
+	if c == nil {
+		return 0
+	}
+	if c.low != nil {
+		return c.low.ResponseCount()
+	}
+	return orderedmap.Len(c.Responses)
+}
+
+// ParameterCount returns the number of parameters in the Components, zero if Components is nil. The low-level
+// Components is counted when there is one, so no high-level objects are built.
+func (c *Components) ParameterCount() int {
+	if c == nil {
+		return 0
+	}
+	if c.low != nil {
+		return c.low.ParameterCount()
+	}
+	return orderedmap.Len(c.Parameters)
+}
+
+// ExampleCount returns the number of examples in the Components, zero if Components is nil. The low-level
+// Components is counted when there is one, so no high-level objects are built.
+func (c *Components) ExampleCount() int {
+	if c == nil {
+		return 0
+	}
+	if c.low != nil {
+		return c.low.ExampleCount()
+	}
+	return orderedmap.Len(c.Examples)
+}
+
+// RequestBodyCount returns the number of request bodies in the Components, zero if Components is nil. The low-level
+// Components is counted when there is one, so no high-level objects are built.
+func (c *Components) RequestBodyCount() int {
+	if c == nil {
+		return 0
+	}
+	if c.low != nil {
+		return c.low.RequestBodyCount()
+	}
+	return orderedmap.Len(c.RequestBodies)
+}
+
+// HeaderCount returns the number of headers in the Components, zero if Components is nil. The low-level
+// Components is counted when there is one, so no high-level objects are built.
+func (c *Components) HeaderCount() int {
+	if c == nil {
+		return 0
+	}
+	if c.low != nil {
+		return c.low.HeaderCount()
+	}
+	return orderedmap.Len(c.Headers)
+}
+
+// SecuritySchemeCount returns the number of security schemes in the Components, zero if Components is nil. The low-level
+// Components is counted when there is one, so no high-level objects are built.
+func (c *Components) SecuritySchemeCount() int {
+	if c == nil {
+		return 0
+	}
+	if c.low != nil {
+		return c.low.SecuritySchemeCount()
+	}
+	return orderedmap.Len(c.SecuritySchemes)
+}
+
+// LinkCount returns the number of links in the Components, zero if Components is nil. The low-level
+// Components is counted when there is one, so no high-level objects are built.
+func (c *Components) LinkCount() int {
+	if c == nil {
+		return 0
+	}
+	if c.low != nil {
+		return c.low.LinkCount()
+	}
+	return orderedmap.Len(c.Links)
+}
+
+// CallbackCount returns the number of callbacks in the Components, zero if Components is nil. The low-level
+// Components is counted when there is one, so no high-level objects are built.
+func (c *Components) CallbackCount() int {
+	if c == nil {
+		return 0
+	}
+	if c.low != nil {
+		return c.low.CallbackCount()
+	}
+	return orderedmap.Len(c.Callbacks)
+}
+
+// PathItemCount returns the number of path items in the Components, zero if Components is nil. The low-level
+// Components is counted when there is one, so no high-level objects are built.
+func (c *Components) PathItemCount() int {
+	if c == nil {
+		return 0
+	}
+	if c.low != nil {
+		return c.low.PathItemCount()
+	}
+	return orderedmap.Len(c.PathItems)
+}
+
 // Render will return a YAML representation of the Components object as a byte slice.
 func (c *Components) Render() ([]byte, error) {
 	return yaml.Marshal(c)
@@ -0,0 +1,203 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"strings"
+
+	highbase "github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// graphSections are the component sections that are included in the dependency graph.
+var graphSections = []string{"schemas", "parameters", "responses", "requestBodies"}
+
+// DependencyGraph returns the dependency graph of the schemas, parameters, responses and request bodies defined by
+// the Components. Every component is keyed by its local reference (for example '#/components/schemas/Pet') and maps
+// to the references of the components it uses directly, in the order they are first found. Components that use
+// nothing map to an empty slice, so unused components can be found by looking for keys that no component depends on.
+//
+// Only references to components of the included sections in the same document are recorded. Components defined as
+// a reference to another component depend on the component they reference. Use DependencyCycles to find cycles.
+func (c *Components) DependencyGraph() map[string][]string {
+	_, graph := c.dependencyGraph()
+	return graph
+}
+
+// DependencyCycles returns every cycle found in the DependencyGraph of the Components. Each cycle is a list of
+// references, where each one depends on the next and the last depends on the first. A component that depends on
+// itself is returned as a cycle of one.
+func (c *Components) DependencyCycles() [][]string {
+	keys, graph := c.dependencyGraph()
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(key string)
+	visit = func(key string) {
+		state[key] = visiting
+		stack = append(stack, key)
+		for _, dep := range graph[key] {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dep {
+						cycles = append(cycles, append([]string(nil), stack[i:]...))
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[key] = done
+	}
+	for _, key := range keys {
+		if state[key] == unvisited {
+			visit(key)
+		}
+	}
+	return cycles
+}
+
+// dependencyGraph returns the keys of the graph in the order the components are defined, along with the graph.
+func (c *Components) dependencyGraph() ([]string, map[string][]string) {
+	graph := make(map[string][]string)
+	var keys []string
+	if c == nil {
+		return keys, graph
+	}
+
+	var sections struct{ Parameters, Responses, RequestBodies *yaml.Node }
+	if l := c.GoLow(); l != nil {
+		sections.Parameters = l.Parameters.ValueNode
+		sections.Responses = l.Responses.ValueNode
+		sections.RequestBodies = l.RequestBodies.ValueNode
+	}
+	add := func(section, name string, refs *componentRefs) {
+		key := "#/components/" + section + "/" + name
+		keys = append(keys, key)
+		graph[key] = refs.refs
+	}
+	for pair := orderedmap.First(c.Schemas); pair != nil; pair = pair.Next() {
+		refs := newComponentRefs()
+		refs.addSchema(pair.Value())
+		add("schemas", pair.Key(), refs)
+	}
+	for pair := orderedmap.First(c.Parameters); pair != nil; pair = pair.Next() {
+		refs := newComponentRefs()
+		if p := pair.Value(); p != nil {
+			if ref := componentReference(sections.Parameters, pair.Key()); ref != "" {
+				refs.add(ref)
+			} else {
+				refs.addSchema(p.Schema)
+				refs.addContent(p.Content)
+			}
+		}
+		add("parameters", pair.Key(), refs)
+	}
+	for pair := orderedmap.First(c.Responses); pair != nil; pair = pair.Next() {
+		refs := newComponentRefs()
+		if r := pair.Value(); r != nil {
+			if ref := componentReference(sections.Responses, pair.Key()); ref != "" {
+				refs.add(ref)
+			} else {
+				for h := orderedmap.First(r.Headers); h != nil; h = h.Next() {
+					if h.Value() != nil {
+						refs.addSchema(h.Value().Schema)
+						refs.addContent(h.Value().Content)
+					}
+				}
+				refs.addContent(r.Content)
+			}
+		}
+		add("responses", pair.Key(), refs)
+	}
+	for pair := orderedmap.First(c.RequestBodies); pair != nil; pair = pair.Next() {
+		refs := newComponentRefs()
+		if rb := pair.Value(); rb != nil {
+			if ref := componentReference(sections.RequestBodies, pair.Key()); ref != "" {
+				refs.add(ref)
+			} else {
+				refs.addContent(rb.Content)
+			}
+		}
+		add("requestBodies", pair.Key(), refs)
+	}
+	return keys, graph
+}
+
+// componentReference returns the reference the component name is defined as in the YAML node of its section, or an
+// empty string if the component is not a reference. The low level model resolves these references when building.
+func componentReference(section *yaml.Node, name string) string {
+	if section == nil {
+		return ""
+	}
+	for i := 0; i < len(section.Content)-1; i += 2 {
+		if section.Content[i].Value == name {
+			_, _, ref := utils.IsNodeRefValue(section.Content[i+1])
+			return ref
+		}
+	}
+	return ""
+}
+
+// componentRefs collects the unique component references used by a single component.
+type componentRefs struct {
+	refs []string
+	seen map[string]bool
+}
+
+func newComponentRefs() *componentRefs {
+	return &componentRefs{refs: []string{}, seen: make(map[string]bool)}
+}
+
+// add records ref, if it's a reference to a component of one of the graphSections.
+func (c *componentRefs) add(ref string) {
+	if c.seen[ref] || !isGraphReference(ref) {
+		return
+	}
+	c.seen[ref] = true
+	c.refs = append(c.refs, ref)
+}
+
+// addSchema records the schema itself if it's a reference, otherwise the references it makes directly.
+func (c *componentRefs) addSchema(sp *highbase.SchemaProxy) {
+	if sp == nil {
+		return
+	}
+	if sp.IsReference() {
+		c.add(sp.GetReference())
+		return
+	}
+	for _, ref := range sp.Schema().DirectReferences() {
+		c.add(ref)
+	}
+}
+
+func (c *componentRefs) addContent(content *orderedmap.Map[string, *MediaType]) {
+	for pair := orderedmap.First(content); pair != nil; pair = pair.Next() {
+		if pair.Value() != nil {
+			c.addSchema(pair.Value().Schema)
+		}
+	}
+}
+
+func isGraphReference(ref string) bool {
+	for _, section := range graphSections {
+		if strings.HasPrefix(ref, "#/components/"+section+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/datamodel/low"
 	v3 "github.com/pb33f/libopenapi/datamodel/low/v3"
 	"github.com/pb33f/libopenapi/index"
@@ -70,3 +72,138 @@ requestBodies:
 	assert.Equal(t, desired, strings.TrimSpace(string(dat)))
 	assert.NotNil(t, r.GoLowUntyped())
 }
+
+func TestComponents_DependencyGraph(t *testing.T) {
+	yml := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+        tags:
+          type: array
+          items:
+            $ref: '#/components/schemas/Tag'
+    Owner:
+      type: object
+      properties:
+        pets:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+    Tag:
+      type: string
+    Node:
+      properties:
+        next:
+          $ref: '#/components/schemas/Node'
+    Unused:
+      type: string
+  parameters:
+    tag:
+      name: tag
+      in: query
+      schema:
+        $ref: '#/components/schemas/Tag'
+  responses:
+    Pet:
+      description: a pet
+      headers:
+        X-Tag:
+          schema:
+            $ref: '#/components/schemas/Tag'
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'
+    AlsoPet:
+      $ref: '#/components/responses/Pet'
+  requestBodies:
+    Pet:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := v3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	comp := NewComponents(lDoc.Components.Value)
+
+	graph := comp.DependencyGraph()
+	assert.Len(t, graph, 9)
+	assert.Equal(t, []string{"#/components/schemas/Owner", "#/components/schemas/Tag"}, graph["#/components/schemas/Pet"])
+	assert.Equal(t, []string{"#/components/schemas/Pet"}, graph["#/components/schemas/Owner"])
+	assert.Empty(t, graph["#/components/schemas/Tag"])
+	assert.Empty(t, graph["#/components/schemas/Unused"])
+	assert.Equal(t, []string{"#/components/schemas/Tag"}, graph["#/components/parameters/tag"])
+	assert.Equal(t, []string{"#/components/schemas/Tag", "#/components/schemas/Pet"}, graph["#/components/responses/Pet"])
+	assert.Equal(t, []string{"#/components/responses/Pet"}, graph["#/components/responses/AlsoPet"])
+	assert.Equal(t, []string{"#/components/schemas/Pet"}, graph["#/components/requestBodies/Pet"])
+
+	assert.Equal(t, [][]string{
+		{"#/components/schemas/Pet", "#/components/schemas/Owner"},
+		{"#/components/schemas/Node"},
+	}, comp.DependencyCycles())
+}
+
+func TestComponents_DependencyGraph_Empty(t *testing.T) {
+	var comp *Components
+	assert.Empty(t, comp.DependencyGraph())
+	assert.Empty(t, comp.DependencyCycles())
+}
+
+func TestComponents_Counts(t *testing.T) {
+	yml := `schemas:
+  Pet:
+    type: object
+  Owner:
+    type: object
+parameters:
+  limit:
+    name: limit
+    in: query
+responses:
+  Error:
+    description: an error
+securitySchemes:
+  basic:
+    type: http
+    scheme: basic
+pathItems:
+  Pets:
+    get:
+      description: list pets`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndexWithConfig(&idxNode, index.CreateOpenAPIIndexConfig())
+
+	var n v3.Components
+	_ = low.BuildModel(idxNode.Content[0], &n)
+	_ = n.Build(context.Background(), idxNode.Content[0], idx)
+	comp := NewComponents(&n)
+
+	assert.Equal(t, 2, comp.SchemaCount())
+	assert.Equal(t, 1, comp.ParameterCount())
+	assert.Equal(t, 1, comp.ResponseCount())
+	assert.Equal(t, 1, comp.SecuritySchemeCount())
+	assert.Zero(t, comp.ExampleCount())
+	assert.Zero(t, comp.RequestBodyCount())
+	assert.Zero(t, comp.HeaderCount())
+	assert.Zero(t, comp.LinkCount())
+	assert.Zero(t, comp.CallbackCount())
+	assert.Equal(t, 1, comp.PathItemCount())
+	assert.Equal(t, "list pets", comp.PathItems.GetOrZero("Pets").Get.Description)
+
+	var empty *Components
+	assert.Zero(t, empty.SchemaCount())
+
+	// components that were not built from a low-level model are counted as they are.
+	created := &Components{Schemas: orderedmap.New[string, *base.SchemaProxy]()}
+	created.Schemas.Set("Pet", base.CreateSchemaProxy(&base.Schema{}))
+	assert.Equal(t, 1, created.SchemaCount())
+	assert.Zero(t, created.ParameterCount())
+}
@@ -11,6 +11,12 @@ package v3
 
 import (
 	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/pb33f/libopenapi/datamodel/high"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
@@ -18,6 +24,8 @@ import (
 	"github.com/pb33f/libopenapi/index"
 	"github.com/pb33f/libopenapi/json"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
+	"github.com/pb33f/libopenapi/what-changed/model"
 	"gopkg.in/yaml.v3"
 )
 
@@ -155,6 +163,166 @@ func (d *Document) GoLow() *low.Document {
 	return d.low
 }
 
+// GoLowUntyped returns the low-level Document that was used to create the high-level one, with no type
+func (d *Document) GoLowUntyped() any {
+	return d.low
+}
+
+// SpecVersion will parse the Version of the Document into its major, minor and patch parts. Leading and trailing
+// whitespace is ignored, as is a leading 'v' (e.g. 'v3.1.0'). A missing patch number (e.g. '3.1') is treated as zero.
+// Any pre-release or build suffix (e.g. '3.1.0-rc1') is ignored. If the version string is malformed, an error is
+// returned.
+func (d *Document) SpecVersion() (major, minor, patch int, err error) {
+	raw := strings.TrimSpace(d.Version)
+	v := strings.TrimPrefix(strings.TrimPrefix(raw, "v"), "V")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return 0, 0, 0, fmt.Errorf("unable to parse OpenAPI version '%s', version is empty", raw)
+	}
+	segments := strings.Split(v, ".")
+	if len(segments) > 3 {
+		return 0, 0, 0, fmt.Errorf("unable to parse OpenAPI version '%s', too many version segments", raw)
+	}
+	parts := make([]int, 3)
+	for i, seg := range segments {
+		n, convErr := strconv.Atoi(seg)
+		if convErr != nil || n < 0 {
+			return 0, 0, 0, fmt.Errorf("unable to parse OpenAPI version '%s', segment '%s' is not a number", raw, seg)
+		}
+		parts[i] = n
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// Is30 will return true if the Document is an OpenAPI 3.0.x specification.
+func (d *Document) Is30() bool {
+	major, minor, _, err := d.SpecVersion()
+	return err == nil && major == 3 && minor == 0
+}
+
+// Is31 will return true if the Document is an OpenAPI 3.1.x specification.
+func (d *Document) Is31() bool {
+	major, minor, _, err := d.SpecVersion()
+	return err == nil && major == 3 && minor == 1
+}
+
+// OpenAPI31SchemaDialect is the JSON Schema dialect of Schema Objects in an OpenAPI 3.1 document that does not declare
+// a 'jsonSchemaDialect'.
+const OpenAPI31SchemaDialect = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+// EffectiveSchemaDialect will return the JSON Schema dialect used by the Schema Objects of the Document, which is
+// the 'jsonSchemaDialect' of the Document when one is declared, or the default for its version otherwise
+// (OpenAPI31SchemaDialect). OpenAPI 3.0 has no dialect (its schemas are an extended subset of JSON Schema), so an
+// empty string is returned for a 3.0 Document.
+func (d *Document) EffectiveSchemaDialect() string {
+	if d.JsonSchemaDialect != "" {
+		return d.JsonSchemaDialect
+	}
+	if d.Is30() {
+		return ""
+	}
+	return OpenAPI31SchemaDialect
+}
+
+// RenameOperationId will locate the Operation with an operationId of oldId (in paths, webhooks and callbacks) and
+// rename it to newId using Operation.SetOperationId. An error is returned if no Operation uses oldId, or if newId is
+// already used by a different Operation.
+func (d *Document) RenameOperationId(oldId, newId string) error {
+	var target *Operation
+	for _, op := range d.collectOperations() {
+		switch op.OperationId {
+		case oldId:
+			if target == nil {
+				target = op
+			}
+		case newId:
+			return fmt.Errorf("unable to rename operationId '%s', '%s' is already used by another operation",
+				oldId, newId)
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("unable to rename operationId '%s', no operation uses it", oldId)
+	}
+	return target.SetOperationId(newId)
+}
+
+// collectOperations returns every Operation in the Document, including those in webhooks and callbacks.
+func (d *Document) collectOperations() []*Operation {
+	var ops []*Operation
+	seen := make(map[*PathItem]bool)
+	var walk func(pi *PathItem)
+	walk = func(pi *PathItem) {
+		if pi == nil || seen[pi] {
+			return
+		}
+		seen[pi] = true
+		for opPair := orderedmap.First(pi.GetOperations()); opPair != nil; opPair = opPair.Next() {
+			op := opPair.Value()
+			ops = append(ops, op)
+			for cbPair := orderedmap.First(op.Callbacks); cbPair != nil; cbPair = cbPair.Next() {
+				if cbPair.Value() == nil {
+					continue
+				}
+				for exPair := orderedmap.First(cbPair.Value().Expression); exPair != nil; exPair = exPair.Next() {
+					walk(exPair.Value())
+				}
+			}
+		}
+	}
+	if d.Paths != nil {
+		for pair := orderedmap.First(d.Paths.PathItems); pair != nil; pair = pair.Next() {
+			walk(pair.Value())
+		}
+	}
+	for pair := orderedmap.First(d.Webhooks); pair != nil; pair = pair.Next() {
+		walk(pair.Value())
+	}
+	return ops
+}
+
+// ResolveServerURLs will return the URL of every Server defined by the Document (expanded with the default value
+// of every variable), resolved against base. base is typically the location the document was retrieved from, and
+// is used to turn relative server URLs like '/v1' into absolute ones. If base is nil, URLs are returned unresolved.
+//
+// If the Document defines no servers, the URL of the default server ('/') is resolved. Servers with a URL that
+// cannot be expanded or parsed are skipped, use Server.ParsedURL to find out why.
+func (d *Document) ResolveServerURLs(base *url.URL) []string {
+	servers := d.Servers
+	if len(servers) == 0 {
+		servers = []*Server{{URL: "/"}}
+	}
+	var urls []string
+	for _, s := range servers {
+		if s == nil {
+			continue
+		}
+		u, err := s.ParsedURL()
+		if err != nil {
+			continue
+		}
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+		urls = append(urls, u.String())
+	}
+	return urls
+}
+
+// CompareDocuments will compare an original and an updated Document, and report every path, operation, parameter,
+// schema (and everything else) that was added, removed or modified. Each change is classified as breaking or not (for
+// example, removing a required parameter is breaking), and carries the line and column of the original and new values.
+//
+// Both documents must be backed by a low-level model (built via NewDocument), if either one is not, nil is returned.
+// nil is also returned if nothing has changed.
+func CompareDocuments(original, updated *Document) *model.DocumentChanges {
+	if original == nil || updated == nil || original.GoLow() == nil || updated.GoLow() == nil {
+		return nil
+	}
+	return model.CompareDocuments(original.GoLow(), updated.GoLow())
+}
+
 // Render will return a YAML representation of the Document object as a byte slice.
 func (d *Document) Render() ([]byte, error) {
 	return yaml.Marshal(d)
@@ -170,6 +338,71 @@ func (d *Document) RenderWithIndention(indent int) []byte {
 	return buf.Bytes()
 }
 
+// RenderOptions control how a Document is rendered, see RenderWithOptions.
+type RenderOptions struct {
+	// Indent is the number of spaces used to indent each level of nested YAML. When zero, the default indentation
+	// of Render (four spaces) is used.
+	Indent int
+
+	// JSONIndent is the number of spaces used to indent each level of nested JSON, see RenderJSONWithOptions. When
+	// zero, the JSON is compact.
+	JSONIndent int
+
+	// JSONEscapeHTML escapes the characters <, > and & in JSON strings, so the JSON can be embedded in HTML.
+	JSONEscapeHTML bool
+
+	// SortComponents renders the entries of every components map (schemas, parameters, responses etc.) in
+	// alphabetical order, instead of the order they appear in the source.
+	SortComponents bool
+
+	// JSON makes RenderTo write JSON (indented with JSONIndent) rather than YAML.
+	JSON bool
+}
+
+// RenderWithOptions will return a YAML representation of the Document object as a byte slice, rendered using opts.
+// The indentation applies to every nested level of the Document, including sequences.
+func (d *Document) RenderWithOptions(opts RenderOptions) ([]byte, error) {
+	if opts.Indent == 0 && !opts.SortComponents {
+		return d.Render()
+	}
+	opts.JSON = false
+	var buf bytes.Buffer
+	if err := d.RenderTo(&buf, &opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderTo writes a YAML (or JSON, when opts.JSON is set) representation of the Document object to w, rendered using
+// opts, in the same way as RenderWithOptions (or RenderJSONWithOptions). A nil opts renders using the defaults.
+//
+// RenderTo does not stream the document as it is built: the whole yaml.Node tree of the document is built in memory
+// before anything is written. What it saves is the rendered output, which is written to w as the tree is encoded,
+// rather than being collected into a single buffer. JSON is written straight from the node tree, without converting
+// it into another tree of values first.
+func (d *Document) RenderTo(w io.Writer, opts *RenderOptions) error {
+	if opts == nil {
+		opts = &RenderOptions{}
+	}
+	if opts.JSON {
+		return json.YAMLNodeToJSONTo(w, d.renderNode(*opts), json.Options{
+			Indent:     opts.JSONIndent,
+			EscapeHTML: opts.JSONEscapeHTML,
+		})
+	}
+	if opts.Indent < 0 {
+		return fmt.Errorf("unable to render document, indent cannot be negative (%d)", opts.Indent)
+	}
+	yamlEncoder := yaml.NewEncoder(w)
+	if opts.Indent > 0 {
+		yamlEncoder.SetIndent(opts.Indent)
+	}
+	if err := yamlEncoder.Encode(d.renderNode(*opts)); err != nil {
+		return err
+	}
+	return yamlEncoder.Close()
+}
+
 // RenderJSON will return a JSON representation of the Document object as a byte slice.
 func (d *Document) RenderJSON(indention string) ([]byte, error) {
 	nb := high.NewNodeBuilder(d, d.low)
@@ -181,6 +414,58 @@ func (d *Document) RenderJSON(indention string) ([]byte, error) {
 	return dat, nil
 }
 
+// RenderJSONWithOptions will return a JSON representation of the Document object as a byte slice, rendered using the
+// JSONIndent and JSONEscapeHTML values of opts. Unlike RenderJSON, numbers are rendered as they are written in the
+// specification (so 1.0 stays 1.0).
+func (d *Document) RenderJSONWithOptions(opts RenderOptions) ([]byte, error) {
+	opts.JSON = true
+	var buf bytes.Buffer
+	if err := d.RenderTo(&buf, &opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderNode builds the YAML node of the Document, ordered according to opts.
+func (d *Document) renderNode(opts RenderOptions) *yaml.Node {
+	nb := high.NewNodeBuilder(d, d.low)
+	n := nb.Render()
+	if opts.SortComponents {
+		sortComponents(n)
+	}
+	return n
+}
+
+// sortComponents sorts the entries of every map in the 'components' of the rendered document node n by name.
+// Extensions and the order of the component types themselves are left as they are.
+func sortComponents(n *yaml.Node) {
+	_, components := utils.FindKeyNodeTop(low.ComponentsLabel, n.Content)
+	if components == nil || components.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(components.Content); i += 2 {
+		if strings.HasPrefix(components.Content[i].Value, "x-") {
+			continue
+		}
+		m := components.Content[i+1]
+		if m.Kind != yaml.MappingNode {
+			continue
+		}
+		pairs := make([][2]*yaml.Node, 0, len(m.Content)/2)
+		for j := 0; j+1 < len(m.Content); j += 2 {
+			pairs = append(pairs, [2]*yaml.Node{m.Content[j], m.Content[j+1]})
+		}
+		sort.SliceStable(pairs, func(a, b int) bool {
+			return pairs[a][0].Value < pairs[b][0].Value
+		})
+		content := make([]*yaml.Node, 0, len(m.Content))
+		for _, p := range pairs {
+			content = append(content, p[0], p[1])
+		}
+		m.Content = content
+	}
+}
+
 func (d *Document) RenderInline() ([]byte, error) {
 	di, _ := d.MarshalYAMLInline()
 	return yaml.Marshal(di)
@@ -0,0 +1,344 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of DeprecatedItem.
+const (
+	DeprecatedOperation = "operation"
+	DeprecatedParameter = "parameter"
+	DeprecatedHeader    = "header"
+	DeprecatedSchema    = "schema"
+)
+
+// DeprecatedItem is an element of a Document that is marked as 'deprecated: true'.
+type DeprecatedItem struct {
+	// Kind is one of 'operation', 'parameter', 'header' or 'schema'.
+	Kind string
+
+	// Path is a JSON pointer to the deprecated element, for example '/paths/~1pets/get/parameters/0'.
+	Path string
+
+	// Line and Column are the position of the 'deprecated' key in the specification, zero if unknown.
+	Line   int
+	Column int
+}
+
+// DeprecatedItems will return every operation, parameter, header and schema (including schema properties) in the
+// Document that is marked as deprecated, ordered by the line they are defined on.
+//
+// A deprecated element that is referenced (for example, a parameter or schema in the components) is reported once,
+// with the path to where it's defined.
+func (d *Document) DeprecatedItems() []DeprecatedItem {
+	w := &deprecationWalker{seen: make(map[any]bool)}
+
+	if c := d.Components; c != nil {
+		for pair := orderedmap.First(c.Schemas); pair != nil; pair = pair.Next() {
+			w.schemaProxy(pair.Value(), joinPointer("", "components", "schemas", pair.Key()))
+		}
+		for pair := orderedmap.First(c.Parameters); pair != nil; pair = pair.Next() {
+			w.parameter(pair.Value(), joinPointer("", "components", "parameters", pair.Key()))
+		}
+		for pair := orderedmap.First(c.Headers); pair != nil; pair = pair.Next() {
+			w.header(pair.Value(), joinPointer("", "components", "headers", pair.Key()))
+		}
+		for pair := orderedmap.First(c.RequestBodies); pair != nil; pair = pair.Next() {
+			if pair.Value() != nil {
+				w.content(pair.Value().Content, joinPointer("", "components", "requestBodies", pair.Key(), "content"))
+			}
+		}
+		for pair := orderedmap.First(c.Responses); pair != nil; pair = pair.Next() {
+			w.response(pair.Value(), joinPointer("", "components", "responses", pair.Key()))
+		}
+		for pair := orderedmap.First(c.Callbacks); pair != nil; pair = pair.Next() {
+			w.callback(pair.Value(), joinPointer("", "components", "callbacks", pair.Key()))
+		}
+	}
+	if d.Paths != nil {
+		for pair := orderedmap.First(d.Paths.PathItems); pair != nil; pair = pair.Next() {
+			w.pathItem(pair.Value(), joinPointer("", "paths", pair.Key()))
+		}
+	}
+	for pair := orderedmap.First(d.Webhooks); pair != nil; pair = pair.Next() {
+		w.pathItem(pair.Value(), joinPointer("", "webhooks", pair.Key()))
+	}
+
+	slices.SortStableFunc(w.items, func(a, b DeprecatedItem) int {
+		return a.Line - b.Line
+	})
+	return w.items
+}
+
+// UsesDeprecatedSchemas will return the paths of every deprecated schema used by the request and responses of the
+// Operation: the schemas of its parameters (including those of its path item it doesn't override, see
+// EffectiveParameters), its request body, and the content and headers of its responses. References are followed, so a schema that is only deprecated in the components (or
+// a deprecated property of one) is found too.
+//
+// Each path is a JSON pointer to where the deprecated schema is defined. Inline schemas are located from the
+// operation, for example '/paths/~1pets/get/requestBody/content/application~1json/schema', and schemas reached by a
+// reference are located from the reference, for example '/components/schemas/Pet/properties/tag'. References to
+// other files keep the location of the file, for example 'pets.yaml#/Pet'. The operation is found in the paths and
+// webhooks of doc, if it can't be found (or doc is nil) inline schemas are located relative to the operation, and
+// the parameters of its path item are not checked. Each path is returned once, in the order it's found.
+func (o *Operation) UsesDeprecatedSchemas(doc *Document) []string {
+	if o == nil {
+		return nil
+	}
+	var path string
+	var parent *PathItem
+	if doc != nil {
+		visit := func(items *orderedmap.Map[string, *PathItem], section string) {
+			for pair := orderedmap.First(items); pair != nil && parent == nil; pair = pair.Next() {
+				if pair.Value() == nil {
+					continue
+				}
+				for op := orderedmap.First(pair.Value().GetOperations()); op != nil; op = op.Next() {
+					if op.Value() == o {
+						parent, path = pair.Value(), joinPointer("", section, pair.Key(), op.Key())
+						break
+					}
+				}
+			}
+		}
+		if doc.Paths != nil {
+			visit(doc.Paths.PathItems, "paths")
+		}
+		visit(doc.Webhooks, "webhooks")
+	}
+
+	w := &deprecationWalker{seen: make(map[any]bool), followReferences: true}
+	for _, p := range o.EffectiveParameters(parent) {
+		if i := slices.Index(o.Parameters, p); i >= 0 {
+			w.parameter(p, joinPointer(path, "parameters", fmt.Sprint(i)))
+		} else {
+			parentPath := path[:strings.LastIndex(path, "/")]
+			w.parameter(p, joinPointer(parentPath, "parameters", fmt.Sprint(slices.Index(parent.Parameters, p))))
+		}
+	}
+	w.bodyAndResponses(o, path)
+
+	var paths []string
+	for _, item := range w.items {
+		if item.Kind == DeprecatedSchema && !slices.Contains(paths, item.Path) {
+			paths = append(paths, item.Path)
+		}
+	}
+	return paths
+}
+
+type deprecationWalker struct {
+	items []DeprecatedItem
+	seen  map[any]bool
+
+	// followReferences walks referenced schemas where they are referenced, rather than where they are defined.
+	followReferences bool
+}
+
+// add records a deprecated item, once per 'deprecated' node (or object, if there is no node).
+func (w *deprecationWalker) add(kind, path string, obj any, keyNode *yaml.Node) {
+	id := obj
+	if keyNode != nil {
+		id = keyNode
+	}
+	if w.seen[id] {
+		return
+	}
+	w.seen[id] = true
+	item := DeprecatedItem{Kind: kind, Path: path}
+	if keyNode != nil {
+		item.Line, item.Column = keyNode.Line, keyNode.Column
+	}
+	w.items = append(w.items, item)
+}
+
+func (w *deprecationWalker) pathItem(pi *PathItem, path string) {
+	if pi == nil || w.seen[pi] {
+		return
+	}
+	w.seen[pi] = true
+	for i, p := range pi.Parameters {
+		w.parameter(p, joinPointer(path, "parameters", fmt.Sprint(i)))
+	}
+	for pair := orderedmap.First(pi.GetOperations()); pair != nil; pair = pair.Next() {
+		w.operation(pair.Value(), joinPointer(path, pair.Key()))
+	}
+}
+
+func (w *deprecationWalker) operation(op *Operation, path string) {
+	if op == nil {
+		return
+	}
+	if op.Deprecated != nil && *op.Deprecated {
+		var keyNode *yaml.Node
+		if op.GoLow() != nil {
+			keyNode = op.GoLow().Deprecated.KeyNode
+		}
+		w.add(DeprecatedOperation, path, op, keyNode)
+	}
+	w.requestAndResponses(op, path)
+	for pair := orderedmap.First(op.Callbacks); pair != nil; pair = pair.Next() {
+		w.callback(pair.Value(), joinPointer(path, "callbacks", pair.Key()))
+	}
+}
+
+// requestAndResponses walks the parameters, request body and responses of an operation.
+func (w *deprecationWalker) requestAndResponses(op *Operation, path string) {
+	for i, p := range op.Parameters {
+		w.parameter(p, joinPointer(path, "parameters", fmt.Sprint(i)))
+	}
+	w.bodyAndResponses(op, path)
+}
+
+// bodyAndResponses walks the request body and responses of op, but not its parameters.
+func (w *deprecationWalker) bodyAndResponses(op *Operation, path string) {
+	if op.RequestBody != nil {
+		w.content(op.RequestBody.Content, joinPointer(path, "requestBody", "content"))
+	}
+	if op.Responses != nil {
+		w.response(op.Responses.Default, joinPointer(path, "responses", "default"))
+		for pair := orderedmap.First(op.Responses.Codes); pair != nil; pair = pair.Next() {
+			w.response(pair.Value(), joinPointer(path, "responses", pair.Key()))
+		}
+	}
+}
+
+func (w *deprecationWalker) callback(cb *Callback, path string) {
+	if cb == nil {
+		return
+	}
+	for pair := orderedmap.First(cb.Expression); pair != nil; pair = pair.Next() {
+		w.pathItem(pair.Value(), joinPointer(path, pair.Key()))
+	}
+}
+
+func (w *deprecationWalker) parameter(p *Parameter, path string) {
+	if p == nil {
+		return
+	}
+	if p.Deprecated {
+		var keyNode *yaml.Node
+		if p.GoLow() != nil {
+			keyNode = p.GoLow().Deprecated.KeyNode
+		}
+		w.add(DeprecatedParameter, path, p, keyNode)
+	}
+	w.schemaProxy(p.Schema, joinPointer(path, "schema"))
+	w.content(p.Content, joinPointer(path, "content"))
+}
+
+func (w *deprecationWalker) header(h *Header, path string) {
+	if h == nil {
+		return
+	}
+	if h.Deprecated {
+		var keyNode *yaml.Node
+		if h.GoLow() != nil {
+			keyNode = h.GoLow().Deprecated.KeyNode
+		}
+		w.add(DeprecatedHeader, path, h, keyNode)
+	}
+	w.schemaProxy(h.Schema, joinPointer(path, "schema"))
+	w.content(h.Content, joinPointer(path, "content"))
+}
+
+func (w *deprecationWalker) response(r *Response, path string) {
+	if r == nil {
+		return
+	}
+	for pair := orderedmap.First(r.Headers); pair != nil; pair = pair.Next() {
+		w.header(pair.Value(), joinPointer(path, "headers", pair.Key()))
+	}
+	w.content(r.Content, joinPointer(path, "content"))
+}
+
+func (w *deprecationWalker) content(content *orderedmap.Map[string, *MediaType], path string) {
+	for pair := orderedmap.First(content); pair != nil; pair = pair.Next() {
+		if pair.Value() != nil {
+			w.schemaProxy(pair.Value().Schema, joinPointer(path, pair.Key(), "schema"))
+		}
+	}
+}
+
+// schemaProxy walks an inline schema, references are reported where they are defined (in the components), and are
+// only walked when following references.
+func (w *deprecationWalker) schemaProxy(sp *base.SchemaProxy, path string) {
+	if sp == nil {
+		return
+	}
+	if sp.IsReference() {
+		if !w.followReferences {
+			return
+		}
+		path = sp.GetReference()
+		if strings.HasPrefix(path, "#") {
+			path = path[1:]
+		}
+	}
+	s := sp.Schema()
+	if s == nil || w.seen[s] {
+		return
+	}
+	w.seen[s] = true
+	if s.Deprecated != nil && *s.Deprecated {
+		var keyNode *yaml.Node
+		if s.GoLow() != nil {
+			keyNode = s.GoLow().Deprecated.KeyNode
+		}
+		w.add(DeprecatedSchema, path, s, keyNode)
+	}
+
+	proxies := func(name string, list []*base.SchemaProxy) {
+		for i, p := range list {
+			w.schemaProxy(p, joinPointer(path, name, fmt.Sprint(i)))
+		}
+	}
+	schemas := func(name string, m *orderedmap.Map[string, *base.SchemaProxy]) {
+		for pair := orderedmap.First(m); pair != nil; pair = pair.Next() {
+			w.schemaProxy(pair.Value(), joinPointer(path, name, pair.Key()))
+		}
+	}
+	schemas("properties", s.Properties)
+	schemas("patternProperties", s.PatternProperties)
+	schemas("dependentSchemas", s.DependentSchemas)
+	proxies("allOf", s.AllOf)
+	proxies("oneOf", s.OneOf)
+	proxies("anyOf", s.AnyOf)
+	proxies("prefixItems", s.PrefixItems)
+	if s.Items != nil && s.Items.IsA() {
+		w.schemaProxy(s.Items.A, joinPointer(path, "items"))
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.IsA() {
+		w.schemaProxy(s.AdditionalProperties.A, joinPointer(path, "additionalProperties"))
+	}
+	if s.UnevaluatedProperties != nil && s.UnevaluatedProperties.IsA() {
+		w.schemaProxy(s.UnevaluatedProperties.A, joinPointer(path, "unevaluatedProperties"))
+	}
+	w.schemaProxy(s.Not, joinPointer(path, "not"))
+	w.schemaProxy(s.Contains, joinPointer(path, "contains"))
+	w.schemaProxy(s.If, joinPointer(path, "if"))
+	w.schemaProxy(s.Then, joinPointer(path, "then"))
+	w.schemaProxy(s.Else, joinPointer(path, "else"))
+	w.schemaProxy(s.PropertyNames, joinPointer(path, "propertyNames"))
+	w.schemaProxy(s.UnevaluatedItems, joinPointer(path, "unevaluatedItems"))
+}
+
+// joinPointer appends escaped segments to a JSON pointer.
+func joinPointer(path string, segments ...string) string {
+	var b strings.Builder
+	b.WriteString(path)
+	for _, s := range segments {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
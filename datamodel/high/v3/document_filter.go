@@ -0,0 +1,225 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	low "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// operationKeys are the keys of a path item that hold an operation.
+var operationKeys = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true,
+	"trace": true,
+}
+
+// FilterByTag will return a new Document, that only contains the operations of the Document (in its paths and
+// webhooks) tagged with tag, and the components they use. The supplied document is not modified, it is rendered,
+// filtered and then re-built into a brand-new Document.
+//
+// Everything else at the top of the document (like info, servers and security) is carried over as it is. Paths and
+// webhooks left without an operation are removed, and so are the declared tags no remaining operation uses. Path
+// items that are local references are copied in place of the reference, so only their tagged operations are kept.
+// Components that are no longer used are pruned (see UnusedComponents), so the result can be rendered as a
+// standalone specification. If no operation is tagged with tag, the paths of the new Document are empty.
+func (d *Document) FilterByTag(tag string) (*Document, error) {
+	return d.filter(nil, func(op *yaml.Node) bool {
+		return operationTags(op)[tag]
+	})
+}
+
+// FilterByPathPrefix will return a new Document, that only contains the paths of the Document under prefix (for
+// example '/admin'), and the components they use. The supplied document is not modified, it is rendered, filtered
+// and then re-built into a brand-new Document.
+//
+// Paths are compared segment by segment, so '/admin' matches '/admin' and '/admin/users', but not '/administrators'.
+// Trailing and repeated slashes are ignored, and templated segments match whatever their parameters are named, so
+// '/users/{id}' matches '/users/{userId}/orders'. Webhooks are named rather than located by a path, so they are
+// never matched against prefix: all of them are kept when keepWebhooks is true, and none of them are otherwise.
+// Everything else is carried over and pruned the same way as FilterByTag.
+func (d *Document) FilterByPathPrefix(prefix string, keepWebhooks bool) (*Document, error) {
+	prefixSegments := normalizedPathSegments(prefix)
+	return d.filter(func(section, path string) bool {
+		if section == low.WebhooksLabel {
+			return keepWebhooks
+		}
+		segments := normalizedPathSegments(path)
+		return len(segments) >= len(prefixSegments) && slices.Equal(segments[:len(prefixSegments)], prefixSegments)
+	}, nil)
+}
+
+// normalizedPathSegments splits path into its segments, without empty segments, and with the names of the
+// parameters of every templated segment removed.
+func normalizedPathSegments(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, pathParamRegex.ReplaceAllString(segment, "{}"))
+		}
+	}
+	return segments
+}
+
+// filter returns a new Document, with only the path items (of the paths and webhooks) that keepItem returns true
+// for, and only their operations that keepOperation returns true for. keepItem is given the section the path item is
+// in ('paths' or 'webhooks') and its name. A nil function keeps everything. Path items left without an operation,
+// tags no operation uses and components that are no longer used are removed. The new Document is built with the
+// configuration of d, so circular references it ignores are ignored in the new Document as well.
+func (d *Document) filter(keepItem func(section, name string) bool, keepOperation func(op *yaml.Node) bool,
+) (*Document, error) {
+	if d == nil {
+		return nil, errors.New("unable to filter document, document is nil")
+	}
+	rendered, err := d.Render()
+	if err != nil {
+		return nil, fmt.Errorf("unable to filter document, cannot render: %w", err)
+	}
+	var root yaml.Node
+	if err = yaml.Unmarshal(rendered, &root); err != nil {
+		return nil, fmt.Errorf("unable to filter document, cannot parse rendered document: %w", err)
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("unable to filter document, rendered document is not a map")
+	}
+
+	top := root.Content[0]
+	used := make(map[string]bool)
+	for i := 0; i+1 < len(top.Content); i += 2 {
+		switch section := top.Content[i].Value; section {
+		case low.PathsLabel, low.WebhooksLabel:
+			filterPathItems(top, section, top.Content[i+1], keepItem, keepOperation, used)
+		}
+	}
+	if _, webhooks := utils.FindKeyNodeTop("webhooks", top.Content); webhooks != nil &&
+		webhooks.Kind == yaml.MappingNode && len(webhooks.Content) == 0 {
+		utils.RemoveKeyNodes(top, "webhooks")
+	}
+	if _, tags := utils.FindKeyNodeTop("tags", top.Content); tags != nil && tags.Kind == yaml.SequenceNode {
+		kept := tags.Content[:0]
+		for _, t := range tags.Content {
+			if _, name := utils.FindKeyNodeTop("name", t.Content); name != nil && used[name.Value] {
+				kept = append(kept, t)
+			}
+		}
+		tags.Content = kept
+		if len(tags.Content) == 0 {
+			utils.RemoveKeyNodes(top, "tags")
+		}
+	}
+
+	filtered, err := buildFiltered(&root, d)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = filtered.PruneUnusedComponents(); err != nil {
+		return nil, fmt.Errorf("unable to filter document, cannot prune components: %w", err)
+	}
+
+	// build the pruned document again, so its index only knows about the components that are left.
+	rendered, err = filtered.Render()
+	if err != nil {
+		return nil, fmt.Errorf("unable to filter document, cannot render filtered document: %w", err)
+	}
+	var pruned yaml.Node
+	if err = yaml.Unmarshal(rendered, &pruned); err != nil {
+		return nil, fmt.Errorf("unable to filter document, cannot parse filtered document: %w", err)
+	}
+	return buildFiltered(&pruned, d)
+}
+
+// filterPathItems removes the path items in items (the value of section) that keepItem returns false for, the
+// operations of every path item that keepOperation returns false for, and the path items left without an operation.
+// Tags of the operations that are kept are added to used.
+func filterPathItems(top *yaml.Node, section string, items *yaml.Node, keepItem func(section, name string) bool,
+	keepOperation func(op *yaml.Node) bool, used map[string]bool,
+) {
+	if items.Kind != yaml.MappingNode {
+		return
+	}
+	kept := items.Content[:0]
+	for i := 0; i+1 < len(items.Content); i += 2 {
+		key, item := items.Content[i], items.Content[i+1]
+		if keepItem != nil && !keepItem(section, key.Value) {
+			continue
+		}
+		if _, ref := utils.FindKeyNodeTop("$ref", item.Content); ref != nil && len(ref.Value) > 0 &&
+			ref.Value[0] == '#' {
+			if target := locatePointer(top, ref.Value[1:]); target != nil && target.Kind == yaml.MappingNode {
+				item = copyNode(target)
+			}
+		}
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		content := item.Content[:0]
+		operations := 0
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			field, value := item.Content[j], item.Content[j+1]
+			if operationKeys[field.Value] {
+				if keepOperation != nil && !keepOperation(value) {
+					continue
+				}
+				for t := range operationTags(value) {
+					used[t] = true
+				}
+				operations++
+			}
+			content = append(content, field, value)
+		}
+		item.Content = content
+		if operations > 0 {
+			kept = append(kept, key, item)
+		}
+	}
+	items.Content = kept
+}
+
+// operationTags returns the tags of an operation node.
+func operationTags(op *yaml.Node) map[string]bool {
+	tags := make(map[string]bool)
+	if op.Kind != yaml.MappingNode {
+		return tags
+	}
+	if _, list := utils.FindKeyNodeTop("tags", op.Content); list != nil {
+		for _, t := range list.Content {
+			tags[t.Value] = true
+		}
+	}
+	return tags
+}
+
+// copyNode returns a deep copy of node, so it can be changed without changing the node it was copied from.
+func copyNode(node *yaml.Node) *yaml.Node {
+	c := *node
+	c.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		c.Content[i] = copyNode(child)
+	}
+	return &c
+}
+
+// buildFiltered builds a new Document from root, with the configuration used to build doc. Errors the original
+// document was built with (like circular references) are not a failure.
+func buildFiltered(root *yaml.Node, doc *Document) (*Document, error) {
+	filtered, err := yaml.Marshal(root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to filter document, cannot render filtered document: %w", err)
+	}
+	info, err := datamodel.ExtractSpecInfo(filtered)
+	if err != nil {
+		return nil, fmt.Errorf("unable to filter document, cannot read filtered document: %w", err)
+	}
+	lowDoc, err := rebuildDocument(info, doc)
+	if lowDoc == nil {
+		return nil, fmt.Errorf("unable to filter document, cannot build filtered document: %w", err)
+	}
+	return NewDocument(lowDoc), nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RefError is a local reference that can't be found, reported by ValidateLocalReferences.
+type RefError struct {
+	// Ref is the value of the '$ref', for example '#/components/schemas/Pett'.
+	Ref string
+
+	// Path is a JSON pointer to the object containing the '$ref', for example '/paths/~1pets/get/responses/200'.
+	Path string
+
+	// Line and Column are the position of the '$ref' value in the specification.
+	Line   int
+	Column int
+}
+
+func (e RefError) Error() string {
+	return fmt.Sprintf("%s: reference '%s' cannot be found [%d:%d]", e.Path, e.Ref, e.Line, e.Column)
+}
+
+// ValidateLocalReferences will check that every local reference of the Document (a '$ref' that starts with '#')
+// points to a node that exists in the specification, and return a RefError for every one that doesn't, in the
+// order they are found. A mistyped reference, like '#/components/schemas/Pett', would otherwise quietly produce
+// nothing when the model is built. References to other files are not checked, and neither are the references
+// made in those files. Example values ('example', the 'examples' of a schema and the 'value' of an Example object)
+// are literal data, so a '$ref' key inside them is not a reference and is skipped. The Document must have been
+// built from a specification, otherwise nil is returned.
+func (d *Document) ValidateLocalReferences() []RefError {
+	if d.low == nil || d.low.Index == nil || d.low.Index.GetRootNode() == nil {
+		return nil
+	}
+	root := d.low.Index.GetRootNode()
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	var errs []RefError
+	seen := make(map[*yaml.Node]bool)
+	var walk func(node *yaml.Node, path string, kind refWalkKind)
+	walk = func(node *yaml.Node, path string, kind refWalkKind) {
+		if node == nil || seen[node] {
+			return
+		}
+		seen[node] = true
+		switch node.Kind {
+		case yaml.AliasNode:
+			walk(node.Alias, path, kind)
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				keyPath := joinPointer(path, key.Value)
+				switch kind {
+				case refWalkNames:
+					walk(value, keyPath, refWalkObject)
+					continue
+				case refWalkExamples:
+					walk(value, keyPath, refWalkExample)
+					continue
+				}
+				if key.Value == "$ref" && value.Kind == yaml.ScalarNode && strings.HasPrefix(value.Value, "#") {
+					if locatePointer(root, value.Value[1:]) == nil {
+						errs = append(errs, RefError{Ref: value.Value, Path: path, Line: value.Line,
+							Column: value.Column})
+					}
+					continue
+				}
+				switch {
+				case key.Value == "example" || (key.Value == "value" && kind == refWalkExample):
+					// literal example data.
+				case key.Value == "examples":
+					// a map of Example objects, or the list of literal examples of a schema.
+					if value.Kind == yaml.MappingNode {
+						walk(value, keyPath, refWalkExamples)
+					}
+				case namedEntryKeys[key.Value]:
+					walk(value, keyPath, refWalkNames)
+				default:
+					walk(value, keyPath, refWalkObject)
+				}
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				walk(item, joinPointer(path, strconv.Itoa(i)), refWalkObject)
+			}
+		}
+	}
+	walk(root, "", refWalkObject)
+	return errs
+}
+
+// refWalkKind is what the keys of a mapping are, when walking the specification for references.
+type refWalkKind int
+
+const (
+	refWalkObject   refWalkKind = iota // the keys are keywords.
+	refWalkNames                       // the keys are names, each value is an object.
+	refWalkExamples                    // the keys are names, each value is an Example object.
+	refWalkExample                     // an Example object, its 'value' is literal data.
+)
+
+// namedEntryKeys are the keywords whose value is a map keyed by names rather than keywords, so a name like 'example'
+// is not mistaken for the keyword.
+var namedEntryKeys = map[string]bool{
+	"paths": true, "webhooks": true, "schemas": true, "responses": true, "parameters": true, "requestBodies": true,
+	"headers": true, "securitySchemes": true, "links": true, "callbacks": true, "pathItems": true, "content": true,
+	"encoding": true, "properties": true, "patternProperties": true, "dependentSchemas": true, "$defs": true,
+	"definitions": true,
+}
+
+// locatePointer returns the node the JSON pointer (the fragment of a reference, without the '#') points to in root,
+// or nil if there is no such node. The pointer is split into tokens before each one is unescaped, so an escaped '/'
+// (%2F or ~1) stays part of its token.
+func locatePointer(root *yaml.Node, pointer string) *yaml.Node {
+	if pointer == "" {
+		return root
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil
+	}
+	node := root
+	for _, segment := range strings.Split(pointer[1:], "/") {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segment = unescaped
+		}
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == segment {
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(segment); err == nil && i >= 0 && i < len(node.Content) {
+				next = node.Content[i]
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"slices"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// AllMediaTypes returns every media type (for example 'application/json') used by the 'content' of a request body or
+// response in the Document, deduplicated and sorted. Request bodies and responses of operations in paths, webhooks
+// and callbacks are included, as are those in components (including the operations of callbacks in components).
+func (d *Document) AllMediaTypes() []string {
+	seen := make(map[string]bool)
+	addContent := func(content *orderedmap.Map[string, *MediaType]) {
+		for pair := orderedmap.First(content); pair != nil; pair = pair.Next() {
+			seen[pair.Key()] = true
+		}
+	}
+	addResponses := func(responses *Responses) {
+		if responses == nil {
+			return
+		}
+		if responses.Default != nil {
+			addContent(responses.Default.Content)
+		}
+		for pair := orderedmap.First(responses.Codes); pair != nil; pair = pair.Next() {
+			if pair.Value() != nil {
+				addContent(pair.Value().Content)
+			}
+		}
+	}
+	addOperation := func(op *Operation) {
+		if op.RequestBody != nil {
+			addContent(op.RequestBody.Content)
+		}
+		addResponses(op.Responses)
+	}
+
+	for _, op := range d.collectOperations() {
+		addOperation(op)
+	}
+	if c := d.Components; c != nil {
+		for pair := orderedmap.First(c.RequestBodies); pair != nil; pair = pair.Next() {
+			if pair.Value() != nil {
+				addContent(pair.Value().Content)
+			}
+		}
+		for pair := orderedmap.First(c.Responses); pair != nil; pair = pair.Next() {
+			if pair.Value() != nil {
+				addContent(pair.Value().Content)
+			}
+		}
+		for pair := orderedmap.First(c.Callbacks); pair != nil; pair = pair.Next() {
+			if pair.Value() == nil {
+				continue
+			}
+			for exp := orderedmap.First(pair.Value().Expression); exp != nil; exp = exp.Next() {
+				if exp.Value() == nil {
+					continue
+				}
+				for op := orderedmap.First(exp.Value().GetOperations()); op != nil; op = op.Next() {
+					addOperation(op.Value())
+				}
+			}
+		}
+	}
+
+	mediaTypes := make([]string, 0, len(seen))
+	for mediaType := range seen {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	slices.Sort(mediaTypes)
+	return mediaTypes
+}
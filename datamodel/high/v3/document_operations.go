@@ -0,0 +1,130 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"fmt"
+	"strings"
+
+	lowV3 "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// OperationRef is an Operation of a Document, with the HTTP method and path it's defined under.
+type OperationRef struct {
+	// Operation is the operation itself.
+	Operation *Operation
+
+	// Method is the (lowercase) HTTP method of the operation, for example 'get'.
+	Method string
+
+	// Path is the path template the operation is defined under, for example '/pets/{petId}'.
+	Path string
+
+	// PathItem is the path item that owns the operation.
+	PathItem *PathItem
+}
+
+// GetAllOperations returns every operation defined by the paths of the Document as a flat list. Operations are
+// ordered by the order their paths are defined in, and then by method in the order the specification lists them
+// (get, put, post, delete, options, head, patch, trace). Webhooks are not included.
+func (d *Document) GetAllOperations() []OperationRef {
+	var ops []OperationRef
+	if d.Paths == nil {
+		return ops
+	}
+	for pair := orderedmap.First(d.Paths.PathItems); pair != nil; pair = pair.Next() {
+		pi := pair.Value()
+		if pi == nil {
+			continue
+		}
+		for _, m := range []struct {
+			method string
+			op     *Operation
+		}{
+			{lowV3.GetLabel, pi.Get},
+			{lowV3.PutLabel, pi.Put},
+			{lowV3.PostLabel, pi.Post},
+			{lowV3.DeleteLabel, pi.Delete},
+			{lowV3.OptionsLabel, pi.Options},
+			{lowV3.HeadLabel, pi.Head},
+			{lowV3.PatchLabel, pi.Patch},
+			{lowV3.TraceLabel, pi.Trace},
+		} {
+			if m.op != nil {
+				ops = append(ops, OperationRef{Operation: m.op, Method: m.method, Path: pair.Key(), PathItem: pi})
+			}
+		}
+	}
+	return ops
+}
+
+// OperationCount returns the number of operations defined by the paths of the Document, the same operations returned
+// by GetAllOperations, without building the list. Webhooks are not included. The low-level Paths is counted when
+// there is one, so no high-level objects are built.
+func (d *Document) OperationCount() int {
+	if d.Paths == nil {
+		return 0
+	}
+	if d.Paths.low != nil {
+		return d.Paths.low.OperationCount()
+	}
+	count := 0
+	for pair := orderedmap.First(d.Paths.PathItems); pair != nil; pair = pair.Next() {
+		pi := pair.Value()
+		if pi == nil {
+			continue
+		}
+		for _, op := range []*Operation{pi.Get, pi.Put, pi.Post, pi.Delete, pi.Options, pi.Head, pi.Patch, pi.Trace} {
+			if op != nil {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// OperationsWithoutId returns every operation defined by the paths of the Document that has no operationId, in the
+// same order as GetAllOperations.
+func (d *Document) OperationsWithoutId() []OperationRef {
+	var missing []OperationRef
+	for _, op := range d.GetAllOperations() {
+		if strings.TrimSpace(op.Operation.OperationId) == "" {
+			missing = append(missing, op)
+		}
+	}
+	return missing
+}
+
+// GenerateOperationIds will set the operationId of every operation returned by OperationsWithoutId, to the id
+// returned by strategy for the method and path of the operation. The low-level model is updated as well (see
+// Operation.SetOperationId), so the new ids are rendered.
+//
+// Generated ids are unique, if an id is already used by another operation (including those of webhooks and
+// callbacks) a numeric suffix is added, for example 'getPets_2'. An error is returned if strategy returns an empty
+// id, operations that were already given an id keep it.
+func (d *Document) GenerateOperationIds(strategy func(method, path string) string) error {
+	used := make(map[string]bool)
+	for _, op := range d.collectOperations() {
+		if op.OperationId != "" {
+			used[op.OperationId] = true
+		}
+	}
+	for _, op := range d.OperationsWithoutId() {
+		id := strings.TrimSpace(strategy(op.Method, op.Path))
+		if id == "" {
+			return fmt.Errorf("unable to generate an operationId for '%s %s', the strategy returned an empty id",
+				strings.ToUpper(op.Method), op.Path)
+		}
+		unique := id
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s_%d", id, i)
+		}
+		if err := op.Operation.SetOperationId(unique); err != nil {
+			return err
+		}
+		used[unique] = true
+	}
+	return nil
+}
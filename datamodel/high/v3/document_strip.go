@@ -0,0 +1,330 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	low "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// StripExamples will remove every 'example' and 'examples' from the Document, across schemas, parameters, headers
+// and media types, as well as the examples defined by the components. Useful for slimming down a specification
+// before it's distributed.
+//
+// The values are removed from both the high-level model and the low-level yaml nodes, so they are not rendered.
+// Only the root document is changed, anything referenced from another file is left alone.
+func (d *Document) StripExamples() {
+	d.walkModel(func(obj any) {
+		switch o := obj.(type) {
+		case *base.SchemaProxy:
+			if s := o.Schema(); s != nil {
+				s.StripExamples()
+			}
+		case *Parameter:
+			o.Example, o.Examples = nil, nil
+			removeLowKeys(o, low.ExampleLabel, low.ExamplesLabel)
+		case *Header:
+			o.Example, o.Examples = nil, nil
+			removeLowKeys(o, low.ExampleLabel, low.ExamplesLabel)
+		case *MediaType:
+			o.Example, o.Examples = nil, nil
+			removeLowKeys(o, low.ExampleLabel, low.ExamplesLabel)
+		case *Components:
+			o.Examples = nil
+			removeLowKeys(o, low.ExamplesLabel)
+		}
+	})
+}
+
+// StripDescriptions will remove every 'description' from the Document (info, tags, servers, operations, parameters,
+// schemas etc.), useful for slimming down a specification before it's distributed. The descriptions of responses
+// are required by the specification, so they are kept.
+//
+// The values are removed from both the high-level model and the low-level yaml nodes, so they are not rendered.
+// Only the root document is changed, anything referenced from another file is left alone.
+func (d *Document) StripDescriptions() {
+	d.walkModel(func(obj any) {
+		switch o := obj.(type) {
+		case *base.SchemaProxy:
+			if s := o.Schema(); s != nil {
+				s.StripDescriptions()
+			}
+		case *base.Info:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		case *base.Tag:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		case *base.ExternalDoc:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		case *base.Example:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		case *Server:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+			for pair := orderedmap.First(o.Variables); pair != nil; pair = pair.Next() {
+				pair.Value().Description = ""
+			}
+			if o.GoLow() != nil && o.GoLow().RootNode != nil {
+				_, vars := utils.FindKeyNodeTop(low.VariablesLabel, o.GoLow().RootNode.Content)
+				for i := 1; vars != nil && i < len(vars.Content); i += 2 {
+					utils.RemoveKeyNodes(vars.Content[i], low.DescriptionLabel)
+				}
+			}
+		case *PathItem:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		case *Operation:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		case *Parameter:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		case *RequestBody:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		case *Header:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		case *Link:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		case *SecurityScheme:
+			o.Description = ""
+			removeLowKeys(o, low.DescriptionLabel)
+		}
+	})
+}
+
+// StripExtensions will remove every extension that starts with any of the prefixes (or every extension, starting
+// with 'x-', if no prefixes are provided) from every object in the Document, including schemas. For example,
+// StripExtensions("x-internal-") will remove 'x-internal-owner' but keep 'x-logo'. Returns the number of extensions
+// that were removed.
+//
+// The extensions are removed from both the high-level model and the low-level yaml nodes, so they are not rendered.
+// Only the root document is changed, anything referenced from another file is left alone.
+func (d *Document) StripExtensions(prefixes ...string) int {
+	removed := high.RemoveExtensions(d, prefixes...)
+	if d.low != nil && d.low.Index != nil && d.low.Index.GetRootNode() != nil {
+		// the low-level document has no root node of its own, the top level of the specification is used.
+		if len(prefixes) == 0 {
+			prefixes = []string{"x-"}
+		}
+		root := d.low.Index.GetRootNode()
+		if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+			root = root.Content[0]
+		}
+		utils.RemoveKeyNodesWithPrefix(root, prefixes...)
+	}
+	d.walkModel(func(obj any) {
+		if sp, ok := obj.(*base.SchemaProxy); ok {
+			if s := sp.Schema(); s != nil {
+				removed += s.StripExtensions(prefixes...)
+			}
+			return
+		}
+		removed += high.RemoveExtensions(obj, prefixes...)
+	})
+	return removed
+}
+
+// removeLowKeys removes keys from the root node of the low-level model backing a high-level one (if there is one).
+func removeLowKeys(h interface{ GoLowUntyped() any }, keys ...string) {
+	utils.RemoveKeyNodes(lowRootNode(h), keys...)
+}
+
+// walkModel calls visit once for every object in the Document that can have a description, examples or extensions
+// (other than the Document itself). Schemas are visited as a *base.SchemaProxy (references are not visited, they are
+// found in the components).
+func (d *Document) walkModel(visit func(obj any)) {
+	seen := make(map[any]bool)
+	once := func(obj any) bool {
+		if seen[obj] {
+			return false
+		}
+		seen[obj] = true
+		visit(obj)
+		return true
+	}
+
+	var walkPathItem func(pi *PathItem)
+	var walkMediaTypes func(m *orderedmap.Map[string, *MediaType])
+
+	walkSchema := func(sp *base.SchemaProxy) {
+		if sp != nil && !sp.IsReference() {
+			once(sp)
+		}
+	}
+	walkExamples := func(m *orderedmap.Map[string, *base.Example]) {
+		for pair := orderedmap.First(m); pair != nil; pair = pair.Next() {
+			if pair.Value() != nil {
+				once(pair.Value())
+			}
+		}
+	}
+	walkExternalDocs := func(ed *base.ExternalDoc) {
+		if ed != nil {
+			once(ed)
+		}
+	}
+	walkServers := func(servers []*Server) {
+		for _, s := range servers {
+			if s != nil {
+				once(s)
+			}
+		}
+	}
+	walkHeaders := func(m *orderedmap.Map[string, *Header]) {
+		for pair := orderedmap.First(m); pair != nil; pair = pair.Next() {
+			h := pair.Value()
+			if h == nil || !once(h) {
+				continue
+			}
+			walkSchema(h.Schema)
+			walkExamples(h.Examples)
+			walkMediaTypes(h.Content)
+		}
+	}
+	walkMediaTypes = func(m *orderedmap.Map[string, *MediaType]) {
+		for pair := orderedmap.First(m); pair != nil; pair = pair.Next() {
+			mt := pair.Value()
+			if mt == nil || !once(mt) {
+				continue
+			}
+			walkSchema(mt.Schema)
+			walkExamples(mt.Examples)
+			for enc := orderedmap.First(mt.Encoding); enc != nil; enc = enc.Next() {
+				if enc.Value() != nil {
+					walkHeaders(enc.Value().Headers)
+				}
+			}
+		}
+	}
+	walkParameter := func(p *Parameter) {
+		if p == nil || !once(p) {
+			return
+		}
+		walkSchema(p.Schema)
+		walkExamples(p.Examples)
+		walkMediaTypes(p.Content)
+	}
+	walkRequestBody := func(rb *RequestBody) {
+		if rb != nil && once(rb) {
+			walkMediaTypes(rb.Content)
+		}
+	}
+	walkLink := func(l *Link) {
+		if l != nil && once(l) && l.Server != nil {
+			once(l.Server)
+		}
+	}
+	walkResponse := func(r *Response) {
+		if r == nil || !once(r) {
+			return
+		}
+		walkHeaders(r.Headers)
+		walkMediaTypes(r.Content)
+		for pair := orderedmap.First(r.Links); pair != nil; pair = pair.Next() {
+			walkLink(pair.Value())
+		}
+	}
+	walkCallback := func(cb *Callback) {
+		if cb == nil || !once(cb) {
+			return
+		}
+		for pair := orderedmap.First(cb.Expression); pair != nil; pair = pair.Next() {
+			walkPathItem(pair.Value())
+		}
+	}
+	walkPathItem = func(pi *PathItem) {
+		if pi == nil || !once(pi) {
+			return
+		}
+		walkServers(pi.Servers)
+		for _, p := range pi.Parameters {
+			walkParameter(p)
+		}
+		for pair := orderedmap.First(pi.GetOperations()); pair != nil; pair = pair.Next() {
+			op := pair.Value()
+			if !once(op) {
+				continue
+			}
+			walkExternalDocs(op.ExternalDocs)
+			walkServers(op.Servers)
+			for _, p := range op.Parameters {
+				walkParameter(p)
+			}
+			walkRequestBody(op.RequestBody)
+			if op.Responses != nil && once(op.Responses) {
+				walkResponse(op.Responses.Default)
+				for r := orderedmap.First(op.Responses.Codes); r != nil; r = r.Next() {
+					walkResponse(r.Value())
+				}
+			}
+			for cb := orderedmap.First(op.Callbacks); cb != nil; cb = cb.Next() {
+				walkCallback(cb.Value())
+			}
+		}
+	}
+
+	if d.Info != nil {
+		once(d.Info)
+	}
+	walkServers(d.Servers)
+	for _, t := range d.Tags {
+		if t != nil && once(t) {
+			walkExternalDocs(t.ExternalDocs)
+		}
+	}
+	walkExternalDocs(d.ExternalDocs)
+	if d.Paths != nil && once(d.Paths) {
+		for pair := orderedmap.First(d.Paths.PathItems); pair != nil; pair = pair.Next() {
+			walkPathItem(pair.Value())
+		}
+	}
+	for pair := orderedmap.First(d.Webhooks); pair != nil; pair = pair.Next() {
+		walkPathItem(pair.Value())
+	}
+	if c := d.Components; c != nil && once(c) {
+		for pair := orderedmap.First(c.Schemas); pair != nil; pair = pair.Next() {
+			walkSchema(pair.Value())
+		}
+		for pair := orderedmap.First(c.Responses); pair != nil; pair = pair.Next() {
+			walkResponse(pair.Value())
+		}
+		for pair := orderedmap.First(c.Parameters); pair != nil; pair = pair.Next() {
+			walkParameter(pair.Value())
+		}
+		walkExamples(c.Examples)
+		for pair := orderedmap.First(c.RequestBodies); pair != nil; pair = pair.Next() {
+			walkRequestBody(pair.Value())
+		}
+		walkHeaders(c.Headers)
+		for pair := orderedmap.First(c.SecuritySchemes); pair != nil; pair = pair.Next() {
+			ss := pair.Value()
+			if ss == nil || !once(ss) || ss.Flows == nil || !once(ss.Flows) {
+				continue
+			}
+			for _, flow := range []*OAuthFlow{
+				ss.Flows.Implicit, ss.Flows.Password, ss.Flows.ClientCredentials, ss.Flows.AuthorizationCode,
+			} {
+				if flow != nil {
+					once(flow)
+				}
+			}
+		}
+		for pair := orderedmap.First(c.Links); pair != nil; pair = pair.Next() {
+			walkLink(pair.Value())
+		}
+		for pair := orderedmap.First(c.Callbacks); pair != nil; pair = pair.Next() {
+			walkCallback(pair.Value())
+		}
+	}
+}
@@ -0,0 +1,499 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	lowmodel "github.com/pb33f/libopenapi/datamodel/low"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError is a structural problem found in a Document by ValidateDocumentStructure.
+type ValidationError struct {
+	// Message describes the problem, for example "'title' is required".
+	Message string
+
+	// Path is a JSON pointer to the element with the problem, for example '/paths/~1pets/get/parameters/0'.
+	Path string
+
+	// Line and Column are the position of the problem in the specification, zero if unknown. A missing value is
+	// reported at the position of the object that should contain it.
+	Line   int
+	Column int
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s [%d:%d]", e.Path, e.Message, e.Line, e.Column)
+}
+
+var componentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+
+// knownSchemaDialects are the JSON Schema dialects a 'jsonSchemaDialect' is expected to be (without a trailing '#').
+var knownSchemaDialects = map[string]bool{
+	OpenAPI31SchemaDialect:                         true,
+	base.DefaultJSONSchemaDialect:                  true,
+	"https://json-schema.org/draft/2019-09/schema": true,
+	"http://json-schema.org/draft-07/schema":       true,
+	"http://json-schema.org/draft-06/schema":       true,
+	"http://json-schema.org/draft-04/schema":       true,
+}
+
+// ValidateDocumentStructure will check that the Document conforms to the structure the OpenAPI 3.0 / 3.1
+// specification requires of it, and return every problem found, ordered by the line they are found on. This is
+// not a validation of payloads or schemas, it checks things like:
+//   - required fields are present and are strings ('openapi', 'info.title', 'info.version', parameter names etc.)
+//   - 'paths' is present in 3.0, and at least one of 'paths', 'webhooks' or 'components' is present in 3.1
+//   - paths start with '/', and path template variables match the path parameters of every operation
+//   - parameters are 'in' query, header, path or cookie, path parameters are required, and parameters and headers
+//     have exactly one of 'schema' or 'content'
+//   - operations have responses (in 3.0), and operationIds and tag names are unique
+//   - responses have a description, request bodies have content
+//   - component names are valid, and security schemes have a valid type and the fields it requires
+//   - 'jsonSchemaDialect' is only used in 3.1, is a URI, and is a recognized JSON Schema dialect
+//
+// Line numbers are only known when the Document is built from a specification.
+func ValidateDocumentStructure(doc *Document) []ValidationError {
+	if doc == nil {
+		return nil
+	}
+	v := &structureValidator{operationIds: make(map[string]bool), seen: make(map[any]bool)}
+	v.is31 = !doc.Is30()
+
+	var root *yaml.Node
+	if doc.low != nil && doc.low.Index != nil {
+		root = doc.low.Index.GetRootNode()
+		if root != nil && root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+			root = root.Content[0]
+		}
+	}
+	v.requireString(root, "", "openapi", doc.Version)
+	if doc.Version != "" {
+		if major, _, _, err := doc.SpecVersion(); err != nil {
+			v.add("/openapi", valueNode(root, "openapi"), "%s", err.Error())
+		} else if major != 3 {
+			v.add("/openapi", valueNode(root, "openapi"), "version '%s' is not an OpenAPI 3 version", doc.Version)
+		}
+	}
+
+	if doc.Info == nil {
+		v.add("", root, "'info' is required")
+	} else {
+		v.info(doc.Info)
+	}
+	if v.is31 {
+		if doc.Paths == nil && doc.Webhooks == nil && doc.Components == nil {
+			v.add("", root, "at least one of 'paths', 'webhooks' or 'components' is required")
+		}
+	} else if doc.Paths == nil {
+		v.add("", root, "'paths' is required")
+	}
+
+	for i, s := range doc.Servers {
+		v.server(s, joinPointer("", "servers", fmt.Sprint(i)))
+	}
+	v.externalDoc(doc.ExternalDocs, "/externalDocs")
+	v.schemaDialect(doc.JsonSchemaDialect, valueNode(root, "jsonSchemaDialect"))
+	tags := make(map[string]bool)
+	for i, t := range doc.Tags {
+		if t == nil {
+			continue
+		}
+		path := joinPointer("", "tags", fmt.Sprint(i))
+		v.requireString(lowRootNode(t), path, "name", t.Name)
+		if t.Name != "" && tags[t.Name] {
+			v.add(path, valueNode(lowRootNode(t), "name"), "tag '%s' is defined more than once", t.Name)
+		}
+		tags[t.Name] = true
+		v.externalDoc(t.ExternalDocs, joinPointer(path, "externalDocs"))
+	}
+
+	if doc.Paths != nil {
+		for pair := orderedmap.First(doc.Paths.PathItems); pair != nil; pair = pair.Next() {
+			path := joinPointer("", "paths", pair.Key())
+			if !strings.HasPrefix(pair.Key(), "/") {
+				v.add(path, lowRootNode(pair.Value()), "path '%s' must start with '/'", pair.Key())
+			}
+			v.pathItem(pair.Value(), path, pair.Key())
+		}
+	}
+	for pair := orderedmap.First(doc.Webhooks); pair != nil; pair = pair.Next() {
+		v.pathItem(pair.Value(), joinPointer("", "webhooks", pair.Key()), "")
+	}
+	v.components(doc.Components)
+
+	slices.SortStableFunc(v.errs, func(a, b ValidationError) int {
+		return a.Line - b.Line
+	})
+	return v.errs
+}
+
+type structureValidator struct {
+	errs         []ValidationError
+	is31         bool
+	operationIds map[string]bool
+	seen         map[any]bool
+}
+
+func (v *structureValidator) add(path string, node *yaml.Node, format string, args ...any) {
+	e := ValidationError{Message: fmt.Sprintf(format, args...), Path: path}
+	if node != nil {
+		e.Line, e.Column = node.Line, node.Column
+	}
+	v.errs = append(v.errs, e)
+}
+
+// requireString checks the string field key of the object at root is present (and is a string). When there is no
+// root node (the object was not built from a specification) the high-level value is checked instead.
+func (v *structureValidator) requireString(root *yaml.Node, path, key, value string) {
+	if root == nil {
+		if value == "" {
+			v.add(path, nil, "'%s' is required", key)
+		}
+		return
+	}
+	node := valueNode(root, key)
+	if node == nil {
+		v.add(path, root, "'%s' is required", key)
+		return
+	}
+	if node.Kind != yaml.ScalarNode {
+		v.add(joinPointer(path, key), node, "'%s' must be a string", key)
+	}
+}
+
+func (v *structureValidator) info(info *base.Info) {
+	root := lowRootNode(info)
+	v.requireString(root, "/info", "title", info.Title)
+	v.requireString(root, "/info", "version", info.Version)
+	if l := info.License; l != nil {
+		v.requireString(lowRootNode(l), "/info/license", "name", l.Name)
+		if l.URL != "" && l.Identifier != "" {
+			v.add("/info/license", lowRootNode(l), "'url' and 'identifier' are mutually exclusive")
+		}
+	}
+}
+
+func (v *structureValidator) server(s *Server, path string) {
+	if s == nil {
+		return
+	}
+	v.requireString(lowRootNode(s), path, "url", s.URL)
+	for pair := orderedmap.First(s.Variables); pair != nil; pair = pair.Next() {
+		if pair.Value() != nil && pair.Value().Default == "" {
+			v.add(joinPointer(path, "variables", pair.Key()), lowRootNode(s), "'default' is required")
+		}
+	}
+}
+
+func (v *structureValidator) schemaDialect(dialect string, node *yaml.Node) {
+	if dialect == "" {
+		return
+	}
+	if !v.is31 {
+		v.add("/jsonSchemaDialect", node, "'jsonSchemaDialect' requires OpenAPI 3.1")
+		return
+	}
+	if u, err := url.Parse(dialect); err != nil || !u.IsAbs() {
+		v.add("/jsonSchemaDialect", node, "'jsonSchemaDialect' must be a URI, not '%s'", dialect)
+		return
+	}
+	if !knownSchemaDialects[strings.TrimSuffix(dialect, "#")] {
+		v.add("/jsonSchemaDialect", node, "'jsonSchemaDialect' '%s' is not a recognized JSON Schema dialect", dialect)
+	}
+}
+
+func (v *structureValidator) externalDoc(ed *base.ExternalDoc, path string) {
+	if ed != nil {
+		v.requireString(lowRootNode(ed), path, "url", ed.URL)
+	}
+}
+
+func (v *structureValidator) pathItem(pi *PathItem, path, template string) {
+	if pi == nil || v.seen[pi] {
+		return
+	}
+	v.seen[pi] = true
+	for i, s := range pi.Servers {
+		v.server(s, joinPointer(path, "servers", fmt.Sprint(i)))
+	}
+	v.parameters(pi.Parameters, path)
+	for pair := orderedmap.First(pi.GetOperations()); pair != nil; pair = pair.Next() {
+		v.operation(pair.Value(), pi, joinPointer(path, pair.Key()), template)
+	}
+}
+
+func (v *structureValidator) operation(op *Operation, pi *PathItem, path, template string) {
+	if op == nil {
+		return
+	}
+	root := lowRootNode(op)
+	if op.OperationId != "" {
+		if v.operationIds[op.OperationId] {
+			v.add(joinPointer(path, "operationId"), valueNode(root, "operationId"),
+				"operationId '%s' is used by more than one operation", op.OperationId)
+		}
+		v.operationIds[op.OperationId] = true
+	}
+	v.externalDoc(op.ExternalDocs, joinPointer(path, "externalDocs"))
+	for i, s := range op.Servers {
+		v.server(s, joinPointer(path, "servers", fmt.Sprint(i)))
+	}
+	v.parameters(op.Parameters, path)
+
+	// every variable of the path template must be a path parameter, and every path parameter a template variable.
+	if template != "" {
+		pathParams := make(map[string]bool)
+		for _, p := range append(append([]*Parameter(nil), pi.Parameters...), op.Parameters...) {
+			if p != nil && p.In == "path" {
+				pathParams[p.Name] = true
+			}
+		}
+		variables := make(map[string]bool)
+		for _, seg := range strings.Split(template, "/") {
+			for s := seg; strings.Contains(s, "{"); {
+				start := strings.Index(s, "{")
+				end := strings.Index(s[start:], "}")
+				if end < 0 {
+					break
+				}
+				variables[s[start+1:start+end]] = true
+				s = s[start+end+1:]
+			}
+		}
+		for _, name := range sortedKeys(variables) {
+			if !pathParams[name] {
+				v.add(path, root, "path parameter '%s' is used by the path, but is not defined", name)
+			}
+		}
+		for _, name := range sortedKeys(pathParams) {
+			if !variables[name] {
+				v.add(path, root, "path parameter '%s' is defined, but is not used by the path", name)
+			}
+		}
+	}
+
+	if rb := op.RequestBody; rb != nil {
+		if orderedmap.Len(rb.Content) == 0 {
+			v.add(joinPointer(path, "requestBody"), lowRootNode(rb), "'content' is required")
+		}
+	}
+	if op.Responses == nil {
+		if !v.is31 {
+			v.add(path, root, "'responses' is required")
+		}
+	} else {
+		if !v.is31 && op.Responses.Default == nil && orderedmap.Len(op.Responses.Codes) == 0 {
+			v.add(joinPointer(path, "responses"), lowRootNode(op.Responses), "at least one response is required")
+		}
+		v.response(op.Responses.Default, joinPointer(path, "responses", "default"))
+		for pair := orderedmap.First(op.Responses.Codes); pair != nil; pair = pair.Next() {
+			v.response(pair.Value(), joinPointer(path, "responses", pair.Key()))
+		}
+	}
+	for pair := orderedmap.First(op.Callbacks); pair != nil; pair = pair.Next() {
+		if pair.Value() == nil {
+			continue
+		}
+		for exp := orderedmap.First(pair.Value().Expression); exp != nil; exp = exp.Next() {
+			v.pathItem(exp.Value(), joinPointer(path, "callbacks", pair.Key(), exp.Key()), "")
+		}
+	}
+}
+
+func (v *structureValidator) parameters(params []*Parameter, path string) {
+	defined := make(map[string]bool)
+	for i, p := range params {
+		if p == nil {
+			continue
+		}
+		pPath := joinPointer(path, "parameters", fmt.Sprint(i))
+		v.parameter(p, pPath)
+		if id := p.In + ":" + p.Name; p.Name != "" && defined[id] {
+			v.add(pPath, lowRootNode(p), "parameter '%s' in '%s' is defined more than once", p.Name, p.In)
+		} else {
+			defined[id] = true
+		}
+	}
+}
+
+func (v *structureValidator) parameter(p *Parameter, path string) {
+	root := lowRootNode(p)
+	v.requireString(root, path, "name", p.Name)
+	v.requireString(root, path, "in", p.In)
+	switch p.In {
+	case "", "query", "header", "cookie":
+	case "path":
+		if p.Required == nil || !*p.Required {
+			v.add(path, root, "path parameter '%s' must be required", p.Name)
+		}
+	default:
+		v.add(joinPointer(path, "in"), valueNode(root, "in"),
+			"'in' must be one of 'query', 'header', 'path' or 'cookie', not '%s'", p.In)
+	}
+	v.schemaOrContent(p.Schema, p.Content, root, path)
+}
+
+func (v *structureValidator) header(h *Header, path string) {
+	if h == nil {
+		return
+	}
+	v.schemaOrContent(h.Schema, h.Content, lowRootNode(h), path)
+}
+
+func (v *structureValidator) schemaOrContent(schema *base.SchemaProxy, content *orderedmap.Map[string, *MediaType], root *yaml.Node, path string) {
+	n := orderedmap.Len(content)
+	switch {
+	case schema != nil && n > 0:
+		v.add(path, root, "'schema' and 'content' are mutually exclusive")
+	case schema == nil && n == 0:
+		v.add(path, root, "one of 'schema' or 'content' is required")
+	case n > 1:
+		v.add(joinPointer(path, "content"), valueNode(root, "content"), "'content' must contain exactly one entry")
+	}
+}
+
+func (v *structureValidator) response(r *Response, path string) {
+	if r == nil || v.seen[r] {
+		return
+	}
+	v.seen[r] = true
+	v.requireString(lowRootNode(r), path, "description", r.Description)
+	for pair := orderedmap.First(r.Headers); pair != nil; pair = pair.Next() {
+		v.header(pair.Value(), joinPointer(path, "headers", pair.Key()))
+	}
+}
+
+func (v *structureValidator) components(c *Components) {
+	if c == nil {
+		return
+	}
+	var sections *yaml.Node
+	if c.low != nil {
+		sections = c.low.RootNode
+	}
+	checkNames := func(section string, names []string) {
+		_, entries := utils.FindKeyNodeTop(section, nodeContent(sections))
+		for _, name := range names {
+			if !componentNamePattern.MatchString(name) {
+				keyNode, _ := utils.FindKeyNodeTop(name, nodeContent(entries))
+				v.add(joinPointer("", "components", section, name), keyNode,
+					"component name '%s' may only contain letters, digits, '.', '-' and '_'", name)
+			}
+		}
+	}
+	checkNames("schemas", keysOf(c.Schemas))
+	checkNames("responses", keysOf(c.Responses))
+	checkNames("parameters", keysOf(c.Parameters))
+	checkNames("examples", keysOf(c.Examples))
+	checkNames("requestBodies", keysOf(c.RequestBodies))
+	checkNames("headers", keysOf(c.Headers))
+	checkNames("securitySchemes", keysOf(c.SecuritySchemes))
+	checkNames("links", keysOf(c.Links))
+	checkNames("callbacks", keysOf(c.Callbacks))
+
+	for pair := orderedmap.First(c.Responses); pair != nil; pair = pair.Next() {
+		v.response(pair.Value(), joinPointer("", "components", "responses", pair.Key()))
+	}
+	for pair := orderedmap.First(c.Parameters); pair != nil; pair = pair.Next() {
+		if pair.Value() != nil {
+			v.parameter(pair.Value(), joinPointer("", "components", "parameters", pair.Key()))
+		}
+	}
+	for pair := orderedmap.First(c.RequestBodies); pair != nil; pair = pair.Next() {
+		if pair.Value() != nil && orderedmap.Len(pair.Value().Content) == 0 {
+			v.add(joinPointer("", "components", "requestBodies", pair.Key()), lowRootNode(pair.Value()),
+				"'content' is required")
+		}
+	}
+	for pair := orderedmap.First(c.Headers); pair != nil; pair = pair.Next() {
+		v.header(pair.Value(), joinPointer("", "components", "headers", pair.Key()))
+	}
+	for pair := orderedmap.First(c.SecuritySchemes); pair != nil; pair = pair.Next() {
+		v.securityScheme(pair.Value(), joinPointer("", "components", "securitySchemes", pair.Key()))
+	}
+}
+
+func (v *structureValidator) securityScheme(ss *SecurityScheme, path string) {
+	if ss == nil {
+		return
+	}
+	root := lowRootNode(ss)
+	v.requireString(root, path, "type", ss.Type)
+	switch ss.Type {
+	case "":
+	case "apiKey":
+		v.requireString(root, path, "name", ss.Name)
+		v.requireString(root, path, "in", ss.In)
+		if ss.In != "" && ss.In != "query" && ss.In != "header" && ss.In != "cookie" {
+			v.add(joinPointer(path, "in"), valueNode(root, "in"),
+				"'in' must be one of 'query', 'header' or 'cookie', not '%s'", ss.In)
+		}
+	case "http":
+		v.requireString(root, path, "scheme", ss.Scheme)
+	case "oauth2":
+		if ss.Flows == nil {
+			v.add(path, root, "'flows' is required")
+		}
+	case "openIdConnect":
+		v.requireString(root, path, "openIdConnectUrl", ss.OpenIdConnectUrl)
+	case "mutualTLS":
+		if !v.is31 {
+			v.add(joinPointer(path, "type"), valueNode(root, "type"), "type 'mutualTLS' requires OpenAPI 3.1")
+		}
+	default:
+		v.add(joinPointer(path, "type"), valueNode(root, "type"),
+			"'type' must be one of 'apiKey', 'http', 'mutualTLS', 'oauth2' or 'openIdConnect', not '%s'", ss.Type)
+	}
+}
+
+// lowRootNode returns the root node of the low-level model backing a high-level one, nil if there isn't one.
+func lowRootNode(h interface{ GoLowUntyped() any }) *yaml.Node {
+	if h == nil || reflect.ValueOf(h).IsNil() {
+		return nil
+	}
+	l, ok := h.GoLowUntyped().(lowmodel.HasRootNode)
+	if !ok || reflect.ValueOf(l).IsNil() {
+		return nil
+	}
+	return l.GetRootNode()
+}
+
+// valueNode returns the value of key in the mapping node root, nil if there isn't one.
+func valueNode(root *yaml.Node, key string) *yaml.Node {
+	_, node := utils.FindKeyNodeTop(key, nodeContent(root))
+	return node
+}
+
+func nodeContent(node *yaml.Node) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+	return node.Content
+}
+
+func keysOf[V any](m *orderedmap.Map[string, V]) []string {
+	var keys []string
+	for pair := orderedmap.First(m); pair != nil; pair = pair.Next() {
+		keys = append(keys, pair.Key())
+	}
+	return keys
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
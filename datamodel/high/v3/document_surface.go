@@ -0,0 +1,292 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	lowmodel "github.com/pb33f/libopenapi/datamodel/low"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// surfaceIgnoredKeywords are the schema keywords that document a schema, rather than change what it accepts.
+var surfaceIgnoredKeywords = map[string]bool{
+	"description": true, "title": true, "example": true, "examples": true, "externalDocs": true,
+	"$comment": true, "deprecated": true, "$id": true, "$anchor": true, "$schema": true,
+}
+
+// surfaceSchemaMaps are the schema keywords whose value is a map of schemas, keyed by a name.
+var surfaceSchemaMaps = map[string]bool{
+	"properties": true, "patternProperties": true, "$defs": true, "definitions": true, "dependentSchemas": true,
+}
+
+// surfaceSchemaLists are the schema keywords whose value is a list of schemas.
+var surfaceSchemaLists = map[string]bool{
+	"allOf": true, "anyOf": true, "oneOf": true, "prefixItems": true,
+}
+
+// surfaceSchemas are the schema keywords whose value is a schema (or a boolean).
+var surfaceSchemas = map[string]bool{
+	"items": true, "additionalProperties": true, "not": true, "if": true, "then": true, "else": true,
+	"contains": true, "propertyNames": true, "unevaluatedItems": true, "unevaluatedProperties": true,
+	"additionalItems": true, "contentSchema": true,
+}
+
+// SurfaceHash will return a SHA256 hash of the API surface of the Document: the paths, the methods of their
+// operations, the parameters (including those inherited from the path), request bodies, the status codes of the
+// responses, their headers, and the media types and schemas of all of them. Everything that only documents the API
+// is left out, like descriptions, summaries, titles, examples, external docs, operationIds and extensions, so two
+// specifications that only differ in their documentation have the same surface hash.
+//
+// The hash doesn't depend on the order things are defined in, or on the names of the components that are
+// referenced, as references are followed (a reference that loops back on itself is hashed by how far back it loops).
+// Webhooks, callbacks, servers and security requirements are not part of the surface.
+func (d *Document) SurfaceHash() [32]byte {
+	surface := make(map[string]any)
+	if d.Paths != nil {
+		for pathPair := orderedmap.First(d.Paths.PathItems); pathPair != nil; pathPair = pathPair.Next() {
+			pathItem := pathPair.Value()
+			if pathItem == nil {
+				continue
+			}
+			operations := make(map[string]any)
+			for opPair := orderedmap.First(pathItem.GetOperations()); opPair != nil; opPair = opPair.Next() {
+				operations[opPair.Key()] = surfaceOperation(opPair.Value(), pathItem)
+			}
+			surface[pathPair.Key()] = operations
+		}
+	}
+	b, err := json.Marshal(surface)
+	if err != nil {
+		// every value in the surface is made safe to marshal, so this is never expected, but the error is hashed
+		// rather than an empty surface.
+		b = []byte(err.Error())
+	}
+	return sha256.Sum256(b)
+}
+
+func surfaceOperation(op *Operation, pathItem *PathItem) map[string]any {
+	params := make(map[string]any)
+	for _, p := range op.EffectiveParameters(pathItem) {
+		style, explode := p.EffectiveStyle()
+		name := p.Name
+		if p.In == "header" {
+			name = strings.ToLower(name) // header names are case-insensitive.
+		}
+		params[p.In+":"+name] = map[string]any{
+			"required":        p.In == "path" || (p.Required != nil && *p.Required),
+			"style":           style,
+			"explode":         explode,
+			"allowEmptyValue": p.AllowEmptyValue,
+			"allowReserved":   p.AllowReserved,
+			"schema":          surfaceSchema(p.Schema),
+			"content":         surfaceContent(p.Content),
+		}
+	}
+	projected := map[string]any{"parameters": params}
+	if op.RequestBody != nil {
+		projected["requestBody"] = map[string]any{
+			"required": op.RequestBody.Required != nil && *op.RequestBody.Required,
+			"content":  surfaceContent(op.RequestBody.Content),
+		}
+	}
+	if op.Responses != nil {
+		responses := make(map[string]any)
+		for pair := orderedmap.First(op.Responses.Codes); pair != nil; pair = pair.Next() {
+			responses[strings.ToUpper(pair.Key())] = surfaceResponse(pair.Value())
+		}
+		if op.Responses.Default != nil {
+			responses["default"] = surfaceResponse(op.Responses.Default)
+		}
+		projected["responses"] = responses
+	}
+	return projected
+}
+
+func surfaceResponse(r *Response) map[string]any {
+	if r == nil {
+		return nil
+	}
+	headers := make(map[string]any)
+	for pair := orderedmap.First(r.Headers); pair != nil; pair = pair.Next() {
+		if h := pair.Value(); h != nil {
+			headers[strings.ToLower(pair.Key())] = map[string]any{
+				"required": h.Required,
+				"style":    h.Style,
+				"explode":  h.Explode,
+				"schema":   surfaceSchema(h.Schema),
+				"content":  surfaceContent(h.Content),
+			}
+		}
+	}
+	return map[string]any{"headers": headers, "content": surfaceContent(r.Content)}
+}
+
+func surfaceContent(content *orderedmap.Map[string, *MediaType]) map[string]any {
+	projected := make(map[string]any)
+	for pair := orderedmap.First(content); pair != nil; pair = pair.Next() {
+		mt := pair.Value()
+		if mt == nil {
+			projected[strings.ToLower(pair.Key())] = nil
+			continue
+		}
+		encoding := make(map[string]any)
+		for enc := orderedmap.First(mt.Encoding); enc != nil; enc = enc.Next() {
+			if e := enc.Value(); e != nil {
+				encoding[enc.Key()] = map[string]any{
+					"contentType":   e.ContentType,
+					"style":         e.Style,
+					"explode":       e.Explode,
+					"allowReserved": e.AllowReserved,
+				}
+			}
+		}
+		projected[strings.ToLower(pair.Key())] = map[string]any{
+			"schema":   surfaceSchema(mt.Schema),
+			"encoding": encoding,
+		}
+	}
+	return projected
+}
+
+// surfaceSchema projects the schema of a SchemaProxy, following references.
+func surfaceSchema(sp *base.SchemaProxy) any {
+	if sp == nil {
+		return nil
+	}
+	p := &schemaProjector{}
+	if lowProxy := sp.GoLow(); lowProxy != nil && lowProxy.GetValueNode() != nil {
+		return p.project(lowProxy.GetValueNode(), lowProxy.GetContext(), lowProxy.GetIndex())
+	}
+	// a schema that was not built from a specification is projected as it renders.
+	if rendered, err := sp.MarshalYAML(); err == nil {
+		if node, ok := rendered.(*yaml.Node); ok {
+			return p.project(node, nil, nil)
+		}
+	}
+	return nil
+}
+
+// schemaProjector projects schema nodes, keeping track of the references being followed to detect loops.
+type schemaProjector struct {
+	stack []*yaml.Node
+}
+
+func (p *schemaProjector) project(node *yaml.Node, ctx context.Context, idx *index.SpecIndex) any {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.AliasNode {
+		return p.project(node.Alias, ctx, idx)
+	}
+	if node.Kind != yaml.MappingNode {
+		return surfaceValue(node)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "$ref" {
+			continue
+		}
+		if idx == nil {
+			return map[string]any{"$ref": node.Content[i+1].Value}
+		}
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		located, foundIdx, _, foundCtx := lowmodel.LocateRefNodeWithContext(ctx, node, idx)
+		if located == nil {
+			return map[string]any{"$ref": node.Content[i+1].Value}
+		}
+		if at := slices.Index(p.stack, located); at >= 0 {
+			return map[string]any{"$recursive": len(p.stack) - at}
+		}
+		if foundIdx != nil {
+			idx = foundIdx
+		}
+		if foundCtx != nil {
+			ctx = foundCtx
+		}
+		p.stack = append(p.stack, located)
+		projected := p.project(located, ctx, idx)
+		p.stack = p.stack[:len(p.stack)-1]
+		return projected
+	}
+
+	projected := make(map[string]any)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch {
+		case surfaceIgnoredKeywords[key] || strings.HasPrefix(key, "x-"):
+		case surfaceSchemaMaps[key] && value.Kind == yaml.MappingNode:
+			schemas := make(map[string]any)
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				schemas[value.Content[j].Value] = p.project(value.Content[j+1], ctx, idx)
+			}
+			projected[key] = schemas
+		case surfaceSchemaLists[key] && value.Kind == yaml.SequenceNode:
+			schemas := make([]any, 0, len(value.Content))
+			for _, item := range value.Content {
+				schemas = append(schemas, p.project(item, ctx, idx))
+			}
+			projected[key] = schemas
+		case surfaceSchemas[key]:
+			projected[key] = p.project(value, ctx, idx)
+		case (key == "required" || key == "type") && value.Kind == yaml.SequenceNode:
+			// the order of required properties and types makes no difference.
+			var values []string
+			for _, item := range value.Content {
+				values = append(values, item.Value)
+			}
+			slices.Sort(values)
+			projected[key] = values
+		default:
+			projected[key] = surfaceValue(value)
+		}
+	}
+	return projected
+}
+
+// surfaceValue decodes a node into a plain value, that marshals to JSON the same way whatever its key order is.
+func surfaceValue(node *yaml.Node) any {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+	return jsonSafeValue(value)
+}
+
+// jsonSafeValue replaces what JSON can't represent in a decoded value: maps with keys that are not strings have their
+// keys formatted as strings, and numbers that are not finite (.nan and .inf) are formatted as strings.
+func jsonSafeValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = jsonSafeValue(item)
+		}
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = jsonSafeValue(item)
+		}
+		return m
+	case []any:
+		for i, item := range v {
+			v[i] = jsonSafeValue(item)
+		}
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+	}
+	return value
+}
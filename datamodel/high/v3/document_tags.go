@@ -0,0 +1,149 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// TagReport is the result of Document.AnalyzeTags, it describes how the tags declared by a Document are used by
+// its operations.
+type TagReport struct {
+	// Unused are the names of tags declared by the Document that no operation uses, in the order they are declared.
+	// A tag declared more than once is only listed once.
+	Unused []string
+
+	// Duplicated are the names of tags declared by the Document more than once, in the order they are first
+	// declared again.
+	Duplicated []string
+
+	// Undeclared are tags used by operations that the Document does not declare, in the order they are first used.
+	Undeclared []*UndeclaredTag
+
+	// OperationCounts is the number of operations that use each tag (declared or not). Unused tags have a count of 0.
+	OperationCounts map[string]int
+}
+
+// UndeclaredTag is a tag used by operations, that is not declared by the Document.
+type UndeclaredTag struct {
+	// Name of the tag.
+	Name string
+
+	// Operations are JSON pointers to each operation that uses the tag, for example '/paths/~1pets/get'.
+	Operations []string
+}
+
+// AnalyzeTags will compare the tags declared by the Document with the tags used by the operations of its paths and
+// webhooks, reporting tags that are declared but not used, tags that are used but not declared, tags that are
+// declared more than once, and the number of operations that use each tag.
+func (d *Document) AnalyzeTags() TagReport {
+	report := TagReport{OperationCounts: make(map[string]int)}
+	declared := make(map[string]bool)
+	duplicated := make(map[string]bool)
+	for _, t := range d.Tags {
+		if t == nil {
+			continue
+		}
+		if declared[t.Name] {
+			if !duplicated[t.Name] {
+				duplicated[t.Name] = true
+				report.Duplicated = append(report.Duplicated, t.Name)
+			}
+			continue
+		}
+		declared[t.Name] = true
+		report.OperationCounts[t.Name] = 0
+	}
+
+	undeclared := make(map[string]*UndeclaredTag)
+	visit := func(pi *PathItem, path string) {
+		if pi == nil {
+			return
+		}
+		for pair := orderedmap.First(pi.GetOperations()); pair != nil; pair = pair.Next() {
+			opPath := joinPointer(path, pair.Key())
+			counted := make(map[string]bool)
+			for _, tag := range pair.Value().Tags {
+				if counted[tag] {
+					continue
+				}
+				counted[tag] = true
+				report.OperationCounts[tag]++
+				if declared[tag] {
+					continue
+				}
+				u := undeclared[tag]
+				if u == nil {
+					u = &UndeclaredTag{Name: tag}
+					undeclared[tag] = u
+					report.Undeclared = append(report.Undeclared, u)
+				}
+				u.Operations = append(u.Operations, opPath)
+			}
+		}
+	}
+	if d.Paths != nil {
+		for pair := orderedmap.First(d.Paths.PathItems); pair != nil; pair = pair.Next() {
+			visit(pair.Value(), joinPointer("", "paths", pair.Key()))
+		}
+	}
+	for pair := orderedmap.First(d.Webhooks); pair != nil; pair = pair.Next() {
+		visit(pair.Value(), joinPointer("", "webhooks", pair.Key()))
+	}
+
+	unused := make(map[string]bool)
+	for _, t := range d.Tags {
+		if t != nil && report.OperationCounts[t.Name] == 0 && !unused[t.Name] {
+			unused[t.Name] = true
+			report.Unused = append(report.Unused, t.Name)
+		}
+	}
+	return report
+}
+
+// TagGroup is a named group of tags, as defined by the 'x-tagGroups' extension of a Document.
+type TagGroup struct {
+	// Name of the group.
+	Name string
+
+	// Tags are the names of the tags in the group, in the order they are listed.
+	Tags []string
+
+	// UnknownTags are the names of the tags in the group that the Document doesn't declare, and no operation uses.
+	UnknownTags []string
+}
+
+// TagGroups will return the groups of tags defined by the 'x-tagGroups' extension of the Document, in the order
+// they are defined. Each group is a mapping with a 'name' and a list of 'tags', groups that can't be read are
+// skipped. Tags listed by a group must be declared by the Document or used by an operation, any that are not are
+// reported by the UnknownTags of the group. An empty slice is returned if the extension is not defined.
+func (d *Document) TagGroups() []TagGroup {
+	groups := []TagGroup{}
+	if d.Extensions == nil {
+		return groups
+	}
+	node, ok := d.Extensions.Get("x-tagGroups")
+	if !ok || node == nil || node.Kind != yaml.SequenceNode {
+		return groups
+	}
+	known := d.AnalyzeTags().OperationCounts
+	for _, item := range node.Content {
+		var raw struct {
+			Name string   `yaml:"name"`
+			Tags []string `yaml:"tags"`
+		}
+		if item.Kind != yaml.MappingNode || item.Decode(&raw) != nil {
+			continue
+		}
+		group := TagGroup{Name: raw.Name, Tags: raw.Tags}
+		for _, tag := range raw.Tags {
+			if _, ok := known[tag]; !ok {
+				group.UnknownTags = append(group.UnknownTags, tag)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
@@ -4,9 +4,11 @@
 package v3
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"log/slog"
+	"math"
 	"net/http"
 	"net/url"
 	"os"
@@ -18,12 +20,14 @@ import (
 	"time"
 
 	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v2 "github.com/pb33f/libopenapi/datamodel/high/v2"
 	lowv2 "github.com/pb33f/libopenapi/datamodel/low/v2"
 	lowv3 "github.com/pb33f/libopenapi/datamodel/low/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/pb33f/libopenapi/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
 
@@ -671,6 +675,103 @@ func TestDocument_MarshalIndention_Error(t *testing.T) {
 	assert.NotEqual(t, string(data), strings.TrimSpace(string(rendered)))
 }
 
+func TestDocument_RenderWithOptions(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      tags:
+        - pets
+      responses:
+        "200":
+          description: ok`
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, _ := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	highDoc := NewDocument(lDoc)
+
+	rendered, err := highDoc.RenderWithOptions(RenderOptions{Indent: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, yml, strings.TrimSpace(string(rendered)))
+
+	rendered, err = highDoc.RenderWithOptions(RenderOptions{Indent: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, `openapi: 3.1.0
+paths:
+    /pets:
+        get:
+            tags:
+                - pets
+            responses:
+                "200":
+                    description: ok`, strings.TrimSpace(string(rendered)))
+
+	// the default is the same as Render.
+	rendered, err = highDoc.RenderWithOptions(RenderOptions{})
+	assert.NoError(t, err)
+	plain, _ := highDoc.Render()
+	assert.Equal(t, string(plain), string(rendered))
+
+	_, err = highDoc.RenderWithOptions(RenderOptions{Indent: -1})
+	assert.Error(t, err)
+}
+
+func TestDocument_RenderWithOptions_SortComponents(t *testing.T) {
+	yml := `openapi: 3.1.0
+components:
+  schemas:
+    Zebra:
+      type: string
+    Ant:
+      type: string
+    Moose:
+      type: string
+  parameters:
+    offset:
+      name: offset
+      in: query
+    limit:
+      name: limit
+      in: query
+  x-order:
+    b: 1
+    a: 2`
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, _ := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	highDoc := NewDocument(lDoc)
+
+	rendered, err := highDoc.RenderWithOptions(RenderOptions{Indent: 2, SortComponents: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `openapi: 3.1.0
+components:
+  schemas:
+    Ant:
+      type: string
+    Moose:
+      type: string
+    Zebra:
+      type: string
+  parameters:
+    limit:
+      name: limit
+      in: query
+    offset:
+      name: offset
+      in: query
+  x-order:
+    b: 1
+    a: 2`, strings.TrimSpace(string(rendered)))
+
+	// the source order is kept by default, and the model is not changed.
+	rendered, err = highDoc.RenderWithOptions(RenderOptions{Indent: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, yml, strings.TrimSpace(string(rendered)))
+	assert.Equal(t, "Zebra", highDoc.Components.Schemas.First().Key())
+
+	rendered, err = highDoc.RenderJSONWithOptions(RenderOptions{SortComponents: true})
+	assert.NoError(t, err)
+	assert.Contains(t, string(rendered), `"schemas":{"Ant":{"type":"string"},"Moose":{"type":"string"},"Zebra":`)
+}
+
 func TestDocument_MarshalJSON(t *testing.T) {
 	data, _ := os.ReadFile("../../../test_specs/petstorev3.json")
 	info, _ := datamodel.ExtractSpecInfo(data)
@@ -894,10 +995,1810 @@ func TestDocument_RenderJSONError(t *testing.T) {
 	}
 	h := NewDocument(lowDoc)
 
-	// render the document to YAML and it should be identical.
+	// negative numbers are rendered as they are written, so they can be decoded.
 	r, e := h.RenderJSON(" ")
-	assert.Nil(t, r)
-	assert.Error(t, e)
-	assert.Equal(t, "yaml: cannot decode !!float `-999.99` as a !!int", e.Error())
+	assert.NoError(t, e)
+	assert.Contains(t, string(r), `"minimum": -999.99`)
+}
+
+func TestDocument_RenderJSONWithOptions(t *testing.T) {
+	jsonFile := `{"openapi":"3.0.0","info":{"title":"<dummy>","version":"1.0.0"},"paths":{"/dummy":{"post":{"requestBody":{"content":{"application/json":{"schema":{"type":"object","properties":{"value":{"type":"number","multipleOf":0.01,"maximum":100}}}}}},"responses":{"200":{"description":"OK"}}}}}}`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(jsonFile))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	// compact, and without escaping.
+	r, err := h.RenderJSONWithOptions(RenderOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, jsonFile, string(r))
+
+	r, err = h.RenderJSONWithOptions(RenderOptions{JSONIndent: 2, JSONEscapeHTML: true})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(r), `{
+  "openapi": "3.0.0",
+  "info": {
+    "title": "\u003cdummy\u003e",`))
+	assert.Contains(t, string(r), `"maximum": 100`)
+}
+
+func TestDocument_RenderTo(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: <dummy>
+  version: 1.0.0
+components:
+  schemas:
+    Zebra:
+      type: string
+    Apple:
+      type: integer`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	var buf bytes.Buffer
+	require.NoError(t, h.RenderTo(&buf, nil))
+	rendered, _ := h.Render()
+	assert.Equal(t, string(rendered), buf.String())
+
+	buf.Reset()
+	opts := &RenderOptions{Indent: 2, SortComponents: true}
+	require.NoError(t, h.RenderTo(&buf, opts))
+	rendered, _ = h.RenderWithOptions(*opts)
+	assert.Equal(t, string(rendered), buf.String())
+	assert.Less(t, strings.Index(buf.String(), "Apple"), strings.Index(buf.String(), "Zebra"))
+
+	buf.Reset()
+	opts = &RenderOptions{JSON: true, JSONIndent: 2}
+	require.NoError(t, h.RenderTo(&buf, opts))
+	rendered, _ = h.RenderJSONWithOptions(*opts)
+	assert.Equal(t, string(rendered), buf.String())
+	assert.True(t, strings.HasPrefix(buf.String(), "{\n  \"openapi\": \"3.1.0\""))
+
+	assert.Error(t, h.RenderTo(&buf, &RenderOptions{Indent: -1}))
+	assert.Error(t, h.RenderTo(&buf, &RenderOptions{JSON: true, JSONIndent: -1}))
+}
+
+func TestDocument_SpecVersion(t *testing.T) {
+	tests := []struct {
+		version             string
+		major, minor, patch int
+		is30, is31          bool
+	}{
+		{"3.0.3", 3, 0, 3, true, false},
+		{"3.1.0", 3, 1, 0, false, true},
+		{" v3.1.1 ", 3, 1, 1, false, true},
+		{"3.1", 3, 1, 0, false, true},
+		{"3.0.0-rc2", 3, 0, 0, true, false},
+		{"3", 3, 0, 0, true, false},
+	}
+	for _, tc := range tests {
+		d := &Document{Version: tc.version}
+		major, minor, patch, err := d.SpecVersion()
+		assert.NoError(t, err, tc.version)
+		assert.Equal(t, tc.major, major, tc.version)
+		assert.Equal(t, tc.minor, minor, tc.version)
+		assert.Equal(t, tc.patch, patch, tc.version)
+		assert.Equal(t, tc.is30, d.Is30(), tc.version)
+		assert.Equal(t, tc.is31, d.Is31(), tc.version)
+	}
+}
+
+func TestDocument_SpecVersion_Malformed(t *testing.T) {
+	for _, v := range []string{"", "   ", "v", "three.one", "3.x.0", "3.1.0.1", "3..1"} {
+		d := &Document{Version: v}
+		_, _, _, err := d.SpecVersion()
+		assert.Error(t, err, v)
+		assert.False(t, d.Is30())
+		assert.False(t, d.Is31())
+	}
+}
+
+func TestDocument_RenameOperationId(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+    post:
+      operationId: createBurger
+      callbacks:
+        burgerCooked:
+          '{$request.body#/callbackUrl}':
+            post:
+              operationId: burgerCooked
+webhooks:
+  newBurger:
+    post:
+      operationId: newBurger`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	assert.NoError(t, d.RenameOperationId("listBurgers", "getBurgers"))
+	assert.Equal(t, "getBurgers", d.Paths.PathItems.GetOrZero("/burgers").Get.OperationId)
+
+	assert.NoError(t, d.RenameOperationId("burgerCooked", "onBurgerCooked"))
+	cb := d.Paths.PathItems.GetOrZero("/burgers").Post.Callbacks.GetOrZero("burgerCooked")
+	assert.Equal(t, "onBurgerCooked", cb.Expression.GetOrZero("{$request.body#/callbackUrl}").Post.OperationId)
+
+	assert.NoError(t, d.RenameOperationId("newBurger", "burgerAdded"))
+	assert.Equal(t, "burgerAdded", d.Webhooks.GetOrZero("newBurger").Post.OperationId)
+
+	err = d.RenameOperationId("createBurger", "getBurgers")
+	assert.EqualError(t, err, "unable to rename operationId 'createBurger', 'getBurgers' is already used by another operation")
+	assert.Equal(t, "createBurger", d.Paths.PathItems.GetOrZero("/burgers").Post.OperationId)
+
+	err = d.RenameOperationId("deleteBurger", "removeBurger")
+	assert.EqualError(t, err, "unable to rename operationId 'deleteBurger', no operation uses it")
+
+	rend, _ := d.Render()
+	assert.Contains(t, string(rend), "operationId: getBurgers")
+	assert.NotContains(t, string(rend), "listBurgers")
+}
+
+func TestCompareDocuments(t *testing.T) {
+	original := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          schema:
+            type: integer`
+
+	updated := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+  /fries:
+    get:
+      operationId: listFries`
+
+	build := func(yml string) *Document {
+		info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+		lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+		assert.NoError(t, err)
+		return NewDocument(lDoc)
+	}
+
+	changes := CompareDocuments(build(original), build(updated))
+	assert.Equal(t, 2, changes.TotalChanges())
+	assert.Equal(t, 1, changes.TotalBreakingChanges())
+
+	for _, c := range changes.GetAllChanges() {
+		switch c.Property {
+		case lowv3.ParametersLabel:
+			assert.True(t, c.Breaking)
+			assert.Equal(t, 7, *c.Context.OriginalLine)
+		case lowv3.PathLabel:
+			assert.Equal(t, "/fries", c.New)
+			assert.False(t, c.Breaking)
+			assert.Equal(t, 6, *c.Context.NewLine)
+		default:
+			t.Errorf("unexpected change to '%s'", c.Property)
+		}
+	}
+
+	assert.Nil(t, CompareDocuments(build(original), build(original)))
+	assert.Nil(t, CompareDocuments(nil, build(original)))
+	assert.Nil(t, CompareDocuments(&Document{}, build(original)))
+}
+
+func TestDocument_ResolveServerURLs(t *testing.T) {
+	yml := `openapi: 3.1.0
+servers:
+  - url: /v1
+  - url: https://{environment}.pb33f.io
+    variables:
+      environment:
+        default: api
+  - url: ../v2/
+  - url: http://pb33f.io:port/`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	base, _ := url.Parse("https://specs.pb33f.io/apis/burgers/openapi.yaml")
+	assert.Equal(t, []string{
+		"https://specs.pb33f.io/v1",
+		"https://api.pb33f.io",
+		"https://specs.pb33f.io/apis/v2/",
+	}, d.ResolveServerURLs(base))
+
+	assert.Equal(t, []string{"/v1", "https://api.pb33f.io", "../v2/"}, d.ResolveServerURLs(nil))
+
+	// no servers means the default server of '/'
+	assert.Equal(t, []string{"https://specs.pb33f.io/"}, (&Document{}).ResolveServerURLs(base))
+}
+
+func TestDocument_StripExamples_StripDescriptions(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: Burgers
+  description: all about burgers
+servers:
+  - url: https://{env}.pb33f.io
+    description: the server
+    variables:
+      env:
+        default: api
+        description: the environment
+tags:
+  - name: burgers
+    description: burger things
+paths:
+  /burgers:
+    description: burger path
+    get:
+      description: list burgers
+      parameters:
+        - name: limit
+          in: query
+          description: how many
+          example: 10
+          schema:
+            type: integer
+            example: 5
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              example:
+                name: big mac
+              examples:
+                small:
+                  $ref: '#/components/examples/Small'
+              schema:
+                $ref: '#/components/schemas/Burger'
+components:
+  examples:
+    Small:
+      description: a small burger
+      value:
+        name: happy meal
+  schemas:
+    Burger:
+      type: object
+      description: a burger
+      examples:
+        - name: whopper
+      properties:
+        example:
+          type: string
+          description: a property named example
+        description:
+          type: string
+          example: tasty`
+
+	build := func() *Document {
+		info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+		lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+		assert.NoError(t, err)
+		return NewDocument(lDoc)
+	}
+
+	d := build()
+	d.StripExamples()
+	op := d.Paths.PathItems.GetOrZero("/burgers").Get
+	assert.Nil(t, op.Parameters[0].Example)
+	assert.Nil(t, op.Parameters[0].Schema.Schema().Example)
+	assert.Nil(t, d.Components.Examples)
+	assert.Equal(t, "a burger", d.Components.Schemas.GetOrZero("Burger").Schema().Description)
+
+	rend, err := d.Render()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(rend), "example: 10")
+	assert.NotContains(t, string(rend), "example: 5")
+	assert.NotContains(t, string(rend), "examples:")
+	assert.NotContains(t, string(rend), "big mac")
+	assert.NotContains(t, string(rend), "whopper")
+	assert.NotContains(t, string(rend), "tasty")
+	assert.Contains(t, string(rend), "description: a burger")
+
+	// properties named 'example' and 'description' are not touched.
+	burger := d.Components.Schemas.GetOrZero("Burger").Schema()
+	assert.Equal(t, 2, burger.Properties.Len())
+
+	// the low-level nodes no longer contain the examples either.
+	rendLow, _ := yaml.Marshal(d.GoLow().Index.GetRootNode())
+	assert.NotContains(t, string(rendLow), "big mac")
+	assert.NotContains(t, string(rendLow), "happy meal")
+
+	d = build()
+	d.StripDescriptions()
+	assert.Empty(t, d.Info.Description)
+	assert.Empty(t, d.Servers[0].Variables.GetOrZero("env").Description)
+	assert.Equal(t, "ok", d.Paths.PathItems.GetOrZero("/burgers").Get.Responses.Codes.GetOrZero("200").Description)
+
+	rend, err = d.Render()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(rend), "description: "))
+	assert.Contains(t, string(rend), "description: ok")
+	assert.Contains(t, string(rend), "name: whopper")
+
+	rendLow, _ = yaml.Marshal(d.GoLow().Index.GetRootNode())
+	assert.Equal(t, 1, strings.Count(string(rendLow), "description: "))
+	assert.NotContains(t, string(rendLow), "a property named example")
+	assert.NotContains(t, string(rendLow), "a small burger")
+}
+
+func TestDocument_StripExtensions(t *testing.T) {
+	yml := `openapi: 3.1.0
+x-internal-owner: team-burgers
+x-logo: https://pb33f.io/logo.png
+info:
+  title: Burgers
+  x-internal-notes: secret
+paths:
+  x-internal-paths: true
+  /burgers:
+    get:
+      x-internal-owner: team-fries
+      responses:
+        "200":
+          description: ok
+          x-cache: true
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+components:
+  securitySchemes:
+    oauth:
+      type: oauth2
+      flows:
+        implicit:
+          authorizationUrl: https://pb33f.io/auth
+          scopes: {}
+          x-internal-flow: true
+  schemas:
+    Burger:
+      type: object
+      x-internal-table: burgers
+      properties:
+        x-internal-name:
+          type: string
+          x-internal-column: name
+          xml:
+            name: burger
+            x-internal-xml: true`
+
+	build := func() *Document {
+		info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+		lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+		assert.NoError(t, err)
+		return NewDocument(lDoc)
+	}
+
+	d := build()
+	assert.Equal(t, 8, d.StripExtensions("x-internal-"))
+	assert.Equal(t, 0, d.StripExtensions("x-internal-"))
+
+	rend, err := d.Render()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(rend), "x-internal-owner")
+	assert.NotContains(t, string(rend), "secret")
+	assert.NotContains(t, string(rend), "x-internal-column")
+	assert.NotContains(t, string(rend), "x-internal-xml")
+	assert.NotContains(t, string(rend), "x-internal-flow")
+	assert.Contains(t, string(rend), "x-logo")
+	assert.Contains(t, string(rend), "x-cache: true")
+
+	// a property named like an extension is not an extension.
+	assert.Contains(t, string(rend), "x-internal-name:")
+
+	rendLow, _ := yaml.Marshal(d.GoLow().Index.GetRootNode())
+	assert.NotContains(t, string(rendLow), "x-internal-owner")
+	assert.NotContains(t, string(rendLow), "x-internal-table")
+	assert.Contains(t, string(rendLow), "x-logo")
+
+	d = build()
+	assert.Equal(t, 10, d.StripExtensions())
+	rend, _ = d.Render()
+	assert.NotContains(t, string(rend), "x-logo")
+	assert.NotContains(t, string(rend), "x-cache")
+	assert.Contains(t, string(rend), "x-internal-name:")
+}
+
+func TestDocument_DeprecatedItems(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /burgers/{id}:
+    get:
+      deprecated: true
+      parameters:
+        - $ref: '#/components/parameters/Old'
+        - name: id
+          in: path
+          deprecated: false
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Old:
+              deprecated: true
+              schema:
+                type: string
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                sauce:
+                  type: string
+                  deprecated: true
+      responses:
+        "200":
+          description: ok
+components:
+  parameters:
+    Old:
+      name: old
+      in: query
+      deprecated: true
+  schemas:
+    Burger:
+      type: object
+      deprecated: true
+      properties:
+        name:
+          type: string
+        pickles:
+          type: array
+          items:
+            type: integer
+            deprecated: true`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	items := d.DeprecatedItems()
+	assert.Equal(t, []DeprecatedItem{
+		{Kind: DeprecatedOperation, Path: "/paths/~1burgers~1{id}/get", Line: 5, Column: 7},
+		{Kind: DeprecatedHeader, Path: "/paths/~1burgers~1{id}/get/responses/200/headers/X-Old", Line: 18, Column: 15},
+		{Kind: DeprecatedSchema, Path: "/paths/~1burgers~1{id}/post/requestBody/content/application~1json/schema/properties/sauce", Line: 34, Column: 19},
+		{Kind: DeprecatedParameter, Path: "/components/parameters/Old", Line: 43, Column: 7},
+		{Kind: DeprecatedSchema, Path: "/components/schemas/Burger", Line: 47, Column: 7},
+		{Kind: DeprecatedSchema, Path: "/components/schemas/Burger/properties/pickles/items", Line: 55, Column: 13},
+	}, items)
+
+	assert.Empty(t, (&Document{}).DeprecatedItems())
+}
+
+func TestDocument_AnalyzeTags(t *testing.T) {
+	yml := `openapi: 3.1.0
+tags:
+  - name: burgers
+  - name: fries
+  - name: drinks
+  - name: fries
+  - name: burgers
+  - name: fries
+paths:
+  /burgers:
+    get:
+      tags: [burgers, burgers]
+    post:
+      tags: [burgers, kitchen]
+  /fries:
+    get:
+      tags: [kitchen, secret]
+webhooks:
+  cooked:
+    post:
+      tags: [kitchen]`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	report := NewDocument(lDoc).AnalyzeTags()
+
+	assert.Equal(t, []string{"fries", "drinks"}, report.Unused)
+	assert.Equal(t, []string{"fries", "burgers"}, report.Duplicated)
+	assert.Equal(t, []*UndeclaredTag{
+		{Name: "kitchen", Operations: []string{"/paths/~1burgers/post", "/paths/~1fries/get", "/webhooks/cooked/post"}},
+		{Name: "secret", Operations: []string{"/paths/~1fries/get"}},
+	}, report.Undeclared)
+	assert.Equal(t, map[string]int{"burgers": 2, "fries": 0, "drinks": 0, "kitchen": 3, "secret": 1}, report.OperationCounts)
+
+	empty := (&Document{}).AnalyzeTags()
+	assert.Empty(t, empty.Unused)
+	assert.Empty(t, empty.Duplicated)
+	assert.Empty(t, empty.Undeclared)
+	assert.Empty(t, empty.OperationCounts)
+}
+
+func TestDocument_TagGroups(t *testing.T) {
+	yml := `openapi: 3.1.0
+tags:
+  - name: burgers
+  - name: fries
+paths:
+  /drinks:
+    get:
+      tags: [drinks]
+x-tagGroups:
+  - name: Food
+    tags: [burgers, fries]
+  - name: Other
+    tags: [drinks, pizza]
+  - not a group`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+
+	assert.Equal(t, []TagGroup{
+		{Name: "Food", Tags: []string{"burgers", "fries"}},
+		{Name: "Other", Tags: []string{"drinks", "pizza"}, UnknownTags: []string{"pizza"}},
+	}, NewDocument(lDoc).TagGroups())
+
+	groups := (&Document{}).TagGroups()
+	assert.NotNil(t, groups)
+	assert.Empty(t, groups)
+}
+
+func TestDocument_GetAllOperations(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets:
+    post:
+      operationId: createPet
+    get:
+      operationId: listPets
+  /pets/{petId}:
+    delete:
+      operationId: deletePet
+    get:
+      operationId: getPet
+  /health: {}
+webhooks:
+  newPet:
+    post:
+      operationId: newPet`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	doc := NewDocument(lDoc)
+	ops := doc.GetAllOperations()
+
+	var ids, methods, paths []string
+	for _, op := range ops {
+		ids = append(ids, op.Operation.OperationId)
+		methods = append(methods, op.Method)
+		paths = append(paths, op.Path)
+	}
+	assert.Equal(t, []string{"listPets", "createPet", "getPet", "deletePet"}, ids)
+	assert.Equal(t, []string{"get", "post", "get", "delete"}, methods)
+	assert.Equal(t, []string{"/pets", "/pets", "/pets/{petId}", "/pets/{petId}"}, paths)
+	assert.Equal(t, doc.Paths.PathItems.GetOrZero("/pets/{petId}"), ops[3].PathItem)
+	assert.Equal(t, 4, doc.OperationCount())
+	assert.Equal(t, 3, doc.Paths.Count())
+
+	assert.Empty(t, (&Document{}).GetAllOperations())
+	assert.Zero(t, (&Document{}).OperationCount())
+	assert.Zero(t, (&Document{}).Paths.Count())
+}
+
+func TestDocument_AllMediaTypes(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json: {}
+          application/xml: {}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json: {}
+        default:
+          $ref: '#/components/responses/Error'
+      callbacks:
+        created:
+          '{$request.body#/url}':
+            post:
+              requestBody:
+                content:
+                  text/plain: {}
+webhooks:
+  newPet:
+    post:
+      requestBody:
+        content:
+          application/cloudevents+json: {}
+components:
+  responses:
+    Error:
+      description: an error
+      content:
+        application/problem+json: {}
+  requestBodies:
+    Unused:
+      content:
+        multipart/form-data: {}
+  callbacks:
+    Unused:
+      '{$url}':
+        get:
+          responses:
+            "200":
+              description: ok
+              content:
+                text/csv: {}`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	doc := NewDocument(lDoc)
+	assert.Equal(t, []string{"application/cloudevents+json", "application/json", "application/problem+json",
+		"application/xml", "multipart/form-data", "text/csv", "text/plain"}, doc.AllMediaTypes())
+
+	assert.Empty(t, (&Document{}).AllMediaTypes())
+}
+
+func TestDocument_GenerateOperationIds(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      operationId: getPets
+    post: {}
+  /pets/:
+    get: {}
+  /health:
+    get:
+      summary: health check
+webhooks:
+  newPet:
+    post:
+      operationId: get_pets`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	doc := NewDocument(lDoc)
+
+	missing := doc.OperationsWithoutId()
+	assert.Len(t, missing, 3)
+	assert.Equal(t, "post", missing[0].Method)
+	assert.Equal(t, "/pets", missing[0].Path)
+	assert.Equal(t, "/pets/", missing[1].Path)
+	assert.Equal(t, "/health", missing[2].Path)
+
+	err = doc.GenerateOperationIds(func(method, path string) string {
+		return method + "_" + strings.ReplaceAll(strings.Trim(path, "/"), "/", "_")
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, doc.OperationsWithoutId())
+
+	pets := doc.Paths.PathItems.GetOrZero("/pets")
+	assert.Equal(t, "getPets", pets.Get.OperationId)
+	assert.Equal(t, "post_pets", pets.Post.OperationId)
+	assert.Equal(t, "get_pets_2", doc.Paths.PathItems.GetOrZero("/pets/").Get.OperationId)
+	assert.Equal(t, "get_health", doc.Paths.PathItems.GetOrZero("/health").Get.OperationId)
+
+	rendered, _ := doc.Render()
+	assert.Contains(t, string(rendered), "operationId: get_pets_2")
+
+	err = (&Document{Paths: doc.Paths}).GenerateOperationIds(func(method, path string) string { return "" })
+	assert.NoError(t, err)
+
+	pets.Post.OperationId = ""
+	err = doc.GenerateOperationIds(func(method, path string) string { return " " })
+	assert.EqualError(t, err, "unable to generate an operationId for 'POST /pets', the strategy returned an empty id")
+}
+
+func TestDocument_UnusedComponents(t *testing.T) {
+	yml := `openapi: 3.1.0
+security:
+  - apiKey: []
+paths:
+  /pets:
+    get:
+      security:
+        - oauth: [read]
+      parameters:
+        - $ref: '#/components/parameters/limit'
+      responses:
+        "200":
+          $ref: '#/components/responses/Pets'
+webhooks:
+  newPet:
+    post:
+      requestBody:
+        $ref: '#/components/requestBodies/Pet'
+components:
+  schemas:
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+      discriminator:
+        propertyName: kind
+        mapping:
+          dog: '#/components/schemas/Dog'
+    Cat:
+      properties:
+        name:
+          $ref: '#/components/schemas/Name/properties/value'
+    Dog:
+      type: object
+    Name:
+      properties:
+        value:
+          type: string
+    Orphan:
+      properties:
+        friend:
+          $ref: '#/components/schemas/Lonely'
+    Lonely:
+      type: object
+  parameters:
+    limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+    unused:
+      name: unused
+      in: query
+  responses:
+    Pets:
+      description: pets
+      content:
+        application/json:
+          schema:
+            type: array
+            items:
+              $ref: '#/components/schemas/Pet'
+  requestBodies:
+    Pet:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: key
+      in: header
+    oauth:
+      type: oauth2
+    basic:
+      type: http
+      scheme: basic`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	doc := NewDocument(lDoc)
+
+	assert.Equal(t, []string{
+		"/components/schemas/Orphan",
+		"/components/schemas/Lonely",
+		"/components/parameters/unused",
+		"/components/securitySchemes/basic",
+	}, doc.UnusedComponents())
+}
+
+func TestDocument_UnusedComponents_NoComponents(t *testing.T) {
+	assert.Nil(t, (&Document{}).UnusedComponents())
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(`openapi: 3.1.0
+paths: {}`))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	assert.Nil(t, NewDocument(lDoc).UnusedComponents())
+}
+
+func TestDocument_PruneUnusedComponents(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+    Orphan:
+      properties:
+        friend:
+          $ref: '#/components/schemas/Lonely'
+    Lonely:
+      type: object
+  examples:
+    unused:
+      value: nope`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	doc := NewDocument(lDoc)
+
+	removed, err := doc.PruneUnusedComponents()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"/components/schemas/Orphan",
+		"/components/schemas/Lonely",
+		"/components/examples/unused",
+	}, removed)
+	assert.Empty(t, doc.UnusedComponents())
+	assert.Equal(t, 1, doc.Components.Schemas.Len())
+	assert.Nil(t, doc.Components.Examples)
+	assert.Equal(t, 1, lDoc.Components.Value.Schemas.Value.Len())
+
+	rendered, err := doc.Render()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(rendered), "Orphan")
+	assert.NotContains(t, string(rendered), "examples")
+	assert.Less(t, len(rendered), len(yml))
+
+	// the rendered specification is still valid, and has nothing left to prune.
+	info, _ = datamodel.ExtractSpecInfo(rendered)
+	lDoc, err = lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	removed, err = NewDocument(lDoc).PruneUnusedComponents()
+	assert.NoError(t, err)
+	assert.Empty(t, removed)
+}
+
+func TestDocument_PruneUnusedComponents_PathItems(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /owners:
+    $ref: '#/components/pathItems/Owners'
+components:
+  pathItems:
+    Owners:
+      get:
+        responses:
+          "200":
+            description: owners
+    Abandoned:
+      get:
+        responses:
+          "200":
+            description: nobody`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	doc := NewDocument(lDoc)
+
+	removed, err := doc.PruneUnusedComponents()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/components/pathItems/Abandoned"}, removed)
+	assert.Equal(t, 1, doc.Components.PathItemCount())
+	assert.NotNil(t, doc.Components.PathItems.GetOrZero("Owners"))
+	assert.Equal(t, 1, lDoc.Components.Value.PathItems.Value.Len())
+	assert.NotNil(t, lDoc.Components.Value.FindPathItem("Owners"))
+	assert.Nil(t, lDoc.Components.Value.FindPathItem("Abandoned"))
+
+	rendered, err := doc.Render()
+	require.NoError(t, err)
+	assert.NotContains(t, string(rendered), "Abandoned")
+	assert.Contains(t, string(rendered), "Owners")
+}
+
+func TestDocument_PruneUnusedComponents_NoSpecification(t *testing.T) {
+	_, err := (&Document{}).PruneUnusedComponents()
+	assert.Error(t, err)
+}
+
+func TestValidateDocumentStructure(t *testing.T) {
+	yml := `openapi: 3.0.3
+info:
+  version: [1]
+tags:
+  - name: pets
+  - name: pets
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: petId
+          in: path
+          schema:
+            type: string
+        - name: q
+          in: body
+          schema:
+            type: string
+          content:
+            application/json: {}
+      requestBody:
+        content: {}
+    put:
+      operationId: getPet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200": {}
+  pets:
+    get:
+      responses:
+        "200":
+          description: ok
+components:
+  securitySchemes:
+    key:
+      type: apiKey
+      in: body
+    bad name:
+      type: magic`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	errs := ValidateDocumentStructure(NewDocument(lDoc))
+
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	assert.Equal(t, []string{
+		"/info: 'title' is required [3:3]",
+		"/info/version: 'version' must be a string [3:12]",
+		"/tags/1: tag 'pets' is defined more than once [6:11]",
+		"/paths/~1pets~1{petId}/get: 'responses' is required [10:7]",
+		"/paths/~1pets~1{petId}/get/parameters/0: path parameter 'petId' must be required [12:11]",
+		"/paths/~1pets~1{petId}/get/parameters/1: 'schema' and 'content' are mutually exclusive [16:11]",
+		"/paths/~1pets~1{petId}/get/parameters/1/in: 'in' must be one of 'query', 'header', 'path' or 'cookie', not 'body' [17:15]",
+		"/paths/~1pets~1{petId}/get/requestBody: 'content' is required [23:9]",
+		"/paths/~1pets~1{petId}/put/operationId: operationId 'getPet' is used by more than one operation [25:20]",
+		"/paths/~1pets~1{petId}/put/responses/200: 'description' is required [33:16]",
+		"/paths/pets: path 'pets' must start with '/' [35:5]",
+		"/components/securitySchemes/key: 'name' is required [42:7]",
+		"/components/securitySchemes/key/in: 'in' must be one of 'query', 'header' or 'cookie', not 'body' [43:11]",
+		"/components/securitySchemes/bad name: component name 'bad name' may only contain letters, digits, '.', '-' and '_' [44:5]",
+		"/components/securitySchemes/bad name/type: 'type' must be one of 'apiKey', 'http', 'mutualTLS', 'oauth2' or 'openIdConnect', not 'magic' [45:13]",
+	}, msgs)
+}
+
+func TestValidateDocumentStructure_Valid(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+webhooks:
+  newPet:
+    post:
+      requestBody:
+        content:
+          application/json: {}`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	assert.Empty(t, ValidateDocumentStructure(NewDocument(lDoc)))
+	assert.Nil(t, ValidateDocumentStructure(nil))
+
+	errs := ValidateDocumentStructure(&Document{Version: "2.0"})
+	assert.Len(t, errs, 3)
+	assert.Equal(t, "version '2.0' is not an OpenAPI 3 version", errs[0].Message)
+	assert.Equal(t, "'info' is required", errs[1].Message)
+	assert.Equal(t, "at least one of 'paths', 'webhooks' or 'components' is required", errs[2].Message)
+}
+
+func TestDocument_EffectiveSchemaDialect(t *testing.T) {
+	assert.Equal(t, OpenAPI31SchemaDialect, (&Document{Version: "3.1.0"}).EffectiveSchemaDialect())
+	assert.Equal(t, "", (&Document{Version: "3.0.3"}).EffectiveSchemaDialect())
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema",
+		(&Document{Version: "3.1.0", JsonSchemaDialect: "https://json-schema.org/draft/2020-12/schema"}).EffectiveSchemaDialect())
+}
+
+func TestValidateDocumentStructure_SchemaDialect(t *testing.T) {
+	validate := func(version, dialect string) []ValidationError {
+		yml := `openapi: ` + version + `
+info:
+  title: pets
+  version: 1.0.0
+jsonSchemaDialect: ` + dialect + `
+paths: {}`
+		info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+		lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+		require.NoError(t, err)
+		return ValidateDocumentStructure(NewDocument(lDoc))
+	}
+
+	assert.Empty(t, validate("3.1.0", OpenAPI31SchemaDialect))
+	assert.Empty(t, validate("3.1.0", "http://json-schema.org/draft-07/schema#"))
+
+	errs := validate("3.1.0", "https://example.com/dialect")
+	require.Len(t, errs, 1)
+	assert.Equal(t, "/jsonSchemaDialect: 'jsonSchemaDialect' 'https://example.com/dialect' is not a recognized "+
+		"JSON Schema dialect [5:20]", errs[0].Error())
+
+	errs = validate("3.1.0", "draft-07")
+	require.Len(t, errs, 1)
+	assert.Equal(t, "'jsonSchemaDialect' must be a URI, not 'draft-07'", errs[0].Message)
+
+	errs = validate("3.0.3", OpenAPI31SchemaDialect)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "'jsonSchemaDialect' requires OpenAPI 3.1", errs[0].Message)
+}
+
+func TestValidateDocumentStructure_PathTemplate(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+paths:
+  /owners/{ownerId}/pets/{petId}:
+    parameters:
+      - name: ownerId
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	errs := ValidateDocumentStructure(NewDocument(lDoc))
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "path parameter 'petId' is used by the path, but is not defined", errs[0].Message)
+	assert.Equal(t, "path parameter 'id' is defined, but is not used by the path", errs[1].Message)
+	assert.Equal(t, "/paths/~1owners~1{ownerId}~1pets~1{petId}/get", errs[1].Path)
+	assert.Equal(t, 14, errs[1].Line)
+}
+
+func TestDocument_SharedSchemas(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string`
+
+	build := func(disable bool) (*base.Schema, *base.Schema, *lowv3.Document) {
+		info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+		config := datamodel.NewDocumentConfiguration()
+		config.DisableSchemaCache = disable
+		lDoc, err := lowv3.CreateDocumentFromConfig(info, config)
+		require.NoError(t, err)
+		pets := NewDocument(lDoc).Paths.PathItems.GetOrZero("/pets")
+		get := pets.Get.Responses.Codes.GetOrZero("200").Content.GetOrZero("application/json").Schema
+		post := pets.Post.RequestBody.Content.GetOrZero("application/json").Schema
+		require.NotSame(t, get, post)
+		return get.Schema(), post.Schema(), lDoc
+	}
+
+	get, post, lDoc := build(false)
+	require.NotNil(t, get)
+	assert.Same(t, get, post)
+	assert.Same(t, get.GoLow(), post.GoLow())
+	assert.Equal(t, "string", get.Properties.GetOrZero("name").Schema().Type[0])
+
+	// low-level and high-level schemas are cached apart.
+	lDoc.Index.GetSchemaCache().Range(func(_, value any) bool {
+		assert.IsType(t, get.GoLow(), value)
+		return true
+	})
+	highCount := 0
+	lDoc.Index.GetHighSchemaCache().Range(func(key, value any) bool {
+		assert.IsType(t, get.GoLow(), key)
+		assert.IsType(t, get, value)
+		highCount++
+		return true
+	})
+	assert.Equal(t, 1, highCount)
+
+	get, post, lDoc = build(true)
+	require.NotNil(t, get)
+	assert.NotSame(t, get, post)
+	assert.NotSame(t, get.GoLow(), post.GoLow())
+	assert.Equal(t, get.Type, post.Type)
+	assert.Nil(t, lDoc.Index.GetHighSchemaCache())
+}
+
+func TestDocument_ValidateLocalReferences(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Limit'
+        - $ref: '#/components/parameters/Offset'
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pett'
+        "404":
+          $ref: '#/components/responses/NotFound'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        tags:
+          $ref: '#/components/schemas/Pet/properties/name'
+        name:
+          type: string
+        owner:
+          $ref: 'owners.yaml#/Owner'
+    a/b:
+      $ref: '#/components/schemas/a~1b/missing'
+  parameters:
+    Limit:
+      name: limit
+      in: query
+  responses:
+    NotFound:
+      description: not found`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, _ := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	errs := NewDocument(lDoc).ValidateLocalReferences()
+	require.Len(t, errs, 3)
+
+	assert.Equal(t, "#/components/parameters/Offset", errs[0].Ref)
+	assert.Equal(t, "/paths/~1pets/get/parameters/1", errs[0].Path)
+	assert.Equal(t, 7, errs[0].Line)
+	assert.Equal(t, 17, errs[0].Column)
+
+	assert.Equal(t, "#/components/schemas/Pett", errs[1].Ref)
+	assert.Equal(t, "/paths/~1pets/get/responses/200/content/application~1json/schema", errs[1].Path)
+	assert.Equal(t, "/paths/~1pets/get/responses/200/content/application~1json/schema: reference "+
+		"'#/components/schemas/Pett' cannot be found [14:23]", errs[1].Error())
+
+	assert.Equal(t, "/components/schemas/a~1b", errs[2].Path)
+
+	assert.Nil(t, (&Document{}).ValidateLocalReferences())
+}
+
+func TestDocument_ValidateLocalReferences_Examples(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/a%2Fb'
+              example:
+                $ref: '#/not/a/reference'
+              examples:
+                literal:
+                  value:
+                    $ref: '#/not/a/reference/either'
+                missing:
+                  $ref: '#/components/examples/Missing'
+components:
+  schemas:
+    a/b:
+      type: object
+      examples:
+        - $ref: '#/just/data'
+      properties:
+        example:
+          $ref: '#/components/schemas/Missing'
+  examples:
+    example:
+      value:
+        $ref: '#/more/data'`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, _ := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	errs := NewDocument(lDoc).ValidateLocalReferences()
+	require.Len(t, errs, 2)
+
+	// a reference to an example is checked, the content of an example is not.
+	assert.Equal(t, "#/components/examples/Missing", errs[0].Ref)
+	assert.Equal(t, "/paths/~1pets/get/responses/200/content/application~1json/examples/missing", errs[0].Path)
+
+	// a property named 'example' is not an example.
+	assert.Equal(t, "#/components/schemas/Missing", errs[1].Ref)
+	assert.Equal(t, "/components/schemas/a~1b/properties/example", errs[1].Path)
+}
+
+func TestDocument_SurfaceHash(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        schema:
+          type: string
+    get:
+      operationId: getPet
+      summary: Get a pet
+      parameters:
+        - name: X-Trace
+          in: header
+          description: trace it
+          schema:
+            type: string
+      responses:
+        "200":
+          description: a pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+              example:
+                name: fido
+        "404":
+          description: not found
+components:
+  schemas:
+    Pet:
+      type: object
+      description: a pet
+      x-internal: true
+      required: [name, id]
+      properties:
+        name:
+          type: string
+          example: fido
+        id:
+          type: integer
+        friend:
+          $ref: '#/components/schemas/Pet'`
+
+	// the same surface, documented differently, in a different order, with a differently named component.
+	documented := `openapi: 3.1.0
+info:
+  title: Pets API
+  description: All about pets
+  version: 2.0.0
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        description: the pet
+        schema:
+          type: string
+    get:
+      operationId: fetchPet
+      description: Fetch a pet, by its id.
+      x-codegen: skip
+      parameters:
+        - name: x-trace
+          in: header
+          schema:
+            type: string
+            title: Trace
+      responses:
+        "404":
+          description: no such pet
+        "200":
+          description: the pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Animal'
+              examples:
+                fido:
+                  value:
+                    name: fido
+components:
+  schemas:
+    Animal:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+          description: the id
+        friend:
+          $ref: '#/components/schemas/Animal'
+        name:
+          type: string`
+
+	hash := func(spec string) [32]byte {
+		info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+		lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+		require.NoError(t, err)
+		return NewDocument(lDoc).SurfaceHash()
+	}
+
+	original := hash(yml)
+	assert.Equal(t, original, hash(documented))
+	assert.NotEqual(t, original, hash(strings.Replace(yml, `"404"`, `"410"`, 1)))
+	assert.NotEqual(t, original, hash(strings.Replace(yml, "type: integer", "type: number", 1)))
+	assert.NotEqual(t, original, hash(strings.Replace(yml, "in: header", "in: query", 1)))
+	assert.NotEqual(t, original, hash(strings.Replace(yml, "    get:", "    put:", 1)))
+	assert.NotEqual(t, original, hash(strings.Replace(yml, "[name, id]", "[name]", 1)))
+
+	// values JSON can't represent as they are decoded are still part of the surface.
+	withConst := func(value string) string {
+		return strings.Replace(yml, "          type: integer", "          type: integer\n          const: "+value, 1)
+	}
+	nan := withConst(".nan")
+	assert.NotEqual(t, hash(nan), hash(strings.Replace(nan, `"404"`, `"410"`, 1)))
+	assert.NotEqual(t, hash(nan), hash(withConst(".inf")))
+	assert.NotEqual(t, hash(withConst("{1: a}")), hash(withConst("{1: b}")))
+	assert.Equal(t, map[string]any{"1": []any{"+Inf"}}, jsonSafeValue(map[any]any{1: []any{math.Inf(1)}}))
+}
+
+func TestDocument_FilterByTag(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: Zoo
+  version: 1.0.0
+servers:
+  - url: https://zoo.example.com
+security:
+  - apiKey: []
+tags:
+  - name: pets
+  - name: staff
+  - name: public
+paths:
+  /pets:
+    get:
+      tags: [pets, public]
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      tags: [staff]
+      requestBody:
+        $ref: '#/components/requestBodies/NewPet'
+      responses:
+        "201":
+          description: created
+  /keepers:
+    get:
+      tags: [staff]
+      responses:
+        "200":
+          description: keepers
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Keeper'
+  /cats:
+    $ref: '#/components/pathItems/Cats'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: string
+    Keeper:
+      type: string
+  requestBodies:
+    NewPet:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: key
+      in: header
+  pathItems:
+    Cats:
+      get:
+        tags: [pets]
+        responses:
+          "200":
+            description: cats
+      delete:
+        tags: [staff]
+        responses:
+          "204":
+            description: gone`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	filtered, err := d.FilterByTag("pets")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Zoo", filtered.Info.Title)
+	assert.Equal(t, "https://zoo.example.com", filtered.Servers[0].URL)
+	require.Len(t, filtered.Security, 1)
+	require.Equal(t, 2, filtered.Paths.PathItems.Len())
+	pets := filtered.Paths.PathItems.GetOrZero("/pets")
+	assert.NotNil(t, pets.Get)
+	assert.Nil(t, pets.Post)
+	cats := filtered.Paths.PathItems.GetOrZero("/cats")
+	assert.NotNil(t, cats.Get)
+	assert.Nil(t, cats.Delete)
+
+	require.Len(t, filtered.Tags, 2)
+	assert.Equal(t, "pets", filtered.Tags[0].Name)
+	assert.Equal(t, "public", filtered.Tags[1].Name)
+
+	assert.Equal(t, 2, filtered.Components.Schemas.Len())
+	assert.NotNil(t, filtered.Components.Schemas.GetOrZero("Pet"))
+	assert.NotNil(t, filtered.Components.Schemas.GetOrZero("Owner"))
+	assert.Equal(t, 0, orderedmap.Len(filtered.Components.RequestBodies))
+	assert.Equal(t, 1, filtered.Components.SecuritySchemes.Len())
+	assert.Empty(t, filtered.UnusedComponents())
+	assert.Empty(t, filtered.ValidateLocalReferences())
+	rendered, err := filtered.Render()
+	require.NoError(t, err)
+	assert.NotContains(t, string(rendered), "Keeper")
+	assert.NotContains(t, string(rendered), "pathItems")
+
+	// the original document is not changed.
+	assert.Equal(t, 3, d.Paths.PathItems.Len())
+	assert.NotNil(t, d.Paths.PathItems.GetOrZero("/pets").Post)
+
+	none, err := d.FilterByTag("nothing")
+	require.NoError(t, err)
+	assert.Equal(t, 0, orderedmap.Len(none.Paths.PathItems))
+	assert.Nil(t, none.Tags)
+
+	_, err = (*Document)(nil).FilterByTag("pets")
+	assert.Error(t, err)
+}
+
+// circularFilterSpec has a required array of itself, which is only valid when array circular references are ignored.
+const circularFilterSpec = `openapi: 3.1.0
+info:
+  title: Trees
+  version: 1.0.0
+paths:
+  /trees:
+    get:
+      tags: [trees]
+      responses:
+        "200":
+          description: trees
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Node'
+  /forests:
+    get:
+      tags: [forests]
+      responses:
+        "200":
+          description: forests
+components:
+  schemas:
+    Node:
+      type: object
+      required: [children]
+      properties:
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'`
+
+func buildCircularFilterDocument(t *testing.T) *Document {
+	info, _ := datamodel.ExtractSpecInfo([]byte(circularFilterSpec))
+	config := datamodel.NewDocumentConfiguration()
+	config.IgnoreArrayCircularReferences = true
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, config)
+	require.NoError(t, err)
+	return NewDocument(lDoc)
+}
+
+func TestDocument_FilterByTag_Circular(t *testing.T) {
+	d := buildCircularFilterDocument(t)
+
+	filtered, err := d.FilterByTag("trees")
+	require.NoError(t, err)
+	assert.Equal(t, 1, filtered.Paths.PathItems.Len())
+	assert.NotNil(t, filtered.Components.Schemas.GetOrZero("Node"))
+	assert.True(t, filtered.GoLow().Config.IgnoreArrayCircularReferences)
+	assert.Len(t, filtered.GoLow().Index.GetResolver().GetIgnoredCircularArrayReferences(), 1)
+
+	// a document built with a circular reference error can still be filtered, as it could be built.
+	info, _ := datamodel.ExtractSpecInfo([]byte(circularFilterSpec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.Error(t, err)
+	filtered, err = NewDocument(lDoc).FilterByTag("trees")
+	require.NoError(t, err)
+	assert.NotNil(t, filtered.Components.Schemas.GetOrZero("Node"))
+}
+
+func TestDocument_FilterByPathPrefix_Circular(t *testing.T) {
+	d := buildCircularFilterDocument(t)
+
+	filtered, err := d.FilterByPathPrefix("/trees", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, filtered.Paths.PathItems.Len())
+	assert.NotNil(t, filtered.Paths.PathItems.GetOrZero("/trees"))
+	assert.NotNil(t, filtered.Components.Schemas.GetOrZero("Node"))
+	assert.Len(t, filtered.GoLow().Index.GetResolver().GetIgnoredCircularArrayReferences(), 1)
+
+	forests, err := d.FilterByPathPrefix("/forests", false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, orderedmap.Len(forests.Components.Schemas))
+}
+
+func TestDocument_FilterByPathPrefix(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: Shop
+  version: 1.0.0
+paths:
+  /admin:
+    get:
+      responses:
+        "200":
+          description: dashboard
+  /admin/users/{userId}:
+    get:
+      tags: [admin]
+      responses:
+        "200":
+          description: user
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+  /administrators:
+    get:
+      responses:
+        "200":
+          description: not under /admin
+  /products:
+    get:
+      tags: [shop]
+      responses:
+        "200":
+          description: products
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Product'
+webhooks:
+  newUser:
+    post:
+      responses:
+        "200":
+          description: ok
+tags:
+  - name: admin
+  - name: shop
+components:
+  schemas:
+    User:
+      type: object
+    Product:
+      type: object`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	admin, err := d.FilterByPathPrefix("/admin/", false)
+	require.NoError(t, err)
+	require.Equal(t, 2, admin.Paths.PathItems.Len())
+	assert.NotNil(t, admin.Paths.PathItems.GetOrZero("/admin"))
+	assert.NotNil(t, admin.Paths.PathItems.GetOrZero("/admin/users/{userId}"))
+	assert.Equal(t, 0, orderedmap.Len(admin.Webhooks))
+	require.Len(t, admin.Tags, 1)
+	assert.Equal(t, "admin", admin.Tags[0].Name)
+	assert.Equal(t, 1, admin.Components.Schemas.Len())
+	assert.NotNil(t, admin.Components.Schemas.GetOrZero("User"))
+
+	users, err := d.FilterByPathPrefix("/admin/users/{id}", true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, users.Paths.PathItems.Len())
+	assert.Equal(t, 1, users.Webhooks.Len())
+
+	all, err := d.FilterByPathPrefix("/", false)
+	require.NoError(t, err)
+	assert.Equal(t, 4, all.Paths.PathItems.Len())
+	assert.Equal(t, 0, orderedmap.Len(all.Webhooks))
+
+	// webhook names are never matched against the prefix.
+	hooks, err := d.FilterByPathPrefix("newUser", false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, hooks.Paths.PathItems.Len())
+	assert.Equal(t, 0, orderedmap.Len(hooks.Webhooks))
+}
+
+func TestOperation_UsesDeprecatedSchemas(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        schema:
+          $ref: '#/components/schemas/LegacyId'
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                nickname:
+                  type: string
+                  deprecated: true
+      responses:
+        "200":
+          description: a pet
+          headers:
+            X-Rate:
+              schema:
+                type: integer
+                deprecated: true
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+    get:
+      responses:
+        "200":
+          description: a pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+    delete:
+      parameters:
+        - name: id
+          in: path
+          schema:
+            type: string
+      responses:
+        "204":
+          description: deleted
+components:
+  schemas:
+    LegacyId:
+      type: string
+      deprecated: true
+    Pet:
+      type: object
+      properties:
+        tag:
+          type: string
+          deprecated: true
+        owner:
+          $ref: '#/components/schemas/Owner'
+        friend:
+          $ref: '#/components/schemas/Pet'
+    Owner:
+      type: object
+      deprecated: true`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	d := NewDocument(lDoc)
+	pi := d.Paths.PathItems.GetOrZero("/pets/{id}")
+
+	assert.Equal(t, []string{
+		"/components/schemas/LegacyId",
+		"/paths/~1pets~1{id}/put/requestBody/content/application~1json/schema/properties/nickname",
+		"/paths/~1pets~1{id}/put/responses/200/headers/X-Rate/schema",
+		"/components/schemas/Pet/properties/tag",
+		"/components/schemas/Owner",
+	}, pi.Put.UsesDeprecatedSchemas(d))
+
+	// without the document, inline schemas are relative to the operation, and path parameters are not checked.
+	assert.Equal(t, []string{
+		"/components/schemas/Pet/properties/tag",
+		"/components/schemas/Owner",
+	}, pi.Get.UsesDeprecatedSchemas(nil))
+
+	// a path item parameter overridden by the operation is not used.
+	assert.Empty(t, pi.Delete.UsesDeprecatedSchemas(d))
+
+	assert.Nil(t, (&Operation{}).UsesDeprecatedSchemas(d))
+	assert.Nil(t, (*Operation)(nil).UsesDeprecatedSchemas(d))
+}
+
+func TestDocument_DynamicRef(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: Trees
+  version: 1.0.0
+components:
+  schemas:
+    Tree:
+      $dynamicAnchor: node
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $dynamicRef: '#node'`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	items := d.Components.Schemas.GetOrZero("Tree").Schema().Properties.GetOrZero("children").Schema().Items.A
+	assert.True(t, items.IsReference())
+	assert.Equal(t, "#/components/schemas/Tree", items.GetReference())
+	require.NotNil(t, items.Schema())
+	assert.Equal(t, "node", items.Schema().DynamicAnchor)
+	assert.Equal(t, "object", items.Schema().Type[0])
 
+	rendered, err := d.Render()
+	require.NoError(t, err)
+	assert.Contains(t, string(rendered), "$dynamicRef: '#node'")
 }
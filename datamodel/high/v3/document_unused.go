@@ -0,0 +1,277 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	lowmodel "github.com/pb33f/libopenapi/datamodel/low"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// UnusedComponents will return a JSON pointer (for example '/components/schemas/Pet') to every component of the
+// Document that can't be reached from its paths, webhooks or security requirements, in the order they are defined.
+//
+// A component is reachable when it's referenced (with '$ref', or from a discriminator mapping) by a path, a webhook
+// or another reachable component. Security schemes are reachable when they are named by the global security
+// requirements, or by the requirements of a reachable operation. Only references local to the document are
+// followed. The Document must have been built from a specification, otherwise nil is returned.
+func (d *Document) UnusedComponents() []string {
+	if d.low == nil || d.low.Components.ValueNode == nil {
+		return nil
+	}
+	u := &unusedWalker{
+		components: make(map[string]*yaml.Node),
+		reached:    make(map[string]bool),
+		walked:     make(map[*yaml.Node]bool),
+		seenItems:  make(map[*PathItem]bool),
+	}
+
+	// collect every component, by pointer.
+	sections := d.low.Components.ValueNode
+	for i := 0; i+1 < len(sections.Content); i += 2 {
+		section, entries := sections.Content[i].Value, sections.Content[i+1]
+		if strings.HasPrefix(section, "x-") || entries.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(entries.Content); j += 2 {
+			pointer := joinPointer("", "components", section, entries.Content[j].Value)
+			u.pointers = append(u.pointers, pointer)
+			u.components[pointer] = entries.Content[j+1]
+		}
+	}
+
+	// walk everything reachable from the roots.
+	if d.low.Paths.ValueNode != nil {
+		u.walk(d.low.Paths.ValueNode)
+	}
+	if d.low.Webhooks.ValueNode != nil {
+		u.walk(d.low.Webhooks.ValueNode)
+	}
+	u.security(d.Security)
+	if d.Paths != nil {
+		for pair := orderedmap.First(d.Paths.PathItems); pair != nil; pair = pair.Next() {
+			u.pathItemSecurity(pair.Value())
+		}
+	}
+	for pair := orderedmap.First(d.Webhooks); pair != nil; pair = pair.Next() {
+		u.pathItemSecurity(pair.Value())
+	}
+
+	var unused []string
+	for _, pointer := range u.pointers {
+		if !u.reached[pointer] {
+			unused = append(unused, pointer)
+		}
+	}
+	return unused
+}
+
+// PruneUnusedComponents will remove every component returned by UnusedComponents from the Document, and return the
+// JSON pointers of the components that were removed. Reachability is checked again after every round of removals,
+// until every remaining component is used.
+//
+// Components are removed from both the high-level model and the low-level yaml nodes, so they are not rendered. A
+// section of the components that is left empty is removed as well. The index of the Document is not updated.
+func (d *Document) PruneUnusedComponents() (removed []string, err error) {
+	if d.low == nil {
+		return nil, errors.New("unable to prune components, the document was not built from a specification")
+	}
+	for {
+		unused := d.UnusedComponents()
+		if len(unused) == 0 {
+			return removed, nil
+		}
+		for _, pointer := range unused {
+			if err = d.removeComponent(pointer); err != nil {
+				return removed, err
+			}
+			removed = append(removed, pointer)
+		}
+	}
+}
+
+// removeComponent removes the component at pointer (for example '/components/schemas/Pet') from the Document.
+func (d *Document) removeComponent(pointer string) error {
+	segments := strings.Split(pointer, "/")
+	if len(segments) != 4 || segments[0] != "" || segments[1] != "components" {
+		return fmt.Errorf("unable to remove component '%s', it's not a component pointer", pointer)
+	}
+	section := segments[2]
+	name := strings.ReplaceAll(strings.ReplaceAll(segments[3], "~1", "/"), "~0", "~")
+
+	// low-level yaml nodes, these must change or the component would be found again.
+	components := d.low.Components.ValueNode
+	_, entries := utils.FindKeyNodeTop(section, components.Content)
+	if !utils.RemoveKeyNodes(entries, name) {
+		return fmt.Errorf("unable to remove component '%s', it can't be found", pointer)
+	}
+	if len(entries.Content) == 0 {
+		utils.RemoveKeyNodes(components, section)
+	}
+
+	// low-level and high-level models.
+	lc := d.low.Components.Value
+	hc := d.Components
+	if lc == nil || hc == nil {
+		return nil
+	}
+	switch section {
+	case "schemas":
+		deleteLowComponent(lc.Schemas.Value, name)
+		hc.Schemas = deleteHighComponent(hc.Schemas, name)
+	case "responses":
+		deleteLowComponent(lc.Responses.Value, name)
+		hc.Responses = deleteHighComponent(hc.Responses, name)
+	case "parameters":
+		deleteLowComponent(lc.Parameters.Value, name)
+		hc.Parameters = deleteHighComponent(hc.Parameters, name)
+	case "examples":
+		deleteLowComponent(lc.Examples.Value, name)
+		hc.Examples = deleteHighComponent(hc.Examples, name)
+	case "requestBodies":
+		deleteLowComponent(lc.RequestBodies.Value, name)
+		hc.RequestBodies = deleteHighComponent(hc.RequestBodies, name)
+	case "headers":
+		deleteLowComponent(lc.Headers.Value, name)
+		hc.Headers = deleteHighComponent(hc.Headers, name)
+	case "securitySchemes":
+		deleteLowComponent(lc.SecuritySchemes.Value, name)
+		hc.SecuritySchemes = deleteHighComponent(hc.SecuritySchemes, name)
+	case "links":
+		deleteLowComponent(lc.Links.Value, name)
+		hc.Links = deleteHighComponent(hc.Links, name)
+	case "callbacks":
+		deleteLowComponent(lc.Callbacks.Value, name)
+		hc.Callbacks = deleteHighComponent(hc.Callbacks, name)
+	case "pathItems":
+		deleteLowComponent(lc.PathItems.Value, name)
+		hc.PathItems = deleteHighComponent(hc.PathItems, name)
+	}
+	return nil
+}
+
+func deleteLowComponent[T any](m *orderedmap.Map[lowmodel.KeyReference[string], lowmodel.ValueReference[T]], name string) {
+	for pair := orderedmap.First(m); pair != nil; pair = pair.Next() {
+		if pair.Key().Value == name {
+			m.Delete(pair.Key())
+			return
+		}
+	}
+}
+
+// deleteHighComponent deletes name from m, returning nil if m is left empty (so it's not rendered).
+func deleteHighComponent[T any](m *orderedmap.Map[string, T], name string) *orderedmap.Map[string, T] {
+	if m == nil {
+		return nil
+	}
+	m.Delete(name)
+	if m.Len() == 0 {
+		return nil
+	}
+	return m
+}
+
+type unusedWalker struct {
+	pointers   []string
+	components map[string]*yaml.Node
+	reached    map[string]bool
+	walked     map[*yaml.Node]bool
+	seenItems  map[*PathItem]bool
+}
+
+// walk follows every local reference made by node (and its children).
+func (u *unusedWalker) walk(node *yaml.Node) {
+	if node == nil || u.walked[node] {
+		return
+	}
+	u.walked[node] = true
+	if node.Kind == yaml.AliasNode {
+		u.walk(node.Alias)
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			switch {
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				u.reach(value.Value)
+			case key == "discriminator" && value.Kind == yaml.MappingNode:
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					if value.Content[j].Value == "mapping" && value.Content[j+1].Kind == yaml.MappingNode {
+						mapping := value.Content[j+1]
+						for k := 1; k < len(mapping.Content); k += 2 {
+							u.reach(mapping.Content[k].Value)
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, child := range node.Content {
+		u.walk(child)
+	}
+}
+
+// reach marks the component a local reference points to (or into) as reached, and walks it.
+func (u *unusedWalker) reach(ref string) {
+	fragment, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return
+	}
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	segments := strings.SplitN(fragment, "/", 5)
+	if len(segments) < 4 || segments[0] != "" || segments[1] != "components" {
+		return
+	}
+	pointer := strings.Join(segments[:4], "/")
+	node, ok := u.components[pointer]
+	if !ok || u.reached[pointer] {
+		return
+	}
+	u.reached[pointer] = true
+	u.walk(node)
+}
+
+func (u *unusedWalker) security(requirements []*base.SecurityRequirement) {
+	for _, req := range requirements {
+		if req == nil {
+			continue
+		}
+		for pair := orderedmap.First(req.Requirements); pair != nil; pair = pair.Next() {
+			u.reach("#" + joinPointer("", "components", "securitySchemes", pair.Key()))
+		}
+	}
+}
+
+// pathItemSecurity reaches the security schemes required by the operations of pi, and of their callbacks.
+func (u *unusedWalker) pathItemSecurity(pi *PathItem) {
+	if pi == nil || u.seenItems[pi] {
+		return
+	}
+	u.seenItems[pi] = true
+	for pair := orderedmap.First(pi.GetOperations()); pair != nil; pair = pair.Next() {
+		op := pair.Value()
+		if op == nil {
+			continue
+		}
+		u.security(op.Security)
+		for cb := orderedmap.First(op.Callbacks); cb != nil; cb = cb.Next() {
+			if cb.Value() == nil {
+				continue
+			}
+			for exp := orderedmap.First(cb.Value().Expression); exp != nil; exp = exp.Next() {
+				u.pathItemSecurity(exp.Value())
+			}
+		}
+	}
+}
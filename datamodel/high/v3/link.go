@@ -4,8 +4,16 @@
 package v3
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/pb33f/libopenapi/datamodel/high"
+	lowmodel "github.com/pb33f/libopenapi/datamodel/low"
 	low "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"github.com/pb33f/libopenapi/index"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"gopkg.in/yaml.v3"
 )
@@ -53,6 +61,143 @@ func NewLink(link *low.Link) *Link {
 	return l
 }
 
+// ResolveOperation will return the Operation in doc that the Link points to, using 'operationId' or 'operationRef'.
+// An 'operationRef' must be a JSON pointer into the same document, for example '#/paths/~1pets~1{petId}/get'.
+// Operations in paths, webhooks and callbacks (including those in components) can be resolved.
+//
+// If the Link has no 'operationId' or 'operationRef', or the operation cannot be found, false is returned.
+func (l *Link) ResolveOperation(doc *Document) (*Operation, bool) {
+	if doc == nil {
+		return nil, false
+	}
+	if l.OperationId != "" {
+		for _, op := range doc.collectOperations() {
+			if op.OperationId == l.OperationId {
+				return op, true
+			}
+		}
+		return nil, false
+	}
+	if l.OperationRef == "" {
+		return nil, false
+	}
+	location, fragment, found := strings.Cut(l.OperationRef, "#")
+	if !found || location != "" {
+		return nil, false // only references into the same document are supported.
+	}
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	segments := strings.Split(strings.TrimPrefix(fragment, "/"), "/")
+	for i := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segments[i], "~1", "/"), "~0", "~")
+	}
+
+	var pathItem *PathItem
+	switch {
+	case len(segments) >= 3 && segments[0] == "paths" && doc.Paths != nil:
+		pathItem = lookup(doc.Paths.PathItems, segments[1])
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "webhooks":
+		pathItem = lookup(doc.Webhooks, segments[1])
+		segments = segments[2:]
+	case len(segments) >= 5 && segments[0] == "components" && segments[1] == "callbacks" && doc.Components != nil:
+		if cb := lookup(doc.Components.Callbacks, segments[2]); cb != nil {
+			pathItem = lookup(cb.Expression, segments[3])
+		}
+		segments = segments[4:]
+	}
+	for pathItem != nil {
+		op := lookup(pathItem.GetOperations(), segments[0])
+		if op == nil {
+			return nil, false
+		}
+		if len(segments) == 1 {
+			return op, true
+		}
+		// an operation inside a callback: <method>/callbacks/<name>/<expression>/<method>
+		if len(segments) < 5 || segments[1] != "callbacks" {
+			return nil, false
+		}
+		pathItem = nil
+		if cb := lookup(op.Callbacks, segments[2]); cb != nil {
+			pathItem = lookup(cb.Expression, segments[3])
+		}
+		segments = segments[4:]
+	}
+	return nil, false
+}
+
+// ResolveExternalOperation will return the Operation the Link points to, in the same way as ResolveOperation, and
+// will also follow an 'operationRef' into another file (for example 'pets.yaml#/paths/~1pets/get'). The file is
+// located relative to the root document by the rolodex of doc, and is loaded and indexed if it hasn't been already.
+//
+// The absolute path (or URL) of the file the Operation was found in is returned with it, which is the root document
+// when the Operation is in doc itself. An error is returned if the Operation cannot be resolved.
+func (l *Link) ResolveExternalOperation(doc *Document) (*Operation, string, error) {
+	if doc == nil {
+		return nil, "", errors.New("unable to resolve link operation, no document was provided")
+	}
+	var idx *index.SpecIndex
+	if doc.low != nil && doc.low.Index != nil {
+		idx = doc.low.Index
+	} else if doc.Rolodex != nil {
+		idx = doc.Rolodex.GetRootIndex()
+	}
+	rootPath := ""
+	if idx != nil {
+		rootPath = idx.GetSpecAbsolutePath()
+	}
+
+	if location, _, _ := strings.Cut(l.OperationRef, "#"); l.OperationId != "" || location == "" {
+		if op, ok := l.ResolveOperation(doc); ok {
+			return op, rootPath, nil
+		}
+		if l.OperationId != "" {
+			return nil, "", fmt.Errorf("unable to resolve link operation, operationId '%s' cannot be found",
+				l.OperationId)
+		}
+		return nil, "", fmt.Errorf("unable to resolve link operation, operationRef '%s' cannot be found",
+			l.OperationRef)
+	}
+
+	if idx == nil {
+		return nil, "", fmt.Errorf("unable to resolve link operation, operationRef '%s' is in another file, "+
+			"and the document has no index", l.OperationRef)
+	}
+	ref, _ := idx.SearchIndexForReference(l.OperationRef)
+	if ref == nil || ref.Node == nil || ref.Node.Kind != yaml.MappingNode {
+		return nil, "", fmt.Errorf("unable to resolve link operation, operationRef '%s' cannot be found",
+			l.OperationRef)
+	}
+
+	var lowOp low.Operation
+	if err := lowmodel.BuildModel(ref.Node, &lowOp); err != nil {
+		return nil, "", fmt.Errorf("unable to build operation '%s': %w", l.OperationRef, err)
+	}
+	refIndex := ref.Index
+	if refIndex == nil {
+		refIndex = idx
+	}
+	if err := lowOp.Build(context.Background(), ref.KeyNode, ref.Node, refIndex); err != nil {
+		return nil, "", fmt.Errorf("unable to build operation '%s': %w", l.OperationRef, err)
+	}
+	file := refIndex.GetSpecAbsolutePath()
+	if ref.Index == nil && ref.RemoteLocation != "" {
+		file = ref.RemoteLocation
+	}
+	return NewOperation(&lowOp), file, nil
+}
+
+// lookup returns the value for key, or the zero value if the map is nil or does not contain key.
+func lookup[V any](m *orderedmap.Map[string, V], key string) V {
+	var zero V
+	if m == nil {
+		return zero
+	}
+	return m.GetOrZero(key)
+}
+
 // GoLow will return the low-level Link instance used to create the high-level one.
 func (l *Link) GoLow() *low.Link {
 	return l.low
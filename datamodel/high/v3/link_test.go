@@ -4,11 +4,16 @@
 package v3
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/pb33f/libopenapi/datamodel"
+	lowv3 "github.com/pb33f/libopenapi/datamodel/low/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLink_MarshalYAML(t *testing.T) {
@@ -37,3 +42,136 @@ server:
 
 	assert.Equal(t, desired, strings.TrimSpace(string(dat)))
 }
+
+func TestLink_ResolveOperation(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+    post:
+      operationId: updatePet
+      callbacks:
+        petUpdated:
+          '{$request.body#/callbackUrl}':
+            post:
+              operationId: petUpdatedCallback
+webhooks:
+  newPet:
+    post:
+      operationId: newPet
+components:
+  callbacks:
+    petDeleted:
+      '{$request.body#/callbackUrl}':
+        delete:
+          operationId: petDeletedCallback`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	tests := []struct {
+		link *Link
+		want string
+	}{
+		{&Link{OperationId: "getPet"}, "getPet"},
+		{&Link{OperationId: "petUpdatedCallback"}, "petUpdatedCallback"},
+		{&Link{OperationRef: "#/paths/~1pets~1{petId}/get"}, "getPet"},
+		{&Link{OperationRef: "#/paths/~1pets~1%7BpetId%7D/post"}, "updatePet"},
+		{&Link{OperationRef: "#/paths/~1pets~1{petId}/post/callbacks/petUpdated/{$request.body#~1callbackUrl}/post"},
+			"petUpdatedCallback"},
+		{&Link{OperationRef: "#/webhooks/newPet/post"}, "newPet"},
+		{&Link{OperationRef: "#/components/callbacks/petDeleted/{$request.body#~1callbackUrl}/delete"},
+			"petDeletedCallback"},
+	}
+	for _, tt := range tests {
+		op, ok := tt.link.ResolveOperation(d)
+		assert.True(t, ok, tt.want)
+		assert.Equal(t, tt.want, op.OperationId)
+	}
+
+	missing := []*Link{
+		{},
+		{OperationId: "deletePet"},
+		{OperationRef: "#/paths/~1pets~1{petId}/delete"},
+		{OperationRef: "#/paths/~1owners/get"},
+		{OperationRef: "#/webhooks/oldPet/post"},
+		{OperationRef: "#/paths/~1pets~1{petId}/post/callbacks/nope/x/post"},
+		{OperationRef: "https://pb33f.io/openapi.yaml#/paths/~1pets~1{petId}/get"},
+	}
+	for _, l := range missing {
+		op, ok := l.ResolveOperation(d)
+		assert.False(t, ok)
+		assert.Nil(t, op)
+	}
+	_, ok := (&Link{OperationId: "getPet"}).ResolveOperation(nil)
+	assert.False(t, ok)
+}
+
+func TestLink_ResolveExternalOperation(t *testing.T) {
+	dir := t.TempDir()
+	pets := `openapi: 3.1.0
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+      responses:
+        "200":
+          description: a pet`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pets.yaml"), []byte(pets), 0o644))
+
+	yml := `openapi: 3.1.0
+paths:
+  /owners:
+    get:
+      operationId: listOwners
+      responses:
+        "200":
+          description: owners
+          links:
+            pet:
+              operationRef: 'pets.yaml#/paths/~1pets~1{petId}/get'
+            owners:
+              operationRef: '#/paths/~1owners/get'
+            missing:
+              operationRef: 'pets.yaml#/paths/~1cats/get'
+            missingId:
+              operationId: nope`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, &datamodel.DocumentConfiguration{
+		BasePath:            dir,
+		AllowFileReferences: true,
+	})
+	require.NoError(t, err)
+	doc := NewDocument(lDoc)
+	links := doc.Paths.PathItems.GetOrZero("/owners").Get.Responses.Codes.GetOrZero("200").Links
+
+	op, file, err := links.GetOrZero("pet").ResolveExternalOperation(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "getPet", op.OperationId)
+	assert.Equal(t, "petId", op.Parameters[0].Name)
+	abs, _ := filepath.Abs(filepath.Join(dir, "pets.yaml"))
+	assert.Equal(t, abs, file)
+
+	op, file, err = links.GetOrZero("owners").ResolveExternalOperation(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "listOwners", op.OperationId)
+	assert.Equal(t, lDoc.Index.GetSpecAbsolutePath(), file)
+
+	_, _, err = links.GetOrZero("missing").ResolveExternalOperation(doc)
+	assert.EqualError(t, err, "unable to resolve link operation, operationRef 'pets.yaml#/paths/~1cats/get' "+
+		"cannot be found")
+	_, _, err = links.GetOrZero("missingId").ResolveExternalOperation(doc)
+	assert.EqualError(t, err, "unable to resolve link operation, operationId 'nope' cannot be found")
+	_, _, err = links.GetOrZero("pet").ResolveExternalOperation(nil)
+	assert.Error(t, err)
+	_, _, err = links.GetOrZero("pet").ResolveExternalOperation(&Document{})
+	assert.Error(t, err)
+}
@@ -4,6 +4,10 @@
 package v3
 
 import (
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/pb33f/libopenapi/datamodel/high"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
@@ -72,6 +76,86 @@ func (m *MediaType) MarshalYAMLInline() (interface{}, error) {
 	return nb.Render(), nil
 }
 
+// GetSchema will return the Schema of the MediaType, resolving the SchemaProxy (and any reference it makes). Returns
+// nil if the MediaType has no schema, or the schema can't be built.
+func (m *MediaType) GetSchema() *base.Schema {
+	if m == nil || m.Schema == nil {
+		return nil
+	}
+	return m.Schema.Schema()
+}
+
+// ResolvedEncoding will return the Encoding of every property of the schema that has one, keyed by the property
+// name. Properties defined by the members of an 'allOf' are included. Encoding entries that don't match a property
+// are left out, use UnknownEncodings to find them.
+func (m *MediaType) ResolvedEncoding() map[string]*Encoding {
+	resolved := make(map[string]*Encoding)
+	properties := m.schemaProperties()
+	for pair := orderedmap.First(m.Encoding); pair != nil; pair = pair.Next() {
+		if properties[pair.Key()] {
+			resolved[pair.Key()] = pair.Value()
+		}
+	}
+	return resolved
+}
+
+// UnknownEncodings will return the name of every Encoding entry that doesn't match a property of the schema, in the
+// order they are defined. The spec requires every encoding name to exist as a property of the schema.
+func (m *MediaType) UnknownEncodings() []string {
+	var unknown []string
+	properties := m.schemaProperties()
+	for pair := orderedmap.First(m.Encoding); pair != nil; pair = pair.Next() {
+		if !properties[pair.Key()] {
+			unknown = append(unknown, pair.Key())
+		}
+	}
+	return unknown
+}
+
+// PartHeaders will return the headers of the multipart part for property, declared by the Encoding of the property,
+// keyed by the header name. Headers defined using a $ref are resolved when the model is built. As per the
+// specification, a 'Content-Type' header is ignored (it's described by the contentType of the Encoding instead).
+// Returns nil if the property has no Encoding, or the Encoding declares no headers.
+func (m *MediaType) PartHeaders(property string) map[string]*Header {
+	if m == nil || m.Encoding == nil {
+		return nil
+	}
+	encoding := m.Encoding.GetOrZero(property)
+	if encoding == nil || orderedmap.Len(encoding.Headers) == 0 {
+		return nil
+	}
+	headers := make(map[string]*Header, orderedmap.Len(encoding.Headers))
+	for pair := orderedmap.First(encoding.Headers); pair != nil; pair = pair.Next() {
+		if !strings.EqualFold(pair.Key(), "Content-Type") {
+			headers[pair.Key()] = pair.Value()
+		}
+	}
+	return headers
+}
+
+// schemaProperties returns the names of the properties defined by the schema, and the members of its allOf.
+func (m *MediaType) schemaProperties() map[string]bool {
+	properties := make(map[string]bool)
+	seen := make(map[*base.Schema]bool)
+	var collect func(s *base.Schema)
+	collect = func(s *base.Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		for pair := orderedmap.First(s.Properties); pair != nil; pair = pair.Next() {
+			properties[pair.Key()] = true
+		}
+		for _, member := range s.AllOf {
+			if member != nil {
+				collect(member.Schema())
+			}
+		}
+	}
+	collect(m.GetSchema())
+	return properties
+}
+
 // ExtractContent takes in a complex and hard to navigate low-level content map, and converts it in to a much simpler
 // and easier to navigate high-level one.
 func ExtractContent(elements *orderedmap.Map[lowmodel.KeyReference[string], lowmodel.ValueReference[*low.MediaType]]) *orderedmap.Map[string, *MediaType] {
@@ -89,3 +173,86 @@ func ExtractContent(elements *orderedmap.Map[lowmodel.KeyReference[string], lowm
 	_ = datamodel.TranslateMapParallel(elements, translateFunc, resultFunc)
 	return extracted
 }
+
+// mediaRange is a single media range parsed from an Accept header, e.g. 'application/*;q=0.8'.
+type mediaRange struct {
+	mainType string
+	subType  string
+	quality  float64
+}
+
+// splitMediaType splits a media type into its (lower-cased) type and subtype, any parameters are discarded.
+// A bare '*' is treated as '*/*'.
+func splitMediaType(mediaType string) (string, string) {
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == "*" {
+		return "*", "*"
+	}
+	mainType, subType, _ := strings.Cut(mediaType, "/")
+	return mainType, subType
+}
+
+// parseAcceptHeader parses an Accept header into media ranges, ordered by quality (highest first). Ranges with a
+// quality of zero are not acceptable, so they are dropped. An empty header accepts anything.
+func parseAcceptHeader(accept string) []mediaRange {
+	if strings.TrimSpace(accept) == "" {
+		return []mediaRange{{mainType: "*", subType: "*", quality: 1}}
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mainType, subType := splitMediaType(part)
+		if mainType == "" || subType == "" {
+			continue
+		}
+		quality := 1.0
+		params := strings.Split(part, ";")
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(param, "=")
+			if strings.EqualFold(strings.TrimSpace(name), "q") {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		ranges = append(ranges, mediaRange{mainType: mainType, subType: subType, quality: quality})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+	return ranges
+}
+
+// matchContent finds the media type in content that best matches the supplied type and subtype (either of which
+// may be a '*' wildcard), content keys may be wildcards as well. The most specific content key wins, ties are
+// broken by the order the media types are defined in.
+func matchContent(content *orderedmap.Map[string, *MediaType], mainType, subType string) (*MediaType, string, bool) {
+	var bestKey string
+	var best *MediaType
+	bestScore := -1
+	for pair := orderedmap.First(content); pair != nil; pair = pair.Next() {
+		keyType, keySubType := splitMediaType(pair.Key())
+		if keyType != "*" && mainType != "*" && keyType != mainType {
+			continue
+		}
+		if keySubType != "*" && subType != "*" && keySubType != subType {
+			continue
+		}
+		score := 0
+		if keyType != "*" && keyType == mainType {
+			score += 2
+		}
+		if keySubType != "*" && keySubType == subType {
+			score++
+		}
+		if score > bestScore {
+			best, bestKey, bestScore = pair.Value(), pair.Key(), score
+		}
+	}
+	return best, bestKey, best != nil
+}
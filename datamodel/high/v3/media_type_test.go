@@ -165,3 +165,109 @@ func TestMediaType_Examples(t *testing.T) {
 	rend, _ := r.Render()
 	assert.Len(t, rend, 290)
 }
+
+func TestMediaType_ResolvedEncoding(t *testing.T) {
+	yml := `schema:
+  type: object
+  properties:
+    id:
+      type: string
+    profileImage:
+      type: string
+      format: binary
+  allOf:
+    - properties:
+        address:
+          type: object
+encoding:
+  profileImage:
+    contentType: image/png
+  address:
+    contentType: application/json
+  nope:
+    contentType: text/plain`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndexWithConfig(&idxNode, index.CreateOpenAPIIndexConfig())
+
+	var n v3.MediaType
+	_ = low.BuildModel(idxNode.Content[0], &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	r := NewMediaType(&n)
+
+	assert.Equal(t, []string{"object"}, r.GetSchema().Type)
+
+	resolved := r.ResolvedEncoding()
+	assert.Len(t, resolved, 2)
+	assert.Equal(t, "image/png", resolved["profileImage"].ContentType)
+	assert.Equal(t, "application/json", resolved["address"].ContentType)
+	assert.Equal(t, []string{"nope"}, r.UnknownEncodings())
+}
+
+func TestMediaType_PartHeaders(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /upload:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                file:
+                  type: string
+                  format: binary
+                meta:
+                  type: object
+            encoding:
+              file:
+                contentType: image/png
+                headers:
+                  X-Rate-Limit:
+                    $ref: '#/components/headers/RateLimit'
+                  X-Checksum:
+                    description: checksum of the part
+                    schema:
+                      type: string
+                  Content-Type:
+                    schema:
+                      type: string
+              meta:
+                contentType: application/json
+components:
+  headers:
+    RateLimit:
+      description: calls per hour
+      schema:
+        type: integer`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := v3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	mt := d.Paths.PathItems.GetOrZero("/upload").Post.RequestBody.Content.GetOrZero("multipart/form-data")
+	file := mt.Encoding.GetOrZero("file")
+	assert.Equal(t, 3, file.Headers.Len())
+	assert.Equal(t, "calls per hour", file.Headers.GetOrZero("X-Rate-Limit").Description)
+
+	headers := mt.PartHeaders("file")
+	assert.Len(t, headers, 2)
+	assert.Equal(t, "calls per hour", headers["X-Rate-Limit"].Description)
+	assert.Equal(t, []string{"integer"}, headers["X-Rate-Limit"].Schema.Schema().Type)
+	assert.Equal(t, "checksum of the part", headers["X-Checksum"].Description)
+
+	assert.Nil(t, mt.PartHeaders("meta"))
+	assert.Nil(t, mt.PartHeaders("nope"))
+	assert.Nil(t, (&MediaType{}).PartHeaders("file"))
+}
+
+func TestMediaType_ResolvedEncoding_NoSchema(t *testing.T) {
+	r := &MediaType{}
+	assert.Nil(t, r.GetSchema())
+	assert.Empty(t, r.ResolvedEncoding())
+	assert.Nil(t, r.UnknownEncodings())
+}
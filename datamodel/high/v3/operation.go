@@ -4,10 +4,16 @@
 package v3
 
 import (
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+
 	"github.com/pb33f/libopenapi/datamodel/high"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	low "github.com/pb33f/libopenapi/datamodel/low/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
 	"gopkg.in/yaml.v3"
 )
 
@@ -93,6 +99,147 @@ func NewOperation(operation *low.Operation) *Operation {
 	return o
 }
 
+// SetOperationId will set the OperationId of the Operation, and will also update the backing low-level model
+// and its *yaml.Node, so the new id is rendered. If the low-level Operation has no operationId yet, one is added
+// to its node. An empty id returns an error.
+func (o *Operation) SetOperationId(id string) error {
+	if strings.TrimSpace(id) == "" {
+		return errors.New("unable to set operationId, the id cannot be empty")
+	}
+	o.OperationId = id
+	if o.low == nil {
+		return nil
+	}
+	o.low.OperationId.Value = id
+	if o.low.OperationId.ValueNode != nil {
+		o.low.OperationId.ValueNode.Value = id
+		return nil
+	}
+	if o.low.RootNode != nil && o.low.RootNode.Kind == yaml.MappingNode {
+		keyNode := utils.CreateStringNode(low.OperationIdLabel)
+		valueNode := utils.CreateStringNode(id)
+		o.low.RootNode.Content = append(o.low.RootNode.Content, keyNode, valueNode)
+		o.low.OperationId.KeyNode = keyNode
+		o.low.OperationId.ValueNode = valueNode
+	}
+	return nil
+}
+
+// EffectiveParameters returns every parameter that applies to the Operation, merging the parameters defined by the
+// parent PathItem with those defined by the Operation. As per the specification, an Operation parameter overrides a
+// PathItem parameter with the same name and location ('in'). Path level parameters come first (in their defined
+// order), followed by the Operation parameters. Parameters defined using a $ref are resolved when the model is
+// built, so the returned parameters are always the resolved ones. The parent may be nil.
+func (o *Operation) EffectiveParameters(parent *PathItem) []*Parameter {
+	key := func(p *Parameter) string {
+		return p.In + ":" + p.Name
+	}
+	overridden := make(map[string]bool)
+	for _, param := range o.Parameters {
+		if param != nil {
+			overridden[key(param)] = true
+		}
+	}
+	var params []*Parameter
+	if parent != nil {
+		for _, param := range parent.Parameters {
+			if param != nil && !overridden[key(param)] {
+				params = append(params, param)
+			}
+		}
+	}
+	for _, param := range o.Parameters {
+		if param != nil {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// RequiredParametersByLocation returns the required parameters of the Operation (see EffectiveParameters, so
+// parameters of the parent PathItem are inherited), keyed by their location ('query', 'header', 'path' or
+// 'cookie'). Parameters are in the order of EffectiveParameters. Path parameters are always required by the
+// specification, so they are included even when they are not marked as required. The parent may be nil.
+func (o *Operation) RequiredParametersByLocation(parent *PathItem) map[string][]*Parameter {
+	required := make(map[string][]*Parameter)
+	for _, param := range o.EffectiveParameters(parent) {
+		if param.In == "path" || (param.Required != nil && *param.Required) {
+			required[param.In] = append(required[param.In], param)
+		}
+	}
+	return required
+}
+
+// EffectiveServers returns the servers that apply to the Operation. As per the specification, servers defined by
+// the Operation override those defined by the parent PathItem, which override those defined by the Document. If no
+// servers are defined at any level, a single Server with a URL of '/' is returned. The path and doc may be nil.
+func (o *Operation) EffectiveServers(path *PathItem, doc *Document) []*Server {
+	switch {
+	case len(o.Servers) > 0:
+		return o.Servers
+	case path != nil && len(path.Servers) > 0:
+		return path.Servers
+	case doc != nil && len(doc.Servers) > 0:
+		return doc.Servers
+	}
+	return []*Server{{URL: "/"}}
+}
+
+// RequestContentTypes returns the media types the request body of the Operation accepts, in the order they are
+// defined. Returns nil if the Operation has no request body.
+func (o *Operation) RequestContentTypes() []string {
+	if o.RequestBody == nil {
+		return nil
+	}
+	return appendContentTypes(nil, o.RequestBody.Content)
+}
+
+// ResponseContentTypes returns the media types produced by every response of the Operation (including the default
+// response), without duplicates and in the order they are defined.
+func (o *Operation) ResponseContentTypes() []string {
+	if o.Responses == nil {
+		return nil
+	}
+	type responseItem struct {
+		resp *Response
+		line int
+	}
+	var responses []responseItem
+	add := func(r *Response) {
+		if r == nil {
+			return
+		}
+		ln := 9999 // default to a high value, so new responses come last.
+		if r.low != nil && r.low.RootNode != nil {
+			ln = r.low.RootNode.Line
+		}
+		responses = append(responses, responseItem{r, ln})
+	}
+	for pair := orderedmap.First(o.Responses.Codes); pair != nil; pair = pair.Next() {
+		add(pair.Value())
+	}
+	add(o.Responses.Default)
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].line < responses[j].line
+	})
+
+	var types []string
+	for _, r := range responses {
+		types = appendContentTypes(types, r.resp.Content)
+	}
+	return types
+}
+
+// appendContentTypes appends the media types of content to types, skipping any that are already present.
+func appendContentTypes(types []string, content *orderedmap.Map[string, *MediaType]) []string {
+	for pair := orderedmap.First(content); pair != nil; pair = pair.Next() {
+		if !slices.Contains(types, pair.Key()) {
+			types = append(types, pair.Key())
+		}
+	}
+	return types
+}
+
 // GoLow will return the low-level Operation instance that was used to create the high-level one.
 func (o *Operation) GoLow() *low.Operation {
 	return o.low
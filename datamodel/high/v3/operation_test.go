@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 
 	"github.com/pb33f/libopenapi/datamodel/low"
@@ -161,3 +162,228 @@ func TestOperation_NoSecurity(t *testing.T) {
 
 	assert.Nil(t, r.Security)
 }
+
+func TestOperation_SetOperationId(t *testing.T) {
+	yml := `operationId: pizza
+summary: cake`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndex(&idxNode)
+
+	var n v3.Operation
+	_ = low.BuildModel(&idxNode, &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	r := NewOperation(&n)
+	assert.NoError(t, r.SetOperationId("burger"))
+	assert.Equal(t, "burger", r.OperationId)
+	assert.Equal(t, "burger", r.GoLow().OperationId.Value)
+	assert.Equal(t, "burger", r.GoLow().OperationId.ValueNode.Value)
+
+	rend, _ := r.Render()
+	assert.Contains(t, string(rend), "operationId: burger")
+
+	assert.Error(t, r.SetOperationId(" "))
+	assert.Equal(t, "burger", r.OperationId)
+}
+
+func TestOperation_SetOperationId_Missing(t *testing.T) {
+	yml := `summary: cake`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndex(&idxNode)
+
+	var n v3.Operation
+	_ = low.BuildModel(&idxNode, &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	r := NewOperation(&n)
+	assert.NoError(t, r.SetOperationId("burger"))
+	assert.Equal(t, "burger", r.GoLow().OperationId.ValueNode.Value)
+	assert.Len(t, r.GoLow().RootNode.Content, 4)
+	assert.Equal(t, "operationId", r.GoLow().RootNode.Content[2].Value)
+
+	// no low-level model, only the high-level field is set.
+	op := &Operation{}
+	assert.NoError(t, op.SetOperationId("fries"))
+	assert.Equal(t, "fries", op.OperationId)
+}
+
+func TestOperation_EffectiveParameters(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        description: path level id
+      - $ref: '#/components/parameters/Limit'
+      - name: id
+        in: query
+        description: a query id
+    get:
+      parameters:
+        - name: id
+          in: path
+          description: operation level id
+        - name: verbose
+          in: header
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      description: how many pets`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := v3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	pathItem := d.Paths.PathItems.GetOrZero("/pets/{id}")
+	params := pathItem.Get.EffectiveParameters(pathItem)
+	assert.Len(t, params, 4)
+	assert.Equal(t, "limit", params[0].Name)
+	assert.Equal(t, "how many pets", params[0].Description)
+	assert.Equal(t, "a query id", params[1].Description)
+	assert.Equal(t, "operation level id", params[2].Description)
+	assert.Equal(t, "verbose", params[3].Name)
+
+	params = pathItem.Get.EffectiveParameters(nil)
+	assert.Len(t, params, 2)
+}
+
+func TestOperation_RequiredParametersByLocation(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+      - name: X-Tenant
+        in: header
+        required: true
+      - name: verbose
+        in: query
+        required: true
+    get:
+      parameters:
+        - name: verbose
+          in: query
+          required: false
+        - $ref: '#/components/parameters/Limit'
+        - name: session
+          in: cookie
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      required: true`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := v3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	names := func(params []*Parameter) []string {
+		var n []string
+		for _, p := range params {
+			n = append(n, p.Name)
+		}
+		return n
+	}
+
+	pathItem := d.Paths.PathItems.GetOrZero("/pets/{id}")
+	required := pathItem.Get.RequiredParametersByLocation(pathItem)
+	assert.Len(t, required, 3)
+	assert.Equal(t, []string{"id"}, names(required["path"]))
+	assert.Equal(t, []string{"X-Tenant"}, names(required["header"]))
+	assert.Equal(t, []string{"limit"}, names(required["query"]))
+	assert.Empty(t, required["cookie"])
+
+	required = pathItem.Get.RequiredParametersByLocation(nil)
+	assert.Len(t, required, 1)
+	assert.Equal(t, []string{"limit"}, names(required["query"]))
+}
+
+func TestOperation_EffectiveServers(t *testing.T) {
+	yml := `openapi: 3.1.0
+servers:
+  - url: https://api.pb33f.io
+paths:
+  /pets:
+    servers:
+      - url: https://pets.pb33f.io
+    get:
+      servers:
+        - url: https://get.pb33f.io
+        - url: https://backup.pb33f.io
+    post: {}
+  /health:
+    get: {}`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := v3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	urls := func(servers []*Server) []string {
+		var u []string
+		for _, s := range servers {
+			u = append(u, s.URL)
+		}
+		return u
+	}
+
+	pets := d.Paths.PathItems.GetOrZero("/pets")
+	health := d.Paths.PathItems.GetOrZero("/health")
+	assert.Equal(t, []string{"https://get.pb33f.io", "https://backup.pb33f.io"}, urls(pets.Get.EffectiveServers(pets, d)))
+	assert.Equal(t, []string{"https://pets.pb33f.io"}, urls(pets.Post.EffectiveServers(pets, d)))
+	assert.Equal(t, []string{"https://api.pb33f.io"}, urls(health.Get.EffectiveServers(health, d)))
+	assert.Equal(t, []string{"/"}, urls(health.Get.EffectiveServers(nil, nil)))
+}
+
+func TestOperation_ContentTypes(t *testing.T) {
+	yml := `openapi: 3.1.0
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json: {}
+          application/xml: {}
+      responses:
+        default:
+          content:
+            application/problem+json: {}
+        "201":
+          content:
+            application/json: {}
+            application/xml: {}
+        "400":
+          content:
+            application/problem+json: {}
+            text/plain: {}
+    get:
+      responses:
+        "204":
+          description: nothing`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	lDoc, err := v3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	d := NewDocument(lDoc)
+
+	post := d.Paths.PathItems.GetOrZero("/pets").Post
+	assert.Equal(t, []string{"application/json", "application/xml"}, post.RequestContentTypes())
+	assert.Equal(t, []string{"application/problem+json", "application/json", "application/xml", "text/plain"},
+		post.ResponseContentTypes())
+
+	get := d.Paths.PathItems.GetOrZero("/pets").Get
+	assert.Nil(t, get.RequestContentTypes())
+	assert.Nil(t, get.ResponseContentTypes())
+	assert.Nil(t, (&Operation{}).ResponseContentTypes())
+}
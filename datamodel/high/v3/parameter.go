@@ -4,6 +4,9 @@
 package v3
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/pb33f/libopenapi/datamodel/high"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	low "github.com/pb33f/libopenapi/datamodel/low/v3"
@@ -129,3 +132,48 @@ func (p *Parameter) IsDefaultHeaderEncoding() bool {
 func (p *Parameter) IsDefaultPathEncoding() bool {
 	return p.IsDefaultHeaderEncoding() // header default encoding and path default encoding are the same
 }
+
+// ResolvedSchema will return the Schema that defines the parameter, whether it's defined by 'schema', or by the single
+// media type of 'content'. When the Schema comes from 'content' the media type is returned as well, otherwise it's
+// empty. The Schema is nil if the media type of 'content' has no schema.
+//
+// As per the specification, a parameter must define exactly one of 'schema' or 'content', and 'content' must contain
+// exactly one media type, an error is returned otherwise. An error is also returned if the Schema can't be built.
+func (p *Parameter) ResolvedSchema() (*base.Schema, string, error) {
+	hasContent := orderedmap.Len(p.Content) > 0
+	switch {
+	case p.Schema != nil && hasContent:
+		return nil, "", fmt.Errorf("parameter '%s' defines both 'schema' and 'content', only one is allowed", p.Name)
+	case p.Schema != nil:
+		schema := p.Schema.Schema()
+		if schema == nil {
+			return nil, "", schemaBuildError(fmt.Sprintf("unable to build the schema of parameter '%s'", p.Name),
+				p.Schema.GetBuildError())
+		}
+		return schema, "", nil
+	case !hasContent:
+		return nil, "", fmt.Errorf("parameter '%s' defines neither 'schema' nor 'content'", p.Name)
+	case orderedmap.Len(p.Content) > 1:
+		return nil, "", fmt.Errorf("parameter '%s' defines %d media types in 'content', only one is allowed",
+			p.Name, orderedmap.Len(p.Content))
+	}
+	pair := orderedmap.First(p.Content)
+	mediaType := pair.Value()
+	if mediaType == nil || mediaType.Schema == nil {
+		return nil, pair.Key(), nil
+	}
+	schema := mediaType.Schema.Schema()
+	if schema == nil {
+		return nil, pair.Key(), schemaBuildError(fmt.Sprintf("unable to build the schema of parameter '%s' "+
+			"(media type '%s')", p.Name, pair.Key()), mediaType.Schema.GetBuildError())
+	}
+	return schema, pair.Key(), nil
+}
+
+// schemaBuildError returns an error with message, wrapping err if there is one.
+func schemaBuildError(message string, err error) error {
+	if err == nil {
+		return errors.New(message)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
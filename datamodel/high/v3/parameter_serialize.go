@@ -0,0 +1,249 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EffectiveStyle will return the style and explode values that apply to the Parameter, using the defaults defined
+// by the specification when they are not set. The default style is 'form' for query and cookie parameters, and
+// 'simple' for path and header parameters. The default explode value is true when the style is 'form', otherwise
+// false.
+//   - https://spec.openapis.org/oas/v3.1.0#style-values
+func (p *Parameter) EffectiveStyle() (style string, explode bool) {
+	style = p.Style
+	if style == "" {
+		switch p.In {
+		case "query", "cookie":
+			style = "form"
+		case "path", "header":
+			style = "simple"
+		}
+	}
+	if p.Explode != nil {
+		return style, *p.Explode
+	}
+	return style, style == "form"
+}
+
+// SerializeValue will serialize value the way the Parameter requires it to be sent, using its EffectiveStyle. The
+// value can be a primitive (a string, bool or number), a slice or array of primitives, or a map with string keys
+// (an object, whose properties are serialized in key order). Pointers are followed.
+//
+// The styles 'matrix', 'label', 'form', 'simple', 'spaceDelimited', 'pipeDelimited' and 'deepObject' are supported.
+// Serialized values are prefixed with the name of the Parameter when the style requires it (for example
+// 'color=blue' for the 'form' style). Values of query parameters are percent-encoded unless reserved characters are
+// allowed, values of path parameters are percent-encoded, header and cookie values are not encoded.
+//
+// An error is returned if the Parameter uses 'content' instead of a style, if the style is not supported, or if the
+// value can't be serialized using the style.
+func (p *Parameter) SerializeValue(value any) (string, error) {
+	if p.Content != nil && p.Content.Len() > 0 {
+		return "", fmt.Errorf("unable to serialize parameter '%s', it's serialized using content", p.Name)
+	}
+	style, explode := p.EffectiveStyle()
+
+	kind, primitive, array, object, err := p.serializableValue(value)
+	if err != nil {
+		return "", fmt.Errorf("unable to serialize parameter '%s': %w", p.Name, err)
+	}
+
+	// pairs joins object properties with sep between each key and value, and between each pair.
+	pairs := func(keySep, pairSep string) string {
+		parts := make([]string, 0, len(object))
+		for i := 0; i+1 < len(object); i += 2 {
+			parts = append(parts, object[i]+keySep+object[i+1])
+		}
+		return strings.Join(parts, pairSep)
+	}
+	// repeat serializes every item of an array as its own name/value pair.
+	repeat := func(prefix, sep string) string {
+		parts := make([]string, 0, len(array))
+		for _, item := range array {
+			parts = append(parts, prefix+p.Name+"="+item)
+		}
+		return strings.Join(parts, sep)
+	}
+
+	switch style {
+	case "matrix":
+		switch {
+		case kind == reflect.Invalid:
+			return ";" + p.Name, nil
+		case array != nil && explode:
+			return repeat(";", ""), nil
+		case array != nil:
+			return ";" + p.Name + "=" + strings.Join(array, ","), nil
+		case object != nil && explode:
+			return ";" + pairs("=", ";"), nil
+		case object != nil:
+			return ";" + p.Name + "=" + strings.Join(object, ","), nil
+		}
+		return ";" + p.Name + "=" + primitive, nil
+	case "label":
+		switch {
+		case kind == reflect.Invalid:
+			return ".", nil
+		case array != nil && explode:
+			return "." + strings.Join(array, "."), nil
+		case array != nil:
+			return "." + strings.Join(array, ","), nil
+		case object != nil && explode:
+			return "." + pairs("=", "."), nil
+		case object != nil:
+			return "." + strings.Join(object, ","), nil
+		}
+		return "." + primitive, nil
+	case "form":
+		switch {
+		case kind == reflect.Invalid:
+			return p.Name + "=", nil
+		case array != nil && explode:
+			return repeat("", "&"), nil
+		case array != nil:
+			return p.Name + "=" + strings.Join(array, ","), nil
+		case object != nil && explode:
+			return pairs("=", "&"), nil
+		case object != nil:
+			return p.Name + "=" + strings.Join(object, ","), nil
+		}
+		return p.Name + "=" + primitive, nil
+	case "simple":
+		switch {
+		case kind == reflect.Invalid:
+			return "", nil
+		case array != nil:
+			return strings.Join(array, ","), nil
+		case object != nil && explode:
+			return pairs("=", ","), nil
+		case object != nil:
+			return strings.Join(object, ","), nil
+		}
+		return primitive, nil
+	case "spaceDelimited", "pipeDelimited":
+		sep := "%20"
+		if style == "pipeDelimited" {
+			sep = "|"
+		}
+		switch {
+		case array != nil && explode:
+			return repeat("", "&"), nil
+		case array != nil:
+			return p.Name + "=" + strings.Join(array, sep), nil
+		case object != nil && !explode:
+			return p.Name + "=" + strings.Join(object, sep), nil
+		}
+		return "", fmt.Errorf("unable to serialize parameter '%s', the '%s' style requires an array "+
+			"(or an object, when not exploded)", p.Name, style)
+	case "deepObject":
+		if object == nil {
+			return "", fmt.Errorf("unable to serialize parameter '%s', the 'deepObject' style requires an object",
+				p.Name)
+		}
+		parts := make([]string, 0, len(object)/2)
+		for i := 0; i+1 < len(object); i += 2 {
+			parts = append(parts, p.Name+"["+object[i]+"]="+object[i+1])
+		}
+		return strings.Join(parts, "&"), nil
+	case "":
+		return "", fmt.Errorf("unable to serialize parameter '%s', no style is defined for location '%s'",
+			p.Name, p.In)
+	}
+	return "", fmt.Errorf("unable to serialize parameter '%s', the style '%s' is not supported", p.Name, style)
+}
+
+// serializableValue breaks value down into an encoded primitive, an array of encoded primitives, or an object (a flat
+// list of encoded keys and values, in key order). The returned kind is reflect.Invalid for a nil value.
+func (p *Parameter) serializableValue(value any) (kind reflect.Kind, primitive string, array, object []string, err error) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Invalid, "", nil, nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+		return reflect.Invalid, "", nil, nil, nil
+	case reflect.Slice, reflect.Array:
+		array = make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, e := formatPrimitive(v.Index(i))
+			if e != nil {
+				return 0, "", nil, nil, e
+			}
+			array = append(array, p.encodeValue(item))
+		}
+		return v.Kind(), "", array, nil, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return 0, "", nil, nil, errors.New("object keys must be strings")
+		}
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		object = make([]string, 0, len(keys)*2)
+		for _, k := range keys {
+			item, e := formatPrimitive(v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key())))
+			if e != nil {
+				return 0, "", nil, nil, e
+			}
+			object = append(object, p.encodeValue(k), p.encodeValue(item))
+		}
+		return v.Kind(), "", nil, object, nil
+	}
+	primitive, err = formatPrimitive(v)
+	if err != nil {
+		return 0, "", nil, nil, err
+	}
+	return v.Kind(), p.encodeValue(primitive), nil, nil, nil
+}
+
+// encodeValue percent-encodes s, if the location of the Parameter requires it.
+func (p *Parameter) encodeValue(s string) string {
+	switch p.In {
+	case "query":
+		if p.AllowReserved {
+			return s
+		}
+		return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+	case "path":
+		return url.PathEscape(s)
+	}
+	return s
+}
+
+// formatPrimitive returns the string form of a primitive value.
+func formatPrimitive(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("values of type '%s' can't be serialized", v.Type())
+}
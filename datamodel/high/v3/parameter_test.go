@@ -169,3 +169,151 @@ func TestParameter_IsDefaultPathEncoding(t *testing.T) {
 	param := Parameter{}
 	assert.True(t, param.IsDefaultPathEncoding())
 }
+
+func TestParameter_EffectiveStyle(t *testing.T) {
+	style, explode := (&Parameter{In: "query"}).EffectiveStyle()
+	assert.Equal(t, "form", style)
+	assert.True(t, explode)
+
+	style, explode = (&Parameter{In: "cookie"}).EffectiveStyle()
+	assert.Equal(t, "form", style)
+	assert.True(t, explode)
+
+	style, explode = (&Parameter{In: "path"}).EffectiveStyle()
+	assert.Equal(t, "simple", style)
+	assert.False(t, explode)
+
+	style, explode = (&Parameter{In: "header"}).EffectiveStyle()
+	assert.Equal(t, "simple", style)
+	assert.False(t, explode)
+
+	style, explode = (&Parameter{In: "query", Style: "deepObject"}).EffectiveStyle()
+	assert.Equal(t, "deepObject", style)
+	assert.False(t, explode)
+
+	no := false
+	style, explode = (&Parameter{In: "query", Explode: &no}).EffectiveStyle()
+	assert.Equal(t, "form", style)
+	assert.False(t, explode)
+}
+
+func TestParameter_SerializeValue(t *testing.T) {
+	yes, no := true, false
+	primitive := "blue"
+	array := []string{"blue", "black", "brown"}
+	object := map[string]int{"R": 100, "G": 200, "B": 150}
+
+	tests := []struct {
+		in, style string
+		explode   *bool
+		value     any
+		expected  string
+	}{
+		{"path", "matrix", &no, primitive, ";color=blue"},
+		{"path", "matrix", &no, array, ";color=blue,black,brown"},
+		{"path", "matrix", &no, object, ";color=B,150,G,200,R,100"},
+		{"path", "matrix", &yes, array, ";color=blue;color=black;color=brown"},
+		{"path", "matrix", &yes, object, ";B=150;G=200;R=100"},
+		{"path", "label", &no, primitive, ".blue"},
+		{"path", "label", &no, array, ".blue,black,brown"},
+		{"path", "label", &yes, array, ".blue.black.brown"},
+		{"path", "label", &yes, object, ".B=150.G=200.R=100"},
+		{"path", "", nil, primitive, "blue"},
+		{"path", "", nil, array, "blue,black,brown"},
+		{"path", "", nil, object, "B,150,G,200,R,100"},
+		{"path", "", &yes, object, "B=150,G=200,R=100"},
+		{"header", "", nil, 42, "42"},
+		{"query", "", nil, primitive, "color=blue"},
+		{"query", "", nil, array, "color=blue&color=black&color=brown"},
+		{"query", "", nil, object, "B=150&G=200&R=100"},
+		{"query", "form", &no, array, "color=blue,black,brown"},
+		{"query", "form", &no, object, "color=B,150,G,200,R,100"},
+		{"query", "spaceDelimited", &no, array, "color=blue%20black%20brown"},
+		{"query", "pipeDelimited", &no, array, "color=blue|black|brown"},
+		{"query", "pipeDelimited", &yes, array, "color=blue&color=black&color=brown"},
+		{"query", "deepObject", &yes, object, "color[B]=150&color[G]=200&color[R]=100"},
+		{"query", "", nil, "light blue", "color=light%20blue"},
+		{"query", "", nil, &primitive, "color=blue"},
+		{"query", "", nil, []any{1.5, true}, "color=1.5&color=true"},
+		{"path", "", nil, "a/b", "a%2Fb"},
+		{"cookie", "", nil, "a b", "color=a b"},
+	}
+	for _, tc := range tests {
+		param := &Parameter{Name: "color", In: tc.in, Style: tc.style, Explode: tc.explode}
+		result, err := param.SerializeValue(tc.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, result, "%s %s %v", tc.in, tc.style, tc.value)
+	}
+}
+
+func TestParameter_SerializeValue_Errors(t *testing.T) {
+	_, err := (&Parameter{Name: "color", In: "query", Style: "deepObject"}).SerializeValue("blue")
+	assert.Error(t, err)
+
+	_, err = (&Parameter{Name: "color", In: "query", Style: "pipeDelimited"}).SerializeValue("blue")
+	assert.Error(t, err)
+
+	_, err = (&Parameter{Name: "color", In: "query", Style: "nope"}).SerializeValue("blue")
+	assert.Error(t, err)
+
+	_, err = (&Parameter{Name: "color", In: "body"}).SerializeValue("blue")
+	assert.Error(t, err)
+
+	_, err = (&Parameter{Name: "color", In: "query"}).SerializeValue(struct{}{})
+	assert.Error(t, err)
+
+	_, err = (&Parameter{Name: "color", In: "query"}).SerializeValue(map[int]string{1: "a"})
+	assert.Error(t, err)
+
+	_, err = (&Parameter{Name: "color", In: "query"}).SerializeValue([][]string{{"a"}})
+	assert.Error(t, err)
+}
+
+func TestParameter_ResolvedSchema(t *testing.T) {
+	str := &base.Schema{Type: []string{"string"}}
+	obj := &base.Schema{Type: []string{"object"}}
+	content := func(mediaTypes ...string) *orderedmap.Map[string, *MediaType] {
+		c := orderedmap.New[string, *MediaType]()
+		for _, mt := range mediaTypes {
+			c.Set(mt, &MediaType{Schema: base.CreateSchemaProxy(obj)})
+		}
+		return c
+	}
+
+	schema, mediaType, err := (&Parameter{Name: "id", Schema: base.CreateSchemaProxy(str)}).ResolvedSchema()
+	assert.NoError(t, err)
+	assert.Same(t, str, schema)
+	assert.Empty(t, mediaType)
+
+	schema, mediaType, err = (&Parameter{Name: "filter", Content: content("application/json")}).ResolvedSchema()
+	assert.NoError(t, err)
+	assert.Same(t, obj, schema)
+	assert.Equal(t, "application/json", mediaType)
+
+	noSchema := orderedmap.New[string, *MediaType]()
+	noSchema.Set("text/plain", &MediaType{})
+	schema, mediaType, err = (&Parameter{Name: "filter", Content: noSchema}).ResolvedSchema()
+	assert.NoError(t, err)
+	assert.Nil(t, schema)
+	assert.Equal(t, "text/plain", mediaType)
+
+	_, _, err = (&Parameter{Name: "both", Schema: base.CreateSchemaProxy(str),
+		Content: content("application/json")}).ResolvedSchema()
+	assert.EqualError(t, err, "parameter 'both' defines both 'schema' and 'content', only one is allowed")
+
+	_, _, err = (&Parameter{Name: "neither"}).ResolvedSchema()
+	assert.EqualError(t, err, "parameter 'neither' defines neither 'schema' nor 'content'")
+
+	_, _, err = (&Parameter{Name: "many", Content: content("application/json", "application/xml")}).ResolvedSchema()
+	assert.EqualError(t, err, "parameter 'many' defines 2 media types in 'content', only one is allowed")
+
+	// a schema that can't be built, without a build error to wrap.
+	_, _, err = (&Parameter{Name: "broken", Schema: &base.SchemaProxy{}}).ResolvedSchema()
+	assert.EqualError(t, err, "unable to build the schema of parameter 'broken'")
+
+	broken := orderedmap.New[string, *MediaType]()
+	broken.Set("application/json", &MediaType{Schema: &base.SchemaProxy{}})
+	_, mediaType, err = (&Parameter{Name: "broken", Content: broken}).ResolvedSchema()
+	assert.EqualError(t, err, "unable to build the schema of parameter 'broken' (media type 'application/json')")
+	assert.Equal(t, "application/json", mediaType)
+}
@@ -4,7 +4,10 @@
 package v3
 
 import (
+	"net/url"
+	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/pb33f/libopenapi/datamodel/high"
@@ -63,6 +66,213 @@ func (p *Paths) GoLowUntyped() any {
 	return p.low
 }
 
+// Count returns the number of paths, zero if Paths is nil. The low-level Paths is counted when there is one, so no
+// high-level objects are built.
+func (p *Paths) Count() int {
+	if p == nil {
+		return 0
+	}
+	if p.low != nil {
+		return p.low.Count()
+	}
+	return orderedmap.Len(p.PathItems)
+}
+
+// MatchPath will locate the PathItem whose path template matches a concrete request path (e.g. '/pets/123' matches
+// '/pets/{id}'). The matched PathItem is returned along with the path parameter values extracted from the concrete
+// path (percent-decoded), keyed by parameter name.
+//
+// Matching follows the precedence rules of the specification, a path with no templating that matches exactly always
+// wins. When multiple templated paths match, the one with literal segments earliest in the path wins (so
+// '/pets/mine' is preferred over '/pets/{id}'), ties are broken by the order of the paths in the document.
+// Multiple parameters in one segment are supported when they are separated by literal text (e.g. '/{name}.{ext}'),
+// adjacent parameters (e.g. '/{a}{b}') are ambiguous and never match. Any query string or fragment is ignored.
+func (p *Paths) MatchPath(concrete string) (*PathItem, map[string]string, bool) {
+	if p == nil || p.PathItems == nil {
+		return nil, nil, false
+	}
+	if i := strings.IndexAny(concrete, "?#"); i >= 0 {
+		concrete = concrete[:i]
+	}
+
+	// exact matches always win.
+	for pair := orderedmap.First(p.PathItems); pair != nil; pair = pair.Next() {
+		if !strings.Contains(pair.Key(), "{") && pair.Key() == concrete {
+			return pair.Value(), map[string]string{}, true
+		}
+	}
+
+	concreteSegments := strings.Split(concrete, "/")
+	var bestItem *PathItem
+	var bestParams map[string]string
+	var bestScore []bool
+	for pair := orderedmap.First(p.PathItems); pair != nil; pair = pair.Next() {
+		if !strings.Contains(pair.Key(), "{") {
+			continue
+		}
+		params, score, ok := matchPathTemplate(pair.Key(), concreteSegments)
+		if !ok {
+			continue
+		}
+		if bestItem == nil || morePathLiterals(score, bestScore) {
+			bestItem, bestParams, bestScore = pair.Value(), params, score
+		}
+	}
+	if bestItem == nil {
+		return nil, nil, false
+	}
+	return bestItem, bestParams, true
+}
+
+var pathParamRegex = regexp.MustCompile(`\{([^{}]+)}`)
+
+// matchPathTemplate matches a path template against the segments of a concrete path, returning the extracted
+// parameters and which segments of the template are literal (used to rank competing matches).
+func matchPathTemplate(template string, concreteSegments []string) (map[string]string, []bool, bool) {
+	segments := strings.Split(template, "/")
+	if len(segments) != len(concreteSegments) {
+		return nil, nil, false
+	}
+	params := make(map[string]string)
+	literals := make([]bool, len(segments))
+	for i, seg := range segments {
+		locs := pathParamRegex.FindAllStringSubmatchIndex(seg, -1)
+		if len(locs) == 0 {
+			if seg != concreteSegments[i] {
+				return nil, nil, false
+			}
+			literals[i] = true
+			continue
+		}
+		var names []string
+		var sb strings.Builder
+		sb.WriteString("^")
+		last := 0
+		for j, loc := range locs {
+			if j > 0 && loc[0] == last {
+				return nil, nil, false // adjacent parameters cannot be split apart.
+			}
+			sb.WriteString(regexp.QuoteMeta(seg[last:loc[0]]))
+			sb.WriteString("(.+?)")
+			names = append(names, seg[loc[2]:loc[3]])
+			last = loc[1]
+		}
+		sb.WriteString(regexp.QuoteMeta(seg[last:]))
+		sb.WriteString("$")
+		matches := regexp.MustCompile(sb.String()).FindStringSubmatch(concreteSegments[i])
+		if matches == nil {
+			return nil, nil, false
+		}
+		for j, name := range names {
+			value, err := url.PathUnescape(matches[j+1])
+			if err != nil {
+				value = matches[j+1]
+			}
+			params[name] = value
+		}
+	}
+	return params, literals, true
+}
+
+// morePathLiterals returns true if a has a literal segment before b does, reading from left to right.
+func morePathLiterals(a, b []bool) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i]
+		}
+	}
+	return false
+}
+
+// PathConflictType is the kind of conflict between two paths, see Paths.FindConflicts.
+type PathConflictType int
+
+const (
+	// PathConflictEquivalent means the paths are the same template, differing only by the names of their
+	// parameters (e.g. '/pets/{id}' and '/pets/{petId}').
+	PathConflictEquivalent PathConflictType = iota
+
+	// PathConflictOverlapping means a concrete path can be matched by both paths (e.g. '/pets/mine' and
+	// '/pets/{id}', or '/{owner}/pets' and '/users/{id}').
+	PathConflictOverlapping
+)
+
+// PathConflict is a pair of paths that a router may not be able to tell apart.
+type PathConflict struct {
+	// Type is the kind of conflict.
+	Type PathConflictType
+
+	// Path is the path that appears first in the document.
+	Path string
+
+	// ConflictsWith is the path that conflicts with Path, it appears later in the document.
+	ConflictsWith string
+}
+
+// FindConflicts will check every pair of paths for templates that are equivalent (they differ only by the names of
+// their parameters) or that overlap (some concrete path can be matched by both). Overlapping paths are reported even
+// when the precedence rules of MatchPath would pick one of them, as they are often a routing bug.
+//
+// Segments with more than one parameter, or a parameter and literal text (e.g. '{name}.{ext}'), are compared by
+// the literal text at the start and end of the segment, so the check may report overlaps that can't really happen.
+// Conflicts are returned in the order of the paths in the document.
+func (p *Paths) FindConflicts() []PathConflict {
+	if p == nil || p.PathItems == nil {
+		return nil
+	}
+	var paths []string
+	for pair := orderedmap.First(p.PathItems); pair != nil; pair = pair.Next() {
+		paths = append(paths, pair.Key())
+	}
+	var conflicts []PathConflict
+	for i := range paths {
+		for j := i + 1; j < len(paths); j++ {
+			a, b := strings.Split(paths[i], "/"), strings.Split(paths[j], "/")
+			if len(a) != len(b) {
+				continue
+			}
+			equivalent, overlapping := true, true
+			for k := range a {
+				if pathParamRegex.ReplaceAllString(a[k], "{}") != pathParamRegex.ReplaceAllString(b[k], "{}") {
+					equivalent = false
+				}
+				if !pathSegmentsOverlap(a[k], b[k]) {
+					overlapping = false
+					break
+				}
+			}
+			switch {
+			case !overlapping:
+			case equivalent:
+				conflicts = append(conflicts, PathConflict{Type: PathConflictEquivalent, Path: paths[i], ConflictsWith: paths[j]})
+			default:
+				conflicts = append(conflicts, PathConflict{Type: PathConflictOverlapping, Path: paths[i], ConflictsWith: paths[j]})
+			}
+		}
+	}
+	return conflicts
+}
+
+// pathSegmentsOverlap returns true if a single concrete path segment could be matched by both segments a and b.
+func pathSegmentsOverlap(a, b string) bool {
+	aParams, bParams := pathParamRegex.FindAllStringIndex(a, -1), pathParamRegex.FindAllStringIndex(b, -1)
+	switch {
+	case len(aParams) == 0 && len(bParams) == 0:
+		return a == b
+	case len(aParams) == 0:
+		_, _, ok := matchPathTemplate(b, []string{a})
+		return ok
+	case len(bParams) == 0:
+		_, _, ok := matchPathTemplate(a, []string{b})
+		return ok
+	}
+	// both are templated, so compare the literal text around the parameters.
+	aPrefix, aSuffix := a[:aParams[0][0]], a[aParams[len(aParams)-1][1]:]
+	bPrefix, bSuffix := b[:bParams[0][0]], b[bParams[len(bParams)-1][1]:]
+	return (strings.HasPrefix(aPrefix, bPrefix) || strings.HasPrefix(bPrefix, aPrefix)) &&
+		(strings.HasSuffix(aSuffix, bSuffix) || strings.HasSuffix(bSuffix, aSuffix))
+}
+
 // Render will return a YAML representation of the Paths object as a byte slice.
 func (p *Paths) Render() ([]byte, error) {
 	return yaml.Marshal(p)
@@ -81,7 +291,7 @@ func (p *Paths) MarshalYAML() (interface{}, error) {
 		pi       *PathItem
 		path     string
 		line     int
-		style    yaml.Style
+		keyNode  *yaml.Node
 		rendered *yaml.Node
 	}
 	var mapped []*pathItem
@@ -90,7 +300,7 @@ func (p *Paths) MarshalYAML() (interface{}, error) {
 		k := pair.Key()
 		pi := pair.Value()
 		ln := 9999 // default to a high value to weight new content to the bottom.
-		var style yaml.Style
+		var keyNode *yaml.Node
 		if p.low != nil {
 			lpi := p.low.FindPath(k)
 			if lpi != nil {
@@ -99,12 +309,12 @@ func (p *Paths) MarshalYAML() (interface{}, error) {
 
 			for pair := orderedmap.First(p.low.PathItems); pair != nil; pair = pair.Next() {
 				if pair.Key().Value == k {
-					style = pair.Key().KeyNode.Style
+					keyNode = pair.Key().KeyNode
 					break
 				}
 			}
 		}
-		mapped = append(mapped, &pathItem{pi, k, ln, style, nil})
+		mapped = append(mapped, &pathItem{pi, k, ln, keyNode, nil})
 	}
 
 	nb := high.NewNodeBuilder(p, p.low)
@@ -118,7 +328,7 @@ func (p *Paths) MarshalYAML() (interface{}, error) {
 			}
 			mapped = append(mapped, &pathItem{
 				nil, label,
-				extNode.Content[u].Line, 0, extNode.Content[u],
+				extNode.Content[u].Line, nil, extNode.Content[u],
 			})
 		}
 	}
@@ -131,7 +341,7 @@ func (p *Paths) MarshalYAML() (interface{}, error) {
 			rendered, _ := mp.pi.MarshalYAML()
 
 			kn := utils.CreateStringNode(mp.path)
-			kn.Style = mp.style
+			high.KeepKeyFormatting(kn, mp.keyNode)
 
 			m.Content = append(m.Content, kn)
 			m.Content = append(m.Content, rendered.(*yaml.Node))
@@ -152,7 +362,7 @@ func (p *Paths) MarshalYAMLInline() (interface{}, error) {
 		pi       *PathItem
 		path     string
 		line     int
-		style    yaml.Style
+		keyNode  *yaml.Node
 		rendered *yaml.Node
 	}
 	var mapped []*pathItem
@@ -161,7 +371,7 @@ func (p *Paths) MarshalYAMLInline() (interface{}, error) {
 		k := pair.Key()
 		pi := pair.Value()
 		ln := 9999 // default to a high value to weight new content to the bottom.
-		var style yaml.Style
+		var keyNode *yaml.Node
 		if p.low != nil {
 			lpi := p.low.FindPath(k)
 			if lpi != nil {
@@ -170,12 +380,12 @@ func (p *Paths) MarshalYAMLInline() (interface{}, error) {
 
 			for pair := orderedmap.First(p.low.PathItems); pair != nil; pair = pair.Next() {
 				if pair.Key().Value == k {
-					style = pair.Key().KeyNode.Style
+					keyNode = pair.Key().KeyNode
 					break
 				}
 			}
 		}
-		mapped = append(mapped, &pathItem{pi, k, ln, style, nil})
+		mapped = append(mapped, &pathItem{pi, k, ln, keyNode, nil})
 	}
 
 	nb := high.NewNodeBuilder(p, p.low)
@@ -190,7 +400,7 @@ func (p *Paths) MarshalYAMLInline() (interface{}, error) {
 			}
 			mapped = append(mapped, &pathItem{
 				nil, label,
-				extNode.Content[u].Line, 0, extNode.Content[u],
+				extNode.Content[u].Line, nil, extNode.Content[u],
 			})
 		}
 	}
@@ -203,7 +413,7 @@ func (p *Paths) MarshalYAMLInline() (interface{}, error) {
 			rendered, _ := mp.pi.MarshalYAMLInline()
 
 			kn := utils.CreateStringNode(mp.path)
-			kn.Style = mp.style
+			high.KeepKeyFormatting(kn, mp.keyNode)
 
 			m.Content = append(m.Content, kn)
 			m.Content = append(m.Content, rendered.(*yaml.Node))
@@ -118,3 +118,130 @@ func TestPaths_MarshalYAMLInline(t *testing.T) {
 	assert.Equal(t, yml, strings.TrimSpace(string(rend)))
 
 }
+
+func TestPaths_MatchPath(t *testing.T) {
+
+	yml := `/pets/{id}:
+    get:
+        description: get a pet
+/pets/mine:
+    get:
+        description: get my pets
+/pets/{id}/toys/{toyId}:
+    get:
+        description: get a toy
+/{owner}/pets:
+    get:
+        description: get an owner's pets
+/files/{name}.{ext}:
+    get:
+        description: get a file
+/bad/{a}{b}:
+    get:
+        description: ambiguous`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndexWithConfig(&idxNode, index.CreateOpenAPIIndexConfig())
+
+	var n v3low.Paths
+	_ = low.BuildModel(&idxNode, &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	paths := NewPaths(&n)
+
+	item, params, ok := paths.MatchPath("/pets/123")
+	assert.True(t, ok)
+	assert.Equal(t, "get a pet", item.Get.Description)
+	assert.Equal(t, map[string]string{"id": "123"}, params)
+
+	// exact matches win over templated ones.
+	item, params, ok = paths.MatchPath("/pets/mine?limit=10")
+	assert.True(t, ok)
+	assert.Equal(t, "get my pets", item.Get.Description)
+	assert.Empty(t, params)
+
+	item, params, ok = paths.MatchPath("/pets/1/toys/ball%20red")
+	assert.True(t, ok)
+	assert.Equal(t, "get a toy", item.Get.Description)
+	assert.Equal(t, map[string]string{"id": "1", "toyId": "ball red"}, params)
+
+	// literal segments earlier in the path win over later ones.
+	item, params, ok = paths.MatchPath("/pets/pets")
+	assert.True(t, ok)
+	assert.Equal(t, "get a pet", item.Get.Description)
+	assert.Equal(t, map[string]string{"id": "pets"}, params)
+
+	item, params, ok = paths.MatchPath("/files/report.final.pdf")
+	assert.True(t, ok)
+	assert.Equal(t, "get a file", item.Get.Description)
+	assert.Equal(t, map[string]string{"name": "report", "ext": "final.pdf"}, params)
+
+	_, _, ok = paths.MatchPath("/bad/ab")
+	assert.False(t, ok)
+
+	_, _, ok = paths.MatchPath("/pets/")
+	assert.False(t, ok)
+
+	_, _, ok = paths.MatchPath("/burgers/123")
+	assert.False(t, ok)
+
+	var empty *Paths
+	_, _, ok = empty.MatchPath("/pets/123")
+	assert.False(t, ok)
+}
+
+func TestPaths_FindConflicts(t *testing.T) {
+	yml := `/pets/{id}:
+    get:
+        description: get a pet
+/pets/{petId}:
+    get:
+        description: get a pet again
+/pets/mine:
+    get:
+        description: get my pets
+/{owner}/toys:
+    get:
+        description: get an owner's toys
+/pets/{id}/toys:
+    get:
+        description: get a pet's toys
+/files/{name}.json:
+    get:
+        description: get a json file
+/files/{name}.xml:
+    get:
+        description: get an xml file
+/files/{name}.{ext}:
+    get:
+        description: get a file
+/stores/{id}/open:
+    get:
+        description: is it open
+/stores/{id}/closed:
+    get:
+        description: is it closed`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndexWithConfig(&idxNode, index.CreateOpenAPIIndexConfig())
+
+	var n v3low.Paths
+	_ = low.BuildModel(&idxNode, &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	paths := NewPaths(&n)
+	assert.Equal(t, []PathConflict{
+		{Type: PathConflictEquivalent, Path: "/pets/{id}", ConflictsWith: "/pets/{petId}"},
+		{Type: PathConflictOverlapping, Path: "/pets/{id}", ConflictsWith: "/pets/mine"},
+		{Type: PathConflictOverlapping, Path: "/pets/{id}", ConflictsWith: "/{owner}/toys"}, // '/pets/toys'
+		{Type: PathConflictOverlapping, Path: "/pets/{petId}", ConflictsWith: "/pets/mine"},
+		{Type: PathConflictOverlapping, Path: "/pets/{petId}", ConflictsWith: "/{owner}/toys"},
+		{Type: PathConflictOverlapping, Path: "/files/{name}.json", ConflictsWith: "/files/{name}.{ext}"},
+		{Type: PathConflictOverlapping, Path: "/files/{name}.xml", ConflictsWith: "/files/{name}.{ext}"},
+	}, paths.FindConflicts())
+
+	var empty *Paths
+	assert.Nil(t, empty.FindConflicts())
+}
@@ -33,6 +33,17 @@ func NewRequestBody(rb *low.RequestBody) *RequestBody {
 	return r
 }
 
+// SelectMediaType will return the media type that best matches the Content-Type of a request, along with the
+// media type key that was matched. Any parameters (such as 'charset') are ignored, and media types in the request
+// body may be defined using 'type/*' and '*/*' wildcards, the most specific match wins.
+func (r *RequestBody) SelectMediaType(contentType string) (*MediaType, string, bool) {
+	mainType, subType := splitMediaType(contentType)
+	if mainType == "" || subType == "" {
+		return nil, "", false
+	}
+	return matchContent(r.Content, mainType, subType)
+}
+
 // GoLow returns the low-level RequestBody instance used to create the high-level one.
 func (r *RequestBody) GoLow() *low.RequestBody {
 	return r.low
@@ -89,3 +89,32 @@ x-high-gravity: why not?`
 
 	assert.Equal(t, desired, strings.TrimSpace(string(rend)))
 }
+
+func TestRequestBody_SelectMediaType(t *testing.T) {
+	content := orderedmap.New[string, *MediaType]()
+	content.Set("application/json", &MediaType{})
+	content.Set("image/*", &MediaType{})
+	content.Set("*/*", &MediaType{})
+	req := &RequestBody{Content: content}
+
+	mt, key, ok := req.SelectMediaType("application/json; charset=utf-8")
+	assert.True(t, ok)
+	assert.Equal(t, "application/json", key)
+	assert.Equal(t, content.GetOrZero("application/json"), mt)
+
+	_, key, ok = req.SelectMediaType("IMAGE/PNG")
+	assert.True(t, ok)
+	assert.Equal(t, "image/*", key)
+
+	_, key, ok = req.SelectMediaType("text/plain")
+	assert.True(t, ok)
+	assert.Equal(t, "*/*", key)
+
+	_, _, ok = req.SelectMediaType("")
+	assert.False(t, ok)
+
+	req = &RequestBody{Content: orderedmap.New[string, *MediaType]()}
+	req.Content.Set("application/json", &MediaType{})
+	_, _, ok = req.SelectMediaType("text/plain")
+	assert.False(t, ok)
+}
@@ -6,6 +6,8 @@ package v3
 import (
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/pb33f/libopenapi/datamodel/high"
@@ -68,6 +70,107 @@ func (r *Responses) FindResponseByCode(code int) *Response {
 	return r.Codes.GetOrZero(fmt.Sprintf("%d", code))
 }
 
+// SelectMediaType performs content negotiation for a response. The response for the status code is located (an
+// exact code first, then a range such as '2XX', then the default response) and the media type that best satisfies
+// the Accept header is returned, along with the media type key that was matched (ready for a Content-Type header).
+// Accept headers may contain '*/*' and 'type/*' wildcards and quality values, an empty Accept header accepts anything.
+func (r *Responses) SelectMediaType(statusCode int, accept string) (*MediaType, string, bool) {
+	code := strconv.Itoa(statusCode)
+	response := r.Codes.GetOrZero(code)
+	if response == nil && len(code) == 3 {
+		for pair := orderedmap.First(r.Codes); pair != nil; pair = pair.Next() {
+			if strings.EqualFold(pair.Key(), code[:1]+"XX") {
+				response = pair.Value()
+				break
+			}
+		}
+	}
+	if response == nil {
+		response = r.Default
+	}
+	if response == nil {
+		return nil, "", false
+	}
+	for _, mr := range parseAcceptHeader(accept) {
+		if mt, key, ok := matchContent(response.Content, mr.mainType, mr.subType); ok {
+			return mt, key, true
+		}
+	}
+	return nil, "", false
+}
+
+// StatusCodes returns the keys of every declared response, exactly as they are declared (explicit codes such as '200',
+// ranges such as '2XX' and 'default'), in the order they are defined.
+func (r *Responses) StatusCodes() []string {
+	type codeItem struct {
+		code string
+		line int
+	}
+	var codes []codeItem
+	line := func(code string) int {
+		ln := 9999 // default to a high value, so new responses come last.
+		if r.low != nil {
+			for lPair := orderedmap.First(r.low.Codes); lPair != nil; lPair = lPair.Next() {
+				if lPair.Key().Value == code && lPair.Key().KeyNode != nil {
+					ln = lPair.Key().KeyNode.Line
+				}
+			}
+		}
+		return ln
+	}
+	for pair := orderedmap.First(r.Codes); pair != nil; pair = pair.Next() {
+		codes = append(codes, codeItem{pair.Key(), line(pair.Key())})
+	}
+	if r.Default != nil {
+		ln := 9999
+		if r.low != nil && r.low.Default.KeyNode != nil {
+			ln = r.low.Default.KeyNode.Line
+		}
+		codes = append(codes, codeItem{low.DefaultLabel, ln})
+	}
+	sort.SliceStable(codes, func(i, j int) bool {
+		return codes[i].line < codes[j].line
+	})
+	keys := make([]string, len(codes))
+	for i := range codes {
+		keys[i] = codes[i].code
+	}
+	return keys
+}
+
+// ExplicitCodes returns every concrete status code covered by the declared responses, in ascending order. Ranges such
+// as '2XX' are expanded into each code of the range (200 to 299) that is not declared explicitly. The default
+// response, and any key that is not a valid status code or range, is ignored (use the Default field to check for a
+// default response).
+func (r *Responses) ExplicitCodes() []int {
+	declared := make(map[int]bool)
+	var ranges []int
+	for pair := orderedmap.First(r.Codes); pair != nil; pair = pair.Next() {
+		key := pair.Key()
+		if code, err := strconv.Atoi(key); err == nil && code >= 100 && code <= 599 {
+			declared[code] = true
+			continue
+		}
+		if len(key) == 3 && strings.EqualFold(key[1:], "XX") && key[0] >= '1' && key[0] <= '5' {
+			ranges = append(ranges, int(key[0]-'0')*100)
+		}
+	}
+	codes := make([]int, 0, len(declared))
+	for code := range declared {
+		codes = append(codes, code)
+	}
+	for _, start := range ranges {
+		for code := start; code < start+100; code++ {
+			if !declared[code] {
+				declared[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	sort.Ints(codes)
+	return codes
+}
+
 // GoLow returns the low-level Response object used to create the high-level one.
 func (r *Responses) GoLow() *low.Responses {
 	return r.low
@@ -93,26 +196,26 @@ func (r *Responses) MarshalYAML() (interface{}, error) {
 	// map keys correctly.
 	m := utils.CreateEmptyMapNode()
 	type responseItem struct {
-		resp  *Response
-		code  string
-		line  int
-		ext   *yaml.Node
-		style yaml.Style
+		resp    *Response
+		code    string
+		line    int
+		ext     *yaml.Node
+		keyNode *yaml.Node
 	}
 	var mapped []*responseItem
 
 	for pair := orderedmap.First(r.Codes); pair != nil; pair = pair.Next() {
 		ln := 9999 // default to a high value to weight new content to the bottom.
-		var style yaml.Style
+		var keyNode *yaml.Node
 		if r.low != nil {
 			for lPair := orderedmap.First(r.low.Codes); lPair != nil; lPair = lPair.Next() {
 				if lPair.Key().Value == pair.Key() {
 					ln = lPair.Key().KeyNode.Line
-					style = lPair.Key().KeyNode.Style
+					keyNode = lPair.Key().KeyNode
 				}
 			}
 		}
-		mapped = append(mapped, &responseItem{pair.Value(), pair.Key(), ln, nil, style})
+		mapped = append(mapped, &responseItem{pair.Value(), pair.Key(), ln, nil, keyNode})
 	}
 
 	// extract extensions
@@ -127,7 +230,7 @@ func (r *Responses) MarshalYAML() (interface{}, error) {
 			}
 			mapped = append(mapped, &responseItem{
 				nil, label,
-				extNode.Content[u].Line, extNode.Content[u], 0,
+				extNode.Content[u].Line, extNode.Content[u], nil,
 			})
 		}
 	}
@@ -140,7 +243,7 @@ func (r *Responses) MarshalYAML() (interface{}, error) {
 			rendered, _ := mp.resp.MarshalYAML()
 
 			kn := utils.CreateStringNode(mp.code)
-			kn.Style = mp.style
+			high.KeepKeyFormatting(kn, mp.keyNode)
 
 			m.Content = append(m.Content, kn)
 			m.Content = append(m.Content, rendered.(*yaml.Node))
@@ -158,26 +261,26 @@ func (r *Responses) MarshalYAMLInline() (interface{}, error) {
 	// map keys correctly.
 	m := utils.CreateEmptyMapNode()
 	type responseItem struct {
-		resp  *Response
-		code  string
-		line  int
-		ext   *yaml.Node
-		style yaml.Style
+		resp    *Response
+		code    string
+		line    int
+		ext     *yaml.Node
+		keyNode *yaml.Node
 	}
 	var mapped []*responseItem
 
 	for pair := orderedmap.First(r.Codes); pair != nil; pair = pair.Next() {
 		ln := 9999 // default to a high value to weight new content to the bottom.
-		var style yaml.Style
+		var keyNode *yaml.Node
 		if r.low != nil {
 			for lPair := orderedmap.First(r.low.Codes); lPair != nil; lPair = lPair.Next() {
 				if lPair.Key().Value == pair.Key() {
 					ln = lPair.Key().KeyNode.Line
-					style = lPair.Key().KeyNode.Style
+					keyNode = lPair.Key().KeyNode
 				}
 			}
 		}
-		mapped = append(mapped, &responseItem{pair.Value(), pair.Key(), ln, nil, style})
+		mapped = append(mapped, &responseItem{pair.Value(), pair.Key(), ln, nil, keyNode})
 	}
 
 	// extract extensions
@@ -193,7 +296,7 @@ func (r *Responses) MarshalYAMLInline() (interface{}, error) {
 			}
 			mapped = append(mapped, &responseItem{
 				nil, label,
-				extNode.Content[u].Line, extNode.Content[u], 0,
+				extNode.Content[u].Line, extNode.Content[u], nil,
 			})
 		}
 	}
@@ -206,7 +309,7 @@ func (r *Responses) MarshalYAMLInline() (interface{}, error) {
 			rendered, _ := mp.resp.MarshalYAMLInline()
 
 			kn := utils.CreateStringNode(mp.code)
-			kn.Style = mp.style
+			high.KeepKeyFormatting(kn, mp.keyNode)
 
 			m.Content = append(m.Content, kn)
 			m.Content = append(m.Content, rendered.(*yaml.Node))
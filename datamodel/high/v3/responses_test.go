@@ -99,3 +99,111 @@ func TestResponses_MarshalYAMLInline(t *testing.T) {
 	assert.Equal(t, yml, strings.TrimSpace(string(rend)))
 
 }
+
+func TestResponses_SelectMediaType(t *testing.T) {
+
+	yml := `"200":
+  description: ok
+  content:
+    application/json:
+      example: json
+    application/xml:
+      example: xml
+    text/*:
+      example: text
+"4XX":
+  description: client error
+  content:
+    application/problem+json:
+      example: problem
+default:
+  description: default
+  content:
+    '*/*':
+      example: anything`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndex(&idxNode)
+
+	var n v3.Responses
+	_ = low.BuildModel(&idxNode, &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	r := NewResponses(&n)
+
+	mt, key, ok := r.SelectMediaType(200, "application/xml")
+	assert.True(t, ok)
+	assert.Equal(t, "application/xml", key)
+	assert.Equal(t, "xml", mt.Example.Value)
+
+	_, key, ok = r.SelectMediaType(200, "application/xml;q=0.5, application/json")
+	assert.True(t, ok)
+	assert.Equal(t, "application/json", key)
+
+	_, key, ok = r.SelectMediaType(200, "application/*")
+	assert.True(t, ok)
+	assert.Equal(t, "application/json", key)
+
+	_, key, ok = r.SelectMediaType(200, "")
+	assert.True(t, ok)
+	assert.Equal(t, "application/json", key)
+
+	_, key, ok = r.SelectMediaType(200, "text/html; charset=utf-8, */*;q=0.1")
+	assert.True(t, ok)
+	assert.Equal(t, "text/*", key)
+
+	_, _, ok = r.SelectMediaType(200, "image/png, application/json;q=0")
+	assert.False(t, ok)
+
+	_, key, ok = r.SelectMediaType(404, "application/json, */*;q=0.8")
+	assert.True(t, ok)
+	assert.Equal(t, "application/problem+json", key)
+
+	_, key, ok = r.SelectMediaType(500, "image/png")
+	assert.True(t, ok)
+	assert.Equal(t, "*/*", key)
+
+	noDefault := &Responses{Codes: r.Codes}
+	_, _, ok = noDefault.SelectMediaType(500, "")
+	assert.False(t, ok)
+}
+
+func TestResponses_StatusCodes(t *testing.T) {
+
+	yml := `"201":
+  description: created
+default:
+  description: default
+"4XX":
+  description: client error
+"404":
+  description: not found
+"5xx":
+  description: server error
+"pizza":
+  description: not a code`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndex(&idxNode)
+
+	var n v3.Responses
+	_ = low.BuildModel(&idxNode, &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	r := NewResponses(&n)
+	assert.Equal(t, []string{"201", "default", "4XX", "404", "5xx", "pizza"}, r.StatusCodes())
+
+	codes := r.ExplicitCodes()
+	assert.Len(t, codes, 201)
+	assert.Equal(t, 201, codes[0])
+	assert.Equal(t, 400, codes[1])
+	assert.Equal(t, 599, codes[200])
+	assert.Equal(t, []int{403, 404, 405}, codes[4:7]) // 404 is declared explicitly, and not repeated.
+	assert.NotNil(t, r.Default)
+
+	empty := &Responses{}
+	assert.Empty(t, empty.StatusCodes())
+	assert.Empty(t, empty.ExplicitCodes())
+}
@@ -0,0 +1,171 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sources of a RuntimeExpression.
+const (
+	RuntimeExpressionURL        = "$url"
+	RuntimeExpressionMethod     = "$method"
+	RuntimeExpressionStatusCode = "$statusCode"
+	RuntimeExpressionRequest    = "$request"
+	RuntimeExpressionResponse   = "$response"
+)
+
+// Locations of a $request or $response RuntimeExpression.
+const (
+	RuntimeExpressionHeader = "header"
+	RuntimeExpressionQuery  = "query"
+	RuntimeExpressionPath   = "path"
+	RuntimeExpressionBody   = "body"
+)
+
+// RuntimeExpression is a parsed runtime expression, used by the keys of a Callback and the parameters of a Link.
+// For example, '$request.body#/callbackUrl' has a Source of '$request', a Location of 'body' and a Pointer of
+// '/callbackUrl'.
+//   - https://spec.openapis.org/oas/v3.1.0#runtime-expressions
+type RuntimeExpression struct {
+	// Expression is the original expression, without any surrounding braces.
+	Expression string
+
+	// Source is one of '$url', '$method', '$statusCode', '$request' or '$response'.
+	Source string
+
+	// Location is one of 'header', 'query', 'path' or 'body' for a '$request' or '$response' Source, otherwise empty.
+	Location string
+
+	// Name is the name of the header, query or path parameter, empty for a body Location.
+	Name string
+
+	// Pointer is the JSON pointer into the body (without the '#'), empty if the whole body is referenced.
+	Pointer string
+}
+
+// ParseRuntimeExpression will parse and validate a single runtime expression, for example '$request.query.id' or
+// '{$response.body#/url}' (the surrounding braces used when embedding an expression in a string are optional).
+func ParseRuntimeExpression(expr string) (*RuntimeExpression, error) {
+	raw := expr
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		expr = expr[1 : len(expr)-1]
+	}
+	fail := func(reason string) (*RuntimeExpression, error) {
+		return nil, fmt.Errorf("unable to parse runtime expression '%s': %s", raw, reason)
+	}
+	re := &RuntimeExpression{Expression: expr}
+
+	switch expr {
+	case RuntimeExpressionURL, RuntimeExpressionMethod, RuntimeExpressionStatusCode:
+		re.Source = expr
+		return re, nil
+	}
+
+	source, rest, _ := strings.Cut(expr, ".")
+	if source != RuntimeExpressionRequest && source != RuntimeExpressionResponse {
+		return fail("must start with '$url', '$method', '$statusCode', '$request.' or '$response.'")
+	}
+	re.Source = source
+
+	if rest == RuntimeExpressionBody || strings.HasPrefix(rest, RuntimeExpressionBody+"#") {
+		re.Location = RuntimeExpressionBody
+		re.Pointer = strings.TrimPrefix(strings.TrimPrefix(rest, RuntimeExpressionBody), "#")
+		if err := checkJSONPointer(re.Pointer); err != nil {
+			return fail(err.Error())
+		}
+		return re, nil
+	}
+
+	location, name, found := strings.Cut(rest, ".")
+	switch location {
+	case RuntimeExpressionHeader, RuntimeExpressionQuery, RuntimeExpressionPath:
+	default:
+		return fail(fmt.Sprintf("unknown location '%s', expected header, query, path or body", location))
+	}
+	if !found || name == "" {
+		return fail(fmt.Sprintf("the %s location requires a name", location))
+	}
+	if location == RuntimeExpressionHeader {
+		for _, c := range name {
+			if !isTokenChar(c) {
+				return fail(fmt.Sprintf("header name '%s' contains an invalid character '%c'", name, c))
+			}
+		}
+	}
+	re.Location = location
+	re.Name = name
+	return re, nil
+}
+
+// String returns the expression, as it would be written.
+func (r *RuntimeExpression) String() string {
+	return r.Expression
+}
+
+// checkJSONPointer validates the syntax of a JSON pointer (RFC 6901).
+func checkJSONPointer(pointer string) error {
+	if pointer == "" {
+		return nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return fmt.Errorf("JSON pointer '%s' must start with '/'", pointer)
+	}
+	for i := 0; i < len(pointer); i++ {
+		if pointer[i] == '~' && (i+1 == len(pointer) || (pointer[i+1] != '0' && pointer[i+1] != '1')) {
+			return fmt.Errorf("JSON pointer '%s' contains an invalid escape, '~' must be followed by '0' or '1'", pointer)
+		}
+	}
+	return nil
+}
+
+// isTokenChar returns true if c is allowed in an HTTP header name (RFC 7230 tchar).
+func isTokenChar(c rune) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", c)
+}
+
+// extractRuntimeExpressions will parse every '{expression}' embedded in s, for example the key of a Callback
+// 'https://pb33f.io/notify?id={$request.query.id}&url={$request.body#/url}'. If s is not embedding expressions
+// and starts with '$', s is parsed as a single expression.
+func extractRuntimeExpressions(s string) ([]*RuntimeExpression, error) {
+	if strings.HasPrefix(s, "$") {
+		re, err := ParseRuntimeExpression(s)
+		if err != nil {
+			return nil, err
+		}
+		return []*RuntimeExpression{re}, nil
+	}
+	var expressions []*RuntimeExpression
+	var errs []error
+	rest := s
+	for {
+		start := strings.IndexAny(rest, "{}")
+		if start < 0 {
+			break
+		}
+		if rest[start] == '}' {
+			errs = append(errs, fmt.Errorf("unable to parse runtime expression in '%s': unexpected '}'", s))
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			errs = append(errs, fmt.Errorf("unable to parse runtime expression in '%s': a '{' is not closed", s))
+			break
+		}
+		re, err := ParseRuntimeExpression(rest[start : start+end+1])
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			expressions = append(expressions, re)
+		}
+		rest = rest[start+end+1:]
+	}
+	return expressions, errors.Join(errs...)
+}
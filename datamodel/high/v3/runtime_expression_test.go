@@ -0,0 +1,55 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRuntimeExpression(t *testing.T) {
+	tests := []struct {
+		expr string
+		want RuntimeExpression
+	}{
+		{"$url", RuntimeExpression{Expression: "$url", Source: "$url"}},
+		{"$method", RuntimeExpression{Expression: "$method", Source: "$method"}},
+		{"{$statusCode}", RuntimeExpression{Expression: "$statusCode", Source: "$statusCode"}},
+		{"$request.header.X-Request-ID", RuntimeExpression{Expression: "$request.header.X-Request-ID",
+			Source: "$request", Location: "header", Name: "X-Request-ID"}},
+		{"$request.query.queryUrl", RuntimeExpression{Expression: "$request.query.queryUrl",
+			Source: "$request", Location: "query", Name: "queryUrl"}},
+		{"$request.path.id", RuntimeExpression{Expression: "$request.path.id",
+			Source: "$request", Location: "path", Name: "id"}},
+		{"{$request.body#/callbackUrl}", RuntimeExpression{Expression: "$request.body#/callbackUrl",
+			Source: "$request", Location: "body", Pointer: "/callbackUrl"}},
+		{"$response.body#/user/~1uuid", RuntimeExpression{Expression: "$response.body#/user/~1uuid",
+			Source: "$response", Location: "body", Pointer: "/user/~1uuid"}},
+		{"$response.body", RuntimeExpression{Expression: "$response.body",
+			Source: "$response", Location: "body"}},
+	}
+	for _, tt := range tests {
+		re, err := ParseRuntimeExpression(tt.expr)
+		assert.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, *re)
+		assert.Equal(t, tt.want.Expression, re.String())
+	}
+}
+
+func TestParseRuntimeExpression_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"$uri":                        "must start with '$url', '$method', '$statusCode', '$request.' or '$response.'",
+		"request.body":                "must start with '$url', '$method', '$statusCode', '$request.' or '$response.'",
+		"$request.cookie.id":          "unknown location 'cookie', expected header, query, path or body",
+		"$request.query":              "the query location requires a name",
+		"$request.header.X Request":   "header name 'X Request' contains an invalid character ' '",
+		"$response.body#callbackUrl":  "JSON pointer 'callbackUrl' must start with '/'",
+		"$response.body#/callback~2x": "JSON pointer '/callback~2x' contains an invalid escape, '~' must be followed by '0' or '1'",
+	}
+	for expr, reason := range tests {
+		_, err := ParseRuntimeExpression(expr)
+		assert.EqualError(t, err, "unable to parse runtime expression '"+expr+"': "+reason)
+	}
+}
@@ -4,6 +4,11 @@
 package v3
 
 import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
 	"github.com/pb33f/libopenapi/datamodel/high"
 	low "github.com/pb33f/libopenapi/datamodel/low/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
@@ -35,6 +40,69 @@ func NewServer(server *low.Server) *Server {
 	return s
 }
 
+// ExpandURL will return the URL of the Server, with every '{variable}' placeholder substituted. The value of a
+// variable is taken from vars, or from the 'default' of the variable if it's not in vars.
+//
+// An error is returned if the URL (or vars) uses a variable that is not defined by the Server, if a variable has
+// no value, or if a value is not one of the 'enum' values of the variable.
+func (s *Server) ExpandURL(vars map[string]string) (string, error) {
+	variables := s.Variables
+	if variables == nil {
+		variables = orderedmap.New[string, *ServerVariable]()
+	}
+	for name := range vars {
+		if _, ok := variables.Get(name); !ok {
+			return "", fmt.Errorf("unable to expand server URL '%s', variable '%s' is not defined", s.URL, name)
+		}
+	}
+	var b strings.Builder
+	rest := s.URL
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			b.WriteString(rest)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unable to expand server URL '%s', a '{' is not closed", s.URL)
+		}
+		name := rest[start+1 : start+end]
+		variable, ok := variables.Get(name)
+		if !ok || variable == nil {
+			return "", fmt.Errorf("unable to expand server URL '%s', variable '%s' is not defined", s.URL, name)
+		}
+		value, ok := vars[name]
+		if !ok {
+			value = variable.Default
+		}
+		if value == "" {
+			return "", fmt.Errorf("unable to expand server URL '%s', variable '%s' has no value", s.URL, name)
+		}
+		if len(variable.Enum) > 0 && !slices.Contains(variable.Enum, value) {
+			return "", fmt.Errorf("unable to expand server URL '%s', '%s' is not a valid value for variable '%s' (%s)",
+				s.URL, value, name, strings.Join(variable.Enum, ", "))
+		}
+		b.WriteString(rest[:start])
+		b.WriteString(value)
+		rest = rest[start+end+1:]
+	}
+}
+
+// ParsedURL will expand the URL of the Server using the default value of every variable (see ExpandURL), and parse
+// the result. The URL may be relative (to the location of the document), use Document.ResolveServerURLs to resolve it.
+func (s *Server) ParsedURL() (*url.URL, error) {
+	expanded, err := s.ExpandURL(nil)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse server URL '%s': %w", s.URL, err)
+	}
+	return u, nil
+}
+
 // GoLow returns the low-level Server instance that was used to create the high-level one
 func (s *Server) GoLow() *low.Server {
 	return s.low
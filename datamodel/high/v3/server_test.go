@@ -43,3 +43,68 @@ variables:
 	rend, _ = server.Render()
 	assert.Equal(t, desired, strings.TrimSpace(string(rend)))
 }
+
+func TestServer_ExpandURL(t *testing.T) {
+	server := &Server{
+		URL: "https://{environment}.pb33f.io:{port}/{basePath}",
+		Variables: orderedmap.ToOrderedMap(map[string]*ServerVariable{
+			"environment": {Default: "api", Enum: []string{"api", "staging"}},
+			"port":        {Default: "443"},
+			"basePath":    {Default: "v1"},
+		}),
+	}
+
+	url, err := server.ExpandURL(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.pb33f.io:443/v1", url)
+
+	url, err = server.ExpandURL(map[string]string{"environment": "staging", "basePath": "v2/beta"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://staging.pb33f.io:443/v2/beta", url)
+
+	_, err = server.ExpandURL(map[string]string{"environment": "prod"})
+	assert.EqualError(t, err, "unable to expand server URL 'https://{environment}.pb33f.io:{port}/{basePath}', "+
+		"'prod' is not a valid value for variable 'environment' (api, staging)")
+
+	_, err = server.ExpandURL(map[string]string{"region": "eu"})
+	assert.EqualError(t, err, "unable to expand server URL 'https://{environment}.pb33f.io:{port}/{basePath}', "+
+		"variable 'region' is not defined")
+}
+
+func TestServer_ExpandURL_Errors(t *testing.T) {
+	server := &Server{URL: "https://{region}.pb33f.io"}
+	_, err := server.ExpandURL(nil)
+	assert.EqualError(t, err, "unable to expand server URL 'https://{region}.pb33f.io', variable 'region' is not defined")
+
+	server.Variables = orderedmap.ToOrderedMap(map[string]*ServerVariable{"region": {}})
+	_, err = server.ExpandURL(nil)
+	assert.EqualError(t, err, "unable to expand server URL 'https://{region}.pb33f.io', variable 'region' has no value")
+
+	server.URL = "https://{region.pb33f.io"
+	_, err = server.ExpandURL(map[string]string{"region": "eu"})
+	assert.EqualError(t, err, "unable to expand server URL 'https://{region.pb33f.io', a '{' is not closed")
+
+	url, err := (&Server{URL: "https://pb33f.io"}).ExpandURL(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://pb33f.io", url)
+}
+
+func TestServer_ParsedURL(t *testing.T) {
+	server := &Server{
+		URL: "https://{environment}.pb33f.io/v1",
+		Variables: orderedmap.ToOrderedMap(map[string]*ServerVariable{
+			"environment": {Default: "api"},
+		}),
+	}
+	u, err := server.ParsedURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "api.pb33f.io", u.Host)
+	assert.Equal(t, "/v1", u.Path)
+
+	_, err = (&Server{URL: "http://pb33f.io:port/"}).ParsedURL()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to parse server URL 'http://pb33f.io:port/'")
+
+	_, err = (&Server{URL: "https://{nope}.pb33f.io"}).ParsedURL()
+	assert.Error(t, err)
+}
@@ -0,0 +1,320 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	low "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// upgradeVersion is the OpenAPI version an upgraded document will declare.
+const upgradeVersion = "3.1.0"
+
+// schemaMapKeywords are schema keywords that hold a map of schemas.
+var schemaMapKeywords = map[string]bool{
+	"properties": true, "patternProperties": true, "dependentSchemas": true,
+}
+
+// schemaKeywords are schema keywords that hold a single schema.
+var schemaKeywords = map[string]bool{
+	"items": true, "not": true, "additionalProperties": true, "contains": true, "if": true, "then": true,
+	"else": true, "propertyNames": true, "unevaluatedItems": true, "unevaluatedProperties": true,
+}
+
+// schemaListKeywords are schema keywords that hold a list of schemas.
+var schemaListKeywords = map[string]bool{
+	"allOf": true, "oneOf": true, "anyOf": true, "prefixItems": true,
+}
+
+// UpgradeTo31 will convert an OpenAPI 3.0 Document into one that uses OpenAPI 3.1 idioms. The supplied document
+// is not modified, it is rendered, transformed and then re-built into a brand-new Document.
+//
+// The following transformations are applied:
+//
+//   - 'nullable: true' is removed and "null" is added to the schema 'type' (which becomes an array).
+//   - 'nullable: false' is removed (it's the default).
+//   - a schema 'example' is converted into a single item 'examples' array.
+//   - 'exclusiveMinimum: true' takes the value of 'minimum' (which is removed), and the same for 'exclusiveMaximum'.
+//   - 'exclusiveMinimum: false' and 'exclusiveMaximum: false' are removed (it's the default).
+//   - the 'openapi' version is changed to 3.1.0
+//
+// This is a best-effort transform. Constructs that cannot be safely converted (for example 'nullable' on a schema
+// with no 'type', a schema with both 'example' and 'examples', or 'exclusiveMinimum: true' without a 'minimum') are
+// left untouched and reported.
+//
+// A list of every transformation applied (and every construct skipped) is returned, along with the new Document.
+// Each entry is located using a JSON path to the schema, as line numbers do not survive the transformation. The new
+// Document is built with the configuration of doc, and errors doc was built with (like circular references) are not
+// a failure.
+func UpgradeTo31(doc *Document) (*Document, []string, error) {
+	if doc == nil {
+		return nil, nil, errors.New("unable to upgrade document, document is nil")
+	}
+	if !doc.Is30() {
+		return nil, nil, fmt.Errorf("unable to upgrade document, version '%s' is not OpenAPI 3.0", doc.Version)
+	}
+	rendered, err := doc.Render()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to upgrade document, cannot render: %w", err)
+	}
+	var root yaml.Node
+	if err = yaml.Unmarshal(rendered, &root); err != nil {
+		return nil, nil, fmt.Errorf("unable to upgrade document, cannot parse rendered document: %w", err)
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, errors.New("unable to upgrade document, rendered document is not a map")
+	}
+
+	u := &upgrader{}
+	top := root.Content[0]
+	for i := 0; i < len(top.Content); i += 2 {
+		if top.Content[i].Value == "openapi" {
+			u.report("changed 'openapi' version from '%s' to '%s'", top.Content[i+1].Value, upgradeVersion)
+			top.Content[i+1].Value = upgradeVersion
+			top.Content[i+1].Style = 0
+		}
+	}
+	u.walk(top, "$")
+
+	upgraded, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, u.changes, fmt.Errorf("unable to upgrade document, cannot render upgraded document: %w", err)
+	}
+	info, err := datamodel.ExtractSpecInfo(upgraded)
+	if err != nil {
+		return nil, u.changes, fmt.Errorf("unable to upgrade document, cannot read upgraded document: %w", err)
+	}
+	lowDoc, err := rebuildDocument(info, doc)
+	if lowDoc == nil {
+		return nil, u.changes, fmt.Errorf("unable to upgrade document, cannot build upgraded document: %w", err)
+	}
+	return NewDocument(lowDoc), u.changes, nil
+}
+
+// upgradeConfiguration returns a copy of the configuration used to build the original document. A document that
+// was not built from a configuration has one created from the settings of its index.
+func upgradeConfiguration(doc *Document) *datamodel.DocumentConfiguration {
+	if doc.low != nil && doc.low.Config != nil {
+		config := *doc.low.Config
+		return &config
+	}
+	config := datamodel.NewDocumentConfiguration()
+	if doc.low == nil || doc.low.Index == nil || doc.low.Index.GetConfig() == nil {
+		return config
+	}
+	idxConfig := doc.low.Index.GetConfig()
+	config.BaseURL = idxConfig.BaseURL
+	config.BasePath = idxConfig.BasePath
+	config.SpecFilePath = idxConfig.SpecFilePath
+	config.RemoteURLHandler = idxConfig.RemoteURLHandler
+	config.HTTPClient = idxConfig.HTTPClient
+	config.MaxDocumentBytes = idxConfig.MaxDocumentBytes
+	config.MaxDocumentNodes = idxConfig.MaxDocumentNodes
+	config.MaxYAMLAliasExpansions = idxConfig.MaxYAMLAliasExpansions
+	config.Context = idxConfig.Context
+	config.AllowedRemoteHosts = idxConfig.AllowedRemoteHosts
+	config.DeniedRemoteHosts = idxConfig.DeniedRemoteHosts
+	config.LazyRemoteResolution = idxConfig.LazyRemoteResolution
+	config.AllowFileReferences = idxConfig.AllowFileLookup
+	config.AllowRemoteReferences = idxConfig.AllowRemoteLookup
+	config.IgnorePolymorphicCircularReferences = idxConfig.IgnorePolymorphicCircularReferences
+	config.IgnoreArrayCircularReferences = idxConfig.IgnoreArrayCircularReferences
+	config.MergeRefSiblings = idxConfig.MergeRefSiblings
+	config.DisableSchemaCache = idxConfig.DisableSchemaCache
+	config.ExtractRefsSequentially = idxConfig.ExtractRefsSequentially
+	if strings.HasPrefix(idxConfig.SpecAbsolutePath, "http") {
+		config.SpecURL, _ = url.Parse(idxConfig.SpecAbsolutePath)
+	}
+	if idxConfig.Logger != nil {
+		config.Logger = idxConfig.Logger
+	}
+	return config
+}
+
+// rebuildDocument builds a new low-level Document from info, with the configuration used to build doc. Like building
+// a document from a specification, circular references are not treated as a failure (they can be ignored by the
+// configuration, or by the caller), and neither is anything else when the build is best-effort. The error returned
+// alongside a Document does not stop it being used, as the original document was built with the same errors.
+func rebuildDocument(info *datamodel.SpecInfo, doc *Document) (*low.Document, error) {
+	config := upgradeConfiguration(doc)
+	lowDoc, err := low.CreateDocumentFromConfig(info, config)
+	if err == nil || lowDoc == nil {
+		return lowDoc, err
+	}
+	if !config.BestEffort {
+		for _, e := range utils.UnwrapErrors(err) {
+			var refErr *index.ResolvingError
+			if errors.As(e, &refErr) && refErr.CircularReference == nil {
+				return nil, err
+			}
+		}
+	}
+	return lowDoc, err
+}
+
+// upgrader walks a rendered document tree, applying 3.1 transformations to every schema it finds.
+type upgrader struct {
+	changes []string
+}
+
+func (u *upgrader) report(msg string, args ...any) {
+	u.changes = append(u.changes, fmt.Sprintf(msg, args...))
+}
+
+// walk descends through non-schema parts of the document, looking for schemas.
+func (u *upgrader) walk(node *yaml.Node, path string) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			u.walk(n, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			childPath := fmt.Sprintf("%s.%s", path, key)
+			switch {
+			case strings.HasPrefix(key, "x-"), key == "example", key == "examples":
+				// extensions and examples are data, not models.
+				continue
+			case key == "schema":
+				u.schema(value, childPath)
+			case key == "schemas" && path == "$.components":
+				for j := 0; j < len(value.Content); j += 2 {
+					u.schema(value.Content[j+1], fmt.Sprintf("%s.%s", childPath, value.Content[j].Value))
+				}
+			default:
+				u.walk(value, childPath)
+			}
+		}
+	}
+}
+
+// schema upgrades a schema, and all the schemas it contains.
+func (u *upgrader) schema(node *yaml.Node, path string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	u.nullable(node, path)
+	u.example(node, path)
+	u.exclusive(node, path, "exclusiveMinimum", "minimum")
+	u.exclusive(node, path, "exclusiveMaximum", "maximum")
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		childPath := fmt.Sprintf("%s.%s", path, key)
+		switch {
+		case schemaKeywords[key]:
+			u.schema(value, childPath)
+		case schemaListKeywords[key] && value.Kind == yaml.SequenceNode:
+			for j, n := range value.Content {
+				u.schema(n, fmt.Sprintf("%s[%d]", childPath, j))
+			}
+		case schemaMapKeywords[key] && value.Kind == yaml.MappingNode:
+			for j := 0; j < len(value.Content); j += 2 {
+				u.schema(value.Content[j+1], fmt.Sprintf("%s.%s", childPath, value.Content[j].Value))
+			}
+		}
+	}
+}
+
+// nullable folds 'nullable' into the schema 'type'.
+func (u *upgrader) nullable(node *yaml.Node, path string) {
+	nIdx, tIdx := mappingIndex(node, "nullable"), mappingIndex(node, "type")
+	if nIdx < 0 {
+		return
+	}
+	nullableValue := node.Content[nIdx+1]
+	if nullableValue.Value != "true" {
+		u.report("removed 'nullable: %s' from '%s'", nullableValue.Value, path)
+		removeMappingIndex(node, nIdx)
+		return
+	}
+	if tIdx < 0 {
+		u.report("skipped 'nullable: true' on '%s', schema has no 'type' to add 'null' to", path)
+		return
+	}
+	typeValue := node.Content[tIdx+1]
+	switch typeValue.Kind {
+	case yaml.ScalarNode:
+		node.Content[tIdx+1] = &yaml.Node{
+			Kind:    yaml.SequenceNode,
+			Tag:     "!!seq",
+			Style:   yaml.FlowStyle,
+			Content: []*yaml.Node{typeValue, {Kind: yaml.ScalarNode, Tag: "!!str", Value: "null"}},
+		}
+	case yaml.SequenceNode:
+		hasNull := false
+		for _, t := range typeValue.Content {
+			hasNull = hasNull || t.Value == "null"
+		}
+		if !hasNull {
+			typeValue.Content = append(typeValue.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "null"})
+		}
+	default:
+		u.report("skipped 'nullable: true' on '%s', schema 'type' is not a string or array", path)
+		return
+	}
+	u.report("converted 'nullable: true' on '%s' into a 'null' type", path)
+	removeMappingIndex(node, nIdx)
+}
+
+// example converts a schema 'example' into 'examples'.
+func (u *upgrader) example(node *yaml.Node, path string) {
+	eIdx := mappingIndex(node, "example")
+	if eIdx < 0 {
+		return
+	}
+	exampleKey := node.Content[eIdx]
+	if mappingIndex(node, "examples") >= 0 {
+		u.report("skipped 'example' on '%s', schema already has 'examples'", path)
+		return
+	}
+	exampleKey.Value = "examples"
+	node.Content[eIdx+1] = &yaml.Node{
+		Kind:    yaml.SequenceNode,
+		Tag:     "!!seq",
+		Content: []*yaml.Node{node.Content[eIdx+1]},
+	}
+	u.report("converted 'example' on '%s' into 'examples'", path)
+}
+
+// exclusive converts a boolean exclusive keyword into the numeric form used by 3.1, using the value of its bound.
+func (u *upgrader) exclusive(node *yaml.Node, path, exclusiveKey, boundKey string) {
+	eIdx := mappingIndex(node, exclusiveKey)
+	if eIdx < 0 || node.Content[eIdx+1].Tag != "!!bool" {
+		return
+	}
+	switch {
+	case node.Content[eIdx+1].Value != "true":
+		u.report("removed '%s: %s' from '%s'", exclusiveKey, node.Content[eIdx+1].Value, path)
+		removeMappingIndex(node, eIdx)
+	case base.ConvertExclusiveBound(node, exclusiveKey, boundKey):
+		u.report("converted '%s: true' on '%s' into the value of '%s'", exclusiveKey, path, boundKey)
+	default:
+		u.report("skipped '%s: true' on '%s', schema has no '%s' to take the value of", exclusiveKey, path, boundKey)
+	}
+}
+
+// mappingIndex returns the index of the key in a mapping node, or -1 if it does not exist.
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeMappingIndex removes the key and value found at index i in a mapping node.
+func removeMappingIndex(node *yaml.Node, i int) {
+	node.Content = append(node.Content[:i], node.Content[i+2:]...)
+}
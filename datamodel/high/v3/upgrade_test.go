@@ -0,0 +1,184 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	lowv3 "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildUpgradeTestDocument(t *testing.T, spec string) *Document {
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+	lowDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	return NewDocument(lowDoc)
+}
+
+func TestUpgradeTo31(t *testing.T) {
+	spec := `openapi: 3.0.3
+info:
+  title: upgrade
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: filter
+          in: query
+          example: fluffy
+          schema:
+            type: string
+            nullable: true
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              example:
+                name: nullable
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+          example: fluffy
+        tags:
+          type: array
+          items:
+            type: string
+            nullable: false
+        owner:
+          nullable: true
+          allOf:
+            - $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object`
+
+	doc := buildUpgradeTestDocument(t, spec)
+	upgraded, changes, err := UpgradeTo31(doc)
+	require.NoError(t, err)
+	require.NotNil(t, upgraded)
+
+	assert.Equal(t, "3.1.0", upgraded.Version)
+	assert.True(t, upgraded.Is31())
+	assert.Equal(t, "3.0.3", doc.Version)
+
+	param := upgraded.Paths.PathItems.GetOrZero("/pets").Get.Parameters[0]
+	assert.Equal(t, []string{"string", "null"}, param.Schema.Schema().Type)
+	assert.Nil(t, param.Schema.Schema().Nullable)
+	assert.Equal(t, "fluffy", param.Example.Value)
+
+	pet := upgraded.Components.Schemas.GetOrZero("Pet").Schema()
+	name := pet.Properties.GetOrZero("name").Schema()
+	assert.Nil(t, name.Example)
+	require.Len(t, name.Examples, 1)
+	assert.Equal(t, "fluffy", name.Examples[0].Value)
+
+	tags := pet.Properties.GetOrZero("tags").Schema()
+	assert.Nil(t, tags.Items.A.Schema().Nullable)
+
+	owner := pet.Properties.GetOrZero("owner").Schema()
+	assert.True(t, *owner.Nullable)
+
+	assert.Len(t, changes, 4)
+	assert.Equal(t, "changed 'openapi' version from '3.0.3' to '3.1.0'", changes[0])
+	assert.Contains(t, changes, "skipped 'nullable: true' on '$.components.schemas.Pet.properties.owner', "+
+		"schema has no 'type' to add 'null' to")
+}
+
+func TestUpgradeTo31_Not30(t *testing.T) {
+	doc := buildUpgradeTestDocument(t, `openapi: 3.1.0
+info:
+  title: upgrade
+  version: 1.0.0`)
+	upgraded, changes, err := UpgradeTo31(doc)
+	assert.Nil(t, upgraded)
+	assert.Nil(t, changes)
+	assert.EqualError(t, err, "unable to upgrade document, version '3.1.0' is not OpenAPI 3.0")
+
+	_, _, err = UpgradeTo31(nil)
+	assert.EqualError(t, err, "unable to upgrade document, document is nil")
+}
+
+func TestUpgradeTo31_Circular(t *testing.T) {
+	spec := `openapi: 3.0.3
+info:
+  title: upgrade
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Node:
+      type: object
+      required: [children]
+      properties:
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+	config := datamodel.NewDocumentConfiguration()
+	config.IgnoreArrayCircularReferences = true
+	lowDoc, err := lowv3.CreateDocumentFromConfig(info, config)
+	require.NoError(t, err)
+
+	upgraded, _, err := UpgradeTo31(NewDocument(lowDoc))
+	require.NoError(t, err)
+	assert.True(t, upgraded.Is31())
+	assert.True(t, upgraded.GoLow().Config.IgnoreArrayCircularReferences)
+	assert.Len(t, upgraded.GoLow().Index.GetResolver().GetIgnoredCircularArrayReferences(), 1)
+
+	// a document built with a circular reference error can still be upgraded, as it could be built.
+	lowDoc, err = lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.Error(t, err)
+	upgraded, _, err = UpgradeTo31(NewDocument(lowDoc))
+	require.NoError(t, err)
+	assert.NotNil(t, upgraded.Components.Schemas.GetOrZero("Node"))
+}
+
+func TestUpgradeTo31_Exclusive(t *testing.T) {
+	spec := `openapi: 3.0.3
+info:
+  title: upgrade
+  version: 1.0.0
+components:
+  schemas:
+    Age:
+      type: integer
+      minimum: 5
+      exclusiveMinimum: true
+      maximum: 120
+      exclusiveMaximum: false
+    Price:
+      type: number
+      exclusiveMaximum: true`
+
+	upgraded, changes, err := UpgradeTo31(buildUpgradeTestDocument(t, spec))
+	require.NoError(t, err)
+
+	age := upgraded.Components.Schemas.GetOrZero("Age").Schema()
+	assert.Nil(t, age.Minimum)
+	require.NotNil(t, age.ExclusiveMinimum)
+	assert.True(t, age.ExclusiveMinimum.IsB())
+	assert.Equal(t, float64(5), age.ExclusiveMinimum.B)
+	assert.Equal(t, float64(120), *age.Maximum)
+	assert.Nil(t, age.ExclusiveMaximum)
+
+	assert.Contains(t, changes, "converted 'exclusiveMinimum: true' on '$.components.schemas.Age' "+
+		"into the value of 'minimum'")
+	assert.Contains(t, changes, "removed 'exclusiveMaximum: false' from '$.components.schemas.Age'")
+	assert.Contains(t, changes, "skipped 'exclusiveMaximum: true' on '$.components.schemas.Price', "+
+		"schema has no 'maximum' to take the value of")
+}
@@ -103,6 +103,8 @@ type Schema struct {
 	UnevaluatedItems      low.NodeReference[*SchemaProxy]
 	UnevaluatedProperties low.NodeReference[*SchemaDynamicValue[*SchemaProxy, bool]]
 	Anchor                low.NodeReference[string]
+	DynamicAnchor         low.NodeReference[string]
+	DynamicRef            low.NodeReference[string]
 
 	// Compatible with all versions
 	Title                low.NodeReference[string]
@@ -137,6 +139,10 @@ type Schema struct {
 	Deprecated           low.NodeReference[bool]
 	Extensions           *orderedmap.Map[low.KeyReference[string], low.ValueReference[*yaml.Node]]
 
+	// UnknownKeywords are keywords that are not modeled by the Schema and are not extensions, for example '$comment'
+	// or the keywords of a custom JSON Schema vocabulary. They are kept, so they can be rendered verbatim.
+	UnknownKeywords *orderedmap.Map[low.KeyReference[string], low.ValueReference[*yaml.Node]]
+
 	// Parent Proxy refers back to the low level SchemaProxy that is proxying this schema.
 	ParentProxy *SchemaProxy
 
@@ -362,6 +368,12 @@ func (s *Schema) Hash() [32]byte {
 	if !s.Anchor.IsEmpty() {
 		d = append(d, fmt.Sprint(s.Anchor.Value))
 	}
+	if !s.DynamicAnchor.IsEmpty() {
+		d = append(d, fmt.Sprint(s.DynamicAnchor.Value))
+	}
+	if !s.DynamicRef.IsEmpty() {
+		d = append(d, fmt.Sprint(s.DynamicRef.Value))
+	}
 
 	for pair := orderedmap.First(orderedmap.SortAlpha(s.DependentSchemas.Value)); pair != nil; pair = pair.Next() {
 		d = append(d, fmt.Sprintf("%s-%s", pair.Key().Value, low.GenerateHashString(pair.Value().Value)))
@@ -389,6 +401,7 @@ func (s *Schema) Hash() [32]byte {
 	}
 
 	d = append(d, low.HashExtensions(s.Extensions)...)
+	d = append(d, low.HashExtensions(s.UnknownKeywords)...)
 	if s.Example.Value != nil {
 		d = append(d, low.GenerateHashString(s.Example.Value))
 	}
@@ -439,6 +452,36 @@ func (s *Schema) GetRootNode() *yaml.Node {
 	return s.RootNode
 }
 
+// mergeRefSiblings returns a new node with the content of the referenced node, overridden by the siblings of the $ref
+// in the reference node (see index.SpecIndexConfig.MergeRefSiblings). The referenced node is returned as it is when
+// the reference has no siblings.
+func mergeRefSiblings(refNode, referenced *yaml.Node) *yaml.Node {
+	referenced = utils.NodeAlias(referenced)
+	if refNode.Kind != yaml.MappingNode || referenced.Kind != yaml.MappingNode {
+		return referenced
+	}
+	overridden := make(map[string]bool)
+	var siblings []*yaml.Node
+	for i := 0; i+1 < len(refNode.Content); i += 2 {
+		if refNode.Content[i].Value != "$ref" {
+			overridden[refNode.Content[i].Value] = true
+			siblings = append(siblings, refNode.Content[i], refNode.Content[i+1])
+		}
+	}
+	if len(siblings) == 0 {
+		return referenced
+	}
+	merged := *referenced
+	merged.Content = make([]*yaml.Node, 0, len(referenced.Content)+len(siblings))
+	for i := 0; i+1 < len(referenced.Content); i += 2 {
+		if !overridden[referenced.Content[i].Value] {
+			merged.Content = append(merged.Content, referenced.Content[i], referenced.Content[i+1])
+		}
+	}
+	merged.Content = append(merged.Content, siblings...)
+	return &merged
+}
+
 // Build will perform a number of operations.
 // Extraction of the following happens in this method:
 //   - Extensions
@@ -475,6 +518,9 @@ func (s *Schema) Build(ctx context.Context, root *yaml.Node, idx *index.SpecInde
 	if h, _, _ := utils.IsNodeRefValue(root); h {
 		ref, _, err, fctx := low.LocateRefNodeWithContext(ctx, root, idx)
 		if ref != nil {
+			if idx.ShouldMergeRefSiblings() {
+				ref = mergeRefSiblings(root, ref)
+			}
 			root = ref
 			if fctx != nil {
 				ctx = fctx
@@ -496,6 +542,7 @@ func (s *Schema) Build(ctx context.Context, root *yaml.Node, idx *index.SpecInde
 	}
 
 	s.extractExtensions(root)
+	s.extractUnknownKeywords(root)
 
 	// determine schema type, singular (3.0) or multiple (3.1), use a variable value
 	_, typeLabel, typeValue := utils.FindKeyNodeFullTop(TypeLabel, root.Content)
@@ -626,6 +673,22 @@ func (s *Schema) Build(ctx context.Context, root *yaml.Node, idx *index.SpecInde
 		}
 	}
 
+	// handle dynamic anchor if set. (3.1)
+	_, dynamicAnchorLabel, dynamicAnchorNode := utils.FindKeyNodeFullTop(DynamicAnchorLabel, root.Content)
+	if dynamicAnchorNode != nil {
+		s.DynamicAnchor = low.NodeReference[string]{
+			Value: dynamicAnchorNode.Value, KeyNode: dynamicAnchorLabel, ValueNode: dynamicAnchorNode,
+		}
+	}
+
+	// handle dynamic reference if set. (3.1)
+	_, dynamicRefLabel, dynamicRefNode := utils.FindKeyNodeFullTop(DynamicRefLabel, root.Content)
+	if dynamicRefNode != nil {
+		s.DynamicRef = low.NodeReference[string]{
+			Value: dynamicRefNode.Value, KeyNode: dynamicRefLabel, ValueNode: dynamicRefNode,
+		}
+	}
+
 	// handle example if set. (3.0)
 	_, expLabel, expNode := utils.FindKeyNodeFullTop(ExampleLabel, root.Content)
 	if expNode != nil {
@@ -1029,23 +1092,31 @@ func buildPropertyMap(ctx context.Context, root *yaml.Node, idx *index.SpecIndex
 			// check our prop isn't reference
 			refString := ""
 			var refNode *yaml.Node
+			lazy := false
 			if h, _, l := utils.IsNodeRefValue(prop); h {
-				ref, fIdx, _, fctx := low.LocateRefNodeWithContext(foundCtx, prop, foundIdx)
-				if ref != nil {
-
+				if isLazyRemote(foundCtx, prop, foundIdx) {
 					refNode = prop
-					prop = ref
 					refString = l
-					foundCtx = fctx
-					foundIdx = fIdx
+					lazy = true
 				} else {
-					return nil, fmt.Errorf("schema properties build failed: cannot find reference %s, line %d, col %d",
-						prop.Content[1].Value, prop.Content[1].Line, prop.Content[1].Column)
+					ref, fIdx, _, fctx := low.LocateRefNodeWithContext(foundCtx, prop, foundIdx)
+					if ref != nil {
+
+						refNode = prop
+						prop = ref
+						refString = l
+						foundCtx = fctx
+						foundIdx = fIdx
+					} else {
+						return nil, fmt.Errorf("schema properties build failed: cannot find reference %s, line %d, col %d",
+							prop.Content[1].Value, prop.Content[1].Line, prop.Content[1].Column)
+					}
 				}
 			}
 
-			sp := &SchemaProxy{ctx: foundCtx, kn: currentProp, vn: prop, idx: foundIdx}
+			sp := &SchemaProxy{ctx: foundCtx, kn: currentProp, vn: prop, idx: foundIdx, lazy: lazy}
 			sp.SetReference(refString, refNode)
+			sp.followDynamicRef()
 
 			propertyMap.Set(low.KeyReference[string]{
 				KeyNode: currentProp,
@@ -1087,6 +1158,40 @@ func (s *Schema) extractExtensions(root *yaml.Node) {
 	s.Extensions = low.ExtractExtensions(root)
 }
 
+// schemaKeywords are the keywords modeled by the Schema, anything else (that is not an extension) is unknown.
+var schemaKeywords = map[string]bool{
+	"$ref": true, SchemaTypeLabel: true, AnchorLabel: true, DynamicAnchorLabel: true, DynamicRefLabel: true,
+	ExclusiveMaximumLabel: true, ExclusiveMinimumLabel: true, TypeLabel: true, AllOfLabel: true, OneOfLabel: true,
+	AnyOfLabel: true, DiscriminatorLabel: true, ExamplesLabel: true, PrefixItemsLabel: true, ContainsLabel: true,
+	"minContains": true, "maxContains": true, IfLabel: true, ElseLabel: true, ThenLabel: true,
+	DependentSchemasLabel: true, PatternPropertiesLabel: true, PropertyNamesLabel: true, UnevaluatedItemsLabel: true,
+	UnevaluatedPropertiesLabel: true, ItemsLabel: true, NotLabel: true, PropertiesLabel: true, TitleLabel: true,
+	"multipleOf": true, "maximum": true, "minimum": true, "maxLength": true, "minLength": true, "pattern": true,
+	"format": true, "maxItems": true, "minItems": true, "uniqueItems": true, "maxProperties": true,
+	"minProperties": true, "required": true, "enum": true, AdditionalPropertiesLabel: true, DescriptionLabel: true,
+	"contentEncoding": true, "contentMediaType": true, "default": true, "const": true, "nullable": true,
+	"readOnly": true, "writeOnly": true, XMLLabel: true, ExternalDocsLabel: true, ExampleLabel: true,
+	"deprecated": true,
+}
+
+// extract keywords that are not modeled by the schema, and are not extensions.
+func (s *Schema) extractUnknownKeywords(root *yaml.Node) {
+	keywords := orderedmap.New[low.KeyReference[string], low.ValueReference[*yaml.Node]]()
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		k, v := root.Content[i], root.Content[i+1]
+		if schemaKeywords[k.Value] || strings.HasPrefix(k.Value, "x-") {
+			continue
+		}
+		keywords.Set(
+			low.KeyReference[string]{Value: k.Value, KeyNode: k},
+			low.ValueReference[*yaml.Node]{Value: v, ValueNode: v},
+		)
+	}
+	if keywords.Len() > 0 {
+		s.UnknownKeywords = keywords
+	}
+}
+
 // build out a child schema for parent schema.
 func buildSchema(ctx context.Context, schemas chan schemaProxyBuildResult, labelNode, valueNode *yaml.Node, errors chan error, idx *index.SpecIndex) {
 	if valueNode != nil {
@@ -1115,6 +1220,7 @@ func buildSchema(ctx context.Context, schemas chan schemaProxyBuildResult, label
 			if isRef {
 				sp.SetReference(refLocation, rf)
 			}
+			sp.followDynamicRef()
 			res := &low.ValueReference[*SchemaProxy]{
 				Value:     sp,
 				ValueNode: vn,
@@ -1133,23 +1239,30 @@ func buildSchema(ctx context.Context, schemas chan schemaProxyBuildResult, label
 		foundIdx := idx
 		if utils.IsNodeMap(valueNode) {
 			h := false
+			lazy := false
 			if h, _, refLocation = utils.IsNodeRefValue(valueNode); h {
 				isRef = true
-				ref, fIdx, _, fctx := low.LocateRefNodeWithContext(foundCtx, valueNode, foundIdx)
-				if ref != nil {
+				if isLazyRemote(foundCtx, valueNode, foundIdx) {
 					refNode = valueNode
-					valueNode = ref
-					foundCtx = fctx
-					foundIdx = fIdx
+					lazy = true
 				} else {
-					errors <- fmt.Errorf("build schema failed: reference cannot be found: %s, line %d, col %d",
-						valueNode.Content[1].Value, valueNode.Content[1].Line, valueNode.Content[1].Column)
+					ref, fIdx, _, fctx := low.LocateRefNodeWithContext(foundCtx, valueNode, foundIdx)
+					if ref != nil {
+						refNode = valueNode
+						valueNode = ref
+						foundCtx = fctx
+						foundIdx = fIdx
+					} else {
+						errors <- fmt.Errorf("build schema failed: reference cannot be found: %s, line %d, col %d",
+							valueNode.Content[1].Value, valueNode.Content[1].Line, valueNode.Content[1].Column)
+					}
 				}
 			}
 
 			// this only runs once, however to keep things consistent, it makes sense to use the same async method
 			// that arrays will use.
 			r := build(foundCtx, foundIdx, labelNode, valueNode, refNode, -1, syncChan, isRef, refLocation)
+			r.res.Value.lazy = lazy
 			schemas <- schemaProxyBuildResult{
 				k: low.KeyReference[string]{
 					KeyNode: labelNode,
@@ -1166,23 +1279,30 @@ func buildSchema(ctx context.Context, schemas chan schemaProxyBuildResult, label
 				h := false
 				foundIdx = idx
 				foundCtx = ctx
+				lazy := false
 				if h, _, refLocation = utils.IsNodeRefValue(vn); h {
 					isRef = true
-					ref, fIdx, _, fctx := low.LocateRefNodeWithContext(foundCtx, vn, foundIdx)
-					if ref != nil {
+					if isLazyRemote(foundCtx, vn, foundIdx) {
 						refNode = vn
-						vn = ref
-						foundCtx = fctx
-						foundIdx = fIdx
+						lazy = true
 					} else {
-						err := fmt.Errorf("build schema failed: reference cannot be found: %s, line %d, col %d",
-							vn.Content[1].Value, vn.Content[1].Line, vn.Content[1].Column)
-						errors <- err
-						return
+						ref, fIdx, _, fctx := low.LocateRefNodeWithContext(foundCtx, vn, foundIdx)
+						if ref != nil {
+							refNode = vn
+							vn = ref
+							foundCtx = fctx
+							foundIdx = fIdx
+						} else {
+							err := fmt.Errorf("build schema failed: reference cannot be found: %s, line %d, col %d",
+								vn.Content[1].Value, vn.Content[1].Line, vn.Content[1].Column)
+							errors <- err
+							return
+						}
 					}
 				}
 				refBuilds++
 				r := build(foundCtx, foundIdx, vn, vn, refNode, i, syncChan, isRef, refLocation)
+				r.res.Value.lazy = lazy
 				results[r.idx] = r.res
 			}
 
@@ -1214,10 +1334,20 @@ func ExtractSchema(ctx context.Context, root *yaml.Node, idx *index.SpecIndex) (
 
 	foundIndex := idx
 	foundCtx := ctx
-	if rf, rl, _ := utils.IsNodeRefValue(root); rf {
+	lazy := false
+	if rf, rl, rv := utils.IsNodeRefValue(root); rf && isLazyRemote(ctx, root, idx) {
+		schNode = root
+		schLabel = rl
+		refLocation = rv
+		refNode = root
+		lazy = true
+	} else if rf {
 		// locate reference in index.
 		ref, fIdx, _, nCtx := low.LocateRefNodeWithContext(ctx, root, idx)
 		if ref != nil {
+			if idx.ShouldMergeRefSiblings() {
+				ref = mergeRefSiblings(root, ref)
+			}
 			schNode = ref
 			schLabel = rl
 			foundCtx = nCtx
@@ -1234,9 +1364,15 @@ func ExtractSchema(ctx context.Context, root *yaml.Node, idx *index.SpecIndex) (
 		_, schLabel, schNode = utils.FindKeyNodeFull(SchemaLabel, root.Content)
 		if schNode != nil {
 			h := false
-			if h, _, refLocation = utils.IsNodeRefValue(schNode); h {
+			if h, _, refLocation = utils.IsNodeRefValue(schNode); h && isLazyRemote(foundCtx, schNode, foundIndex) {
+				refNode = schNode
+				lazy = true
+			} else if h {
 				ref, fIdx, _, nCtx := low.LocateRefNodeWithContext(foundCtx, schNode, foundIndex)
 				if ref != nil {
+					if foundIndex.ShouldMergeRefSiblings() {
+						ref = mergeRefSiblings(schNode, ref)
+					}
 					refNode = schNode
 					schNode = ref
 					if fIdx != nil {
@@ -1257,15 +1393,16 @@ func ExtractSchema(ctx context.Context, root *yaml.Node, idx *index.SpecIndex) (
 
 	if schNode != nil {
 		// check if schema has already been built.
-		schema := &SchemaProxy{kn: schLabel, vn: schNode, idx: foundIndex, ctx: foundCtx}
+		schema := &SchemaProxy{kn: schLabel, vn: schNode, idx: foundIndex, ctx: foundCtx, lazy: lazy}
 		schema.SetReference(refLocation, refNode)
+		schema.followDynamicRef()
 
 		n := &low.NodeReference[*SchemaProxy]{
 			Value:     schema,
 			KeyNode:   schLabel,
-			ValueNode: schNode,
+			ValueNode: schema.vn,
 		}
-		n.SetReference(refLocation, refNode)
+		n.SetReference(schema.GetReference(), schema.GetReferenceNode())
 		return n, nil
 	}
 	return nil, nil
@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"log/slog"
+	"sync"
 
 	"github.com/pb33f/libopenapi/datamodel/low"
 	"github.com/pb33f/libopenapi/index"
@@ -54,6 +55,8 @@ type SchemaProxy struct {
 	rendered   *Schema
 	buildError error
 	ctx        context.Context
+	lock       sync.Mutex
+	lazy       bool // the value node is a remote reference, that is located when the schema is built.
 }
 
 // Build will prepare the SchemaProxy for rendering, it does not build the Schema, only sets up internal state.
@@ -66,9 +69,34 @@ func (sp *SchemaProxy) Build(ctx context.Context, key, value *yaml.Node, idx *in
 	if rf, _, r := utils.IsNodeRefValue(value); rf {
 		sp.SetReference(r, value)
 	}
+	sp.followDynamicRef()
 	return nil
 }
 
+// followDynamicRef makes the proxy a reference to the schema its '$dynamicRef' resolves to (see
+// index.SpecIndex.FindDynamicRef), so it's followed like a '$ref'. The reference is the location of that schema, and
+// the reference node is the original schema, so it renders with its '$dynamicRef'. A proxy that is already a
+// reference is left as it is, and so is a '$dynamicRef' that can't be resolved, which is built like any other schema.
+func (sp *SchemaProxy) followDynamicRef() {
+	if sp.IsReference() || sp.idx == nil || !utils.IsNodeMap(sp.vn) {
+		return
+	}
+	_, _, dynamicRef := utils.FindKeyNodeFullTop(DynamicRefLabel, sp.vn.Content)
+	if dynamicRef == nil {
+		return
+	}
+	located := sp.idx.FindDynamicRef(dynamicRef.Value)
+	if located == nil || located.Node == sp.vn {
+		if logger := sp.idx.GetLogger(); logger != nil {
+			logger.Warn("dynamic reference cannot be followed, the schema is built without it",
+				"dynamicRef", dynamicRef.Value, "line", dynamicRef.Line, "column", dynamicRef.Column)
+		}
+		return
+	}
+	sp.SetReference(located.Definition, sp.vn)
+	sp.vn = located.Node
+}
+
 // Schema will first check if this SchemaProxy has already rendered the schema, and return the pre-rendered version
 // first.
 //
@@ -80,10 +108,39 @@ func (sp *SchemaProxy) Build(ctx context.Context, key, value *yaml.Node, idx *in
 //
 // If anything goes wrong during the build, then nothing is returned and the error that occurred can
 // be retrieved by using GetBuildError()
+//
+// Schema() is safe to call from multiple goroutines, the schema is only ever built once.
+//
+// Every SchemaProxy that references the same schema returns the same *Schema, unless the schema cache has been
+// disabled (see index.SpecIndexConfig.DisableSchemaCache).
 func (sp *SchemaProxy) Schema() *Schema {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
 	if sp.rendered != nil {
 		return sp.rendered
 	}
+	if sp.lazy {
+		// the remote reference is located (and fetched) the first time the schema is built.
+		ref, fIdx, err, fCtx := low.LocateRefNodeWithContext(sp.ctx, sp.vn, sp.idx)
+		if ref == nil {
+			sp.buildError = err
+			return nil
+		}
+		if sp.idx.ShouldMergeRefSiblings() {
+			ref = mergeRefSiblings(sp.vn, ref)
+		}
+		sp.vn, sp.ctx, sp.lazy = ref, fCtx, false
+		if fIdx != nil {
+			sp.idx = fIdx
+		}
+	}
+	cache, key := sp.schemaCacheKey()
+	if cache != nil {
+		if cached, ok := cache.Load(key); ok {
+			sp.rendered = cached.(*Schema)
+			return sp.rendered
+		}
+	}
 	schema := new(Schema)
 	utils.CheckForMergeNodes(sp.vn)
 	err := schema.Build(sp.ctx, sp.vn, sp.idx)
@@ -92,13 +149,68 @@ func (sp *SchemaProxy) Schema() *Schema {
 		return nil
 	}
 	schema.ParentProxy = sp // https://github.com/pb33f/libopenapi/issues/29
+	if cache != nil {
+		actual, _ := cache.LoadOrStore(key, schema)
+		schema = actual.(*Schema)
+	}
 	sp.rendered = schema
 	return schema
 }
 
+// schemaCacheKey returns the schema cache of the index (see index.SpecIndex.GetSchemaCache) and the node the schema
+// of this proxy is cached against, which is the node the reference resolves to. Only references are cached, so
+// the cache is nil for a proxy that isn't a reference, or for a reference that can't be located cleanly.
+//
+// A cached schema is shared by every proxy of the same reference, so its ParentProxy and RootNode belong to the
+// proxy that built it first.
+func (sp *SchemaProxy) schemaCacheKey() (*sync.Map, *yaml.Node) {
+	if !sp.IsReference() {
+		return nil, nil
+	}
+	if sp.idx.ShouldMergeRefSiblings() && hasRefSiblings(sp.GetReferenceNode()) {
+		return nil, nil // the schema is merged with the siblings of this reference, so it can't be shared.
+	}
+	cache := sp.idx.GetSchemaCache()
+	if cache == nil {
+		return nil, nil
+	}
+	if h, _, _ := utils.IsNodeRefValue(sp.vn); !h {
+		return cache, sp.vn // a lazy remote reference, already located.
+	}
+	located, _, err, _ := low.LocateRefNodeWithContext(sp.ctx, sp.vn, sp.idx)
+	if located == nil || err != nil {
+		return nil, nil
+	}
+	return cache, located
+}
+
+// hasRefSiblings returns true if node is a reference with other keywords next to the $ref.
+func hasRefSiblings(node *yaml.Node) bool {
+	if h, _, _ := utils.IsNodeRefValue(node); !h {
+		return false
+	}
+	return len(node.Content) > 2
+}
+
+// isLazyRemote returns true if node is a reference to a remote schema that should be located when the schema is
+// first built, rather than straight away (see index.SpecIndexConfig.LazyRemoteResolution).
+func isLazyRemote(ctx context.Context, node *yaml.Node, idx *index.SpecIndex) bool {
+	h, _, ref := utils.IsNodeRefValue(node)
+	if !h || idx == nil {
+		return false
+	}
+	base := ""
+	if ctx != nil {
+		base, _ = ctx.Value(index.CurrentPathKey).(string)
+	}
+	return idx.IsLazyRemoteReference(ref, base)
+}
+
 // GetBuildError returns the build error that was set when Schema() was called. If Schema() has not been run, or
 // there were no errors during build, then nil will be returned.
 func (sp *SchemaProxy) GetBuildError() error {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
 	return sp.buildError
 }
 
@@ -126,6 +238,11 @@ func (sp *SchemaProxy) GetContext() context.Context {
 	return sp.ctx
 }
 
+// GetIndex will return the index.SpecIndex the SchemaProxy was built with.
+func (sp *SchemaProxy) GetIndex() *index.SpecIndex {
+	return sp.idx
+}
+
 // GetValueNode will return the yaml.Node pointer used by the proxy to generate the Schema.
 func (sp *SchemaProxy) GetValueNode() *yaml.Node {
 	return sp.vn
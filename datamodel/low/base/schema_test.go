@@ -477,6 +477,79 @@ const: tasty`
 	assert.Equal(t, "tasty", schConst)
 }
 
+func Test_Schema_31_DynamicRef(t *testing.T) {
+	testSpec := `$dynamicAnchor: node
+type: object
+properties:
+  children:
+    type: array
+    items:
+      $dynamicRef: '#node'`
+
+	var rootNode yaml.Node
+	mErr := yaml.Unmarshal([]byte(testSpec), &rootNode)
+	assert.NoError(t, mErr)
+
+	sch := Schema{}
+	mbErr := low.BuildModel(rootNode.Content[0], &sch)
+	assert.NoError(t, mbErr)
+
+	schErr := sch.Build(context.Background(), rootNode.Content[0], nil)
+	assert.NoError(t, schErr)
+	assert.Equal(t, "node", sch.DynamicAnchor.Value)
+	assert.Equal(t, 1, sch.DynamicAnchor.KeyNode.Line)
+
+	items := sch.FindProperty("children").Value.Schema().Items.Value.A.Schema()
+	assert.Equal(t, "#node", items.DynamicRef.Value)
+	assert.Equal(t, 7, items.DynamicRef.ValueNode.Line)
+}
+
+func Test_Schema_31_DynamicRef_Followed(t *testing.T) {
+	yml := `openapi: 3.1.0
+components:
+  schemas:
+    Tree:
+      $dynamicAnchor: node
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $dynamicRef: '#node'
+        label:
+          $dynamicRef: '#missing'`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndexWithConfig(&idxNode, index.CreateOpenAPIIndexConfig())
+	tree := idx.FindComponent("#/components/schemas/Tree")
+	if !assert.NotNil(t, tree) {
+		return
+	}
+
+	sch := Schema{}
+	_ = low.BuildModel(tree.Node, &sch)
+	assert.NoError(t, sch.Build(context.Background(), tree.Node, idx))
+
+	// the dynamic reference is followed like a $ref, to the schema that declares the anchor.
+	items := sch.FindProperty("children").Value.Schema().Items.Value.A
+	assert.True(t, items.IsReference())
+	assert.Equal(t, "#/components/schemas/Tree", items.GetReference())
+	_, _, dynamicRef := utils.FindKeyNodeFullTop(DynamicRefLabel, items.GetReferenceNode().Content)
+	assert.Equal(t, "#node", dynamicRef.Value)
+	followed := items.Schema()
+	if !assert.NotNil(t, followed) {
+		return
+	}
+	assert.Equal(t, "node", followed.DynamicAnchor.Value)
+	assert.NotNil(t, followed.FindProperty("children"))
+
+	// a dynamic reference that cannot be resolved is built as it is.
+	label := sch.FindProperty("label").Value
+	assert.False(t, label.IsReference())
+	assert.Equal(t, "#missing", label.Schema().DynamicRef.Value)
+}
+
 func TestSchema_Build_PropsLookup(t *testing.T) {
 	yml := `components:
   schemas:
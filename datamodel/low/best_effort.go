@@ -0,0 +1,44 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package low
+
+import (
+	"errors"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// SectionError returns err as a *datamodel.SectionError for the top level section of the document named label,
+// located at the key of the section. It is used by the document builders when they are configured to build as much
+// of a document as they can (see datamodel.DocumentConfiguration.BestEffort).
+func SectionError(root *yaml.Node, label string, err error) error {
+	sErr := &datamodel.SectionError{Path: "/" + label, Err: err}
+	if root != nil {
+		if _, ln, _ := utils.FindKeyNodeFullTop(label, root.Content); ln != nil {
+			sErr.Line, sErr.Column = ln.Line, ln.Column
+		}
+	}
+	return sErr
+}
+
+// LocatedError returns a resolving or indexing error of the rolodex as a *datamodel.SectionError located at the
+// node the error was found at, any other error is returned as it is.
+func LocatedError(err error) error {
+	var node *yaml.Node
+	var resErr *index.ResolvingError
+	var idxErr *index.IndexingError
+	switch {
+	case errors.As(err, &resErr):
+		node = resErr.Node
+	case errors.As(err, &idxErr):
+		node = idxErr.Node
+	}
+	if node == nil {
+		return err
+	}
+	return &datamodel.SectionError{Line: node.Line, Column: node.Column, Err: err}
+}
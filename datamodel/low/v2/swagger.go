@@ -143,10 +143,22 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 	idxConfig.SpecInfo = info
 	idxConfig.IgnoreArrayCircularReferences = config.IgnoreArrayCircularReferences
 	idxConfig.IgnorePolymorphicCircularReferences = config.IgnorePolymorphicCircularReferences
+	idxConfig.DisableSchemaCache = config.DisableSchemaCache
 	idxConfig.AvoidCircularReferenceCheck = true
 	idxConfig.BaseURL = config.BaseURL
 	idxConfig.BasePath = config.BasePath
+	idxConfig.HTTPClient = config.HTTPClient
+	idxConfig.MaxDocumentBytes = config.MaxDocumentBytes
+	idxConfig.MaxDocumentNodes = config.MaxDocumentNodes
+	idxConfig.MaxYAMLAliasExpansions = config.MaxYAMLAliasExpansions
+	idxConfig.Context = config.Context
+	idxConfig.AllowedRemoteHosts = config.AllowedRemoteHosts
+	idxConfig.DeniedRemoteHosts = config.DeniedRemoteHosts
+	idxConfig.LazyRemoteResolution = config.LazyRemoteResolution
 	idxConfig.Logger = config.Logger
+	if config.SpecURL != nil {
+		idxConfig.SpecAbsolutePath = config.SpecURL.String()
+	}
 	rolodex := index.NewRolodex(idxConfig)
 	rolodex.SetRootNode(info.RootNode)
 	doc.Rolodex = rolodex
@@ -162,9 +174,11 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 
 			// create a local filesystem
 			localFSConf := index.LocalFSConfig{
-				BaseDirectory: cwd,
-				IndexConfig:   idxConfig,
-				FileFilters:   config.FileFilter,
+				BaseDirectory:           cwd,
+				Logger:                  config.Logger,
+				IndexConfig:             idxConfig,
+				FileFilters:             config.FileFilter,
+				RestrictToBaseDirectory: config.RestrictToBaseDirectory,
 			}
 			fileFS, _ := index.NewLocalFSWithConfig(&localFSConf)
 			idxConfig.AllowFileLookup = true
@@ -174,8 +188,8 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 		}
 	}
 
-	// if base url is provided, add a remote filesystem to the rolodex.
-	if idxConfig.BaseURL != nil {
+	// if base url (or the location of a remote spec) is provided, add a remote filesystem to the rolodex.
+	if idxConfig.BaseURL != nil || config.SpecURL != nil {
 
 		// create a remote filesystem
 		remoteFS, _ := index.NewRemoteFSWithConfig(idxConfig)
@@ -185,7 +199,11 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 		idxConfig.AllowRemoteLookup = true
 
 		// add to the rolodex
-		rolodex.AddRemoteFS(config.BaseURL.String(), remoteFS)
+		u := config.SpecURL
+		if config.BaseURL != nil {
+			u = config.BaseURL
+		}
+		rolodex.AddRemoteFS(u.String(), remoteFS)
 
 	}
 
@@ -201,9 +219,19 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 		rolodex.CheckForCircularReferences()
 	}
 
+	// stop here if the document build has been cancelled.
+	if config.Context != nil && config.Context.Err() != nil {
+		return nil, config.Context.Err()
+	}
+
 	// extract errors
 	roloErrs := rolodex.GetCaughtErrors()
 	if roloErrs != nil {
+		if config.BestEffort {
+			for i := range roloErrs {
+				roloErrs[i] = low.LocatedError(roloErrs[i])
+			}
+		}
 		errs = append(errs, roloErrs...)
 	}
 
@@ -219,25 +247,44 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 	// extract externalDocs
 	extDocs, err := low.ExtractObject[*base.ExternalDoc](ctx, base.ExternalDocsLabel, info.RootNode, rolodex.GetRootIndex())
 	if err != nil {
+		if config.BestEffort {
+			err = low.SectionError(info.RootNode.Content[0], base.ExternalDocsLabel, err)
+		}
 		errs = append(errs, err)
 	}
 
 	doc.ExternalDocs = extDocs
 
-	extractionFuncs := []documentFunction{
-		extractInfo,
-		extractPaths,
-		extractDefinitions,
-		extractParamDefinitions,
-		extractResponsesDefinitions,
-		extractSecurityDefinitions,
-		extractTags,
-		extractSecurity,
+	extractionFuncs := []struct {
+		label string
+		run   documentFunction
+	}{
+		{base.InfoLabel, extractInfo},
+		{PathsLabel, extractPaths},
+		{DefinitionsLabel, extractDefinitions},
+		{ParametersLabel, extractParamDefinitions},
+		{ResponsesLabel, extractResponsesDefinitions},
+		{SecurityDefinitionsLabel, extractSecurityDefinitions},
+		{base.TagsLabel, extractTags},
+		{SecurityLabel, extractSecurity},
 	}
 	doneChan := make(chan bool)
 	errChan := make(chan error)
 	for i := range extractionFuncs {
-		go extractionFuncs[i](ctx, info.RootNode.Content[0], &doc, rolodex.GetRootIndex(), doneChan, errChan)
+		go func(label string, run documentFunction) {
+			// every extraction reports exactly once, so buffered channels let it finish before the error is wrapped.
+			done, failed := make(chan bool, 1), make(chan error, 1)
+			run(ctx, info.RootNode.Content[0], &doc, rolodex.GetRootIndex(), done, failed)
+			select {
+			case <-done:
+				doneChan <- true
+			case e := <-failed:
+				if config.BestEffort {
+					e = low.SectionError(info.RootNode.Content[0], label, e)
+				}
+				errChan <- e
+			}
+		}(extractionFuncs[i].label, extractionFuncs[i].run)
 	}
 	completedExtractions := 0
 	for completedExtractions < len(extractionFuncs) {
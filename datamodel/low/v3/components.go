@@ -34,6 +34,7 @@ type Components struct {
 	SecuritySchemes low.NodeReference[*orderedmap.Map[low.KeyReference[string], low.ValueReference[*SecurityScheme]]]
 	Links           low.NodeReference[*orderedmap.Map[low.KeyReference[string], low.ValueReference[*Link]]]
 	Callbacks       low.NodeReference[*orderedmap.Map[low.KeyReference[string], low.ValueReference[*Callback]]]
+	PathItems       low.NodeReference[*orderedmap.Map[low.KeyReference[string], low.ValueReference[*PathItem]]] // 3.1 only
 	Extensions      *orderedmap.Map[low.KeyReference[string], low.ValueReference[*yaml.Node]]
 	KeyNode         *yaml.Node
 	RootNode        *yaml.Node
@@ -77,6 +78,7 @@ func (co *Components) Hash() [32]byte {
 	generateHashForObjectMap(co.SecuritySchemes.Value, &f)
 	generateHashForObjectMap(co.Links.Value, &f)
 	generateHashForObjectMap(co.Callbacks.Value, &f)
+	generateHashForObjectMap(co.PathItems.Value, &f)
 	f = append(f, low.HashExtensions(co.Extensions)...)
 	return sha256.Sum256([]byte(strings.Join(f, "|")))
 }
@@ -133,6 +135,91 @@ func (co *Components) FindCallback(callback string) *low.ValueReference[*Callbac
 	return low.FindItemInOrderedMap[*Callback](callback, co.Callbacks.Value)
 }
 
+// FindPathItem attempts to locate a PathItem from 'pathItems' (3.1 only) with a specific name
+func (co *Components) FindPathItem(pathItem string) *low.ValueReference[*PathItem] {
+	return low.FindItemInOrderedMap[*PathItem](pathItem, co.PathItems.Value)
+}
+
+// SchemaCount returns the number of schemas in the Components, zero if Components is nil.
+func (co *Components) SchemaCount() int {
+	if co == nil {
+		return 0
+	}
+	return orderedmap.Len(co.Schemas.Value)
+}
+
+// ResponseCount returns the number of responses in the Components, zero if Components is nil.
+func (co *Components) ResponseCount() int {
+	if co == nil {
+		return 0
+	}
+	return orderedmap.Len(co.Responses.Value)
+}
+
+// ParameterCount returns the number of parameters in the Components, zero if Components is nil.
+func (co *Components) ParameterCount() int {
+	if co == nil {
+		return 0
+	}
+	return orderedmap.Len(co.Parameters.Value)
+}
+
+// ExampleCount returns the number of examples in the Components, zero if Components is nil.
+func (co *Components) ExampleCount() int {
+	if co == nil {
+		return 0
+	}
+	return orderedmap.Len(co.Examples.Value)
+}
+
+// RequestBodyCount returns the number of request bodies in the Components, zero if Components is nil.
+func (co *Components) RequestBodyCount() int {
+	if co == nil {
+		return 0
+	}
+	return orderedmap.Len(co.RequestBodies.Value)
+}
+
+// HeaderCount returns the number of headers in the Components, zero if Components is nil.
+func (co *Components) HeaderCount() int {
+	if co == nil {
+		return 0
+	}
+	return orderedmap.Len(co.Headers.Value)
+}
+
+// SecuritySchemeCount returns the number of security schemes in the Components, zero if Components is nil.
+func (co *Components) SecuritySchemeCount() int {
+	if co == nil {
+		return 0
+	}
+	return orderedmap.Len(co.SecuritySchemes.Value)
+}
+
+// LinkCount returns the number of links in the Components, zero if Components is nil.
+func (co *Components) LinkCount() int {
+	if co == nil {
+		return 0
+	}
+	return orderedmap.Len(co.Links.Value)
+}
+
+// CallbackCount returns the number of callbacks in the Components, zero if Components is nil.
+func (co *Components) CallbackCount() int {
+	if co == nil {
+		return 0
+	}
+	return orderedmap.Len(co.Callbacks.Value)
+}
+
+// PathItemCount returns the number of path items in the Components, zero if Components is nil.
+func (co *Components) PathItemCount() int {
+	if co == nil {
+		return 0
+	}
+	return orderedmap.Len(co.PathItems.Value)
+}
+
 // Build converts root YAML node containing components to low level model.
 // Process each component in parallel.
 func (co *Components) Build(ctx context.Context, root *yaml.Node, idx *index.SpecIndex) error {
@@ -145,7 +232,7 @@ func (co *Components) Build(ctx context.Context, root *yaml.Node, idx *index.Spe
 	var reterr error
 	var ceMutex sync.Mutex
 	var wg sync.WaitGroup
-	wg.Add(9)
+	wg.Add(10)
 
 	captureError := func(err error) {
 		ceMutex.Lock()
@@ -209,6 +296,12 @@ func (co *Components) Build(ctx context.Context, root *yaml.Node, idx *index.Spe
 		co.Callbacks = callbacks
 		wg.Done()
 	}()
+	go func() {
+		pathItems, err := extractComponentValues[*PathItem](ctx, PathItemsLabel, root, idx)
+		captureError(err)
+		co.PathItems = pathItems
+		wg.Done()
+	}()
 
 	wg.Wait()
 	return reterr
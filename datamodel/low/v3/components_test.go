@@ -104,6 +104,20 @@ func TestComponents_Build_Success(t *testing.T) {
 
 	assert.Equal(t, "76328a0e32a9989471d335734af04a37bdfad333cf8cd8aa8065998c3a1489a2",
 		low.GenerateHashString(&n))
+
+	assert.Equal(t, 2, n.SchemaCount())
+	assert.Equal(t, 2, n.ResponseCount())
+	assert.Equal(t, 2, n.ParameterCount())
+	assert.Equal(t, 2, n.ExampleCount())
+	assert.Equal(t, 2, n.RequestBodyCount())
+	assert.Equal(t, 2, n.HeaderCount())
+	assert.Equal(t, 2, n.SecuritySchemeCount())
+	assert.Equal(t, 2, n.LinkCount())
+	assert.Equal(t, 2, n.CallbackCount())
+	assert.Zero(t, n.PathItemCount())
+
+	var empty *Components
+	assert.Zero(t, empty.SchemaCount())
 }
 
 func TestComponents_Build_Success_Skip(t *testing.T) {
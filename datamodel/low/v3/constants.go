@@ -17,6 +17,7 @@ const (
 	RequestBodiesLabel         = "requestBodies"
 	ResponsesLabel             = "responses"
 	CallbacksLabel             = "callbacks"
+	PathItemsLabel             = "pathItems"
 	ContentLabel               = "content"
 	PathsLabel                 = "paths"
 	PathLabel                  = "path"
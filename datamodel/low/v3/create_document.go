@@ -35,17 +35,31 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 		return nil, errors.New("no openapi version/tag found, cannot create document")
 	}
 	version = low.NodeReference[string]{Value: versionNode.Value, KeyNode: labelNode, ValueNode: versionNode}
-	doc := Document{Version: version}
+	doc := Document{Version: version, Config: config}
 
 	// create an index config and shadow the document configuration.
 	idxConfig := index.CreateClosedAPIIndexConfig()
 	idxConfig.SpecInfo = info
 	idxConfig.IgnoreArrayCircularReferences = config.IgnoreArrayCircularReferences
 	idxConfig.IgnorePolymorphicCircularReferences = config.IgnorePolymorphicCircularReferences
+	idxConfig.DisableSchemaCache = config.DisableSchemaCache
+	idxConfig.MergeRefSiblings = config.MergeRefSiblings
 	idxConfig.AvoidCircularReferenceCheck = true
 	idxConfig.BaseURL = config.BaseURL
 	idxConfig.BasePath = config.BasePath
+	idxConfig.SpecFilePath = config.SpecFilePath
+	idxConfig.HTTPClient = config.HTTPClient
+	idxConfig.MaxDocumentBytes = config.MaxDocumentBytes
+	idxConfig.MaxDocumentNodes = config.MaxDocumentNodes
+	idxConfig.MaxYAMLAliasExpansions = config.MaxYAMLAliasExpansions
+	idxConfig.Context = config.Context
+	idxConfig.AllowedRemoteHosts = config.AllowedRemoteHosts
+	idxConfig.DeniedRemoteHosts = config.DeniedRemoteHosts
+	idxConfig.LazyRemoteResolution = config.LazyRemoteResolution
 	idxConfig.Logger = config.Logger
+	if config.SpecURL != nil {
+		idxConfig.SpecAbsolutePath = config.SpecURL.String()
+	}
 	extract := config.ExtractRefsSequentially
 	idxConfig.ExtractRefsSequentially = extract
 	rolodex := index.NewRolodex(idxConfig)
@@ -63,9 +77,11 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 
 			// create a local filesystem
 			localFSConf := index.LocalFSConfig{
-				BaseDirectory: cwd,
-				IndexConfig:   idxConfig,
-				FileFilters:   config.FileFilter,
+				BaseDirectory:           cwd,
+				Logger:                  config.Logger,
+				IndexConfig:             idxConfig,
+				FileFilters:             config.FileFilter,
+				RestrictToBaseDirectory: config.RestrictToBaseDirectory,
 			}
 
 			fileFS, _ := index.NewLocalFSWithConfig(&localFSConf)
@@ -76,7 +92,7 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 		}
 	}
 	// if base url is provided, add a remote filesystem to the rolodex.
-	if idxConfig.BaseURL != nil || config.AllowRemoteReferences {
+	if idxConfig.BaseURL != nil || config.AllowRemoteReferences || config.SpecURL != nil {
 
 		// create a remote filesystem
 		remoteFS, _ := index.NewRemoteFSWithConfig(idxConfig)
@@ -89,6 +105,8 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 		u := "default"
 		if config.BaseURL != nil {
 			u = config.BaseURL.String()
+		} else if config.SpecURL != nil {
+			u = config.SpecURL.String()
 		}
 		rolodex.AddRemoteFS(u, remoteFS)
 	}
@@ -120,9 +138,19 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 			config.Logger.Debug("circular check completed", "ms", done)
 		}
 	}
+	// stop here if the document build has been cancelled.
+	if config.Context != nil && config.Context.Err() != nil {
+		return nil, config.Context.Err()
+	}
+
 	// extract errors
 	roloErrs := rolodex.GetCaughtErrors()
 	if roloErrs != nil {
+		if config.BestEffort {
+			for i := range roloErrs {
+				roloErrs[i] = low.LocatedError(roloErrs[i])
+			}
+		}
 		errs = append(errs, roloErrs...)
 	}
 
@@ -142,10 +170,14 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 
 	runExtraction := func(ctx context.Context, info *datamodel.SpecInfo, doc *Document, idx *index.SpecIndex,
 		runFunc func(ctx context.Context, i *datamodel.SpecInfo, d *Document, idx *index.SpecIndex) error,
+		label string,
 		ers *[]error,
 		wg *sync.WaitGroup,
 	) {
 		if er := runFunc(ctx, info, doc, idx); er != nil {
+			if config.BestEffort {
+				er = low.SectionError(info.RootNode.Content[0], label, er)
+			}
 			*ers = append(*ers, er)
 		}
 		wg.Done()
@@ -160,6 +192,8 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 		extractPaths,
 		extractWebhooks,
 	}
+	extractionLabels := []string{base.InfoLabel, ServersLabel, base.TagsLabel, ComponentsLabel, SecurityLabel,
+		base.ExternalDocsLabel, PathsLabel, WebhooksLabel}
 
 	ctx := context.Background()
 
@@ -168,8 +202,8 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 		config.Logger.Debug("running extractions")
 	}
 	now = time.Now()
-	for _, f := range extractionFuncs {
-		runExtraction(ctx, info, &doc, rolodex.GetRootIndex(), f, &errs, &wg)
+	for i, f := range extractionFuncs {
+		runExtraction(ctx, info, &doc, rolodex.GetRootIndex(), f, extractionLabels[i], &errs, &wg)
 	}
 	wg.Wait()
 	done = time.Duration(time.Since(now).Milliseconds())
@@ -177,6 +211,9 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 		config.Logger.Debug("extractions complete", "time", done)
 
 	}
+	if config.StrictUnknownFields {
+		errs = append(errs, findUnknownFields(info.RootNode.Content[0])...)
+	}
 	return &doc, errors.Join(errs...)
 }
 
@@ -1,12 +1,16 @@
 package v3
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pb33f/libopenapi/index"
 	"github.com/pb33f/libopenapi/utils"
@@ -183,6 +187,43 @@ func TestRolodexRemoteFileSystem_CustomHttpHandler(t *testing.T) {
 	assert.Error(t, err)
 }
 
+type testAuthTransport struct{}
+
+func (a *testAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer pizza")
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRolodexRemoteFileSystem_CustomHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer pizza" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = rw.Write([]byte(`type: object
+description: a secure pizza`))
+	}))
+	defer server.Close()
+
+	spec := fmt.Sprintf(`openapi: 3.1.0
+components:
+  schemas:
+    Pizza:
+      $ref: '%s/schemas/pizza.yaml'`, server.URL)
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+
+	cf := datamodel.NewDocumentConfiguration()
+	cf.AllowRemoteReferences = true
+	cf.HTTPClient = &http.Client{Transport: &testAuthTransport{}}
+	lDoc, err := CreateDocumentFromConfig(info, cf)
+	require.NoError(t, err)
+
+	pizza := lDoc.Components.Value.FindSchema("Pizza").Value.Schema()
+	require.NotNil(t, pizza)
+	assert.Equal(t, "a secure pizza", pizza.Description.Value)
+}
+
 func TestCircularReference_IgnoreArray(t *testing.T) {
 	spec := `openapi: 3.1.0
 components:
@@ -884,3 +925,112 @@ func ExampleCreateDocument() {
 	fmt.Print(document.Info.Value.Contact.Value.Email.Value)
 	// Output: apiteam@swagger.io
 }
+
+func TestCreateDocument_CancelledContext(t *testing.T) {
+	data, _ := os.ReadFile("../../../test_specs/burgershop.openapi.yaml")
+	info, _ := datamodel.ExtractSpecInfo(data)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cf := datamodel.NewDocumentConfiguration()
+	cf.Context = ctx
+	lDoc, err := CreateDocumentFromConfig(info, cf)
+	assert.Nil(t, lDoc)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRolodexRemoteFileSystem_ContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	idxConfig := index.CreateOpenAPIIndexConfig()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	idxConfig.Context = ctx
+
+	rfs, err := index.NewRemoteFSWithConfig(idxConfig)
+	require.NoError(t, err)
+	_, err = rfs.RemoteHandlerFunc(server.URL + "/schemas/pizza.yaml")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCreateDocument_LazyRemoteResolution(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		_, _ = rw.Write([]byte(`type: object
+description: a remote pizza
+properties:
+  topping:
+    $ref: '#/components/schemas/Topping'
+components:
+  schemas:
+    Topping:
+      type: string
+      description: a remote topping`))
+	}))
+	defer server.Close()
+
+	spec := fmt.Sprintf(`openapi: 3.1.0
+paths:
+  /pizza:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  pizza:
+                    $ref: '%[1]s/schemas/pizza.yaml'
+components:
+  schemas:
+    Pizza:
+      $ref: '%[1]s/schemas/pizza.yaml'
+    Order:
+      allOf:
+        - $ref: '%[1]s/schemas/pizza.yaml'`, server.URL)
+
+	build := func(lazy bool) *Document {
+		info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+		cf := datamodel.NewDocumentConfiguration()
+		cf.AllowRemoteReferences = true
+		cf.LazyRemoteResolution = lazy
+		lDoc, err := CreateDocumentFromConfig(info, cf)
+		require.NoError(t, err)
+		return lDoc
+	}
+
+	lDoc := build(false)
+	assert.NotZero(t, requests.Load())
+
+	requests.Store(0)
+	lDoc = build(true)
+	assert.Zero(t, requests.Load())
+	assert.Empty(t, lDoc.Index.GetReferenceIndexErrors())
+	assert.True(t, lDoc.Index.GetConfig().LazyRemoteResolution)
+
+	// the remote document is fetched when a schema backed by it is first built, and only once.
+	media := lDoc.Paths.Value.FindPath("/pizza").Value.Get.Value.Responses.Value.FindResponseByCode("200").Value.
+		FindContent("application/json").Value
+	pizza := media.Schema.Value.Schema().FindProperty("pizza").Value.Schema()
+	require.NotNil(t, pizza)
+	assert.Equal(t, "a remote pizza", pizza.Description.Value)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// references inside the remote document are relative to it.
+	topping := pizza.FindProperty("topping").Value.Schema()
+	require.NotNil(t, topping)
+	assert.Equal(t, "a remote topping", topping.Description.Value)
+
+	order := lDoc.Components.Value.FindSchema("Order").Value.Schema()
+	require.NotNil(t, order)
+	assert.Equal(t, "a remote pizza", order.AllOf.Value[0].Value.Schema().Description.Value)
+	assert.Equal(t, "a remote pizza", lDoc.Components.Value.FindSchema("Pizza").Value.Schema().Description.Value)
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -9,6 +9,7 @@
 package v3
 
 import (
+	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/pb33f/libopenapi/datamodel/low"
 	"github.com/pb33f/libopenapi/datamodel/low/base"
 	"github.com/pb33f/libopenapi/index"
@@ -86,6 +87,11 @@ type Document struct {
 
 	// Rolodex is a reference to the rolodex used when creating this document.
 	Rolodex *index.Rolodex
+
+	// Config is the DocumentConfiguration used when creating this document.
+	//
+	// This property is not a part of the OpenAPI schema, this is custom to libopenapi.
+	Config *datamodel.DocumentConfiguration
 }
 
 // FindSecurityRequirement will attempt to locate a security requirement string from a supplied name.
@@ -75,6 +75,36 @@ func (p *Paths) GetExtensions() *orderedmap.Map[low.KeyReference[string], low.Va
 	return p.Extensions
 }
 
+// Count returns the number of paths, zero if Paths is nil.
+func (p *Paths) Count() int {
+	if p == nil {
+		return 0
+	}
+	return orderedmap.Len(p.PathItems)
+}
+
+// OperationCount returns the number of operations defined by every path, zero if Paths is nil.
+func (p *Paths) OperationCount() int {
+	if p == nil {
+		return 0
+	}
+	count := 0
+	for pair := orderedmap.First(p.PathItems); pair != nil; pair = pair.Next() {
+		pi := pair.Value().Value
+		if pi == nil {
+			continue
+		}
+		for _, op := range []low.NodeReference[*Operation]{
+			pi.Get, pi.Put, pi.Post, pi.Delete, pi.Options, pi.Head, pi.Patch, pi.Trace,
+		} {
+			if op.Value != nil {
+				count++
+			}
+		}
+	}
+	return count
+}
+
 // Build will extract extensions and all PathItems. This happens asynchronously for speed.
 func (p *Paths) Build(ctx context.Context, keyNode, root *yaml.Node, idx *index.SpecIndex) error {
 	root = utils.NodeAlias(root)
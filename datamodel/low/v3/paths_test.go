@@ -76,6 +76,12 @@ x-milk: cold`
 	assert.Equal(t, "cold", xMilk)
 	assert.Equal(t, "hello", path.Parameters.Value[0].Value.Name.Value)
 	assert.Equal(t, 1, orderedmap.Len(n.GetExtensions()))
+	assert.Equal(t, 1, n.Count())
+	assert.Equal(t, 8, n.OperationCount())
+
+	var empty *Paths
+	assert.Zero(t, empty.Count())
+	assert.Zero(t, empty.OperationCount())
 }
 
 func TestPaths_Build_Fail(t *testing.T) {
@@ -0,0 +1,157 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownFieldError is returned (when DocumentConfiguration.StrictUnknownFields is set) for a field of an OpenAPI
+// object that is not one of the fixed fields of the object, and is not an extension.
+type UnknownFieldError struct {
+	// Field is the name of the unknown field, for example 'component'.
+	Field string
+
+	// Object is the kind of object the field was found in, for example 'document' or 'operation'.
+	Object string
+
+	// Path is a JSON pointer to the object the field was found in, empty for the document itself.
+	Path string
+
+	// Line and Column are the position of the field in the specification.
+	Line   int
+	Column int
+}
+
+func (e *UnknownFieldError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("unknown field '%s' in %s [%d:%d]", e.Field, e.Object, e.Line, e.Column)
+	}
+	return fmt.Sprintf("unknown field '%s' in %s '%s' [%d:%d]", e.Field, e.Object, e.Path, e.Line, e.Column)
+}
+
+// objectFields are the fixed fields of every OpenAPI 3.0 and 3.1 object that is checked for unknown fields. The value
+// of a field is the object it holds: an empty string is a value that isn't checked (like a schema), '[]' is a list of
+// objects, and '{}' is a map of objects. The paths, responses and callback objects have patterned fields, so are not
+// listed here.
+var objectFields = map[string]map[string]string{
+	"document": {"openapi": "", "info": "info", "jsonSchemaDialect": "", "servers": "[]server", "paths": "paths",
+		"webhooks": "{}pathItem", "components": "components", "security": "", "tags": "[]tag",
+		"externalDocs": "externalDocs"},
+	"info": {"title": "", "summary": "", "description": "", "termsOfService": "", "contact": "contact",
+		"license": "license", "version": ""},
+	"contact":        {"name": "", "url": "", "email": ""},
+	"license":        {"name": "", "identifier": "", "url": ""},
+	"server":         {"url": "", "description": "", "variables": "{}serverVariable"},
+	"serverVariable": {"enum": "", "default": "", "description": ""},
+	"components": {"schemas": "", "responses": "{}response", "parameters": "{}parameter", "examples": "{}example",
+		"requestBodies": "{}requestBody", "headers": "{}header", "securitySchemes": "{}securityScheme",
+		"links": "{}link", "callbacks": "{}callback", "pathItems": "{}pathItem"},
+	"pathItem": {"$ref": "", "summary": "", "description": "", "get": "operation", "put": "operation",
+		"post": "operation", "delete": "operation", "options": "operation", "head": "operation",
+		"patch": "operation", "trace": "operation", "servers": "[]server", "parameters": "[]parameter"},
+	"operation": {"tags": "", "summary": "", "description": "", "externalDocs": "externalDocs", "operationId": "",
+		"parameters": "[]parameter", "requestBody": "requestBody", "responses": "responses",
+		"callbacks": "{}callback", "deprecated": "", "security": "", "servers": "[]server"},
+	"externalDocs": {"description": "", "url": ""},
+	"parameter": {"name": "", "in": "", "description": "", "required": "", "deprecated": "", "allowEmptyValue": "",
+		"style": "", "explode": "", "allowReserved": "", "schema": "", "example": "", "examples": "{}example",
+		"content": "{}mediaType"},
+	"requestBody": {"description": "", "content": "{}mediaType", "required": ""},
+	"mediaType":   {"schema": "", "example": "", "examples": "{}example", "encoding": "{}encoding"},
+	"encoding": {"contentType": "", "headers": "{}header", "style": "", "explode": "",
+		"allowReserved": ""},
+	"response": {"description": "", "headers": "{}header", "content": "{}mediaType", "links": "{}link"},
+	"example":  {"summary": "", "description": "", "value": "", "externalValue": ""},
+	"link": {"operationRef": "", "operationId": "", "parameters": "", "requestBody": "", "description": "",
+		"server": "server"},
+	"header": {"description": "", "required": "", "deprecated": "", "allowEmptyValue": "", "style": "",
+		"explode": "", "allowReserved": "", "schema": "", "example": "", "examples": "{}example",
+		"content": "{}mediaType"},
+	"tag": {"name": "", "description": "", "externalDocs": "externalDocs"},
+	"securityScheme": {"type": "", "description": "", "name": "", "in": "", "scheme": "", "bearerFormat": "",
+		"flows": "oauthFlows", "openIdConnectUrl": ""},
+	"oauthFlows": {"implicit": "oauthFlow", "password": "oauthFlow", "clientCredentials": "oauthFlow",
+		"authorizationCode": "oauthFlow"},
+	"oauthFlow": {"authorizationUrl": "", "tokenUrl": "", "refreshUrl": "", "scopes": ""},
+}
+
+var responseCodePattern = regexp.MustCompile(`^[1-5](\d\d|XX)$`)
+
+// findUnknownFields returns an UnknownFieldError for every field of the document (and of every OpenAPI object in
+// it, except schemas) that is not a fixed field of its object, and is not an extension. Objects that are references
+// are not checked.
+func findUnknownFields(root *yaml.Node) []error {
+	var errs []error
+	var check func(node *yaml.Node, object, path string)
+	checkValue := func(node *yaml.Node, object, path string) {
+		switch {
+		case object == "" || node == nil:
+		case strings.HasPrefix(object, "[]"):
+			if node.Kind == yaml.SequenceNode {
+				for i, item := range node.Content {
+					check(item, object[2:], fmt.Sprintf("%s/%d", path, i))
+				}
+			}
+		case strings.HasPrefix(object, "{}"):
+			if node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					check(node.Content[i+1], object[2:], path+"/"+escapePointer(node.Content[i].Value))
+				}
+			}
+		default:
+			check(node, object, path)
+		}
+	}
+	check = func(node *yaml.Node, object, path string) {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := objectFields[object]
+		if object != "pathItem" && fields != nil {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == RefLabel {
+					return
+				}
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if strings.HasPrefix(key.Value, "x-") {
+				continue
+			}
+			child, known := fields[key.Value]
+			switch object {
+			case "paths":
+				child, known = "pathItem", strings.HasPrefix(key.Value, "/")
+			case "responses":
+				child, known = "response", key.Value == DefaultLabel || responseCodePattern.MatchString(key.Value)
+			case "callback":
+				child, known = "pathItem", true
+			}
+			if !known {
+				errs = append(errs, &UnknownFieldError{
+					Field:  key.Value,
+					Object: object,
+					Path:   path,
+					Line:   key.Line,
+					Column: key.Column,
+				})
+				continue
+			}
+			checkValue(value, child, path+"/"+escapePointer(key.Value))
+		}
+	}
+	check(root, "document", "")
+	return errs
+}
+
+// escapePointer escapes a key for use as a JSON pointer segment.
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDocument_StrictUnknownFields(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+  x-logo: pets.png
+component:
+  schemas: {}
+paths:
+  x-internal: true
+  pets:
+    get: {}
+  /pets:
+    get:
+      sumary: list pets
+      responses:
+        "200":
+          description: ok
+          contents: {}
+        2XX:
+          description: ok
+        "600":
+          description: no
+    parameters:
+      - $ref: '#/components/parameters/limit'
+        unknown: ignored
+components:
+  schemas:
+    Pet:
+      anything: goes
+  parameters:
+    limit:
+      name: limit
+      in: query
+  securitySchemes:
+    oauth:
+      type: oauth2
+      flows:
+        implicit:
+          authorizationUrl: https://example.com
+          scope: {}`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	config := datamodel.NewDocumentConfiguration()
+	_, err := CreateDocumentFromConfig(info, config)
+	assert.NoError(t, err)
+
+	config.StrictUnknownFields = true
+	doc, err := CreateDocumentFromConfig(info, config)
+	require.NotNil(t, doc)
+	errs := utils.UnwrapErrors(err)
+	require.Len(t, errs, 6)
+	assert.Equal(t, "unknown field 'component' in document [6:1]", errs[0].Error())
+	assert.Equal(t, "unknown field 'pets' in paths '/paths' [10:3]", errs[1].Error())
+	assert.Equal(t, "unknown field 'sumary' in operation '/paths/~1pets/get' [14:7]", errs[2].Error())
+	assert.Equal(t, "unknown field 'contents' in response '/paths/~1pets/get/responses/200' [18:11]",
+		errs[3].Error())
+	assert.Equal(t, "unknown field '600' in responses '/paths/~1pets/get/responses' [21:9]", errs[4].Error())
+
+	var unknown *UnknownFieldError
+	require.True(t, errors.As(errs[5], &unknown))
+	assert.Equal(t, "scope", unknown.Field)
+	assert.Equal(t, "oauthFlow", unknown.Object)
+	assert.Equal(t, "/components/securitySchemes/oauth/flows/implicit", unknown.Path)
+	assert.Equal(t, 40, unknown.Line)
+	assert.Equal(t, 11, unknown.Column)
+}
+
+func TestCreateDocument_StrictUnknownFields_Burgershop(t *testing.T) {
+	data, _ := os.ReadFile("../../../test_specs/burgershop.openapi.yaml")
+	info, _ := datamodel.ExtractSpecInfo(data)
+	config := datamodel.NewDocumentConfiguration()
+	config.StrictUnknownFields = true
+	_, err := CreateDocumentFromConfig(info, config)
+	assert.NoError(t, err)
+}
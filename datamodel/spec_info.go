@@ -18,6 +18,17 @@ const (
 	YAMLFileType = "yaml"
 )
 
+// ErrDocumentTooLarge is returned when a specification exceeds the configured MaxDocumentBytes or MaxDocumentNodes.
+var ErrDocumentTooLarge = errors.New("document is too large")
+
+// ErrTooManyAliasExpansions is returned when a specification dereferences YAML aliases more than the configured
+// MaxYAMLAliasExpansions, which is the hallmark of a 'billion laughs' style alias bomb.
+var ErrTooManyAliasExpansions = errors.New("document contains too many alias expansions")
+
+// ErrDuplicateKey is returned when DetectDuplicateKeys is set, and a specification defines the same key more than
+// once in a mapping.
+var ErrDuplicateKey = errors.New("duplicate key")
+
 // SpecInfo represents a 'ready-to-process' OpenAPI Document. The RootNode is the most important property
 // used by the library, this contains the top of the document tree that every single low model is based off.
 type SpecInfo struct {
@@ -34,10 +45,11 @@ type SpecInfo struct {
 	APISchema           string                  `json:"-"`     // API Schema for supplied spec type (2 or 3)
 	Generated           time.Time               `json:"-"`
 	OriginalIndentation int                     `json:"-"` // the original whitespace
+	ParseErrors         []error                 `json:"-"` // sections dropped when parsing with BestEffort
 }
 
 func ExtractSpecInfoWithConfig(spec []byte, config *DocumentConfiguration) (*SpecInfo, error) {
-	return ExtractSpecInfoWithDocumentCheck(spec, config.BypassDocumentCheck)
+	return extractSpecInfo(spec, config)
 }
 
 // ExtractSpecInfoWithDocumentCheckSync accepts an OpenAPI/Swagger specification that has been read into a byte array
@@ -55,6 +67,119 @@ func ExtractSpecInfoWithDocumentCheckSync(spec []byte, bypass bool) (*SpecInfo,
 // and will return a SpecInfo pointer, which contains details on the version and an un-marshaled
 // ensures the document is an OpenAPI document.
 func ExtractSpecInfoWithDocumentCheck(spec []byte, bypass bool) (*SpecInfo, error) {
+	return extractSpecInfo(spec, &DocumentConfiguration{BypassDocumentCheck: bypass})
+}
+
+// CheckDocumentSize returns ErrDocumentTooLarge if the size of a document exceeds maxBytes. A maxBytes of zero or
+// less means there is no limit.
+func CheckDocumentSize(size int64, maxBytes int64) error {
+	if maxBytes > 0 && size > maxBytes {
+		return fmt.Errorf("%w: %d bytes exceeds the maximum of %d bytes", ErrDocumentTooLarge, size, maxBytes)
+	}
+	return nil
+}
+
+// checkNodeCount walks the node tree and returns ErrDocumentTooLarge as soon as more than maxNodes have been seen.
+func checkNodeCount(root *yaml.Node, maxNodes int) error {
+	if maxNodes <= 0 {
+		return nil
+	}
+	count := 0
+	stack := []*yaml.Node{root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		count++
+		if count > maxNodes {
+			return fmt.Errorf("%w: document contains more than the maximum of %d nodes", ErrDocumentTooLarge, maxNodes)
+		}
+		stack = append(stack, n.Content...)
+	}
+	return nil
+}
+
+// checkAliasExpansions counts every alias dereference that would be made if the document was fully expanded,
+// and returns ErrTooManyAliasExpansions (naming the anchor being expanded) as soon as more than maxExpansions
+// have been counted. The expansion count of each anchor is memoized, so an alias bomb is detected without
+// actually expanding it.
+func checkAliasExpansions(root *yaml.Node, maxExpansions int) error {
+	if maxExpansions <= 0 {
+		return nil
+	}
+	var lastAnchor, anchor string
+	memo := make(map[*yaml.Node]int)
+	var count func(n *yaml.Node) int
+	count = func(n *yaml.Node) int {
+		if c, ok := memo[n]; ok {
+			return c
+		}
+		memo[n] = 0 // an anchor cannot contain itself, but guard against it anyway.
+		total := 0
+		for _, child := range n.Content {
+			if child.Kind == yaml.AliasNode && child.Alias != nil {
+				lastAnchor = child.Value
+				total += 1 + count(child.Alias)
+			} else {
+				total += count(child)
+			}
+			if total > maxExpansions {
+				// stop counting (and saturate) so deeply nested bombs cannot overflow.
+				if anchor == "" {
+					anchor = lastAnchor
+				}
+				total = maxExpansions + 1
+				break
+			}
+		}
+		memo[n] = total
+		return total
+	}
+	if count(root) > maxExpansions {
+		return fmt.Errorf("%w: expanding anchor '%s' exceeds the maximum of %d alias expansions",
+			ErrTooManyAliasExpansions, anchor, maxExpansions)
+	}
+	return nil
+}
+
+// checkDuplicateKeys walks the node tree and returns an ErrDuplicateKey error for every key that is defined more than
+// once in the same mapping, joined together.
+func checkDuplicateKeys(root *yaml.Node) error {
+	var errs []error
+	stack := []*yaml.Node{root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n.Kind == yaml.MappingNode {
+			keys := make(map[string]*yaml.Node)
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key := n.Content[i]
+				if key.Kind != yaml.ScalarNode || key.Value == "<<" {
+					continue // merge keys are expected to repeat.
+				}
+				if first, ok := keys[key.Value]; ok {
+					errs = append(errs, fmt.Errorf("%w: '%s' on line %d, column %d is already defined on line %d",
+						ErrDuplicateKey, key.Value, key.Line, key.Column, first.Line))
+					continue
+				}
+				keys[key.Value] = key
+			}
+		}
+		// push children in reverse, so mappings are checked in the order they appear.
+		for i := len(n.Content) - 1; i >= 0; i-- {
+			stack = append(stack, n.Content[i])
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func extractSpecInfo(spec []byte, config *DocumentConfiguration) (*SpecInfo, error) {
+	bypass := config.BypassDocumentCheck
+
+	// refuse anything too big before attempting to parse it.
+	if err := CheckDocumentSize(int64(len(spec)), config.MaxDocumentBytes); err != nil {
+		return nil, err
+	}
+
 	var parsedSpec yaml.Node
 
 	specInfo := &SpecInfo{}
@@ -75,7 +200,29 @@ func ExtractSpecInfoWithDocumentCheck(spec []byte, bypass bool) (*SpecInfo, erro
 
 	err := yaml.Unmarshal(spec, &parsedSpec)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse specification: %s", err.Error())
+		if !config.BestEffort || specInfo.SpecFileType == JSONFileType {
+			return nil, fmt.Errorf("unable to parse specification: %s", err.Error())
+		}
+		recovered, sectionErrs := parseBestEffort(spec)
+		if recovered == nil {
+			return nil, fmt.Errorf("unable to parse specification: %s", err.Error())
+		}
+		parsedSpec = *recovered
+		specInfo.ParseErrors = sectionErrs
+	}
+
+	if err = checkNodeCount(&parsedSpec, config.MaxDocumentNodes); err != nil {
+		return nil, err
+	}
+
+	if err = checkAliasExpansions(&parsedSpec, config.MaxYAMLAliasExpansions); err != nil {
+		return nil, err
+	}
+
+	if config.DetectDuplicateKeys {
+		if err = checkDuplicateKeys(&parsedSpec); err != nil {
+			return nil, err
+		}
 	}
 
 	specInfo.RootNode = &parsedSpec
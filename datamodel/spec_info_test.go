@@ -304,3 +304,84 @@ func TestExtractSpecInfoSync_Error(t *testing.T) {
 	_, e := ExtractSpecInfoWithDocumentCheckSync([]byte(random), true)
 	assert.Error(t, e)
 }
+
+func TestExtractSpecInfoWithConfig_Limits(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: pizza
+  version: 1.0.0`
+
+	_, err := ExtractSpecInfoWithConfig([]byte(yml), &DocumentConfiguration{})
+	assert.NoError(t, err)
+
+	_, err = ExtractSpecInfoWithConfig([]byte(yml), &DocumentConfiguration{MaxDocumentBytes: 10})
+	assert.ErrorIs(t, err, ErrDocumentTooLarge)
+
+	_, err = ExtractSpecInfoWithConfig([]byte(yml), &DocumentConfiguration{MaxDocumentNodes: 5})
+	assert.ErrorIs(t, err, ErrDocumentTooLarge)
+}
+
+func TestExtractSpecInfoWithConfig_AliasBomb(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: laughs
+  version: 1.0.0
+x-a: &a ["lol","lol","lol","lol","lol","lol","lol","lol","lol"]
+x-b: &b [*a,*a,*a,*a,*a,*a,*a,*a,*a]
+x-c: &c [*b,*b,*b,*b,*b,*b,*b,*b,*b]
+x-d: &d [*c,*c,*c,*c,*c,*c,*c,*c,*c]
+x-e: &e [*d,*d,*d,*d,*d,*d,*d,*d,*d]
+x-f: &f [*e,*e,*e,*e,*e,*e,*e,*e,*e]
+x-g: &g [*f,*f,*f,*f,*f,*f,*f,*f,*f]
+x-h: &h [*g,*g,*g,*g,*g,*g,*g,*g,*g]
+x-i: &i [*h,*h,*h,*h,*h,*h,*h,*h,*h]`
+
+	_, err := ExtractSpecInfoWithConfig([]byte(yml), &DocumentConfiguration{MaxYAMLAliasExpansions: 1000})
+	assert.ErrorIs(t, err, ErrTooManyAliasExpansions)
+	assert.Contains(t, err.Error(), "expanding anchor 'd'")
+}
+
+func TestExtractSpecInfoWithConfig_AliasExpansionsUnderLimit(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: pizza
+  version: 1.0.0
+x-a: &a
+  cheese: yes
+x-b: [*a, *a]`
+
+	_, err := ExtractSpecInfoWithConfig([]byte(yml), &DocumentConfiguration{MaxYAMLAliasExpansions: 2})
+	assert.NoError(t, err)
+
+	_, err = ExtractSpecInfoWithConfig([]byte(yml), &DocumentConfiguration{MaxYAMLAliasExpansions: 1})
+	assert.ErrorIs(t, err, ErrTooManyAliasExpansions)
+	assert.Contains(t, err.Error(), "expanding anchor 'a'")
+}
+
+func TestExtractSpecInfoWithConfig_DuplicateKeys(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+    get:
+      operationId: getPets
+x-base: &base
+  a: 1
+x-merged:
+  <<: *base
+  <<: *base
+info: {}`
+
+	// duplicates are quietly accepted unless they are asked for.
+	_, err := ExtractSpecInfoWithConfig([]byte(yml), &DocumentConfiguration{})
+	assert.NoError(t, err)
+
+	_, err = ExtractSpecInfoWithConfig([]byte(yml), &DocumentConfiguration{DetectDuplicateKeys: true})
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+	assert.Equal(t, "duplicate key: 'info' on line 16, column 1 is already defined on line 2\n"+
+		"duplicate key: 'get' on line 9, column 5 is already defined on line 7", err.Error())
+}
@@ -16,6 +16,10 @@ package libopenapi
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/pb33f/libopenapi/index"
 
@@ -27,6 +31,7 @@ import (
 	"github.com/pb33f/libopenapi/utils"
 	what_changed "github.com/pb33f/libopenapi/what-changed"
 	"github.com/pb33f/libopenapi/what-changed/model"
+	"github.com/vmware-labs/yaml-jsonpath/pkg/yamlpath"
 	"gopkg.in/yaml.v3"
 )
 
@@ -42,6 +47,12 @@ type Document interface {
 	// GetSpecInfo will return the *datamodel.SpecInfo instance that contains all specification information.
 	GetSpecInfo() *datamodel.SpecInfo
 
+	// Query will evaluate a JSONPath expression against the low-level root *yaml.Node of the document and return
+	// every matching node. The nodes returned are the original nodes from the parsed specification, so all line and
+	// column information is intact. The common JSONPath subset is supported, including recursive descent (..),
+	// wildcards (*) and filters ([?(...)]).
+	Query(jsonPath string) ([]*yaml.Node, error)
+
 	// SetConfiguration will set the configuration for the document. This allows for finer grained control over
 	// allowing remote or local references, as well as a BaseURL to allow for relative file references.
 	SetConfiguration(configuration *datamodel.DocumentConfiguration)
@@ -62,6 +73,52 @@ type Document interface {
 	// any other types.
 	BuildV3Model() (*DocumentModel[v3high.Document], []error)
 
+	// GetBuildErrors will return every error found in the specification: the sections that could not be parsed when
+	// the configuration has BestEffort set (as *datamodel.SectionError), followed by the errors returned when a model
+	// was built by BuildV2Model or BuildV3Model. With BestEffort set, every error has a location in the
+	// specification, either as a *datamodel.SectionError, or as an error that carries its own node or line.
+	GetBuildErrors() []error
+
+	// SyncFromLow will rebuild the low-level and high-level models from the current state of the root *yaml.Node
+	// (see GetSpecInfo), without re-reading the original specification bytes. Use this after editing the low-level
+	// nodes directly, so the edits are reflected by the high-level model. The model is rebuilt in place, so any
+	// *DocumentModel already returned by BuildV2Model or BuildV3Model is updated, (but any pointers held to
+	// objects inside the old model are not).
+	//
+	// Safe edits to make before calling SyncFromLow are changes to the nodes of the root document: changing scalar
+	// values, and adding or removing keys and values in mapping and sequence nodes (as long as the document remains
+	// a valid specification). New nodes have no line or column information.
+	//
+	// Edits that are NOT safe, or will not be reflected, are: changes to nodes of local or remote files that were
+	// loaded via references (they are re-read by a new rolodex), changes to the 'openapi' or 'swagger' version (the
+	// SpecInfo is not re-extracted), and replacing the root node itself. The original specification bytes are not
+	// updated either, use Render or RenderAndReload to produce new bytes.
+	//
+	// If no model has been built yet, there is nothing to sync and an error is returned. Any errors from rebuilding
+	// the model are returned joined together, circular reference errors do not prevent the model from being rebuilt.
+	SyncFromLow() error
+
+	// RewriteReferences will apply fn to the value of every '$ref' found in the root *yaml.Node of the document
+	// (see GetSpecInfo), replacing the value with the one returned, and then rebuild the model (see SyncFromLow). If
+	// no model has been built yet, one is built for the version of the specification. This is useful when
+	// relocating files, for example changing './v1/' to './v2/' in every reference.
+	//
+	// The first return is a warning for every rewritten reference that can't be resolved by the rebuilt model, the
+	// second is any error from rebuilding the model (which will also report references that can't be resolved, as
+	// any build does). References in local or remote files that were loaded via
+	// references are not rewritten, the same as SyncFromLow.
+	RewriteReferences(fn func(ref string) string) ([]error, error)
+
+	// CanonicalizeReferences will rewrite every '$ref' of the document (see RewriteReferences) into a canonical form,
+	// so references to the same target are textually identical. For example './types.yaml#/Foo', 'types.yaml#/Foo'
+	// and 'schemas/../types.yaml#/Foo' all become 'types.yaml#/Foo'.
+	//
+	// File paths are cleaned, and made relative to the BasePath of the configuration when they are inside it. URLs
+	// have a lower case scheme and host and a cleaned path, and are made relative when they are inside the BaseURL
+	// of the configuration. An empty fragment is removed ('types.yaml#' becomes 'types.yaml'), local references are
+	// not changed. The returns are the same as RewriteReferences.
+	CanonicalizeReferences() ([]error, error)
+
 	// RenderAndReload will render the high level model as it currently exists (including any mutations, additions
 	// and removals to and from any object in the tree). It will then reload the low level model with the new bytes
 	// extracted from the model that was re-rendered. This is useful if you want to make changes to the high level model
@@ -93,6 +150,21 @@ type Document interface {
 	// **IMPORTANT** This method only supports OpenAPI Documents.
 	Render() ([]byte, error)
 
+	// RenderWithOptions will render the high level model in the same way as Render, using opts to control the output.
+	// When opts.Indent is zero the original indentation of the specification is used. JSON specifications are
+	// indented with opts.JSONIndent spaces, or opts.Indent when it's zero, and escape HTML if opts.JSONEscapeHTML is
+	// set. Numbers in JSON are rendered as they are written in the specification. To render compact JSON, use the
+	// RenderJSONWithOptions method of the high level model.
+	RenderWithOptions(opts v3high.RenderOptions) ([]byte, error)
+
+	// RoundTripDiff will render the high level model (see Render) and compare it line by line with the original
+	// specification bytes, returning every range of lines that differs. An unmodified document should render exactly
+	// as it was read, so this is a guard against the rendering accidentally re-ordering or re-formatting parts of the
+	// document that were not changed. If no model has been built yet, an OpenAPI 3 model is built.
+	//
+	// Line endings and a final line break are ignored. **IMPORTANT** This method only supports OpenAPI Documents.
+	RoundTripDiff() ([]LineDiff, error)
+
 	// Serialize will re-render a Document back into a []byte slice. If any modifications have been made to the
 	// underlying data model using low level APIs, then those changes will be reflected in the serialized output.
 	//
@@ -111,6 +183,7 @@ type document struct {
 	config            *datamodel.DocumentConfiguration
 	highOpenAPI3Model *DocumentModel[v3high.Document]
 	highSwaggerModel  *DocumentModel[v2high.Swagger]
+	buildErrors       []error
 }
 
 // DocumentModel represents either a Swagger document (version 2) or an OpenAPI document (version 3) that is
@@ -150,18 +223,60 @@ func NewDocumentWithTypeCheck(specByteArray []byte, bypassCheck bool) (Document,
 // NewDocumentWithConfiguration is the same as NewDocument, except it's a convenience function that calls NewDocument
 // under the hood and then calls SetConfiguration() on the returned Document.
 func NewDocumentWithConfiguration(specByteArray []byte, configuration *datamodel.DocumentConfiguration) (Document, error) {
-	var d Document
-	var err error
-	if configuration != nil && configuration.BypassDocumentCheck {
-		d, err = NewDocumentWithTypeCheck(specByteArray, true)
-	} else {
-		d, err = NewDocument(specByteArray)
+	if configuration == nil {
+		return NewDocument(specByteArray)
 	}
 
-	if d != nil {
-		d.SetConfiguration(configuration)
+	// the configuration carries the document check bypass and any size limits.
+	info, err := datamodel.ExtractSpecInfoWithConfig(specByteArray, configuration)
+	if err != nil {
+		return nil, err
 	}
-	return d, err
+	d := new(document)
+	d.version = info.Version
+	d.info = info
+	d.SetConfiguration(configuration)
+	return d, nil
+}
+
+// NewDocumentFromURL fetches the specification found at specURL, and creates a new Document from it using the
+// configuration (which can be nil). The SpecURL of the configuration is set to the location of the specification, so
+// relative references are resolved against the directory it was fetched from, and remote references are allowed.
+//
+// The specification is fetched using the RemoteURLHandler or HTTPClient of the configuration, and the remote host
+// restrictions and size limits of the configuration are respected.
+func NewDocumentFromURL(specURL string, configuration *datamodel.DocumentConfiguration) (Document, error) {
+	u, err := url.Parse(specURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse specification URL '%s': %w", specURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unable to fetch specification '%s', the URL scheme must be http or https", specURL)
+	}
+
+	config := datamodel.NewDocumentConfiguration()
+	if configuration != nil {
+		copied := *configuration
+		config = &copied
+	}
+	config.SpecURL = u
+
+	remoteFS, _ := index.NewRemoteFSWithConfig(&index.SpecIndexConfig{
+		HTTPClient:         config.HTTPClient,
+		Context:            config.Context,
+		AllowedRemoteHosts: config.AllowedRemoteHosts,
+		DeniedRemoteHosts:  config.DeniedRemoteHosts,
+		MaxDocumentBytes:   config.MaxDocumentBytes,
+		Logger:             config.Logger,
+	})
+	if config.RemoteURLHandler != nil {
+		remoteFS.RemoteHandlerFunc = config.RemoteURLHandler
+	}
+	spec, err := remoteFS.Fetch(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch specification '%s': %w", specURL, err)
+	}
+	return NewDocumentWithConfiguration(spec, config)
 }
 
 func (d *document) GetRolodex() *index.Rolodex {
@@ -176,6 +291,17 @@ func (d *document) GetSpecInfo() *datamodel.SpecInfo {
 	return d.info
 }
 
+func (d *document) Query(jsonPath string) ([]*yaml.Node, error) {
+	if d.info == nil || d.info.RootNode == nil {
+		return nil, errors.New("unable to query document, no specification has been loaded")
+	}
+	path, err := yamlpath.NewPath(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query document, invalid JSONPath '%s': %w", jsonPath, err)
+	}
+	return path.Find(d.info.RootNode)
+}
+
 func (d *document) GetConfiguration() *datamodel.DocumentConfiguration {
 	return d.config
 }
@@ -239,6 +365,31 @@ func (d *document) Render() ([]byte, error) {
 	return newBytes, jsonErr
 }
 
+func (d *document) RenderWithOptions(opts v3high.RenderOptions) ([]byte, error) {
+	if d.highSwaggerModel != nil && d.highOpenAPI3Model == nil {
+		return nil, errors.New("this method only supports OpenAPI 3 documents, not Swagger")
+	}
+	if opts.Indent < 0 || opts.JSONIndent < 0 {
+		return nil, errors.New("unable to render document, indentation cannot be negative")
+	}
+
+	indent := opts.Indent
+	if indent == 0 {
+		indent = d.info.OriginalIndentation
+	}
+	if d.info.SpecFileType == datamodel.JSONFileType {
+		if opts.JSONIndent == 0 {
+			opts.JSONIndent = max(indent, 2)
+		}
+		return d.highOpenAPI3Model.Model.RenderJSONWithOptions(opts)
+	}
+	if opts.Indent == 0 && !opts.SortComponents {
+		return d.highOpenAPI3Model.Model.RenderWithIndention(indent), nil
+	}
+	opts.Indent = indent
+	return d.highOpenAPI3Model.Model.RenderWithOptions(opts)
+}
+
 func (d *document) BuildV2Model() (*DocumentModel[v2high.Swagger], []error) {
 	if d.highSwaggerModel != nil {
 		return d.highSwaggerModel, nil
@@ -267,11 +418,13 @@ func (d *document) BuildV2Model() (*DocumentModel[v2high.Swagger], []error) {
 		errs = append(errs, utils.UnwrapErrors(docErr)...)
 	}
 
+	d.buildErrors = errs
+
 	// Do not short-circuit on circular reference errors, so the client
 	// has the option of ignoring them.
 	for _, err := range errs {
 		var refErr *index.ResolvingError
-		if errors.As(err, &refErr) {
+		if errors.As(err, &refErr) && !d.config.BestEffort {
 			if refErr.CircularReference == nil {
 				return nil, errs
 			}
@@ -317,11 +470,13 @@ func (d *document) BuildV3Model() (*DocumentModel[v3high.Document], []error) {
 		errs = append(errs, utils.UnwrapErrors(docErr)...)
 	}
 
+	d.buildErrors = errs
+
 	// Do not short-circuit on circular reference errors, so the client
 	// has the option of ignoring them.
 	for _, err := range utils.UnwrapErrors(docErr) {
 		var refErr *index.ResolvingError
-		if errors.As(err, &refErr) {
+		if errors.As(err, &refErr) && !d.config.BestEffort {
 			if refErr.CircularReference == nil {
 				return nil, errs
 			}
@@ -338,6 +493,169 @@ func (d *document) BuildV3Model() (*DocumentModel[v3high.Document], []error) {
 	return d.highOpenAPI3Model, errs
 }
 
+func (d *document) GetBuildErrors() []error {
+	var errs []error
+	if d.info != nil {
+		errs = append(errs, d.info.ParseErrors...)
+	}
+	return append(errs, d.buildErrors...)
+}
+
+func (d *document) SyncFromLow() error {
+	if d.info == nil || d.info.RootNode == nil {
+		return errors.New("unable to sync document, no specification has been loaded")
+	}
+	switch {
+	case d.highOpenAPI3Model != nil:
+		existing := d.highOpenAPI3Model
+		d.highOpenAPI3Model = nil
+		rebuilt, errs := d.BuildV3Model()
+		if rebuilt == nil {
+			d.highOpenAPI3Model = existing
+			return errors.Join(errs...)
+		}
+		*existing = *rebuilt
+		d.highOpenAPI3Model = existing
+		return errors.Join(errs...)
+	case d.highSwaggerModel != nil:
+		existing := d.highSwaggerModel
+		d.highSwaggerModel = nil
+		rebuilt, errs := d.BuildV2Model()
+		if rebuilt == nil {
+			d.highSwaggerModel = existing
+			return errors.Join(errs...)
+		}
+		*existing = *rebuilt
+		d.highSwaggerModel = existing
+		return errors.Join(errs...)
+	}
+	return errors.New("unable to sync document, no model has been built yet")
+}
+
+func (d *document) RewriteReferences(fn func(ref string) string) ([]error, error) {
+	if d.info == nil || d.info.RootNode == nil {
+		return nil, errors.New("unable to rewrite references, no specification has been loaded")
+	}
+
+	// rewritten references, mapped to the reference they were rewritten from.
+	rewritten := make(map[string]string)
+	seen := make(map[*yaml.Node]bool)
+	var rewrite func(node *yaml.Node)
+	rewrite = func(node *yaml.Node) {
+		if node == nil || seen[node] {
+			return
+		}
+		seen[node] = true
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				if key.Value != "$ref" || value.Kind != yaml.ScalarNode {
+					continue
+				}
+				if ref := fn(value.Value); ref != value.Value {
+					if _, ok := rewritten[ref]; !ok {
+						rewritten[ref] = value.Value
+					}
+					value.Value = ref
+				}
+			}
+		}
+		for _, child := range node.Content {
+			rewrite(child)
+		}
+		rewrite(node.Alias)
+	}
+	rewrite(d.info.RootNode)
+
+	// rebuild the model, or build one if there isn't one yet.
+	var err error
+	var idx *index.SpecIndex
+	switch {
+	case d.highOpenAPI3Model != nil || d.highSwaggerModel != nil:
+		err = d.SyncFromLow()
+	case d.info.SpecFormat == datamodel.OAS2:
+		_, errs := d.BuildV2Model()
+		err = errors.Join(errs...)
+	default:
+		_, errs := d.BuildV3Model()
+		err = errors.Join(errs...)
+	}
+	switch {
+	case d.highOpenAPI3Model != nil:
+		idx = d.highOpenAPI3Model.Index
+	case d.highSwaggerModel != nil:
+		idx = d.highSwaggerModel.Index
+	}
+	if idx == nil || len(rewritten) == 0 {
+		return nil, err
+	}
+
+	var warnings []error
+	for _, loc := range idx.GetAllReferencesWithLocations() {
+		if original, ok := rewritten[loc.Reference]; ok && !loc.Resolved {
+			warnings = append(warnings, fmt.Errorf("rewritten reference '%s' (was '%s') can't be resolved, line %d, col %d",
+				loc.Reference, original, loc.Line, loc.Column))
+		}
+	}
+	return warnings, err
+}
+
+func (d *document) CanonicalizeReferences() ([]error, error) {
+	var basePath string
+	var baseURL *url.URL
+	if d.config != nil {
+		basePath = d.config.BasePath
+		baseURL = d.config.BaseURL
+	}
+	if basePath != "" {
+		basePath, _ = filepath.Abs(basePath)
+	}
+	return d.RewriteReferences(func(ref string) string {
+		return canonicalReference(ref, basePath, baseURL)
+	})
+}
+
+// canonicalReference returns the canonical form of ref, see CanonicalizeReferences. basePath must be absolute.
+func canonicalReference(ref, basePath string, baseURL *url.URL) string {
+	file, fragment, hasFragment := strings.Cut(ref, "#")
+	if file == "" {
+		return ref
+	}
+
+	if lower := strings.ToLower(file); strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		u, err := url.Parse(file)
+		if err != nil {
+			return ref
+		}
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+		if u.Path != "" {
+			u.Path = path.Clean(u.Path)
+		}
+		u.RawPath = ""
+		file = u.String()
+		if baseURL != nil && strings.EqualFold(baseURL.Scheme, u.Scheme) && strings.EqualFold(baseURL.Host, u.Host) {
+			dir := strings.TrimSuffix(path.Clean("/"+baseURL.Path), "/") + "/"
+			if rel, ok := strings.CutPrefix(u.Path, dir); ok && rel != "" && u.RawQuery == "" {
+				file = rel
+			}
+		}
+	} else {
+		if filepath.IsAbs(file) && basePath != "" {
+			if rel, err := filepath.Rel(basePath, file); err == nil && rel != ".." &&
+				!strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				file = rel
+			}
+		}
+		file = path.Clean(filepath.ToSlash(file))
+	}
+
+	if hasFragment && fragment != "" {
+		return file + "#" + fragment
+	}
+	return file
+}
+
 // CompareDocuments will accept a left and right Document implementing struct, build a model for the correct
 // version and then compare model documents for changes.
 //
@@ -0,0 +1,158 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package libopenapi
+
+import (
+	"errors"
+	"strings"
+)
+
+// LineDiff is a range of lines that differ between the original specification and the rendered model, see
+// Document.RoundTripDiff.
+type LineDiff struct {
+	// OriginalLine is the (1 based) line number of the first line of Original. When Original is empty (lines were
+	// only added), it's the line the Rendered lines are inserted before.
+	OriginalLine int
+
+	// Original are the lines of the original specification that were removed or changed.
+	Original []string
+
+	// RenderedLine is the (1 based) line number of the first line of Rendered. When Rendered is empty (lines were
+	// only removed), it's the line the Original lines were removed before.
+	RenderedLine int
+
+	// Rendered are the lines of the rendered model that were added, or replace the Original lines.
+	Rendered []string
+}
+
+func (d *document) RoundTripDiff() ([]LineDiff, error) {
+	if d.info == nil || d.info.SpecBytes == nil {
+		return nil, errors.New("unable to diff document, no specification has been loaded")
+	}
+	if d.highOpenAPI3Model == nil && d.highSwaggerModel == nil {
+		if m, errs := d.BuildV3Model(); m == nil {
+			return nil, errors.Join(errs...)
+		}
+	}
+	rendered, err := d.Render()
+	if err != nil {
+		return nil, err
+	}
+	return diffLines(splitLines(string(*d.info.SpecBytes)), splitLines(string(rendered))), nil
+}
+
+// maxDiffEdits is the most lines that diffLines will search for the shortest edit script between two documents.
+const maxDiffEdits = 2000
+
+// splitLines splits s into lines, ignoring line endings and a final line break.
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// diffLines returns the ranges of lines that differ between a and b, using the shortest edit script between them
+// (Myers' algorithm). If more than maxDiffEdits lines differ, the lines between the first and last difference are
+// returned as a single difference.
+func diffLines(a, b []string) []LineDiff {
+	// lines that are the same at the start and end of both don't need to be searched.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(ma) == 0 && len(mb) == 0 {
+		return nil
+	}
+
+	// find the furthest reaching path for every number of edits, keeping the part of each step that can be used
+	// by the next one, so the path can be traced back.
+	n, m := len(ma), len(mb)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	found := false
+search:
+	for edits := 0; edits <= n+m && edits <= maxDiffEdits; edits++ {
+		trace = append(trace, append([]int(nil), v[offset-edits-1:offset+edits+2]...))
+		for k := -edits; k <= edits; k += 2 {
+			var x int
+			if k == -edits || (k != edits && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && ma[x] == mb[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break search
+			}
+		}
+	}
+	if !found {
+		// too different to search, so everything between the common prefix and suffix is one difference.
+		return []LineDiff{{OriginalLine: prefix + 1, Original: ma, RenderedLine: prefix + 1, Rendered: mb}}
+	}
+
+	// trace the path back, collecting the lines that match.
+	type match struct{ a, b int }
+	var matches []match
+	x, y := n, m
+	for edits := len(trace) - 1; edits > 0; edits-- {
+		step := trace[edits]
+		at := func(k int) int { return step[k+edits+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -edits || (k != edits && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			matches = append(matches, match{x, y})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		matches = append(matches, match{x, y})
+	}
+
+	// every gap between matching lines is a difference.
+	var diffs []LineDiff
+	lastA, lastB := 0, 0
+	addDiff := func(toA, toB int) {
+		if toA > lastA || toB > lastB {
+			diff := LineDiff{OriginalLine: prefix + lastA + 1, RenderedLine: prefix + lastB + 1}
+			if toA > lastA {
+				diff.Original = ma[lastA:toA]
+			}
+			if toB > lastB {
+				diff.Rendered = mb[lastB:toB]
+			}
+			diffs = append(diffs, diff)
+		}
+	}
+	for i := len(matches) - 1; i >= 0; i-- {
+		addDiff(matches[i].a, matches[i].b)
+		lastA, lastB = matches[i].a+1, matches[i].b+1
+	}
+	addDiff(n, m)
+	return diffs
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package libopenapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_RoundTripDiff(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: Burgers
+  version: 1.0.0
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+      summary: list all burgers
+      responses:
+        "200":
+          description: ok
+`
+	doc, err := NewDocument([]byte(yml))
+	require.NoError(t, err)
+
+	// an unmodified document renders exactly as it was read.
+	diffs, err := doc.RoundTripDiff()
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	model, _ := doc.BuildV3Model()
+	model.Model.Paths.PathItems.GetOrZero("/burgers").Get.Summary = "list every burger"
+	model.Model.Info.Description = "all the burgers"
+
+	diffs, err = doc.RoundTripDiff()
+	require.NoError(t, err)
+	assert.Equal(t, []LineDiff{
+		{OriginalLine: 5, RenderedLine: 5, Rendered: []string{"  description: all the burgers"}},
+		{
+			OriginalLine: 9, Original: []string{"      summary: list all burgers"},
+			RenderedLine: 10, Rendered: []string{"      summary: list every burger"},
+		},
+	}, diffs)
+}
+
+func TestDocument_RoundTripDiff_Swagger(t *testing.T) {
+	doc, err := NewDocument([]byte("swagger: 2.0\ninfo:\n  title: Burgers"))
+	require.NoError(t, err)
+	_, errs := doc.BuildV2Model()
+	require.Empty(t, errs)
+	_, err = doc.RoundTripDiff()
+	assert.Error(t, err)
+}
+
+func TestDiffLines(t *testing.T) {
+	assert.Empty(t, diffLines([]string{"a", "b"}, []string{"a", "b"}))
+	assert.Empty(t, diffLines(nil, nil))
+
+	assert.Equal(t, []LineDiff{
+		{OriginalLine: 2, Original: []string{"b"}, RenderedLine: 2},
+		{OriginalLine: 4, RenderedLine: 3, Rendered: []string{"x", "y"}},
+		{OriginalLine: 5, Original: []string{"e"}, RenderedLine: 6, Rendered: []string{"f"}},
+	}, diffLines([]string{"a", "b", "c", "d", "e"}, []string{"a", "c", "x", "y", "d", "f"}))
+
+	assert.Equal(t, []LineDiff{
+		{OriginalLine: 1, RenderedLine: 1, Rendered: []string{"a", "b"}},
+	}, diffLines(nil, []string{"a", "b"}))
+
+	assert.Equal(t, []string{"a", "b"}, splitLines("a\r\nb\n"))
+	assert.Nil(t, splitLines(""))
+}
@@ -4,9 +4,14 @@ package libopenapi
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -20,6 +25,7 @@ import (
 	"github.com/pb33f/libopenapi/what-changed/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestLoadDocument_Simple_V2(t *testing.T) {
@@ -438,6 +444,146 @@ func TestDocument_RenderWithLargeIndention(t *testing.T) {
 	assert.Equal(t, json, string(bytes))
 }
 
+func TestDocument_RenderWithOptions(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: pets
+tags:
+  - name: pets`
+	doc, err := NewDocument([]byte(yml))
+	require.NoError(t, err)
+	_, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+
+	// the original indentation is kept by default.
+	rendered, err := doc.RenderWithOptions(v3high.RenderOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, yml, strings.TrimSpace(string(rendered)))
+
+	rendered, err = doc.RenderWithOptions(v3high.RenderOptions{Indent: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, `openapi: 3.1.0
+info:
+    title: pets
+tags:
+    - name: pets`, strings.TrimSpace(string(rendered)))
+
+	_, err = doc.RenderWithOptions(v3high.RenderOptions{Indent: -2})
+	assert.Error(t, err)
+}
+
+func TestDocument_RenderWithOptions_SortComponents(t *testing.T) {
+	yml := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object
+    Owner:
+      type: object`
+	doc, err := NewDocument([]byte(yml))
+	require.NoError(t, err)
+	_, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+
+	rendered, err := doc.RenderWithOptions(v3high.RenderOptions{SortComponents: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `openapi: 3.1.0
+components:
+  schemas:
+    Owner:
+      type: object
+    Pet:
+      type: object`, strings.TrimSpace(string(rendered)))
+}
+
+func TestDocument_RenderWithOptions_JSON(t *testing.T) {
+	doc, err := NewDocument([]byte(`{
+  "openapi": "3.1.0",
+  "info": {
+    "title": "pets"
+  }
+}`))
+	require.NoError(t, err)
+	_, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+
+	rendered, err := doc.RenderWithOptions(v3high.RenderOptions{Indent: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+    "openapi": "3.1.0",
+    "info": {
+        "title": "pets"
+    }
+}`, string(rendered))
+}
+
+func TestDocument_RenderWithOptions_JSONEscapeHTML(t *testing.T) {
+	spec := `{
+ "openapi": "3.1.0",
+ "info": {
+  "title": "<pets> & more",
+  "version": "1.0"
+ }
+}`
+	doc, err := NewDocument([]byte(spec))
+	require.NoError(t, err)
+	_, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+
+	rendered, err := doc.RenderWithOptions(v3high.RenderOptions{JSONIndent: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, spec, string(rendered))
+
+	rendered, err = doc.RenderWithOptions(v3high.RenderOptions{JSONIndent: 1, JSONEscapeHTML: true})
+	assert.NoError(t, err)
+	assert.Contains(t, string(rendered), `"title": "\u003cpets\u003e \u0026 more"`)
+}
+
+func TestDocument_Render_KeepsComments(t *testing.T) {
+	spec := `# head of document
+openapi: 3.1.0 # version
+info:
+  # the title
+  title: pets # inline
+  version: "1.0"
+paths:
+  # pets path
+  /pets:
+    get:
+      # deprecated, remove in v2
+      operationId: listPets
+      tags: # tag list
+        - pets # a tag
+      responses:
+        "200":
+          description: ok # ok
+components:
+  schemas:
+    Pet:
+      # a pet
+      type: object
+      properties:
+        name:
+          type: string # the name
+      # footer of pet
+`
+
+	doc, err := NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+
+	rendered, err := doc.Render()
+	assert.NoError(t, err)
+	assert.Equal(t, spec, string(rendered))
+
+	// a changed value loses its comment, the comments of its key are kept.
+	m.Model.Info.Title = "dogs"
+	rendered, err = doc.Render()
+	assert.NoError(t, err)
+	assert.Contains(t, string(rendered), "  # the title\n  title: dogs\n")
+}
+
 func TestDocument_Render_ChangeCheck_Burgershop(t *testing.T) {
 	bs, _ := os.ReadFile("test_specs/burgershop.openapi.yaml")
 	doc, _ := NewDocument(bs)
@@ -1363,9 +1509,11 @@ func TestDocument_Issue264(t *testing.T) {
 
 	_, _ = d.BuildV3Model()
 
-	_, _, _, errs := d.RenderAndReload() // code panics here
-	assert.Len(t, errs, 1)
-	assert.Equal(t, "yaml: cannot decode !!float `-999.99` as a !!int", errs[0].Error())
+	_, _, m, errs := d.RenderAndReload() // code panics here
+	assert.Empty(t, errs)
+	value := m.Model.Paths.PathItems.GetOrZero("/dummy").Post.RequestBody.Content.GetOrZero("application/json").
+		Schema.Schema().Properties.GetOrZero("value").Schema()
+	assert.Equal(t, -999.99, *value.Minimum)
 }
 
 func TestDocument_Issue269(t *testing.T) {
@@ -1392,3 +1540,466 @@ components:
 	assert.Len(t, errs, 0)
 
 }
+
+func TestDocument_Query(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+    post:
+      operationId: createPet
+      deprecated: true
+  /pets/{id}:
+    get:
+      operationId: getPet`
+
+	doc, err := NewDocument([]byte(spec))
+	require.NoError(t, err)
+
+	results, err := doc.Query("$..operationId")
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, "listPets", results[0].Value)
+	assert.Equal(t, 5, results[0].Line)
+	assert.Equal(t, 20, results[0].Column)
+
+	results, err = doc.Query("$.paths.*")
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	results, err = doc.Query("$.paths.*.*[?(@.deprecated == true)]")
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, 7, results[0].Line)
+
+	results, err = doc.Query("$.paths['/pets/{id}'].get.operationId")
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "getPet", results[0].Value)
+}
+
+func TestDocument_Query_BadPath(t *testing.T) {
+	doc, err := NewDocument([]byte(`openapi: 3.1.0`))
+	require.NoError(t, err)
+
+	results, err := doc.Query("$.paths[?(@.")
+	assert.Error(t, err)
+	assert.Nil(t, results)
+}
+
+func TestDocument_Query_NoSpec(t *testing.T) {
+	d := new(document)
+	results, err := d.Query("$..operationId")
+	assert.Error(t, err)
+	assert.Nil(t, results)
+}
+
+func TestDocument_SyncFromLow(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: Pizza
+  version: 1.0.0
+paths:
+  /pizza:
+    get:
+      operationId: getPizza`
+
+	doc, err := NewDocument([]byte(yml))
+	require.NoError(t, err)
+	model, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+
+	// change a value, and add a new path to the low-level nodes.
+	model.Model.Info.GoLow().Title.ValueNode.Value = "Burgers"
+	pathsNode := model.Model.Paths.GoLow().RootNode
+	var path yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("get:\n  operationId: getBurger"), &path))
+	pathsNode.Content = append(pathsNode.Content, utils.CreateStringNode("/burger"), path.Content[0])
+
+	assert.Equal(t, "Pizza", model.Model.Info.Title)
+	assert.NoError(t, doc.SyncFromLow())
+
+	// the model already returned is updated in place.
+	assert.Equal(t, "Burgers", model.Model.Info.Title)
+	assert.Equal(t, 2, model.Model.Paths.PathItems.Len())
+	assert.Equal(t, "getBurger", model.Model.Paths.PathItems.GetOrZero("/burger").Get.OperationId)
+
+	rebuilt, errs := doc.BuildV3Model()
+	assert.Empty(t, errs)
+	assert.Same(t, model, rebuilt)
+}
+
+func TestDocument_SyncFromLow_V2(t *testing.T) {
+	yml := `swagger: 2.0
+info:
+  title: Pizza`
+
+	doc, err := NewDocument([]byte(yml))
+	require.NoError(t, err)
+	model, errs := doc.BuildV2Model()
+	require.Empty(t, errs)
+
+	model.Model.Info.GoLow().Title.ValueNode.Value = "Burgers"
+	assert.NoError(t, doc.SyncFromLow())
+	assert.Equal(t, "Burgers", model.Model.Info.Title)
+}
+
+func TestDocument_SyncFromLow_NoModel(t *testing.T) {
+	doc, err := NewDocument([]byte(`openapi: 3.1.0`))
+	require.NoError(t, err)
+	assert.EqualError(t, doc.SyncFromLow(), "unable to sync document, no model has been built yet")
+}
+
+func TestDocument_RewriteReferences(t *testing.T) {
+	yml := `openapi: 3.1.0
+info:
+  title: Pizza
+  version: 1.0.0
+paths:
+  /pizza:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/OldPizza'
+        "404":
+          $ref: '#/components/responses/OldMissing'
+components:
+  schemas:
+    Pizza:
+      type: object
+  responses:
+    Missing:
+      description: not found`
+
+	doc, err := NewDocument([]byte(yml))
+	require.NoError(t, err)
+
+	warnings, err := doc.RewriteReferences(func(ref string) string {
+		if ref == "#/components/schemas/OldPizza" {
+			return "#/components/schemas/Pizza"
+		}
+		return strings.Replace(ref, "OldMissing", "Nope", 1)
+	})
+	// the reference that can't be resolved is also a build error.
+	assert.ErrorContains(t, err, "component '#/components/responses/Nope' does not exist in the specification")
+	require.Len(t, warnings, 1)
+	assert.EqualError(t, warnings[0], "rewritten reference '#/components/responses/Nope' "+
+		"(was '#/components/responses/OldMissing') can't be resolved, line 15, col 17")
+
+	// rewriting again rebuilds the model that was built.
+	model, _ := doc.BuildV3Model()
+	require.NotNil(t, model)
+	warnings, err = doc.RewriteReferences(func(ref string) string {
+		return strings.Replace(ref, "Nope", "Missing", 1)
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	op := model.Model.Paths.PathItems.GetOrZero("/pizza").Get
+	schema := op.Responses.Codes.GetOrZero("200").Content.GetOrZero("application/json").Schema
+	assert.Equal(t, "#/components/schemas/Pizza", schema.GetReference())
+	assert.Equal(t, []string{"object"}, schema.Schema().Type)
+	assert.Equal(t, "not found", op.Responses.Codes.GetOrZero("404").Description)
+}
+
+func TestDocument_RewriteReferences_NoSpec(t *testing.T) {
+	doc := &document{}
+	_, err := doc.RewriteReferences(func(ref string) string { return ref })
+	assert.EqualError(t, err, "unable to rewrite references, no specification has been loaded")
+}
+
+func TestDocument_CanonicalizeReferences(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.yaml"), []byte(`Pizza:
+  type: object`), 0o644))
+
+	yml := `openapi: 3.1.0
+info:
+  title: Pizza
+  version: 1.0.0
+paths:
+  /a:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: './types.yaml#/Pizza'
+  /b:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: 'schemas/../types.yaml#/Pizza'
+  /c:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '` + filepath.ToSlash(filepath.Join(dir, "types.yaml")) + `#/Pizza'`
+
+	config := datamodel.NewDocumentConfiguration()
+	config.BasePath = dir
+	doc, err := NewDocumentWithConfiguration([]byte(yml), config)
+	require.NoError(t, err)
+
+	warnings, err := doc.CanonicalizeReferences()
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	model, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+	for _, p := range []string{"/a", "/b", "/c"} {
+		schema := model.Model.Paths.PathItems.GetOrZero(p).Get.Responses.Codes.GetOrZero("200").
+			Content.GetOrZero("application/json").Schema
+		assert.Equal(t, "types.yaml#/Pizza", schema.GetReference(), p)
+		assert.Equal(t, []string{"object"}, schema.Schema().Type, p)
+	}
+}
+
+func TestCanonicalReference(t *testing.T) {
+	base, _ := url.Parse("https://pb33f.io/specs/")
+	tests := []struct{ ref, expected string }{
+		{"#/components/schemas/Pizza", "#/components/schemas/Pizza"},
+		{"./types.yaml#/Pizza", "types.yaml#/Pizza"},
+		{"types.yaml#", "types.yaml"},
+		{"./a/../b/./types.yaml", "b/types.yaml"},
+		{"../types.yaml#/Pizza", "../types.yaml#/Pizza"},
+		{"/specs/api/types.yaml#/Pizza", "api/types.yaml#/Pizza"},
+		{"/other/types.yaml#/Pizza", "/other/types.yaml#/Pizza"},
+		{"HTTPS://PB33F.io/specs/./types.yaml#/Pizza", "types.yaml#/Pizza"},
+		{"https://pb33f.io/other/types.yaml#/Pizza", "https://pb33f.io/other/types.yaml#/Pizza"},
+		{"https://example.com/a/../types.yaml", "https://example.com/types.yaml"},
+		{"https://EXAMPLE.com#/Pizza", "https://example.com#/Pizza"},
+		{"https://example.com/a/./my%20types.yaml", "https://example.com/a/my%20types.yaml"},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, canonicalReference(tc.ref, filepath.FromSlash("/specs"), base), tc.ref)
+	}
+}
+
+func TestNewDocumentFromURL(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`components:
+  schemas:
+    Error:
+      type: object`))
+	}))
+	defer other.Close()
+
+	files := map[string]string{
+		"/specs/api/openapi.yaml": `openapi: 3.1.0
+info:
+  title: Pets
+paths: {}
+components:
+  schemas:
+    Pet:
+      $ref: '../shared/pet.yaml#/components/schemas/Pet'
+    Error:
+      $ref: '` + other.URL + `/error.yaml#/components/schemas/Error'`,
+		"/specs/shared/pet.yaml": `components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f, ok := files[r.URL.Path]; ok {
+			_, _ = w.Write([]byte(f))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	doc, err := NewDocumentFromURL(server.URL+"/specs/api/openapi.yaml", nil)
+	require.NoError(t, err)
+
+	m, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+	assert.Equal(t, server.URL+"/specs/api/openapi.yaml", m.Index.GetSpecAbsolutePath())
+	assert.Equal(t, server.URL+"/specs/api/openapi.yaml", doc.GetConfiguration().SpecURL.String())
+
+	pet := m.Model.Components.Schemas.GetOrZero("Pet").Schema()
+	require.NotNil(t, pet)
+	assert.Equal(t, []string{"object"}, pet.Type)
+	assert.NotNil(t, pet.Properties.GetOrZero("name"))
+
+	e := m.Model.Components.Schemas.GetOrZero("Error").Schema()
+	require.NotNil(t, e)
+	assert.Equal(t, []string{"object"}, e.Type)
+}
+
+func TestNewDocumentFromURL_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewDocumentFromURL(server.URL+"/openapi.yaml", nil)
+	assert.ErrorContains(t, err, "unable to fetch specification")
+
+	_, err = NewDocumentFromURL("file:///openapi.yaml", nil)
+	assert.ErrorContains(t, err, "the URL scheme must be http or https")
+
+	_, err = NewDocumentFromURL(server.URL+"/openapi.yaml", &datamodel.DocumentConfiguration{
+		AllowedRemoteHosts: []string{"example.com"},
+	})
+	assert.ErrorContains(t, err, "is not in the allowed remote hosts")
+}
+
+func TestDocument_BestEffort(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Missing'
+  /owners:
+    get:
+      summary: list owners: all of them
+components:
+  schemas:
+    Pet:
+      type: object`
+
+	_, err := NewDocumentWithConfiguration([]byte(spec), datamodel.NewDocumentConfiguration())
+	assert.Error(t, err)
+
+	config := datamodel.NewDocumentConfiguration()
+	config.BestEffort = true
+	doc, err := NewDocumentWithConfiguration([]byte(spec), config)
+	require.NoError(t, err)
+	require.Len(t, doc.GetBuildErrors(), 1)
+
+	m, errs := doc.BuildV3Model()
+	require.NotNil(t, m)
+	assert.NotEmpty(t, errs)
+	assert.Equal(t, "pets", m.Model.Info.Title)
+	assert.Equal(t, 1, m.Model.Paths.PathItems.Len())
+	assert.NotNil(t, m.Model.Components.Schemas.GetOrZero("Pet"))
+
+	buildErrs := doc.GetBuildErrors()
+	require.Len(t, buildErrs, len(errs)+1)
+	for _, e := range buildErrs {
+		var sErr *datamodel.SectionError
+		require.True(t, errors.As(e, &sErr), e.Error())
+		assert.NotZero(t, sErr.Line)
+	}
+	assert.Contains(t, buildErrs[0].Error(), "section '/paths/~1owners/get/summary' [17:7]")
+}
+
+func TestDocument_BestEffort_Swagger(t *testing.T) {
+	spec := `swagger: "2.0"
+info:
+  title: pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: pets
+          schema:
+            $ref: '#/definitions/Missing'
+definitions:
+  Pet:
+    type: object`
+
+	config := datamodel.NewDocumentConfiguration()
+	config.BestEffort = true
+	doc, err := NewDocumentWithConfiguration([]byte(spec), config)
+	require.NoError(t, err)
+
+	m, errs := doc.BuildV2Model()
+	require.NotNil(t, m)
+	require.NotEmpty(t, errs)
+	assert.Equal(t, "pets", m.Model.Info.Title)
+	assert.NotNil(t, m.Model.Definitions.Definitions.GetOrZero("Pet"))
+	var paths *datamodel.SectionError
+	for _, e := range errs {
+		var sErr *datamodel.SectionError
+		require.True(t, errors.As(e, &sErr), e.Error())
+		assert.NotZero(t, sErr.Line)
+		if sErr.Path == "/paths" {
+			paths = sErr
+		}
+	}
+	require.NotNil(t, paths)
+	assert.Equal(t, 5, paths.Line)
+}
+
+func TestDocument_MergeRefSiblings(t *testing.T) {
+	spec := `openapi: %s
+info:
+  title: pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: a pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+                description: the pet that was found
+                readOnly: true
+components:
+  schemas:
+    Pet:
+      type: object
+      description: a pet`
+
+	schemaOf := func(version string, merge bool) (*base.Schema, *base.Schema) {
+		doc, err := NewDocumentWithConfiguration([]byte(fmt.Sprintf(spec, version)),
+			&datamodel.DocumentConfiguration{MergeRefSiblings: merge})
+		require.NoError(t, err)
+		model, errs := doc.BuildV3Model()
+		require.Empty(t, errs)
+		response := model.Model.Paths.PathItems.GetOrZero("/pets").Get.Responses.Codes.GetOrZero("200")
+		return response.Content.GetOrZero("application/json").Schema.Schema(),
+			model.Model.Components.Schemas.GetOrZero("Pet").Schema()
+	}
+
+	// 3.1 merges the siblings into the referenced schema, which is not changed.
+	s, pet := schemaOf("3.1.0", true)
+	assert.Equal(t, "the pet that was found", s.Description)
+	assert.True(t, *s.ReadOnly)
+	assert.Equal(t, []string{"object"}, s.Type)
+	assert.Equal(t, "a pet", pet.Description)
+	assert.Nil(t, pet.ReadOnly)
+
+	// 3.0 does not allow siblings, so they are discarded.
+	s, _ = schemaOf("3.0.3", true)
+	assert.Equal(t, "a pet", s.Description)
+	assert.Nil(t, s.ReadOnly)
+
+	// and they are discarded unless asked for.
+	s, _ = schemaOf("3.1.0", false)
+	assert.Equal(t, "a pet", s.Description)
+}
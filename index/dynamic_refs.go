@@ -0,0 +1,160 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// captureAnchor records a '$anchor', '$dynamicAnchor' or '$dynamicRef' found in a schema node. The key is the
+// position of the keyword in the node's content, the path is the location of the schema in the document.
+func (index *SpecIndex) captureAnchor(node *yaml.Node, key int, seenPath []string) {
+	if utils.IsNodeArray(node) || key+1 >= len(node.Content) || !utils.IsNodeStringValue(node.Content[key+1]) {
+		return
+	}
+	if slices.Contains(seenPath, "example") || slices.Contains(seenPath, "examples") {
+		return
+	}
+	definitionPath := fmt.Sprintf("#/%s", strings.Join(seenPath, "/"))
+	_, jsonPath := utils.ConvertComponentIdIntoFriendlyPathSearch(definitionPath)
+	value := node.Content[key+1].Value
+
+	ref := &Reference{
+		FullDefinition: fmt.Sprintf("%s%s", index.specAbsolutePath, definitionPath),
+		Definition:     definitionPath,
+		Name:           value,
+		Node:           node,
+		KeyNode:        node.Content[key],
+		Path:           jsonPath,
+		Index:          index,
+	}
+
+	switch node.Content[key].Value {
+	case "$anchor":
+		if index.allAnchors[value] == nil {
+			index.allAnchors[value] = ref
+		}
+	case "$dynamicAnchor":
+		index.allDynamicAnchors[value] = append(index.allDynamicAnchors[value], ref)
+	case "$dynamicRef":
+		ref.Definition = value
+		index.allDynamicRefs = append(index.allDynamicRefs, ref)
+	}
+}
+
+// ExtractDynamicRefs will map every '$dynamicRef' found in the document to the schema it resolves to.
+//
+// Full dynamic scope resolution requires evaluating a schema against an instance, which the index cannot do.
+// Instead, the common recursive schema case is supported:
+//
+//   - A fragment that is a JSON pointer (e.g. '#/$defs/node') is located like any other reference.
+//   - A fragment that names a single '$dynamicAnchor' resolves to the schema that declares it.
+//   - A fragment that names multiple '$dynamicAnchor' declarations resolves to the first one found in
+//     the document (the outermost), and a warning is logged and kept, as the dynamic scope has not been evaluated.
+//   - A fragment that names no '$dynamicAnchor', but does name an '$anchor', is treated like a '$ref' to it.
+//
+// Dynamic references that cannot be resolved are recorded as reference errors. Dynamic references to anchors in
+// other documents are skipped with a warning, and every warning is available from GetDynamicRefWarnings.
+//
+// The resolver, and the schema proxies of the models, follow a mapped dynamic reference like a '$ref' to the schema
+// it resolves to.
+func (index *SpecIndex) ExtractDynamicRefs() {
+	for _, ref := range index.allDynamicRefs {
+		value := ref.Definition
+		if index.mappedDynamicRefs[value] != nil {
+			continue
+		}
+		location, fragment, _ := strings.Cut(value, "#")
+
+		var located *Reference
+		switch {
+		case strings.HasPrefix(fragment, "/"):
+			located = index.FindComponent(value)
+		case location != "" && location != index.specAbsolutePath:
+			index.logger.Warn("dynamic references to anchors in other documents are not supported",
+				"dynamicRef", value, "line", ref.KeyNode.Line, "column", ref.KeyNode.Column)
+			index.dynamicRefWarning(ref, fmt.Errorf("dynamic reference '%s' is to an anchor in another document, "+
+				"which is not supported", value))
+			continue
+		case len(index.allDynamicAnchors[fragment]) > 0:
+			anchors := index.allDynamicAnchors[fragment]
+			if len(anchors) > 1 {
+				index.logger.Warn("dynamic anchor is declared more than once, dynamic scope is not evaluated, "+
+					"resolving to the first declaration", "dynamicRef", value, "anchor", anchors[0].Definition,
+					"line", ref.KeyNode.Line, "column", ref.KeyNode.Column)
+				index.dynamicRefWarning(ref, fmt.Errorf("dynamic reference '%s' resolves to the first of %d "+
+					"declarations of dynamic anchor '%s', the dynamic scope is not evaluated", value, len(anchors), fragment))
+			}
+			located = anchors[0]
+		default:
+			located = index.allAnchors[fragment]
+		}
+
+		if located == nil {
+			index.errorLock.Lock()
+			index.refErrors = append(index.refErrors, &IndexingError{
+				Err:     fmt.Errorf("dynamic reference '%s' cannot be resolved", value),
+				Node:    ref.Node,
+				KeyNode: ref.KeyNode,
+				Path:    ref.Path,
+			})
+			index.errorLock.Unlock()
+			continue
+		}
+		index.mappedDynamicRefs[value] = located
+	}
+}
+
+// dynamicRefWarning keeps a warning about the dynamic reference ref.
+func (index *SpecIndex) dynamicRefWarning(ref *Reference, err error) {
+	index.errorLock.Lock()
+	index.dynamicRefWarnings = append(index.dynamicRefWarnings, &IndexingError{
+		Err:     err,
+		Node:    ref.Node,
+		KeyNode: ref.KeyNode,
+		Path:    ref.Path,
+	})
+	index.errorLock.Unlock()
+}
+
+// GetDynamicRefWarnings returns the warnings found when resolving '$dynamicRef' values: references that resolve to
+// the first of several '$dynamicAnchor' declarations, and references to anchors in other documents. They are not
+// errors, so they are not part of GetReferenceIndexErrors.
+func (index *SpecIndex) GetDynamicRefWarnings() []*IndexingError {
+	index.errorLock.RLock()
+	defer index.errorLock.RUnlock()
+	return index.dynamicRefWarnings
+}
+
+// GetAllAnchors returns every '$anchor' found in the document, keyed by the anchor name.
+func (index *SpecIndex) GetAllAnchors() map[string]*Reference {
+	return index.allAnchors
+}
+
+// GetAllDynamicAnchors returns every '$dynamicAnchor' found in the document, keyed by the anchor name. An anchor
+// may be declared by more than one schema.
+func (index *SpecIndex) GetAllDynamicAnchors() map[string][]*Reference {
+	return index.allDynamicAnchors
+}
+
+// GetAllDynamicRefs returns every '$dynamicRef' found in the document. The Definition of each reference is
+// the '$dynamicRef' value, and the Node is the schema containing it.
+func (index *SpecIndex) GetAllDynamicRefs() []*Reference {
+	return index.allDynamicRefs
+}
+
+// GetMappedDynamicRefs returns every '$dynamicRef' value that was resolved, mapped to the schema it resolves to.
+func (index *SpecIndex) GetMappedDynamicRefs() map[string]*Reference {
+	return index.mappedDynamicRefs
+}
+
+// FindDynamicRef returns the schema a '$dynamicRef' value resolves to, or nil if it could not be resolved.
+func (index *SpecIndex) FindDynamicRef(dynamicRef string) *Reference {
+	return index.mappedDynamicRefs[dynamicRef]
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSpecIndex_ExtractDynamicRefs(t *testing.T) {
+	yml := `openapi: 3.1.0
+components:
+  schemas:
+    Tree:
+      $dynamicAnchor: node
+      type: object
+      properties:
+        data: true
+        children:
+          type: array
+          items:
+            $dynamicRef: '#node'
+    StrictTree:
+      $anchor: strict
+      type: object
+      properties:
+        parent:
+          $dynamicRef: '#strict'
+        sibling:
+          $dynamicRef: '#/components/schemas/Tree'`
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &rootNode)
+	idx := NewSpecIndexWithConfig(&rootNode, CreateOpenAPIIndexConfig())
+
+	assert.Len(t, idx.GetAllDynamicRefs(), 3)
+	assert.Len(t, idx.GetAllDynamicAnchors()["node"], 1)
+	assert.NotNil(t, idx.GetAllAnchors()["strict"])
+	assert.Len(t, idx.GetMappedDynamicRefs(), 3)
+	assert.Empty(t, idx.GetReferenceIndexErrors())
+
+	node := idx.FindDynamicRef("#node")
+	require.NotNil(t, node)
+	assert.Equal(t, "#/components/schemas/Tree", node.Definition)
+	assert.Equal(t, "$.components.schemas['Tree']", node.Path)
+
+	strict := idx.FindDynamicRef("#strict")
+	require.NotNil(t, strict)
+	assert.Equal(t, "#/components/schemas/StrictTree", strict.Definition)
+
+	pointer := idx.FindDynamicRef("#/components/schemas/Tree")
+	require.NotNil(t, pointer)
+	assert.Equal(t, node.Node, pointer.Node)
+
+	assert.Nil(t, idx.FindDynamicRef("#nope"))
+}
+
+func TestSpecIndex_ExtractDynamicRefs_MultipleAnchors(t *testing.T) {
+	yml := `openapi: 3.1.0
+components:
+  schemas:
+    Tree:
+      $dynamicAnchor: node
+      properties:
+        children:
+          items:
+            $dynamicRef: '#node'
+    ExtendedTree:
+      $dynamicAnchor: node
+      allOf:
+        - $ref: '#/components/schemas/Tree'`
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &rootNode)
+	var logs bytes.Buffer
+	c := CreateOpenAPIIndexConfig()
+	c.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	idx := NewSpecIndexWithConfig(&rootNode, c)
+
+	assert.Len(t, idx.GetAllDynamicAnchors()["node"], 2)
+	node := idx.FindDynamicRef("#node")
+	require.NotNil(t, node)
+	assert.Equal(t, "#/components/schemas/Tree", node.Definition)
+	assert.Contains(t, logs.String(), "dynamic anchor is declared more than once")
+	require.Len(t, idx.GetDynamicRefWarnings(), 1)
+	assert.Equal(t, "dynamic reference '#node' resolves to the first of 2 declarations of dynamic anchor 'node', "+
+		"the dynamic scope is not evaluated", idx.GetDynamicRefWarnings()[0].Error())
+	assert.Equal(t, 9, idx.GetDynamicRefWarnings()[0].KeyNode.Line)
+	assert.Empty(t, idx.GetReferenceIndexErrors())
+}
+
+func TestSpecIndex_ExtractDynamicRefs_Unresolved(t *testing.T) {
+	yml := `openapi: 3.1.0
+components:
+  schemas:
+    Tree:
+      properties:
+        children:
+          $dynamicRef: '#missing'
+        remote:
+          $dynamicRef: 'https://pb33f.io/tree.json#node'`
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &rootNode)
+	var logs bytes.Buffer
+	c := CreateOpenAPIIndexConfig()
+	c.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	idx := NewSpecIndexWithConfig(&rootNode, c)
+
+	assert.Empty(t, idx.GetMappedDynamicRefs())
+	require.Len(t, idx.GetReferenceIndexErrors(), 1)
+	assert.Equal(t, "dynamic reference '#missing' cannot be resolved", idx.GetReferenceIndexErrors()[0].Error())
+	assert.Contains(t, logs.String(), "dynamic references to anchors in other documents are not supported")
+	require.Len(t, idx.GetDynamicRefWarnings(), 1)
+	assert.Equal(t, "dynamic reference 'https://pb33f.io/tree.json#node' is to an anchor in another document, "+
+		"which is not supported", idx.GetDynamicRefWarnings()[0].Error())
+}
+
+func TestResolver_DynamicRefs(t *testing.T) {
+	yml := `openapi: 3.1.0
+components:
+  schemas:
+    Tree:
+      $dynamicAnchor: node
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $dynamicRef: '#node'
+    Forest:
+      type: object
+      properties:
+        trees:
+          $dynamicRef: '#/components/schemas/Tree'
+        lost:
+          $dynamicRef: '#missing'`
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &rootNode)
+	idx := NewSpecIndexWithConfig(&rootNode, CreateOpenAPIIndexConfig())
+
+	resolver := NewResolver(idx)
+	assert.Empty(t, resolver.CheckForCircularReferences())
+
+	// the tree follows its dynamic reference back to itself.
+	circular := resolver.GetCircularReferences()
+	require.Len(t, circular, 1)
+	assert.Equal(t, "#/components/schemas/Tree", circular[0].LoopPoint.Definition)
+	assert.True(t, circular[0].IsArrayResult)
+	assert.False(t, circular[0].IsInfiniteLoop)
+
+	// the unresolved dynamic reference is an index error, not a resolving error.
+	require.Len(t, idx.GetReferenceIndexErrors(), 1)
+	assert.Equal(t, "dynamic reference '#missing' cannot be resolved", idx.GetReferenceIndexErrors()[0].Error())
+}
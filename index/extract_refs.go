@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pb33f/libopenapi/utils"
 	"slices"
@@ -19,7 +20,7 @@ import (
 // ExtractRefs will return a deduplicated slice of references for every unique ref found in the document.
 // The total number of refs, will generally be much higher, you can extract those from GetRawReferenceCount()
 func (index *SpecIndex) ExtractRefs(node, parent *yaml.Node, seenPath []string, level int, poly bool, pName string) []*Reference {
-	if node == nil {
+	if node == nil || index.contextErr() != nil {
 		return nil
 	}
 	var found []*Reference
@@ -266,8 +267,9 @@ func (index *SpecIndex) ExtractRefs(node, parent *yaml.Node, seenPath []string,
 										fullDefinitionPath = fmt.Sprintf("%s#/%s", abs, uri[1])
 										componentName = fmt.Sprintf("#/%s", uri[1])
 									} else {
-										// if the index has a base URL, use that to resolve the path.
-										if index.config.BaseURL != nil && !filepath.IsAbs(defRoot) {
+										// if the index has a base URL (or is a remote spec), use that to resolve the path.
+										if (index.config.BaseURL != nil || strings.HasPrefix(defRoot, "http")) &&
+											!filepath.IsAbs(defRoot) {
 											var u url.URL
 											if strings.HasPrefix(defRoot, "http") {
 												up, _ := url.Parse(defRoot)
@@ -439,6 +441,11 @@ func (index *SpecIndex) ExtractRefs(node, parent *yaml.Node, seenPath []string,
 				definitionPath := fmt.Sprintf("#/%s", strings.Join(loc, "/"))
 				_, jsonPath := utils.ConvertComponentIdIntoFriendlyPathSearch(definitionPath)
 
+				// capture anchors and dynamic references (3.1)
+				if n.Value == "$anchor" || n.Value == "$dynamicAnchor" || n.Value == "$dynamicRef" {
+					index.captureAnchor(node, i, seenPath)
+				}
+
 				// capture descriptions and summaries
 				if n.Value == "description" {
 
@@ -625,6 +632,20 @@ func (index *SpecIndex) ExtractComponentsFromRefs(refs []*Reference) []*Referenc
 	}
 
 	locate := func(ref *Reference, refIndex int, sequence []*ReferenceMapped) {
+		started := time.Now()
+		if index.contextErr() != nil {
+			if !index.config.ExtractRefsSequentially {
+				c <- true
+			}
+			return
+		}
+		if index.IsLazyRemoteReference(ref.FullDefinition, "") {
+			// remote references are fetched when they are first searched for.
+			if !index.config.ExtractRefsSequentially {
+				c <- true
+			}
+			return
+		}
 		index.refLock.Lock()
 		if index.allMappedRefs[ref.FullDefinition] != nil {
 			rm := &ReferenceMapped{
@@ -634,10 +655,12 @@ func (index *SpecIndex) ExtractComponentsFromRefs(refs []*Reference) []*Referenc
 				FullDefinition:    index.allMappedRefs[ref.FullDefinition].FullDefinition,
 			}
 			sequence[refIndex] = rm
+			index.refLock.Unlock()
+			notifyResolution(index.config, ResolutionEvent{Type: ResolutionResolved,
+				Reference: ref.FullDefinition, Duration: time.Since(started)})
 			if !index.config.ExtractRefsSequentially {
 				c <- true
 			}
-			index.refLock.Unlock()
 		} else {
 			index.refLock.Unlock()
 			located := index.FindComponent(ref.FullDefinition)
@@ -657,7 +680,8 @@ func (index *SpecIndex) ExtractComponentsFromRefs(refs []*Reference) []*Referenc
 				}
 				sequence[refIndex] = rm
 				index.refLock.Unlock()
-
+				notifyResolution(index.config, ResolutionEvent{Type: ResolutionResolved,
+					Reference: ref.FullDefinition, Duration: time.Since(started)})
 			} else {
 
 				_, path := utils.ConvertComponentIdIntoFriendlyPathSearch(ref.Definition)
@@ -670,6 +694,8 @@ func (index *SpecIndex) ExtractComponentsFromRefs(refs []*Reference) []*Referenc
 				index.errorLock.Lock()
 				index.refErrors = append(index.refErrors, indexError)
 				index.errorLock.Unlock()
+				notifyResolution(index.config, ResolutionEvent{Type: ResolutionFailed,
+					Reference: ref.FullDefinition, Duration: time.Since(started), Error: indexError.Err})
 			}
 			if !index.config.ExtractRefsSequentially {
 				c <- true
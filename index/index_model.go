@@ -4,6 +4,7 @@
 package index
 
 import (
+	"context"
 	"io/fs"
 	"log/slog"
 	"net/http"
@@ -44,6 +45,16 @@ type ReferenceMapped struct {
 	FullDefinition    string
 }
 
+// RefLocation is the location of a single $ref found in a specification, see GetAllReferencesWithLocations.
+type RefLocation struct {
+	Reference      string // the reference, as it's written.
+	FullDefinition string // the fully qualified reference.
+	File           string // the absolute path (or URL) of the file the reference is in, if known.
+	Line           int
+	Column         int
+	Resolved       bool // true if the referenced component was found.
+}
+
 // SpecIndexConfig is a configuration struct for the SpecIndex introduced in 0.6.0 that provides an expandable
 // set of granular options. The first being the ability to set the Base URL for resolving relative references, and
 // allowing or disallowing remote or local file lookups.
@@ -69,6 +80,27 @@ type SpecIndexConfig struct {
 	// deprecated: Use the Rolodex instead
 	RemoteURLHandler func(url string) (*http.Response, error)
 
+	// HTTPClient is the client used by the remote file system to fetch remote documents. Use it to configure
+	// authentication, proxies, mTLS or timeouts. If the RemoteURLHandler is set, it takes precedence over the HTTPClient.
+	// If neither is set, a default client (with a 120-second timeout) is used.
+	HTTPClient *http.Client
+
+	// MaxDocumentBytes is the maximum size (in bytes) of a local or remote document that will be loaded and parsed.
+	// MaxDocumentNodes is the maximum number of YAML nodes a parsed document may contain. Documents over either
+	// limit are refused with datamodel.ErrDocumentTooLarge. Zero (the default) means there is no limit.
+	MaxDocumentBytes int64
+	MaxDocumentNodes int
+
+	// MaxYAMLAliasExpansions is the maximum number of YAML alias dereferences a local or remote document may make
+	// once fully expanded, documents over the limit are refused with datamodel.ErrTooManyAliasExpansions.
+	// Zero (the default) means there is no limit.
+	MaxYAMLAliasExpansions int
+
+	// Context is used to cancel (or set a deadline on) indexing, resolving and remote lookups. If the context is
+	// cancelled, the index will stop walking the document and ctx.Err() is recorded as a reference error.
+	// If not set, indexing cannot be cancelled.
+	Context context.Context
+
 	// FSHandler is an entity that implements the `fs.FS` interface that will be used to fetch local or remote documents.
 	// This is useful if you want to use a custom file system handler, or if you want to use a custom http client or
 	// custom network implementation for a lookup.
@@ -96,6 +128,23 @@ type SpecIndexConfig struct {
 	AllowRemoteLookup bool // Allow remote lookups for references. Defaults to false
 	AllowFileLookup   bool // Allow file lookups for references. Defaults to false
 
+	// AllowedRemoteHosts is a list of hosts that remote references are allowed to be fetched from. If set, any remote
+	// reference to a host not in the list will be refused. DeniedRemoteHosts is a list of hosts that remote references
+	// will never be fetched from, it takes precedence over AllowedRemoteHosts.
+	//
+	// Hosts can use wildcard patterns, for example '*.internal.example.com' will match 'api.internal.example.com'
+	// (but not 'internal.example.com'). Ports are ignored when matching.
+	AllowedRemoteHosts []string
+	DeniedRemoteHosts  []string
+
+	// LazyRemoteResolution will stop remote references from being fetched while the index is built (and while
+	// circular references are checked). Remote documents are only fetched when a reference to them is searched for,
+	// for example when a SchemaProxy backed by the reference is first built. Useful for specifications with many
+	// remote references that are rarely used.
+	//
+	// Remote references are not checked for circular references, and are not mapped by the index.
+	LazyRemoteResolution bool
+
 	// If set to true, the index will not be built out, which means only the foundational elements will be
 	// parsed and added to the index. This is useful to avoid building out an index if the specification is
 	// broken up into references and want it fully resolved.
@@ -107,8 +156,9 @@ type SpecIndexConfig struct {
 	// manually, otherwise resolving may explode.
 	AvoidCircularReferenceCheck bool
 
-	// Logger is a logger that will be used for logging errors and warnings. If not set, the default logger
-	// will be used, set to the Error level.
+	// Logger is a logger that will be used for logging errors and warnings by the index, the resolver, the rolodex
+	// and the remote file system (and the indexes of the files they load). If not set, the default logger will be
+	// used, set to the Error level.
 	Logger *slog.Logger
 
 	// SpecInfo is a pointer to the SpecInfo struct that contains the root node and the spec version. It's the
@@ -124,6 +174,11 @@ type SpecIndexConfig struct {
 	// If the index is for a single file spec, then the root will be empty.
 	SpecAbsolutePath string
 
+	// SpecFilePath is the path of the root specification file, relative to the BasePath (or absolute). Set it when the
+	// root specification is not in the BasePath directory itself (for example 'specs/api/openapi.yaml'), so the
+	// relative references it makes are resolved from the directory it lives in, rather than from the BasePath.
+	SpecFilePath string
+
 	// IgnorePolymorphicCircularReferences will skip over checking for circular references in polymorphic schemas.
 	// A polymorphic schema is any schema that is composed other schemas using references via `oneOf`, `anyOf` of `allOf`.
 	// This is disabled by default, which means polymorphic circular references will be checked.
@@ -136,6 +191,17 @@ type SpecIndexConfig struct {
 	// this is disabled by default, which means array circular references will be checked.
 	IgnoreArrayCircularReferences bool
 
+	// MergeRefSiblings will keep the keywords next to a $ref (e.g. a 'description') when references in an OpenAPI
+	// 3.1 document are resolved, by the rolodex (see Resolver.MergeRefSiblings) or when a schema is built, merging
+	// them into the resolved node. This is disabled by default, which means siblings are discarded when a reference
+	// is resolved.
+	MergeRefSiblings bool
+
+	// DisableSchemaCache will stop schemas from being shared between the schema proxies of the same reference. By
+	// default, every reference to the same schema in a document builds it once, and every proxy returns that same
+	// *Schema. Enable this if each reference needs its own isolated copy of the schema (for example, to mutate it).
+	DisableSchemaCache bool
+
 	// SkipDocumentCheck will skip the document check when building the index. A document check will look for an 'openapi'
 	// or 'swagger' node in the root of the document. If it's not found, then the document is not a valid OpenAPI or
 	// the file is a JSON Schema. To allow JSON Schema files to be included set this to true.
@@ -147,6 +213,19 @@ type SpecIndexConfig struct {
 	// to be bundled.
 	ExtractRefsSequentially bool
 
+	// RemoteCache is consulted by the remote file system before fetching a remote document, and is updated
+	// with the contents of every remote document fetched. Sharing a cache between documents means that
+	// common remote definitions are only fetched once. If not set, nothing is cached.
+	//
+	// NewRemoteCache will create an in-memory cache with a time-to-live for each document.
+	RemoteCache RemoteCache
+
+	// ResolutionListener is called with a ResolutionEvent as references are resolved, remote documents are
+	// fetched, and circular references are detected. Useful for reporting progress on large specifications. It's
+	// called from the goroutine doing the work (references may be resolved concurrently), so it must be safe for
+	// concurrent use and must not block. If not set, no events are sent.
+	ResolutionListener func(event ResolutionEvent)
+
 	// private fields
 	uri []string
 }
@@ -258,11 +337,17 @@ type SpecIndex struct {
 	allSummaries                        []*DescriptionReference                       // every single summary found in the spec.
 	allEnums                            []*EnumReference                              // every single enum found in the spec.
 	allObjectsWithProperties            []*ObjectReference                            // every single object with properties found in the spec.
+	allAnchors                          map[string]*Reference                         // every '$anchor' found in the spec, keyed by name.
+	allDynamicAnchors                   map[string][]*Reference                       // every '$dynamicAnchor' found in the spec, keyed by name.
+	allDynamicRefs                      []*Reference                                  // every '$dynamicRef' found in the spec.
+	mappedDynamicRefs                   map[string]*Reference                         // '$dynamicRef' values mapped to the schemas they resolve to.
+	dynamicRefWarnings                  []*IndexingError                              // '$dynamicRef' values resolved (or skipped) with a warning.
 	enumCount                           int
 	descriptionCount                    int
 	summaryCount                        int
 	refLock                             sync.Mutex
 	componentLock                       sync.RWMutex
+	countLock                           sync.Mutex // guards the lazily calculated counts, and the maps they populate.
 	errorLock                           sync.RWMutex
 	circularReferences                  []*CircularReferenceResult // only available when the resolver has been used.
 	polyCircularReferences              []*CircularReferenceResult // only available when the resolver has been used.
@@ -273,7 +358,10 @@ type SpecIndex struct {
 	polyComponentIndexChan              chan bool
 	resolver                            *Resolver
 	cache                               *sync.Map
+	schemaCache                         *sync.Map
+	highSchemaCache                     *sync.Map
 	built                               bool
+	merged                              bool // made by Rolodex.MergedIndex, the counts are set from the merged maps.
 	uri                                 []string
 	logger                              *slog.Logger
 	nodeMap                             map[int]map[int]*yaml.Node
@@ -303,6 +391,47 @@ func (index *SpecIndex) GetCache() *sync.Map {
 	return index.cache
 }
 
+// GetSchemaCache returns the cache of built low-level schemas shared by every schema proxy in the document, keyed by
+// the node they were built from. The cache belongs to the root index of the rolodex, if there is one.
+// Returns nil if the index is nil, or if SpecIndexConfig.DisableSchemaCache is set.
+func (index *SpecIndex) GetSchemaCache() *sync.Map {
+	if owner := index.schemaCacheOwner(); owner != nil {
+		return owner.schemaCache
+	}
+	return nil
+}
+
+// GetHighSchemaCache returns the cache of high-level schemas shared by every high-level schema proxy in the
+// document, keyed by the low-level schema they were built from. It is kept apart from GetSchemaCache, so each cache
+// only ever holds one type of key and value. Returns nil under the same conditions as GetSchemaCache.
+func (index *SpecIndex) GetHighSchemaCache() *sync.Map {
+	if owner := index.schemaCacheOwner(); owner != nil {
+		return owner.highSchemaCache
+	}
+	return nil
+}
+
+// schemaCacheOwner returns the index that holds the schema caches for this index, or nil if caching is disabled.
+func (index *SpecIndex) schemaCacheOwner() *SpecIndex {
+	if index == nil || (index.config != nil && index.config.DisableSchemaCache) {
+		return nil
+	}
+	if index.rolodex != nil && index.rolodex.rootIndex != nil && index.rolodex.rootIndex != index {
+		return index.rolodex.rootIndex.schemaCacheOwner()
+	}
+	return index
+}
+
+// ShouldMergeRefSiblings returns true if the keywords next to a $ref should be merged into the node it references when
+// the reference is resolved, which is when SpecIndexConfig.MergeRefSiblings is set and the root document is OpenAPI
+// 3.1 or later. Returns false if the index is nil.
+func (index *SpecIndex) ShouldMergeRefSiblings() bool {
+	if index == nil || index.config == nil || !index.config.MergeRefSiblings {
+		return false
+	}
+	return index.rootIsOpenAPI31()
+}
+
 // SetAbsolutePath sets the absolute path to the spec file for the index. Will be absolute, either as a http link or a file.
 func (index *SpecIndex) SetAbsolutePath(absolutePath string) {
 	index.specAbsolutePath = absolutePath
@@ -65,6 +65,9 @@ func boostrapIndexCollections(index *SpecIndex) {
 	index.polymorphicRefs = make(map[string]*Reference)
 	index.refsWithSiblings = make(map[string]Reference)
 	index.opServersRefs = make(map[string]map[string][]*Reference)
+	index.allAnchors = make(map[string]*Reference)
+	index.allDynamicAnchors = make(map[string][]*Reference)
+	index.mappedDynamicRefs = make(map[string]*Reference)
 	index.componentIndexChan = make(chan bool)
 	index.polyComponentIndexChan = make(chan bool)
 }
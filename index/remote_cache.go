@@ -0,0 +1,88 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"sync"
+	"time"
+)
+
+// RemoteCache is a read-through cache for remote documents, used by the RemoteFS to avoid fetching the same
+// document more than once. Implementations must be safe for concurrent use, a single cache may be shared across
+// many documents and indexes.
+type RemoteCache interface {
+	// Get returns the bytes of a remote document for the URL, and true if the document was found in the cache.
+	Get(url string) ([]byte, bool)
+
+	// Set stores the bytes of a remote document against the URL.
+	Set(url string, data []byte)
+}
+
+// MemoryRemoteCache is an in-memory RemoteCache, every document is held for a time-to-live (TTL) after it has been
+// stored, after which it's considered expired and will be fetched again.
+type MemoryRemoteCache struct {
+	ttl     time.Duration
+	entries map[string]memoryRemoteCacheEntry
+	lock    sync.RWMutex
+	now     func() time.Time
+}
+
+type memoryRemoteCacheEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+// NewRemoteCache creates a new in-memory RemoteCache. Documents are expired after the supplied TTL,
+// if the TTL is zero or less, documents never expire.
+func NewRemoteCache(ttl time.Duration) *MemoryRemoteCache {
+	return &MemoryRemoteCache{
+		ttl:     ttl,
+		entries: make(map[string]memoryRemoteCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// Get returns the bytes of a remote document for the URL, and true if the document exists and has not expired.
+func (c *MemoryRemoteCache) Get(url string) ([]byte, bool) {
+	c.lock.RLock()
+	entry, ok := c.entries[url]
+	c.lock.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if !entry.expires.IsZero() && c.now().After(entry.expires) {
+		c.lock.Lock()
+		if current, found := c.entries[url]; found && current.expires.Equal(entry.expires) {
+			delete(c.entries, url)
+		}
+		c.lock.Unlock()
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// Set stores the bytes of a remote document against the URL, replacing any existing document.
+func (c *MemoryRemoteCache) Set(url string, data []byte) {
+	entry := memoryRemoteCacheEntry{data: data}
+	if c.ttl > 0 {
+		entry.expires = c.now().Add(c.ttl)
+	}
+	c.lock.Lock()
+	c.entries[url] = entry
+	c.lock.Unlock()
+}
+
+// Len returns the number of documents held in the cache, including any that have expired but not yet been evicted.
+func (c *MemoryRemoteCache) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return len(c.entries)
+}
+
+// Clear removes every document from the cache.
+func (c *MemoryRemoteCache) Clear() {
+	c.lock.Lock()
+	c.entries = make(map[string]memoryRemoteCacheEntry)
+	c.lock.Unlock()
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRemoteCache(t *testing.T) {
+	cache := NewRemoteCache(time.Minute)
+	_, ok := cache.Get("https://pb33f.io/pizza.yaml")
+	assert.False(t, ok)
+
+	cache.Set("https://pb33f.io/pizza.yaml", []byte("pizza"))
+	data, ok := cache.Get("https://pb33f.io/pizza.yaml")
+	assert.True(t, ok)
+	assert.Equal(t, "pizza", string(data))
+	assert.Equal(t, 1, cache.Len())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestMemoryRemoteCache_Expired(t *testing.T) {
+	now := time.Now()
+	cache := NewRemoteCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	cache.Set("https://pb33f.io/pizza.yaml", []byte("pizza"))
+
+	now = now.Add(30 * time.Second)
+	_, ok := cache.Get("https://pb33f.io/pizza.yaml")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("https://pb33f.io/pizza.yaml")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestMemoryRemoteCache_NoTTL(t *testing.T) {
+	now := time.Now()
+	cache := NewRemoteCache(0)
+	cache.now = func() time.Time { return now }
+	cache.Set("https://pb33f.io/pizza.yaml", []byte("pizza"))
+
+	now = now.Add(24 * time.Hour)
+	_, ok := cache.Get("https://pb33f.io/pizza.yaml")
+	assert.True(t, ok)
+}
+
+func TestMemoryRemoteCache_Concurrent(t *testing.T) {
+	cache := NewRemoteCache(time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			cache.Set("https://pb33f.io/pizza.yaml", []byte("pizza"))
+		}()
+		go func() {
+			defer wg.Done()
+			cache.Get("https://pb33f.io/pizza.yaml")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, cache.Len())
+}
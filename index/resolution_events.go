@@ -0,0 +1,77 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import "time"
+
+// ResolutionEventType is the kind of ResolutionEvent sent to the ResolutionListener of a SpecIndexConfig.
+type ResolutionEventType int
+
+const (
+	// ResolutionFetchingRemote is sent before a remote document is fetched.
+	ResolutionFetchingRemote ResolutionEventType = iota
+
+	// ResolutionFetchedRemote is sent once a remote document has been fetched (or has failed to be fetched).
+	ResolutionFetchedRemote
+
+	// ResolutionResolved is sent when a reference has been located.
+	ResolutionResolved
+
+	// ResolutionFailed is sent when a reference can't be located.
+	ResolutionFailed
+
+	// ResolutionCircular is sent when a circular reference is detected.
+	ResolutionCircular
+)
+
+// String returns a readable name for the event type.
+func (t ResolutionEventType) String() string {
+	switch t {
+	case ResolutionFetchingRemote:
+		return "fetching remote"
+	case ResolutionFetchedRemote:
+		return "fetched remote"
+	case ResolutionResolved:
+		return "resolved"
+	case ResolutionFailed:
+		return "failed"
+	case ResolutionCircular:
+		return "circular detected"
+	}
+	return "unknown"
+}
+
+// ResolutionEvent describes a step taken while resolving the references of a specification.
+type ResolutionEvent struct {
+	// Type is the kind of event.
+	Type ResolutionEventType
+
+	// Reference is the full definition of the reference the event is for. For remote fetches, it's the URL of the
+	// remote document.
+	Reference string
+
+	// Time is when the event happened.
+	Time time.Time
+
+	// Duration is how long it took to resolve the reference (or to fetch the remote document). It's zero for
+	// ResolutionFetchingRemote and ResolutionCircular events.
+	Duration time.Duration
+
+	// Error is set for ResolutionFailed events, and ResolutionFetchedRemote events when the fetch failed.
+	Error error
+
+	// Circular is the circular reference, for ResolutionCircular events.
+	Circular *CircularReferenceResult
+}
+
+// notifyResolution sends event to the ResolutionListener of config, if there is one.
+func notifyResolution(config *SpecIndexConfig, event ResolutionEvent) {
+	if config == nil || config.ResolutionListener == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	config.ResolutionListener(event)
+}
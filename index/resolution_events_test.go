@@ -0,0 +1,134 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// eventRecorder collects the events sent to a ResolutionListener.
+type eventRecorder struct {
+	lock   sync.Mutex
+	events []ResolutionEvent
+}
+
+func (r *eventRecorder) listen(event ResolutionEvent) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) ofType(t ResolutionEventType) []ResolutionEvent {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var found []ResolutionEvent
+	for _, e := range r.events {
+		if e.Type == t {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+func TestSpecIndex_ResolutionListener(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [owner]
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+      required: [pet]
+      properties:
+        pet:
+          $ref: '#/components/schemas/Pet'
+    Broken:
+      $ref: '#/components/schemas/Missing'`
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(spec), &rootNode)
+
+	recorder := &eventRecorder{}
+	cfg := CreateClosedAPIIndexConfig()
+	cfg.ResolutionListener = recorder.listen
+
+	rolo := NewRolodex(cfg)
+	rolo.SetRootNode(&rootNode)
+	_ = rolo.IndexTheRolodex()
+	rolo.CheckForCircularReferences()
+
+	resolved := recorder.ofType(ResolutionResolved)
+	assert.Len(t, resolved, 2)
+	for _, e := range resolved {
+		assert.Contains(t, []string{"#/components/schemas/Pet", "#/components/schemas/Owner"}, e.Reference)
+		assert.False(t, e.Time.IsZero())
+		assert.NoError(t, e.Error)
+	}
+
+	failed := recorder.ofType(ResolutionFailed)
+	if assert.Len(t, failed, 1) {
+		assert.Equal(t, "#/components/schemas/Missing", failed[0].Reference)
+		assert.EqualError(t, failed[0].Error, "component '#/components/schemas/Missing' does not exist in the specification")
+	}
+
+	circular := recorder.ofType(ResolutionCircular)
+	if assert.NotEmpty(t, circular) {
+		assert.NotNil(t, circular[0].Circular)
+		assert.Equal(t, circular[0].Circular.LoopPoint.FullDefinition, circular[0].Reference)
+	}
+	assert.Equal(t, "circular detected", ResolutionCircular.String())
+}
+
+func TestRemoteFS_ResolutionListener(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/missing.yaml" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = rw.Write([]byte(`type: string`))
+	}))
+	defer server.Close()
+
+	recorder := &eventRecorder{}
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.ResolutionListener = recorder.listen
+	remoteFS, _ := NewRemoteFSWithConfig(cfg)
+	remoteFS.RemoteHandlerFunc = http.Get
+
+	_, err := remoteFS.Open(server.URL + "/string.yaml")
+	assert.NoError(t, err)
+	_, err = remoteFS.Open(server.URL + "/missing.yaml")
+	assert.Error(t, err)
+
+	fetching := recorder.ofType(ResolutionFetchingRemote)
+	if assert.Len(t, fetching, 2) {
+		assert.Equal(t, server.URL+"/string.yaml", fetching[0].Reference)
+		assert.Equal(t, server.URL+"/missing.yaml", fetching[1].Reference)
+	}
+
+	fetched := recorder.ofType(ResolutionFetchedRemote)
+	if assert.Len(t, fetched, 2) {
+		assert.NoError(t, fetched[0].Error)
+		assert.Positive(t, fetched[0].Duration)
+		assert.EqualError(t, fetched[1].Error, "unable to fetch remote document '"+server.URL+"/missing.yaml' (error 404)")
+	}
+}
+
+func TestNotifyResolution_NoListener(t *testing.T) {
+	// nothing to call, nothing should happen.
+	notifyResolution(nil, ResolutionEvent{Type: ResolutionFailed, Error: errors.New("nope")})
+	notifyResolution(CreateClosedAPIIndexConfig(), ResolutionEvent{Type: ResolutionFailed})
+	assert.Equal(t, "unknown", ResolutionEventType(99).String())
+}
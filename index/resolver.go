@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/pb33f/libopenapi/utils"
@@ -67,6 +68,14 @@ type Resolver struct {
 	IgnorePoly             bool
 	IgnoreArray            bool
 	circChecked            bool
+
+	// MergeRefSiblings keeps the keywords next to a $ref (e.g. a 'description') when the reference is resolved,
+	// merging them into the resolved node and overriding the same keywords of the referenced node. Siblings are only
+	// merged for OpenAPI 3.1 (and later) documents, OpenAPI 3.0 ignores them, so they are always discarded.
+	MergeRefSiblings bool
+	mergeChecked     bool
+	mergeSiblings    bool
+	refSiblings      map[*yaml.Node][]*yaml.Node
 }
 
 // NewResolver will create a new resolver from a *index.SpecIndex
@@ -97,6 +106,16 @@ func (resolver *Resolver) GetResolvingErrors() []*ResolvingError {
 	return resolver.resolvingErrors
 }
 
+// addCircularReference records a circular reference, and notifies the ResolutionListener of the index about it.
+func (resolver *Resolver) addCircularReference(circRef *CircularReferenceResult) {
+	resolver.circularReferences = append(resolver.circularReferences, circRef)
+	event := ResolutionEvent{Type: ResolutionCircular, Circular: circRef}
+	if circRef.LoopPoint != nil {
+		event.Reference = circRef.LoopPoint.FullDefinition
+	}
+	notifyResolution(resolver.specIndex.config, event)
+}
+
 func (resolver *Resolver) GetCircularReferences() []*CircularReferenceResult {
 	return resolver.GetSafeCircularReferences()
 }
@@ -191,6 +210,9 @@ func (resolver *Resolver) GetRelativesSeen() int {
 // original data)
 func (resolver *Resolver) Resolve() []*ResolvingError {
 	visitIndex(resolver, resolver.specIndex)
+	if resolver.cancelled() {
+		return resolver.resolvingErrors
+	}
 
 	for _, circRef := range resolver.circularReferences {
 		// If the circular reference is not required, we can ignore it, as it's a terminable loop rather than an infinite one
@@ -217,6 +239,9 @@ func (resolver *Resolver) Resolve() []*ResolvingError {
 // CheckForCircularReferences Check for circular references, without resolving, a non-destructive run.
 func (resolver *Resolver) CheckForCircularReferences() []*ResolvingError {
 	visitIndexWithoutDamagingIt(resolver, resolver.specIndex)
+	if resolver.cancelled() {
+		return resolver.resolvingErrors
+	}
 	for _, circRef := range resolver.circularReferences {
 		// If the circular reference is not required, we can ignore it, as it's a terminable loop rather than an infinite one
 		if !circRef.IsInfiniteLoop {
@@ -239,11 +264,25 @@ func (resolver *Resolver) CheckForCircularReferences() []*ResolvingError {
 	return resolver.resolvingErrors
 }
 
+// cancelled checks if the index Context has been cancelled. If it has, the context error is recorded as a
+// resolving error and true is returned.
+func (resolver *Resolver) cancelled() bool {
+	err := resolver.specIndex.contextErr()
+	if err == nil {
+		return false
+	}
+	resolver.resolvingErrors = append(resolver.resolvingErrors, &ResolvingError{ErrorRef: err})
+	return true
+}
+
 func visitIndexWithoutDamagingIt(res *Resolver, idx *SpecIndex) {
 	mapped := idx.GetMappedReferencesSequenced()
 	mappedIndex := idx.GetMappedReferences()
 	res.indexesVisited++
 	for _, ref := range mapped {
+		if idx.contextErr() != nil {
+			return
+		}
 		seenReferences := make(map[string]bool)
 		var journey []*Reference
 		res.journeysTaken++
@@ -251,6 +290,9 @@ func visitIndexWithoutDamagingIt(res *Resolver, idx *SpecIndex) {
 	}
 	schemas := idx.GetAllComponentSchemas()
 	for s, schemaRef := range schemas {
+		if idx.contextErr() != nil {
+			return
+		}
 		if mappedIndex[s] == nil {
 			seenReferences := make(map[string]bool)
 			var journey []*Reference
@@ -263,6 +305,7 @@ func visitIndexWithoutDamagingIt(res *Resolver, idx *SpecIndex) {
 type refMap struct {
 	ref   *Reference
 	nodes []*yaml.Node
+	kind  yaml.Kind
 }
 
 func visitIndex(res *Resolver, idx *SpecIndex) {
@@ -272,6 +315,9 @@ func visitIndex(res *Resolver, idx *SpecIndex) {
 
 	var refs []refMap
 	for _, ref := range mapped {
+		if idx.contextErr() != nil {
+			return
+		}
 		seenReferences := make(map[string]bool)
 		var journey []*Reference
 		res.journeysTaken++
@@ -283,6 +329,7 @@ func visitIndex(res *Resolver, idx *SpecIndex) {
 					refs = append(refs, refMap{
 						ref:   ref.OriginalReference,
 						nodes: n,
+						kind:  ref.Reference.Node.Kind,
 					})
 				}
 			}
@@ -292,6 +339,9 @@ func visitIndex(res *Resolver, idx *SpecIndex) {
 
 	schemas := idx.GetAllComponentSchemas()
 	for s, schemaRef := range schemas {
+		if idx.contextErr() != nil {
+			return
+		}
 		if mappedIndex[s] == nil {
 			seenReferences := make(map[string]bool)
 			var journey []*Reference
@@ -302,6 +352,9 @@ func visitIndex(res *Resolver, idx *SpecIndex) {
 
 	schemas = idx.GetAllSecuritySchemes()
 	for s, schemaRef := range schemas {
+		if idx.contextErr() != nil {
+			return
+		}
 		if mappedIndex[s] == nil {
 			seenReferences := make(map[string]bool)
 			var journey []*Reference
@@ -315,7 +368,7 @@ func visitIndex(res *Resolver, idx *SpecIndex) {
 		locatedDef := mappedIndex[sequenced.Definition]
 		if locatedDef != nil {
 			if !locatedDef.Circular && locatedDef.Seen {
-				sequenced.Node.Content = locatedDef.Node.Content
+				res.resolveNode(sequenced.Node, locatedDef.Node.Content, locatedDef.Node.Kind)
 			}
 		}
 	}
@@ -380,7 +433,7 @@ func (resolver *Resolver) VisitReference(ref *Reference, seen map[string]bool, j
 						resolver.ignoredArrayReferences = append(resolver.ignoredArrayReferences, circRef)
 					} else {
 						if !resolver.circChecked {
-							resolver.circularReferences = append(resolver.circularReferences, circRef)
+							resolver.addCircularReference(circRef)
 						}
 					}
 					r.Seen = true
@@ -402,7 +455,7 @@ func (resolver *Resolver) VisitReference(ref *Reference, seen map[string]bool, j
 			if resolve && !original.Circular {
 				ref.Resolved = true
 				r.Resolved = true
-				r.Node.Content = resolved // this is where we perform the actual resolving.
+				resolver.resolveNode(r.Node, resolved, original.Node.Kind) // this is where we perform the actual resolving.
 			}
 			r.Seen = true
 			ref.Seen = true
@@ -414,6 +467,16 @@ func (resolver *Resolver) VisitReference(ref *Reference, seen map[string]bool, j
 	return ref.Node.Content
 }
 
+// searchReference searches the index for a reference, remote references that are resolved lazily are not searched
+// for (that would fetch them).
+func (resolver *Resolver) searchReference(def string) *Reference {
+	if resolver.specIndex.IsLazyRemoteReference(def, "") {
+		return nil
+	}
+	r, _ := resolver.specIndex.SearchIndexForReference(def)
+	return r
+}
+
 func (resolver *Resolver) isInfiniteCircularDependency(ref *Reference, visitedDefinitions map[string]bool,
 	initialRef *Reference,
 ) (bool, map[string]bool) {
@@ -421,7 +484,10 @@ func (resolver *Resolver) isInfiniteCircularDependency(ref *Reference, visitedDe
 		return false, visitedDefinitions
 	}
 	for refDefinition := range ref.RequiredRefProperties {
-		r, _ := resolver.specIndex.SearchIndexForReference(refDefinition)
+		r := resolver.searchReference(refDefinition)
+		if r == nil {
+			continue
+		}
 		if initialRef != nil && initialRef.FullDefinition == r.FullDefinition {
 			return true, visitedDefinitions
 		}
@@ -480,7 +546,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 			IsInfiniteLoop: true,
 		}
 		if !resolver.circChecked {
-			resolver.circularReferences = append(resolver.circularReferences, circRef)
+			resolver.addCircularReference(circRef)
 			ref.Circular = true
 		}
 		return nil
@@ -511,7 +577,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 
 			}
 
-			if i%2 == 0 && n.Value == "$ref" && len(node.Content) > i%2+1 {
+			if i%2 == 0 && (n.Value == "$ref" || n.Value == "$dynamicRef") && len(node.Content) > i%2+1 {
 
 				if !utils.IsNodeStringValue(node.Content[i+1]) {
 					continue
@@ -523,6 +589,19 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 
 
 				value := node.Content[i+1].Value
+				if n.Value == "$dynamicRef" {
+					// a dynamic reference is followed like a reference to the schema it resolves to (see
+					// ExtractDynamicRefs), one that cannot be resolved has already been recorded by the index.
+					dynamicIndex := resolver.specIndex
+					if ref.Index != nil {
+						dynamicIndex = ref.Index
+					}
+					dynamic := dynamicIndex.FindDynamicRef(value)
+					if dynamic == nil {
+						continue
+					}
+					value = dynamic.Definition
+				}
 				value = strings.ReplaceAll(value, "\\\\", "\\")
 				var locatedRef *Reference
 				var fullDef string
@@ -613,6 +692,9 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 					IsRemote:       true,
 				}
 
+				if resolver.specIndex.IsLazyRemoteReference(fullDef, "") {
+					continue // not fetched until it's needed, so it cannot be checked.
+				}
 				locatedRef, _ = resolver.specIndex.SearchIndexForReferenceByReference(searchRef)
 
 				if locatedRef == nil {
@@ -629,7 +711,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 				if resolve {
 					// if this is a reference also, we want to resolve it.
 					if ok, _, _ := utils.IsNodeRefValue(ref.Node); ok {
-						ref.Node.Content = locatedRef.Node.Content
+						resolver.resolveNode(ref.Node, locatedRef.Node.Content, locatedRef.Node.Kind)
 						ref.Resolved = true
 					}
 				}
@@ -666,7 +748,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 									// create full definition lookup based on ref.
 									def := resolver.buildDefPath(ref, l)
 
-									mappedRefs, _ := resolver.specIndex.SearchIndexForReference(def)
+									mappedRefs := resolver.searchReference(def)
 									if mappedRefs != nil && !mappedRefs.Circular {
 										circ := false
 										for f := range journey {
@@ -695,7 +777,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 												resolver.ignoredPolyReferences = append(resolver.ignoredPolyReferences, circRef)
 											} else {
 												if !resolver.circChecked {
-													resolver.circularReferences = append(resolver.circularReferences, circRef)
+													resolver.addCircularReference(circRef)
 												}
 											}
 										}
@@ -711,7 +793,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 									// create full definition lookup based on ref.
 									def := resolver.buildDefPath(ref, l)
 
-									mappedRefs, _ := resolver.specIndex.SearchIndexForReference(def)
+									mappedRefs := resolver.searchReference(def)
 									if mappedRefs != nil && !mappedRefs.Circular {
 										circ := false
 										for f := range journey {
@@ -740,7 +822,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 												resolver.ignoredPolyReferences = append(resolver.ignoredPolyReferences, circRef)
 											} else {
 												if !resolver.circChecked {
-													resolver.circularReferences = append(resolver.circularReferences, circRef)
+													resolver.addCircularReference(circRef)
 												}
 											}
 										}
@@ -756,7 +838,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 							if utils.IsNodeMap(v) {
 								if d, _, l := utils.IsNodeRefValue(v); d {
 									def := resolver.buildDefPath(ref, l)
-									mappedRefs, _ := resolver.specIndex.SearchIndexForReference(def)
+									mappedRefs := resolver.searchReference(def)
 									if mappedRefs != nil && !mappedRefs.Circular {
 										circ := false
 										for f := range journey {
@@ -786,7 +868,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 												resolver.ignoredPolyReferences = append(resolver.ignoredPolyReferences, circRef)
 											} else {
 												if !resolver.circChecked {
-													resolver.circularReferences = append(resolver.circularReferences, circRef)
+													resolver.addCircularReference(circRef)
 												}
 											}
 										}
@@ -893,6 +975,81 @@ func (resolver *Resolver) ResolvePendingNodes() {
 	// map everything afterwards
 	for _, r := range resolver.specIndex.pendingResolve {
 		// r.Node.Content = refs[r].nodes
-		r.ref.Node.Content = r.nodes
+		resolver.resolveNode(r.ref.Node, r.nodes, r.kind)
+	}
+}
+
+// resolveNode replaces the content of the reference node with the resolved content (of a node of the given kind),
+// merging in the siblings of the $ref when MergeRefSiblings applies.
+func (resolver *Resolver) resolveNode(node *yaml.Node, resolved []*yaml.Node, kind yaml.Kind) {
+	if !resolver.mergeRefSiblings() || node.Kind != yaml.MappingNode || kind != yaml.MappingNode {
+		node.Content = resolved
+		return
+	}
+
+	// siblings are captured the first time the node is resolved, as resolving replaces them.
+	siblings, ok := resolver.refSiblings[node]
+	if !ok {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != "$ref" {
+				siblings = append(siblings, node.Content[i], node.Content[i+1])
+			}
+		}
+		if resolver.refSiblings == nil {
+			resolver.refSiblings = make(map[*yaml.Node][]*yaml.Node)
+		}
+		resolver.refSiblings[node] = siblings
+	}
+	if len(siblings) == 0 {
+		node.Content = resolved
+		return
+	}
+
+	// the resolved content is shared with the referenced node, so it's copied rather than modified.
+	merged := make([]*yaml.Node, 0, len(resolved)+len(siblings))
+	overridden := make(map[string]bool, len(siblings)/2)
+	for i := 0; i < len(siblings); i += 2 {
+		overridden[siblings[i].Value] = true
+	}
+	for i := 0; i+1 < len(resolved); i += 2 {
+		if !overridden[resolved[i].Value] {
+			merged = append(merged, resolved[i], resolved[i+1])
+		}
+	}
+	node.Content = append(merged, siblings...)
+}
+
+// mergeRefSiblings returns true if MergeRefSiblings is set and the document being resolved (the root document of the
+// rolodex, if there is one) is OpenAPI 3.1 or later.
+func (resolver *Resolver) mergeRefSiblings() bool {
+	if !resolver.MergeRefSiblings {
+		return false
+	}
+	if resolver.mergeChecked {
+		return resolver.mergeSiblings
+	}
+	resolver.mergeChecked = true
+	resolver.mergeSiblings = resolver.specIndex.rootIsOpenAPI31()
+	return resolver.mergeSiblings
+}
+
+// rootIsOpenAPI31 returns true if the root document of the index (the root document of the rolodex, if there is one)
+// is OpenAPI 3.1 or later.
+func (index *SpecIndex) rootIsOpenAPI31() bool {
+	idx := index
+	if rolodex := idx.GetRolodex(); rolodex != nil && rolodex.GetRootIndex() != nil {
+		idx = rolodex.GetRootIndex()
+	}
+	root := idx.GetRootNode()
+	if root == nil || len(root.Content) == 0 {
+		return false
+	}
+	_, versionNode := utils.FindKeyNodeTop("openapi", root.Content[0].Content)
+	if versionNode == nil {
+		return false
 	}
+	majorVersion, minorVersion, _ := strings.Cut(strings.TrimSpace(versionNode.Value), ".")
+	major, _ := strconv.Atoi(majorVersion)
+	minor, _ := strconv.Atoi(strings.SplitN(minorVersion, ".", 2)[0])
+	return major > 3 || (major == 3 && minor >= 1)
 }
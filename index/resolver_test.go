@@ -15,6 +15,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
 
@@ -677,6 +678,74 @@ func TestResolver_ResolveThroughPaths(t *testing.T) {
 	assert.Len(t, err, 0)
 }
 
+func TestResolver_MergeRefSiblings(t *testing.T) {
+	spec := func(version string) string {
+		return `openapi: ` + version + `
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: a pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+                description: the pet that was found
+                readOnly: true
+components:
+  schemas:
+    Pet:
+      type: object
+      description: a pet`
+	}
+	resolve := func(version string, merge bool) (schema, pet *yaml.Node) {
+		var rootNode yaml.Node
+		_ = yaml.Unmarshal([]byte(spec(version)), &rootNode)
+		idx := NewSpecIndexWithConfig(&rootNode, CreateClosedAPIIndexConfig())
+		resolver := NewResolver(idx)
+		resolver.MergeRefSiblings = merge
+		assert.Empty(t, resolver.Resolve())
+
+		path, _ := yamlpath.NewPath("$.paths./pets.get.responses.200.content.application/json.schema")
+		found, _ := path.Find(&rootNode)
+		require.Len(t, found, 1)
+		path, _ = yamlpath.NewPath("$.components.schemas.Pet")
+		pets, _ := path.Find(&rootNode)
+		require.Len(t, pets, 1)
+		return found[0], pets[0]
+	}
+	description := func(n *yaml.Node) string {
+		_, v := utils.FindKeyNodeTop("description", n.Content)
+		if v == nil {
+			return ""
+		}
+		return v.Value
+	}
+
+	// 3.1 siblings override the referenced schema.
+	schema, pet := resolve("3.1.0", true)
+	assert.Equal(t, "the pet that was found", description(schema))
+	_, readOnly := utils.FindKeyNodeTop("readOnly", schema.Content)
+	require.NotNil(t, readOnly)
+	assert.Equal(t, "true", readOnly.Value)
+	_, schemaType := utils.FindKeyNodeTop("type", schema.Content)
+	require.NotNil(t, schemaType)
+	assert.Equal(t, "object", schemaType.Value)
+	assert.Len(t, schema.Content, 6)
+	assert.Equal(t, "a pet", description(pet))
+
+	// 3.0 ignores siblings.
+	schema, _ = resolve("3.0.3", true)
+	assert.Equal(t, "a pet", description(schema))
+	assert.Len(t, schema.Content, 4)
+
+	// siblings are discarded unless merging is enabled.
+	schema, _ = resolve("3.1.0", false)
+	assert.Equal(t, "a pet", description(schema))
+	assert.Len(t, schema.Content, 4)
+}
+
 func TestResolver_ResolveComponents_MixedRef(t *testing.T) {
 	mixedref, _ := os.ReadFile("../test_specs/mixedref-burgershop.openapi.yaml")
 	var rootNode yaml.Node
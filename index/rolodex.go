@@ -32,6 +32,9 @@ type RolodexFile interface {
 	GetFileExtension() FileExtension
 	GetFullPath() string
 	GetErrors() []error
+
+	// GetContentAsYAMLNode returns the content of the file parsed as a *yaml.Node, it works the same for local and
+	// remote files, so there is no need to type-assert to *LocalFile or *RemoteFile.
 	GetContentAsYAMLNode() (*yaml.Node, error)
 	GetIndex() *SpecIndex
 	Name() string
@@ -42,6 +45,12 @@ type RolodexFile interface {
 	Mode() os.FileMode
 }
 
+var (
+	_ RolodexFile = &LocalFile{}
+	_ RolodexFile = &RemoteFile{}
+	_ RolodexFile = &rolodexFile{}
+)
+
 // RolodexFS is an interface that represents a RolodexFS, is the same interface as `fs.FS`, except it
 // also exposes a GetFiles() signature, to extract all files in the FS.
 type RolodexFS interface {
@@ -72,6 +81,8 @@ type Rolodex struct {
 	infiniteCircularReferences []*CircularReferenceResult
 	ignoredCircularReferences  []*CircularReferenceResult
 	logger                     *slog.Logger
+	watchInterval              time.Duration
+	reindexOnChange            bool
 }
 
 // NewRolodex creates a new rolodex with the provided index configuration.
@@ -136,12 +147,15 @@ func (r *Rolodex) GetCaughtErrors() []error {
 	return r.caughtErrors
 }
 
-// AddLocalFS adds a local file system to the rolodex.
+// AddLocalFS adds a local file system to the rolodex. If the file system was not configured with a logger of its
+// own (LocalFSConfig.Logger), it will use the logger of the rolodex.
 func (r *Rolodex) AddLocalFS(baseDir string, fileSystem fs.FS) {
 	absBaseDir, _ := filepath.Abs(baseDir)
 	if f, ok := fileSystem.(*LocalFS); ok {
 		f.rolodex = r
-		f.logger = r.logger
+		if f.fsConfig == nil || f.fsConfig.Logger == nil {
+			f.logger = r.logger
+		}
 	}
 	r.localFS[absBaseDir] = fileSystem
 }
@@ -167,11 +181,14 @@ func (r *Rolodex) AddIndex(idx *SpecIndex) {
 	}
 }
 
-// AddRemoteFS adds a remote file system to the rolodex.
+// AddRemoteFS adds a remote file system to the rolodex. If the file system was not configured with a logger of its
+// own (SpecIndexConfig.Logger), it will use the logger of the rolodex.
 func (r *Rolodex) AddRemoteFS(baseURL string, fileSystem fs.FS) {
 	if f, ok := fileSystem.(*RemoteFS); ok {
 		f.rolodex = r
-		f.logger = r.logger
+		if f.indexConfig == nil || f.indexConfig.Logger == nil {
+			f.logger = r.logger
+		}
 	}
 	r.remoteFS[baseURL] = fileSystem
 }
@@ -201,6 +218,12 @@ func (r *Rolodex) IndexTheRolodex() error {
 			copiedConfig := *r.indexConfig
 			copiedConfig.SpecAbsolutePath = fullPath
 			copiedConfig.AvoidBuildIndex = true // we will build out everything in two steps.
+			switch f := fs.(type) {
+			case *LocalFS:
+				copiedConfig.Logger = f.logger
+			case *RemoteFS:
+				copiedConfig.Logger = f.logger
+			}
 			idx, err := idxFile.Index(&copiedConfig)
 
 			if err != nil {
@@ -218,6 +241,7 @@ func (r *Rolodex) IndexTheRolodex() error {
 				if copiedConfig.IgnorePolymorphicCircularReferences {
 					resolver.IgnorePolymorphicCircularReferences()
 				}
+				resolver.MergeRefSiblings = copiedConfig.MergeRefSiblings
 				indexChan <- idx
 			}
 
@@ -299,7 +323,8 @@ func (r *Rolodex) IndexTheRolodex() error {
 
 		// if there is a base path, then we need to set the root spec config to point to a theoretical root.yaml
 		// which does not exist, but is used to formulate the absolute path to root references correctly.
-		if r.indexConfig.BasePath != "" && r.indexConfig.BaseURL == nil {
+		if r.indexConfig.BasePath != "" && r.indexConfig.BaseURL == nil &&
+			!strings.HasPrefix(r.indexConfig.SpecAbsolutePath, "http") {
 
 			basePath := r.indexConfig.BasePath
 			if !filepath.IsAbs(basePath) {
@@ -307,7 +332,14 @@ func (r *Rolodex) IndexTheRolodex() error {
 			}
 
 			if len(r.localFS) > 0 || len(r.remoteFS) > 0 {
-				r.indexConfig.SpecAbsolutePath = filepath.Join(basePath, "root.yaml")
+				// if the base path is the entry point file itself, relative references are resolved from its directory.
+				if r.indexConfig.SpecFilePath != "" {
+					r.indexConfig.SpecAbsolutePath = specFilePath(r.indexConfig, basePath)
+				} else if info, err := os.Stat(basePath); err == nil && !info.IsDir() {
+					r.indexConfig.SpecAbsolutePath = basePath
+				} else {
+					r.indexConfig.SpecAbsolutePath = filepath.Join(basePath, "root.yaml")
+				}
 			}
 		}
 
@@ -320,6 +352,7 @@ func (r *Rolodex) IndexTheRolodex() error {
 		if r.indexConfig.IgnorePolymorphicCircularReferences {
 			resolver.IgnorePolymorphicCircularReferences()
 		}
+		resolver.MergeRefSiblings = r.indexConfig.MergeRefSiblings
 		r.rootIndex = index
 		r.logger.Debug("[rolodex] starting root index build")
 		index.BuildIndex()
@@ -448,9 +481,14 @@ func (r *Rolodex) Open(location string) (RolodexFile, error) {
 
 		for k, v := range r.localFS {
 
-			// check if this is a URL or an abs/rel reference.
+			// check if this is a URL or an abs/rel reference, relative references are resolved from the
+			// entry point directory of a native rolodex FS.
 			if !filepath.IsAbs(location) {
-				fileLookup, _ = filepath.Abs(filepath.Join(k, location))
+				base := k
+				if lfs, ok := v.(*LocalFS); ok && lfs.relativeBase() != "" {
+					base = lfs.relativeBase()
+				}
+				fileLookup, _ = filepath.Abs(filepath.Join(base, location))
 			}
 
 			f, err := v.Open(fileLookup)
@@ -482,7 +520,7 @@ func (r *Rolodex) Open(location string) (RolodexFile, error) {
 					localFile = &LocalFile{
 						filename:     filepath.Base(fileLookup),
 						name:         filepath.Base(fileLookup),
-						extension:    ExtractFileType(fileLookup),
+						extension:    fileTypeOf(fileLookup, bytes),
 						data:         bytes,
 						fullPath:     fileLookup,
 						lastModified: s.ModTime(),
@@ -527,7 +565,7 @@ func (r *Rolodex) Open(location string) (RolodexFile, error) {
 						remoteFile = &RemoteFile{
 							filename:     filepath.Base(fileLookup),
 							name:         filepath.Base(fileLookup),
-							extension:    ExtractFileType(fileLookup),
+							extension:    fileTypeOf(fileLookup, bytes),
 							data:         bytes,
 							fullPath:     fileLookup,
 							lastModified: s.ModTime(),
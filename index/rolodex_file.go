@@ -45,14 +45,14 @@ func (rf *rolodexFile) Index(config *SpecIndexConfig) (*SpecIndex, error) {
 	}
 	var content []byte
 	if rf.localFile != nil {
-		content = rf.localFile.data
+		content = []byte(rf.localFile.GetContent())
 	}
 	if rf.remoteFile != nil {
-		content = rf.remoteFile.data
+		content = []byte(rf.remoteFile.GetContent())
 	}
 
 	// first, we must parse the content of the file
-	info, err := datamodel.ExtractSpecInfoWithDocumentCheckSync(content, config.SkipDocumentCheck)
+	info, err := extractSpecInfo(content, config.SkipDocumentCheck, config)
 	if err != nil {
 		return nil, err
 	}
@@ -64,12 +64,22 @@ func (rf *rolodexFile) Index(config *SpecIndexConfig) (*SpecIndex, error) {
 	return index, nil
 }
 
+// extractSpecInfo parses the content of a rolodex file, applying the document limits set in the index configuration.
+func extractSpecInfo(content []byte, bypass bool, config *SpecIndexConfig) (*datamodel.SpecInfo, error) {
+	return datamodel.ExtractSpecInfoWithConfig(content, &datamodel.DocumentConfiguration{
+		BypassDocumentCheck:    bypass,
+		MaxDocumentBytes:       config.MaxDocumentBytes,
+		MaxDocumentNodes:       config.MaxDocumentNodes,
+		MaxYAMLAliasExpansions: config.MaxYAMLAliasExpansions,
+	})
+}
+
 func (rf *rolodexFile) GetContent() string {
 	if rf.localFile != nil {
-		return string(rf.localFile.data)
+		return rf.localFile.GetContent()
 	}
 	if rf.remoteFile != nil {
-		return string(rf.remoteFile.data)
+		return rf.remoteFile.GetContent()
 	}
 	return ""
 }
@@ -106,10 +116,10 @@ func (rf *rolodexFile) GetFullPath() string {
 
 func (rf *rolodexFile) ModTime() time.Time {
 	if rf.localFile != nil {
-		return rf.localFile.lastModified
+		return rf.localFile.ModTime()
 	}
 	if rf.remoteFile != nil {
-		return rf.remoteFile.lastModified
+		return rf.remoteFile.GetLastModified()
 	}
 	return time.Now()
 }
@@ -44,6 +44,46 @@ func (l *LocalFS) GetFiles() map[string]RolodexFile {
 	return files
 }
 
+// relativeBase returns the directory relative paths are resolved from, which is the directory of the entry point (the
+// root document). That is the directory of the SpecAbsolutePath (or SpecFilePath) of the index configuration when
+// it's a local file, otherwise it's the base directory, or the directory it lives in if the base directory is a file.
+func (l *LocalFS) relativeBase() string {
+	if l.indexConfig != nil {
+		switch {
+		case l.indexConfig.SpecAbsolutePath != "" && !strings.HasPrefix(l.indexConfig.SpecAbsolutePath, "http"):
+			entryPoint, _ := filepath.Abs(l.indexConfig.SpecAbsolutePath)
+			return filepath.Dir(entryPoint)
+		case l.indexConfig.SpecFilePath != "":
+			return filepath.Dir(specFilePath(l.indexConfig, l.baseDirectory))
+		}
+	}
+	return l.entryPointDirectory
+}
+
+// specFilePath returns the absolute path of the SpecFilePath of config, which is relative to basePath.
+func specFilePath(config *SpecIndexConfig, basePath string) string {
+	p := config.SpecFilePath
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(basePath, p)
+	}
+	p, _ = filepath.Abs(p)
+	return p
+}
+
+// GetRootIndex returns the index of the entry-point file of the LocalFS, once it has been built. The entry point is
+// the SpecAbsolutePath of the index configuration, or the base directory if it's a single file. Returns nil if there
+// is no entry-point file in the LocalFS, or it has not been indexed yet. It will never build an index.
+func (l *LocalFS) GetRootIndex() *SpecIndex {
+	entryPoint := l.baseDirectory
+	if l.indexConfig != nil && l.indexConfig.SpecAbsolutePath != "" {
+		entryPoint = l.indexConfig.SpecAbsolutePath
+	}
+	if f, ok := l.Files.Load(entryPoint); ok {
+		return f.(*LocalFile).GetIndex()
+	}
+	return nil
+}
+
 // GetErrors returns any errors that occurred during the indexing process.
 func (l *LocalFS) GetErrors() []error {
 	return l.readingErrors
@@ -67,7 +107,15 @@ func (l *LocalFS) Open(name string) (fs.File, error) {
 	}
 
 	if !filepath.IsAbs(name) {
-		name, _ = filepath.Abs(filepath.Join(l.baseDirectory, name))
+		name, _ = filepath.Abs(filepath.Join(l.relativeBase(), name))
+	}
+
+	if l.fsConfig != nil && l.fsConfig.RestrictToBaseDirectory {
+		if err := l.checkWithinBaseDirectory(name); err != nil {
+			l.logger.Error("[rolodex file loader]: file is outside of the base directory", "file", name,
+				"baseDirectory", l.baseDirectory)
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
 	}
 
 	if f, ok := l.Files.Load(name); ok {
@@ -114,6 +162,7 @@ func (l *LocalFS) Open(name string) (fs.File, error) {
 					copiedCfg := *l.indexConfig
 					copiedCfg.SpecAbsolutePath = name
 					copiedCfg.AvoidBuildIndex = true
+					copiedCfg.Logger = l.logger
 
 					idx, idxError := extractedFile.Index(&copiedCfg)
 
@@ -170,22 +219,34 @@ type LocalFile struct {
 	index         *SpecIndex
 	parsed        *yaml.Node
 	offset        int64
+
+	// contentLock guards data, parsed, index and lastModified, which are replaced when the file is re-indexed.
+	contentLock sync.RWMutex
 }
 
-// GetIndex returns the *SpecIndex for the file.
+// GetIndex returns the *SpecIndex for the file, or nil if the file has not been indexed yet.
 func (l *LocalFile) GetIndex() *SpecIndex {
+	l.contentLock.RLock()
+	defer l.contentLock.RUnlock()
 	return l.index
 }
 
+// IsIndexed returns true if the file has been indexed (Index has been called successfully).
+func (l *LocalFile) IsIndexed() bool {
+	return l.GetIndex() != nil
+}
+
 // Index returns the *SpecIndex for the file. If the index has not been created, it will be created (indexed)
 func (l *LocalFile) Index(config *SpecIndexConfig) (*SpecIndex, error) {
-	if l.index != nil {
-		return l.index, nil
+	l.contentLock.RLock()
+	existing, content := l.index, l.data
+	l.contentLock.RUnlock()
+	if existing != nil {
+		return existing, nil
 	}
-	content := l.data
 
 	// first, we must parse the content of the file
-	info, err := datamodel.ExtractSpecInfoWithDocumentCheckSync(content, true)
+	info, err := extractSpecInfo(content, true, config)
 	if err != nil {
 		return nil, err
 	}
@@ -193,18 +254,44 @@ func (l *LocalFile) Index(config *SpecIndexConfig) (*SpecIndex, error) {
 	index := NewSpecIndexWithConfig(info.RootNode, config)
 	index.specAbsolutePath = l.fullPath
 
+	l.contentLock.Lock()
+	defer l.contentLock.Unlock()
+	if l.index == nil {
+		l.index = index
+	}
+	return l.index, nil
+}
+
+// reindex replaces the content of the file with data, and creates a new (unbuilt) index for it using config. The file
+// is left as it was if data can't be parsed.
+func (l *LocalFile) reindex(data []byte, config *SpecIndexConfig) (*SpecIndex, error) {
+	info, err := extractSpecInfo(data, true, config)
+	if err != nil {
+		return nil, err
+	}
+	index := NewSpecIndexWithConfig(info.RootNode, config)
+	index.specAbsolutePath = l.fullPath
+	l.contentLock.Lock()
+	l.data = data
+	l.parsed = nil
 	l.index = index
+	l.lastModified = time.Now()
+	l.contentLock.Unlock()
 	return index, nil
 }
 
 // GetContent returns the content of the file as a string.
 func (l *LocalFile) GetContent() string {
+	l.contentLock.RLock()
+	defer l.contentLock.RUnlock()
 	return string(l.data)
 }
 
 // GetContentAsYAMLNode returns the content of the file as a *yaml.Node. If something went wrong
 // then an error is returned.
 func (l *LocalFile) GetContentAsYAMLNode() (*yaml.Node, error) {
+	l.contentLock.Lock()
+	defer l.contentLock.Unlock()
 	if l.parsed != nil {
 		return l.parsed, nil
 	}
@@ -253,6 +340,8 @@ func (l *LocalFile) Name() string {
 
 // Size returns the size of the file.
 func (l *LocalFile) Size() int64 {
+	l.contentLock.RLock()
+	defer l.contentLock.RUnlock()
 	return int64(len(l.data))
 }
 
@@ -263,6 +352,8 @@ func (l *LocalFile) Mode() fs.FileMode {
 
 // ModTime returns the modification time of the file.
 func (l *LocalFile) ModTime() time.Time {
+	l.contentLock.RLock()
+	defer l.contentLock.RUnlock()
 	return l.lastModified
 }
 
@@ -304,7 +395,8 @@ type LocalFSConfig struct {
 	// the base directory to index
 	BaseDirectory string
 
-	// supply your own logger
+	// Logger is used to log everything the LocalFS does, and by the index of every file it loads. If not set, the
+	// logger of the rolodex the LocalFS is added to is used (or a default logger, set to the Error level).
 	Logger *slog.Logger
 
 	// supply a list of specific files to index only
@@ -315,6 +407,15 @@ type LocalFSConfig struct {
 
 	// supply an index configuration to use
 	IndexConfig *SpecIndexConfig
+
+	// RestrictToBaseDirectory will reject any file that resolves to a location outside the base directory
+	// (for example a reference to '../../../../etc/passwd'). Use this when indexing untrusted specifications.
+	RestrictToBaseDirectory bool
+
+	// FollowSymlinks will collect the targets of symbolic links found when walking the DirFS, symlinked directories
+	// are walked as well. A link to a directory that has already been walked is not followed, so circular links
+	// cannot cause an endless walk. If not set, symbolic links are skipped (and logged).
+	FollowSymlinks bool
 }
 
 // NewLocalFSWithConfig creates a new LocalFS with the supplied configuration.
@@ -335,21 +436,74 @@ func NewLocalFSWithConfig(config *LocalFSConfig) (*LocalFS, error) {
 	var absBaseDir string
 	absBaseDir, _ = filepath.Abs(config.BaseDirectory)
 
+	// relative references are resolved from the directory of the entry point, so if the base directory is a file,
+	// use the directory the file lives in (unless the index configuration locates the entry point, see relativeBase).
+	entryPointDirectory := absBaseDir
+	if info, err := os.Stat(absBaseDir); err == nil && !info.IsDir() {
+		entryPointDirectory = filepath.Dir(absBaseDir)
+	}
+
 	localFS := &LocalFS{
 		indexConfig:         config.IndexConfig,
 		fsConfig:            config,
 		logger:              log,
 		baseDirectory:       absBaseDir,
-		entryPointDirectory: config.BaseDirectory,
+		entryPointDirectory: entryPointDirectory,
 	}
 
 	// if a directory filesystem is supplied, use that to walk the directory and pick up everything it finds.
 	if config.DirFS != nil {
-		walkErr := fs.WalkDir(config.DirFS, ".", func(p string, d fs.DirEntry, err error) error {
+		// when following symbolic links, the real path of every directory (and linked file) visited is tracked, so
+		// links are never followed in circles, and link targets are only collected once.
+		visited := make(map[string]bool)
+		realBase := absBaseDir
+		if resolved, err := filepath.EvalSymlinks(absBaseDir); err == nil {
+			realBase = resolved
+		}
+
+		var walk fs.WalkDirFunc
+		walk = func(p string, d fs.DirEntry, err error) error {
 			if err != nil {
 				return err
 			}
 
+			isLink := d.Type()&fs.ModeSymlink != 0
+			if isLink && !config.FollowSymlinks {
+				log.Info("[rolodex file loader]: skipping symbolic link, FollowSymlinks is not enabled", "file", p)
+				return nil
+			}
+			if config.FollowSymlinks && (isLink || d.IsDir()) {
+				target, evalErr := filepath.EvalSymlinks(filepath.Join(absBaseDir, p))
+				if evalErr != nil {
+					if isLink {
+						log.Warn("[rolodex file loader]: skipping symbolic link that cannot be resolved", "file", p,
+							"error", evalErr)
+						return nil
+					}
+					target = filepath.Join(realBase, p)
+				}
+				if visited[target] && target != filepath.Join(realBase, p) {
+					log.Debug("[rolodex file loader]: skipping symbolic link, the target has already been visited",
+						"file", p, "target", target)
+					if d.IsDir() {
+						return fs.SkipDir
+					}
+					return nil
+				}
+				if isLink {
+					info, statErr := fs.Stat(config.DirFS, p)
+					if statErr != nil {
+						log.Warn("[rolodex file loader]: skipping broken symbolic link", "file", p, "error", statErr)
+						return nil
+					}
+					if info.IsDir() {
+						// fs.WalkDir does not follow links, so walk the linked directory on its own.
+						return fs.WalkDir(config.DirFS, p, walk)
+					}
+				}
+				visited[target] = true
+			}
+
 			// we don't care about directories, or errors, just read everything we can.
 			if d.IsDir() {
 				if d.Name() != config.BaseDirectory {
@@ -369,7 +523,8 @@ func NewLocalFSWithConfig(config *LocalFSConfig) (*LocalFS, error) {
 			}
 			_, fErr := localFS.extractFile(p)
 			return fErr
-		})
+		}
+		walkErr := fs.WalkDir(config.DirFS, ".", walk)
 
 		if walkErr != nil {
 			return nil, walkErr
@@ -380,6 +535,35 @@ func NewLocalFSWithConfig(config *LocalFSConfig) (*LocalFS, error) {
 	return localFS, nil
 }
 
+// checkWithinBaseDirectory returns an error if the cleaned, absolute path is not inside the base directory.
+// Symbolic links are followed (if they exist) so a link cannot be used to escape the base directory either.
+func (l *LocalFS) checkWithinBaseDirectory(name string) error {
+	base := l.baseDirectory
+	if info, err := os.Stat(base); err == nil && !info.IsDir() {
+		base = filepath.Dir(base)
+	}
+	if resolved, err := filepath.EvalSymlinks(base); err == nil {
+		base = resolved
+	}
+	target := filepath.Clean(name)
+	if resolved, err := filepath.EvalSymlinks(target); err == nil {
+		target = resolved
+	}
+	rel, err := filepath.Rel(base, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("file '%s' is outside of the base directory '%s' and cannot be opened", name, l.baseDirectory)
+	}
+	return nil
+}
+
+// maxDocumentBytes returns the MaxDocumentBytes limit from the index configuration, or zero if there is none.
+func (l *LocalFS) maxDocumentBytes() int64 {
+	if l.indexConfig != nil {
+		return l.indexConfig.MaxDocumentBytes
+	}
+	return 0
+}
+
 func (l *LocalFS) extractFile(p string) (*LocalFile, error) {
 	extension := ExtractFileType(p)
 	var readingErrors []error
@@ -387,15 +571,18 @@ func (l *LocalFS) extractFile(p string) (*LocalFile, error) {
 	config := l.fsConfig
 	if !filepath.IsAbs(p) {
 		if config != nil && config.BaseDirectory != "" {
-			abs, _ = filepath.Abs(filepath.Join(config.BaseDirectory, p))
+			abs, _ = filepath.Abs(filepath.Join(l.relativeBase(), p))
 		} else {
 			abs, _ = filepath.Abs(p)
 		}
 	}
 	var fileData []byte
 
-	switch extension {
-	case YAML, JSON:
+	// files without an extension are read and sniffed, to work out if they contain JSON or YAML.
+	sniff := extension == UNSUPPORTED && filepath.Ext(p) == ""
+
+	switch {
+	case extension == YAML, extension == JSON, sniff:
 		var file fs.File
 		var fileError error
 		if config != nil && config.DirFS != nil {
@@ -420,13 +607,28 @@ func (l *LocalFS) extractFile(p string) (*LocalFile, error) {
 		stat, _ := file.Stat()
 		if stat != nil {
 			modTime = stat.ModTime()
+
+			// refuse to read anything larger than the configured limit.
+			if sizeErr := datamodel.CheckDocumentSize(stat.Size(), l.maxDocumentBytes()); sizeErr != nil {
+				_ = file.Close()
+				l.logger.Error("[rolodex file loader]: file is too large to be read", "file", abs,
+					"size", stat.Size())
+				return nil, fmt.Errorf("unable to read file '%s': %w", abs, sizeErr)
+			}
 		}
 		fileData, _ = io.ReadAll(file)
 
+		if sniff {
+			if extension = SniffFileType(fileData); extension == UNSUPPORTED {
+				l.logger.Debug("[rolodex file loader]: skipping file without JSON/YAML content", "file", abs)
+				return nil, nil
+			}
+		}
+
 		lf := &LocalFile{
 			filename:      p,
 			name:          filepath.Base(p),
-			extension:     ExtractFileType(p),
+			extension:     extension,
 			data:          fileData,
 			fullPath:      abs,
 			lastModified:  modTime,
@@ -434,7 +636,7 @@ func (l *LocalFS) extractFile(p string) (*LocalFile, error) {
 		}
 		l.Files.Store(abs, lf)
 		return lf, nil
-	case UNSUPPORTED:
+	default:
 		if config != nil && config.DirFS != nil {
 			l.logger.Debug("[rolodex file loader]: skipping non JSON/YAML file", "file", abs)
 		}
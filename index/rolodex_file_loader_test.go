@@ -4,6 +4,7 @@
 package index
 
 import (
+	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"testing/fstest"
 	"time"
@@ -327,3 +329,198 @@ func TestRecursiveLocalFile_MultipleRequests(t *testing.T) {
 		completed++
 	}
 }
+
+func TestRolodexLocalFS_RestrictToBaseDirectory(t *testing.T) {
+
+	tmp := t.TempDir()
+	base := filepath.Join(tmp, "specs")
+	_ = os.Mkdir(base, 0o755)
+	_ = os.WriteFile(filepath.Join(base, "spec.yaml"), []byte("hello: world"), 0o644)
+	_ = os.WriteFile(filepath.Join(tmp, "secret.yaml"), []byte("password: pizza"), 0o644)
+
+	cf := CreateOpenAPIIndexConfig()
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory:           base,
+		IndexConfig:             cf,
+		RestrictToBaseDirectory: true,
+	})
+	assert.NoError(t, err)
+
+	f, e := fileFS.Open("spec.yaml")
+	assert.NoError(t, e)
+	assert.NotNil(t, f)
+
+	f, e = fileFS.Open("../secret.yaml")
+	assert.Nil(t, f)
+	assert.Error(t, e)
+	assert.Contains(t, e.Error(), "is outside of the base directory")
+
+	f, e = fileFS.Open("../../../../../../etc/passwd")
+	assert.Nil(t, f)
+	assert.Error(t, e)
+
+	f, e = fileFS.Open(filepath.Join(tmp, "secret.yaml"))
+	assert.Nil(t, f)
+	assert.Error(t, e)
+
+	// a sibling directory that shares the base directory as a prefix is still outside.
+	f, e = fileFS.Open(filepath.Join(tmp, "specs-other", "spec.yaml"))
+	assert.Nil(t, f)
+	assert.Error(t, e)
+	assert.Contains(t, e.Error(), "is outside of the base directory")
+}
+
+func TestRolodexLocalFS_RestrictToBaseDirectory_Symlink(t *testing.T) {
+
+	tmp := t.TempDir()
+	base := filepath.Join(tmp, "specs")
+	_ = os.Mkdir(base, 0o755)
+	_ = os.WriteFile(filepath.Join(tmp, "secret.yaml"), []byte("password: pizza"), 0o644)
+	if err := os.Symlink(filepath.Join(tmp, "secret.yaml"), filepath.Join(base, "link.yaml")); err != nil {
+		t.Skip("symlinks are not supported")
+	}
+
+	fileFS, _ := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory:           base,
+		IndexConfig:             CreateOpenAPIIndexConfig(),
+		RestrictToBaseDirectory: true,
+	})
+
+	f, e := fileFS.Open("link.yaml")
+	assert.Nil(t, f)
+	assert.Error(t, e)
+}
+
+func TestRolodexLocalFS_NoRestriction(t *testing.T) {
+
+	tmp := t.TempDir()
+	base := filepath.Join(tmp, "specs")
+	_ = os.Mkdir(base, 0o755)
+	_ = os.WriteFile(filepath.Join(tmp, "other.yaml"), []byte("hello: world"), 0o644)
+
+	fileFS, _ := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: base,
+		IndexConfig:   CreateOpenAPIIndexConfig(),
+	})
+
+	f, e := fileFS.Open("../other.yaml")
+	assert.NoError(t, e)
+	assert.NotNil(t, f)
+}
+
+func TestRolodexLocalFS_MaxDocumentBytes(t *testing.T) {
+
+	tmp := t.TempDir()
+	_ = os.WriteFile(filepath.Join(tmp, "small.yaml"), []byte("hello: world"), 0o644)
+	_ = os.WriteFile(filepath.Join(tmp, "big.yaml"), []byte("hello: "+strings.Repeat("x", 128)), 0o644)
+
+	cf := CreateOpenAPIIndexConfig()
+	cf.MaxDocumentBytes = 64
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: tmp,
+		IndexConfig:   cf,
+	})
+	assert.NoError(t, err)
+
+	f, e := fileFS.Open("small.yaml")
+	assert.NoError(t, e)
+	assert.NotNil(t, f)
+
+	f, e = fileFS.Open("big.yaml")
+	assert.Nil(t, f)
+	assert.ErrorIs(t, e, datamodel.ErrDocumentTooLarge)
+}
+
+func TestRolodexLocalFS_FollowSymlinks(t *testing.T) {
+
+	tmp := t.TempDir()
+	base := filepath.Join(tmp, "specs")
+	shared := filepath.Join(tmp, "shared")
+	_ = os.Mkdir(base, 0o755)
+	_ = os.Mkdir(shared, 0o755)
+	_ = os.WriteFile(filepath.Join(base, "spec.yaml"), []byte("hello: world"), 0o644)
+	_ = os.WriteFile(filepath.Join(shared, "schema.yaml"), []byte("type: string"), 0o644)
+	if err := os.Symlink(shared, filepath.Join(base, "shared")); err != nil {
+		t.Skip("symlinks are not supported")
+	}
+	_ = os.Symlink(filepath.Join(shared, "schema.yaml"), filepath.Join(base, "schema.yaml"))
+	_ = os.Symlink(base, filepath.Join(base, "loop"))
+	_ = os.Symlink(filepath.Join(tmp, "missing.yaml"), filepath.Join(base, "broken.yaml"))
+
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: base,
+		DirFS:         os.DirFS(base),
+		IndexConfig:   CreateOpenAPIIndexConfig(),
+	})
+	assert.NoError(t, err)
+	files := fileFS.GetFiles()
+	assert.Len(t, files, 1)
+	assert.Contains(t, files, filepath.Join(base, "spec.yaml"))
+
+	fileFS, err = NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory:  base,
+		DirFS:          os.DirFS(base),
+		IndexConfig:    CreateOpenAPIIndexConfig(),
+		FollowSymlinks: true,
+	})
+	assert.NoError(t, err)
+	files = fileFS.GetFiles()
+	assert.Len(t, files, 3) // the loop is not followed.
+	assert.Contains(t, files, filepath.Join(base, "spec.yaml"))
+	assert.Contains(t, files, filepath.Join(base, "schema.yaml"))
+	assert.Contains(t, files, filepath.Join(base, "shared", "schema.yaml"))
+}
+
+func TestRolodexLocalFS_GetRootIndex(t *testing.T) {
+
+	testFS := fstest.MapFS{
+		"spec.yaml":  {Data: []byte("openapi: 3.1.0"), ModTime: time.Now()},
+		"other.yaml": {Data: []byte("type: string"), ModTime: time.Now()},
+	}
+
+	cf := CreateOpenAPIIndexConfig()
+	cf.SpecAbsolutePath, _ = filepath.Abs("spec.yaml")
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: ".",
+		DirFS:         testFS,
+		IndexConfig:   cf,
+	})
+	assert.NoError(t, err)
+
+	f, _ := fileFS.Open("spec.yaml")
+	lf := f.(*LocalFile)
+	assert.False(t, lf.IsIndexed())
+	assert.Nil(t, lf.GetIndex())
+	assert.Nil(t, fileFS.GetRootIndex())
+
+	idx, err := lf.Index(cf)
+	assert.NoError(t, err)
+	assert.True(t, lf.IsIndexed())
+	assert.Equal(t, idx, lf.GetIndex())
+	assert.Equal(t, idx, fileFS.GetRootIndex())
+
+	other, _ := fileFS.Open("other.yaml")
+	assert.False(t, other.(*LocalFile).IsIndexed())
+}
+
+func TestRolodexLocalFS_SniffNoExtension(t *testing.T) {
+
+	testFS := fstest.MapFS{
+		"spec.yaml": {Data: []byte("openapi: 3.1.0"), ModTime: time.Now()},
+		"pet":       {Data: []byte(`{"type": "object"}`), ModTime: time.Now()},
+		"error":     {Data: []byte("type: object"), ModTime: time.Now()},
+		"LICENSE":   {Data: []byte("MIT License"), ModTime: time.Now()},
+		"logo.png":  {Data: []byte("type: object"), ModTime: time.Now()},
+	}
+
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: "/",
+		DirFS:         testFS,
+	})
+	assert.NoError(t, err)
+
+	files := fileFS.GetFiles()
+	assert.Len(t, files, 3)
+	assert.Equal(t, JSON, files[filepath.Join("/", "pet")].GetFileExtension())
+	assert.Equal(t, YAML, files[filepath.Join("/", "error")].GetFileExtension())
+}
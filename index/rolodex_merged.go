@@ -0,0 +1,125 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"strings"
+	"sync"
+)
+
+// MergedIndex returns a single SpecIndex that presents the references, components, schemas and paths of every file
+// loaded by the rolodex (the root document first), so the whole set of documents can be queried in one place.
+//
+// References are keyed by their full definition, which is already absolute. Components are keyed by the absolute
+// location of the file they are defined in, followed by their definition, for example
+// '/specs/pets.yaml#/components/schemas/Pet'. Paths are keyed by their path, a path defined by more than one file
+// is the one found first.
+//
+// The merged index is a read-only snapshot made when MergedIndex is called: it is not updated as files are loaded
+// or re-indexed. Its maps are new, but the *Reference values in them (and the operations of each path) are shared
+// with the indexes it was made from, so they must not be changed. It has no root node, so anything that walks the
+// document is not available, use the index of each file for those. The exception is GetPathCount, GetOperationCount
+// and GetComponentSchemaCount, which count the merged paths, their operations and the merged component schemas.
+// Returns nil if the rolodex has not been indexed.
+func (r *Rolodex) MergedIndex() *SpecIndex {
+	if !r.indexed {
+		return nil
+	}
+	merged := new(SpecIndex)
+	boostrapIndexCollections(merged)
+	merged.config = r.indexConfig
+	merged.rolodex = r
+	merged.cache = new(sync.Map)
+	merged.built = true
+	merged.merged = true
+
+	var indexes []*SpecIndex
+	if r.rootIndex != nil {
+		indexes = append(indexes, r.rootIndex)
+		merged.specAbsolutePath = r.rootIndex.specAbsolutePath
+		merged.logger = r.rootIndex.logger
+	}
+	r.indexLock.Lock()
+	for _, idx := range r.indexes {
+		if idx != r.rootIndex {
+			indexes = append(indexes, idx)
+		}
+	}
+	r.indexLock.Unlock()
+
+	for _, idx := range indexes {
+		merged.mergeIndex(idx)
+	}
+
+	merged.pathCount = len(merged.pathRefs)
+	for _, operations := range merged.pathRefs {
+		merged.operationCount += len(operations)
+	}
+	merged.allComponentSchemaDefinitions.Range(func(_, _ any) bool {
+		merged.schemaCount++
+		return true
+	})
+	return merged
+}
+
+// mergeIndex copies the references, components, schemas and paths of idx into the merged index.
+func (index *SpecIndex) mergeIndex(idx *SpecIndex) {
+	absolute := func(definition string) string {
+		if strings.HasPrefix(definition, "#") {
+			return idx.specAbsolutePath + definition
+		}
+		return definition
+	}
+	mergeMap := func(into, from map[string]*Reference) {
+		for k, v := range from {
+			if _, ok := into[absolute(k)]; !ok {
+				into[absolute(k)] = v
+			}
+		}
+	}
+
+	idx.refLock.Lock()
+	mergeMap(index.allRefs, idx.allRefs)
+	mergeMap(index.allMappedRefs, idx.allMappedRefs)
+	mergeMap(index.polymorphicRefs, idx.polymorphicRefs)
+	index.rawSequencedRefs = append(index.rawSequencedRefs, idx.rawSequencedRefs...)
+	index.allMappedRefsSequenced = append(index.allMappedRefsSequenced, idx.allMappedRefsSequenced...)
+	idx.refLock.Unlock()
+
+	index.polymorphicAllOfRefs = append(index.polymorphicAllOfRefs, idx.polymorphicAllOfRefs...)
+	index.polymorphicOneOfRefs = append(index.polymorphicOneOfRefs, idx.polymorphicOneOfRefs...)
+	index.polymorphicAnyOfRefs = append(index.polymorphicAnyOfRefs, idx.polymorphicAnyOfRefs...)
+
+	if idx.allComponentSchemaDefinitions != nil {
+		idx.allComponentSchemaDefinitions.Range(func(k, v any) bool {
+			index.allComponentSchemaDefinitions.LoadOrStore(absolute(k.(string)), v)
+			return true
+		})
+	}
+	mergeMap(index.allParameters, idx.allParameters)
+	mergeMap(index.allSecuritySchemes, idx.allSecuritySchemes)
+	mergeMap(index.allRequestBodies, idx.allRequestBodies)
+	mergeMap(index.allResponses, idx.allResponses)
+	mergeMap(index.allHeaders, idx.allHeaders)
+	mergeMap(index.allExamples, idx.allExamples)
+	mergeMap(index.allLinks, idx.allLinks)
+	mergeMap(index.allCallbacks, idx.allCallbacks)
+
+	index.allRefSchemaDefinitions = append(index.allRefSchemaDefinitions, idx.allRefSchemaDefinitions...)
+	index.allInlineSchemaDefinitions = append(index.allInlineSchemaDefinitions, idx.allInlineSchemaDefinitions...)
+	index.allInlineSchemaObjectDefinitions = append(index.allInlineSchemaObjectDefinitions,
+		idx.allInlineSchemaObjectDefinitions...)
+
+	for path, operations := range idx.pathRefs {
+		if _, ok := index.pathRefs[path]; !ok {
+			index.pathRefs[path] = operations
+		}
+	}
+
+	index.allDescriptions = append(index.allDescriptions, idx.allDescriptions...)
+	index.allSummaries = append(index.allSummaries, idx.allSummaries...)
+	index.allEnums = append(index.allEnums, idx.allEnums...)
+	index.allObjectsWithProperties = append(index.allObjectsWithProperties, idx.allObjectsWithProperties...)
+	index.refErrors = append(index.refErrors, idx.refErrors...)
+}
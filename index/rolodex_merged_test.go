@@ -0,0 +1,112 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRolodex_MergedIndex(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"types.yaml": `Foo:
+  type: string`,
+		"pets.yaml": `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          $ref: 'types.yaml#/Foo'`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	root := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                $ref: 'pets.yaml#/components/schemas/Pet'
+components:
+  schemas:
+    Owner:
+      type: object`
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(root), &rootNode)
+
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.BasePath = dir
+	cfg.SpecAbsolutePath = filepath.Join(dir, "root.yaml")
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: dir,
+		IndexConfig:   cfg,
+		DirFS:         os.DirFS(dir),
+	})
+	require.NoError(t, err)
+
+	rolo := NewRolodex(cfg)
+	rolo.AddLocalFS(dir, fileFS)
+	rolo.SetRootNode(&rootNode)
+	assert.Nil(t, rolo.MergedIndex())
+	require.NoError(t, rolo.IndexTheRolodex())
+
+	merged := rolo.MergedIndex()
+	require.NotNil(t, merged)
+	assert.Same(t, rolo, merged.GetRolodex())
+	assert.Nil(t, merged.GetRootNode())
+
+	typesPath, _ := filepath.Abs(filepath.Join(dir, "types.yaml"))
+	petsPath, _ := filepath.Abs(filepath.Join(dir, "pets.yaml"))
+
+	// references made by every file.
+	refs := merged.GetAllReferences()
+	assert.Contains(t, refs, petsPath+"#/components/schemas/Pet")
+	assert.Contains(t, refs, typesPath+"#/Foo")
+	assert.Contains(t, merged.GetMappedReferences(), typesPath+"#/Foo")
+
+	// components of every file, under their absolute keys.
+	schemas := merged.GetAllComponentSchemas()
+	assert.Contains(t, schemas, filepath.Join(dir, "root.yaml")+"#/components/schemas/Owner")
+	assert.Contains(t, schemas, petsPath+"#/components/schemas/Pet")
+
+	assert.Contains(t, merged.GetAllPaths(), "/pets")
+
+	// counts are made from the merged paths and components.
+	assert.Equal(t, 1, merged.GetPathCount())
+	assert.Equal(t, 1, merged.GetOperationCount())
+	assert.Equal(t, 2, merged.GetComponentSchemaCount())
+
+	// references can be searched for across every file.
+	ref, foundIdx := merged.SearchIndexForReference(typesPath + "#/Foo")
+	require.NotNil(t, ref)
+	assert.Equal(t, "Foo", ref.Name)
+	assert.Equal(t, typesPath, foundIdx.GetSpecAbsolutePath())
+	ref, _ = merged.SearchIndexForReference(petsPath + "#/components/schemas/Pet")
+	require.NotNil(t, ref)
+	assert.Equal(t, "Pet", ref.Name)
+
+	// the merged index is a copy.
+	delete(merged.GetAllReferences(), typesPath+"#/Foo")
+	found := false
+	for _, idx := range rolo.GetIndexes() {
+		if _, ok := idx.GetAllReferences()[typesPath+"#/Foo"]; ok {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
@@ -4,8 +4,12 @@
 package index
 
 import (
+	"bytes"
 	"fmt"
+	"path/filepath"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -56,3 +60,33 @@ func ExtractFileType(ref string) FileExtension {
 	}
 	return UNSUPPORTED
 }
+
+// SniffFileType returns the type of a file by looking at its content, it's used when a file has no extension.
+// Content that starts with '{' or '[' (ignoring whitespace) is JSON, content that parses as a YAML mapping or sequence
+// is YAML. Anything else is UNSUPPORTED.
+func SniffFileType(content []byte) FileExtension {
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 {
+		return UNSUPPORTED
+	}
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		return JSON
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(trimmed, &node); err != nil || len(node.Content) == 0 {
+		return UNSUPPORTED
+	}
+	if k := node.Content[0].Kind; k == yaml.MappingNode || k == yaml.SequenceNode {
+		return YAML
+	}
+	return UNSUPPORTED
+}
+
+// fileTypeOf returns the type of a file from its location, the content is sniffed if the location has no extension.
+func fileTypeOf(location string, content []byte) FileExtension {
+	ext := ExtractFileType(location)
+	if ext == UNSUPPORTED && filepath.Ext(location) == "" {
+		return SniffFileType(content)
+	}
+	return ext
+}
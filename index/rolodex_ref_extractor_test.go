@@ -33,3 +33,13 @@ func TestExtractedRef_GetReference(t *testing.T) {
 	assert.Equal(t, "#/components/schemas/One", a.GetReference())
 
 }
+
+func TestSniffFileType(t *testing.T) {
+	assert.Equal(t, JSON, SniffFileType([]byte(`  {"openapi": "3.1.0"}`)))
+	assert.Equal(t, JSON, SniffFileType([]byte("\n[1, 2]")))
+	assert.Equal(t, YAML, SniffFileType([]byte("openapi: 3.1.0")))
+	assert.Equal(t, YAML, SniffFileType([]byte("- one\n- two")))
+	assert.Equal(t, UNSUPPORTED, SniffFileType([]byte("just some text")))
+	assert.Equal(t, UNSUPPORTED, SniffFileType([]byte("a: b: c")))
+	assert.Equal(t, UNSUPPORTED, SniffFileType([]byte(" \n ")))
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pb33f/libopenapi/utils"
+)
+
+// FilesReferencing returns the absolute path (or URL) of every indexed file that contains a reference to target,
+// sorted alphabetically. The rolodex must have been indexed first.
+//
+// The target is canonicalized in the same way as references are: a relative path (e.g. 'types.yaml#/Foo') is
+// resolved against the base path (or base URL) of the rolodex, and a target that is only a fragment
+// (e.g. '#/components/schemas/Foo') is a location in the root document. When target has no fragment, any reference
+// to the file, or to a location in it, is a match.
+func (r *Rolodex) FilesReferencing(target string) []string {
+	file, fragment, hasFragment := strings.Cut(r.canonicalLocation(target), "#")
+
+	indexes := r.GetIndexes()
+	if r.rootIndex != nil {
+		indexes = append([]*SpecIndex{r.rootIndex}, indexes...)
+	}
+	seen := make(map[string]bool)
+	var files []string
+	for _, idx := range indexes {
+		path := idx.GetSpecAbsolutePath()
+		if seen[path] {
+			continue
+		}
+		for _, ref := range idx.GetRawReferencesSequenced() {
+			refFile, refFragment, _ := strings.Cut(ref.FullDefinition, "#")
+			if refFile == file && (!hasFragment || refFragment == fragment) {
+				seen[path] = true
+				files = append(files, path)
+				break
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// canonicalLocation returns the absolute path (or URL) of location, resolving it in the same way as a reference in
+// the root document.
+func (r *Rolodex) canonicalLocation(location string) string {
+	file, fragment, hasFragment := strings.Cut(location, "#")
+	rootPath := ""
+	if r.rootIndex != nil {
+		rootPath = r.rootIndex.GetSpecAbsolutePath()
+	}
+	switch {
+	case file == "":
+		file = rootPath
+	case strings.HasPrefix(file, "http"):
+	case filepath.IsAbs(file):
+		file = filepath.Clean(file)
+	case strings.HasPrefix(rootPath, "http") || (r.indexConfig.BaseURL != nil && r.indexConfig.BasePath == ""):
+		var u url.URL
+		if strings.HasPrefix(rootPath, "http") {
+			up, _ := url.Parse(rootPath)
+			up.Path = utils.ReplaceWindowsDriveWithLinuxPath(filepath.Dir(up.Path))
+			u = *up
+		} else {
+			u = *r.indexConfig.BaseURL
+		}
+		u.Path = utils.ReplaceWindowsDriveWithLinuxPath(utils.CheckPathOverlap(u.Path, file, string(os.PathSeparator)))
+		file = u.String()
+	default:
+		base := r.indexConfig.BasePath
+		if rootPath != "" {
+			base = filepath.Dir(rootPath)
+		}
+		file, _ = filepath.Abs(utils.CheckPathOverlap(base, file, string(os.PathSeparator)))
+	}
+	if hasFragment {
+		return file + "#" + fragment
+	}
+	return file
+}
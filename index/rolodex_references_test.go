@@ -0,0 +1,85 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRolodex_FilesReferencing(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"types.yaml": `Foo:
+  type: object
+  properties:
+    bar:
+      $ref: '#/Bar'
+Bar:
+  type: string`,
+		"pets.yaml": `Pet:
+  type: object
+  properties:
+    foo:
+      $ref: 'types.yaml#/Foo'`,
+		"owners.yaml": `Owner:
+  type: object
+  properties:
+    bar:
+      $ref: './types.yaml#/Bar'`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	root := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      $ref: 'pets.yaml#/Pet'
+    Owner:
+      $ref: 'owners.yaml#/Owner'
+    Foo:
+      $ref: 'types.yaml#/Foo'
+    Local:
+      $ref: '#/components/schemas/Pet'`
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(root), &rootNode)
+
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.BasePath = dir
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: dir,
+		IndexConfig:   cfg,
+		DirFS:         os.DirFS(dir),
+	})
+	require.NoError(t, err)
+
+	rolo := NewRolodex(cfg)
+	rolo.AddLocalFS(dir, fileFS)
+	rolo.SetRootNode(&rootNode)
+	require.NoError(t, rolo.IndexTheRolodex())
+
+	rootPath := rolo.GetRootIndex().GetSpecAbsolutePath()
+	abs := func(name string) string {
+		p, _ := filepath.Abs(filepath.Join(dir, name))
+		return p
+	}
+
+	assert.Equal(t, []string{abs("pets.yaml"), rootPath}, rolo.FilesReferencing("types.yaml#/Foo"))
+	assert.Equal(t, []string{abs("owners.yaml"), abs("types.yaml")}, rolo.FilesReferencing(abs("types.yaml")+"#/Bar"))
+
+	// without a fragment, every reference to the file matches.
+	assert.Equal(t, []string{abs("owners.yaml"), abs("pets.yaml"), rootPath, abs("types.yaml")},
+		rolo.FilesReferencing("types.yaml"))
+
+	// a fragment is a location in the root document.
+	assert.Equal(t, []string{rootPath}, rolo.FilesReferencing("#/components/schemas/Pet"))
+	assert.Empty(t, rolo.FilesReferencing("types.yaml#/Missing"))
+}
@@ -0,0 +1,240 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// reindexableFile is a file loaded by the LocalFS or RemoteFS, that can have its content replaced.
+type reindexableFile interface {
+	GetIndex() *SpecIndex
+	reindex(data []byte, config *SpecIndexConfig) (*SpecIndex, error)
+}
+
+// ReindexFile replaces the content of a file already loaded by the rolodex (absPath is the full path, or URL, of the
+// file) with newContent, and rebuilds the index of just that file. The indexes of every other file are kept, only
+// the references they make into the file are located again, and any cached searches for them are forgotten.
+// References that can no longer be located are dropped, and schemas built from the old content of the file are
+// evicted from the schema caches (see SpecIndex.GetSchemaCache and SpecIndex.GetHighSchemaCache).
+//
+// The root document is re-indexed when absPath is its location (the absolute path of the root index, see
+// GetRootIndex), and the new root node and index replace the ones the rolodex was indexed with.
+//
+// If newContent can't be parsed, an error is returned and the file keeps its existing content and index. Otherwise,
+// any errors found when indexing the new content (and checking it for circular references) are returned.
+func (r *Rolodex) ReindexFile(absPath string, newContent []byte) error {
+	if !r.indexed {
+		return fmt.Errorf("rolodex has not been indexed, cannot re-index '%s'", absPath)
+	}
+	copiedConfig := *r.indexConfig
+	copiedConfig.SpecAbsolutePath = absPath
+	copiedConfig.AvoidBuildIndex = true
+
+	var idx, oldIndex *SpecIndex
+	var rootNode *yaml.Node
+	if r.isRootDocument(absPath) {
+		copiedConfig.Logger = r.logger
+		info, err := extractSpecInfo(newContent, true, &copiedConfig)
+		if err != nil {
+			return fmt.Errorf("unable to re-index '%s': %w", absPath, err)
+		}
+		rootNode = info.RootNode
+		idx = NewSpecIndexWithConfig(rootNode, &copiedConfig)
+		oldIndex = r.GetRootIndex()
+	} else {
+		file, logger := r.findLoadedFile(absPath)
+		if file == nil {
+			return fmt.Errorf("file '%s' has not been loaded by the rolodex, cannot re-index it", absPath)
+		}
+		copiedConfig.Logger = logger
+		oldIndex = file.GetIndex()
+		var err error
+		if idx, err = file.reindex(newContent, &copiedConfig); err != nil {
+			return fmt.Errorf("unable to re-index '%s': %w", absPath, err)
+		}
+	}
+	idx.rolodex = r
+	resolver := NewResolver(idx)
+	if copiedConfig.IgnoreArrayCircularReferences {
+		resolver.IgnoreArrayCircularReferences()
+	}
+	if copiedConfig.IgnorePolymorphicCircularReferences {
+		resolver.IgnorePolymorphicCircularReferences()
+	}
+	resolver.MergeRefSiblings = copiedConfig.MergeRefSiblings
+
+	r.indexLock.Lock()
+	if rootNode != nil {
+		r.rootNode = rootNode
+		r.rootIndex = idx
+	} else {
+		replaced := false
+		for i := range r.indexes {
+			if r.indexes[i] == oldIndex {
+				r.indexes[i] = idx
+				replaced = true
+			}
+		}
+		if !replaced {
+			r.indexes = append(r.indexes, idx)
+		}
+		r.indexMap[absPath] = idx
+	}
+	r.indexLock.Unlock()
+
+	idx.BuildIndex()
+	var caughtErrors []error
+	caughtErrors = append(caughtErrors, idx.refErrors...)
+	if !copiedConfig.AvoidCircularReferenceCheck {
+		for _, e := range resolver.CheckForCircularReferences() {
+			caughtErrors = append(caughtErrors, e)
+		}
+	}
+
+	// every other index may have located references in the old version of the file.
+	others := r.GetIndexes()
+	if root := r.GetRootIndex(); root != nil {
+		others = append(others, root)
+	}
+	for _, other := range others {
+		if other != idx {
+			other.remapReferences(absPath, oldIndex)
+		}
+	}
+
+	// a new root index comes with empty schema caches, otherwise schemas built from the old file must go.
+	if rootNode == nil && oldIndex != nil {
+		idx.evictSchemas(oldIndex.GetRootNode())
+	}
+	return errors.Join(caughtErrors...)
+}
+
+// evictSchemas removes every schema built from a node in the tree under root from the schema caches of the index.
+// Low-level schemas are cached by the node they were built from, and high-level schemas by the low-level schema,
+// so both are found by their root node.
+func (index *SpecIndex) evictSchemas(root *yaml.Node) {
+	if root == nil {
+		return
+	}
+	nodes := make(map[*yaml.Node]bool)
+	stack := []*yaml.Node{root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n == nil || nodes[n] {
+			continue
+		}
+		nodes[n] = true
+		stack = append(stack, n.Content...)
+	}
+
+	if cache := index.GetSchemaCache(); cache != nil {
+		cache.Range(func(key, _ any) bool {
+			if n, ok := key.(*yaml.Node); ok && nodes[n] {
+				cache.Delete(key)
+			}
+			return true
+		})
+	}
+	if cache := index.GetHighSchemaCache(); cache != nil {
+		cache.Range(func(key, _ any) bool {
+			if s, ok := key.(interface{ GetRootNode() *yaml.Node }); ok && nodes[s.GetRootNode()] {
+				cache.Delete(key)
+			}
+			return true
+		})
+	}
+}
+
+// isRootDocument returns true if absPath is the location of the root document of the rolodex.
+func (r *Rolodex) isRootDocument(absPath string) bool {
+	root := r.GetRootIndex()
+	return absPath != "" && root != nil && root.GetSpecAbsolutePath() == absPath
+}
+
+// findLoadedFile returns the file at absPath loaded by any LocalFS or RemoteFS of the rolodex, and the logger of the
+// file system that loaded it.
+func (r *Rolodex) findLoadedFile(absPath string) (reindexableFile, *slog.Logger) {
+	for _, v := range r.localFS {
+		if l, ok := v.(*LocalFS); ok {
+			if f, found := l.Files.Load(absPath); found {
+				return f.(*LocalFile), l.logger
+			}
+		}
+	}
+	for _, v := range r.remoteFS {
+		if rfs, ok := v.(*RemoteFS); ok {
+			if f, found := rfs.Files.Load(absPath); found {
+				return f.(*RemoteFile), rfs.logger
+			}
+		}
+	}
+	return nil, nil
+}
+
+// remapReferences locates every mapped reference that points into the file at absPath again (the file was indexed
+// by old), and forgets every cached search that found something in it.
+func (index *SpecIndex) remapReferences(absPath string, old *SpecIndex) {
+	stale := func(ref *Reference) bool {
+		if ref == nil {
+			return false
+		}
+		file, _, _ := strings.Cut(ref.FullDefinition, "#")
+		return (old != nil && ref.Index == old) || ref.RemoteLocation == absPath || file == absPath
+	}
+
+	if index.cache != nil {
+		index.cache.Range(func(key, value any) bool {
+			if ref, ok := value.(*Reference); ok && stale(ref) {
+				index.cache.Delete(key)
+			}
+			return true
+		})
+	}
+
+	index.refLock.Lock()
+	var keys []string
+	for key, ref := range index.allMappedRefs {
+		if stale(ref) {
+			keys = append(keys, key)
+		}
+	}
+	index.refLock.Unlock()
+	if len(keys) == 0 {
+		return
+	}
+
+	located := make(map[string]*Reference, len(keys))
+	for _, key := range keys {
+		located[key] = index.FindComponent(key)
+	}
+
+	index.refLock.Lock()
+	defer index.refLock.Unlock()
+	for key, ref := range located {
+		if ref == nil {
+			delete(index.allMappedRefs, key)
+			continue
+		}
+		index.allMappedRefs[key] = ref
+	}
+	sequenced := index.allMappedRefsSequenced[:0]
+	for _, mapped := range index.allMappedRefsSequenced {
+		if ref, ok := located[mapped.FullDefinition]; ok {
+			if ref == nil {
+				continue
+			}
+			mapped.Reference = ref
+			mapped.Definition = ref.Definition
+		}
+		sequenced = append(sequenced, mapped)
+	}
+	index.allMappedRefsSequenced = sequenced
+}
@@ -0,0 +1,203 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRolodex_ReindexFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"types.yaml": `Foo:
+  type: string`,
+		"pets.yaml": `Pet:
+  type: object
+  properties:
+    name:
+      type: string`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	root := `openapi: 3.1.0
+components:
+  schemas:
+    Foo:
+      $ref: 'types.yaml#/Foo'
+    Pet:
+      $ref: 'pets.yaml#/Pet'`
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(root), &rootNode)
+
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.BasePath = dir
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: dir,
+		IndexConfig:   cfg,
+		DirFS:         os.DirFS(dir),
+	})
+	require.NoError(t, err)
+
+	rolo := NewRolodex(cfg)
+	rolo.AddLocalFS(dir, fileFS)
+	rolo.SetRootNode(&rootNode)
+
+	typesPath, _ := filepath.Abs(filepath.Join(dir, "types.yaml"))
+	petsPath, _ := filepath.Abs(filepath.Join(dir, "pets.yaml"))
+	assert.Error(t, rolo.ReindexFile(typesPath, nil))
+	require.NoError(t, rolo.IndexTheRolodex())
+
+	indexFor := func(path string) *SpecIndex {
+		for _, idx := range rolo.GetIndexes() {
+			if idx.GetSpecAbsolutePath() == path {
+				return idx
+			}
+		}
+		return nil
+	}
+	typeOf := func(ref string) string {
+		found, _ := rolo.GetRootIndex().SearchIndexForReference(ref)
+		require.NotNil(t, found)
+		_, v := findMapValue(found.Node, "type")
+		return v
+	}
+
+	oldTypes, pets := indexFor(typesPath), indexFor(petsPath)
+	assert.Equal(t, "string", typeOf(typesPath+"#/Foo"))
+
+	// schemas built from the file are cached against its nodes.
+	oldFoo := oldTypes.GetRootNode().Content[0].Content[1]
+	petNode := pets.GetRootNode().Content[0].Content[1]
+	lowCache, highCache := rolo.GetRootIndex().GetSchemaCache(), rolo.GetRootIndex().GetHighSchemaCache()
+	lowCache.Store(oldFoo, "foo")
+	lowCache.Store(petNode, "pet")
+	highCache.Store(&cachedSchema{root: oldFoo}, "foo")
+	highCache.Store(&cachedSchema{root: petNode}, "pet")
+
+	require.NoError(t, rolo.ReindexFile(typesPath, []byte(`Foo:
+  type: integer`)))
+
+	// the file has a new index, other files keep theirs.
+	assert.NotSame(t, oldTypes, indexFor(typesPath))
+	assert.Same(t, pets, indexFor(petsPath))
+	assert.Len(t, rolo.GetIndexes(), 2)
+
+	// only the schemas built from the old content of the file are evicted.
+	cached := func(cache *sync.Map) []any {
+		var values []any
+		cache.Range(func(_, value any) bool {
+			values = append(values, value)
+			return true
+		})
+		return values
+	}
+	assert.Equal(t, []any{"pet"}, cached(lowCache))
+	assert.Equal(t, []any{"pet"}, cached(highCache))
+
+	// references into the file are located again.
+	assert.Equal(t, "integer", typeOf(typesPath+"#/Foo"))
+	mapped := rolo.GetRootIndex().GetMappedReferences()[typesPath+"#/Foo"]
+	_, v := findMapValue(mapped.Node, "type")
+	assert.Equal(t, "integer", v)
+
+	f, err := rolo.Open(typesPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Foo:\n  type: integer", f.GetContent())
+
+	// content that can't be parsed leaves the file as it is.
+	current := indexFor(typesPath)
+	assert.Error(t, rolo.ReindexFile(typesPath, []byte("Foo: [")))
+	assert.Same(t, current, indexFor(typesPath))
+
+	// references to components that no longer exist are dropped.
+	require.NoError(t, rolo.ReindexFile(typesPath, []byte(`Bar:
+  type: integer`)))
+	assert.Nil(t, rolo.GetRootIndex().GetMappedReferences()[typesPath+"#/Foo"])
+
+	assert.EqualError(t, rolo.ReindexFile(filepath.Join(dir, "missing.yaml"), nil),
+		"file '"+filepath.Join(dir, "missing.yaml")+"' has not been loaded by the rolodex, cannot re-index it")
+}
+
+func TestRolodex_ReindexFile_Root(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.yaml"), []byte(`Foo:
+  type: string
+Bar:
+  type: integer`), 0o644))
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(`openapi: 3.1.0
+components:
+  schemas:
+    Foo:
+      $ref: 'types.yaml#/Foo'`), &rootNode)
+
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.BasePath = dir
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: dir,
+		IndexConfig:   cfg,
+		DirFS:         os.DirFS(dir),
+	})
+	require.NoError(t, err)
+
+	rolo := NewRolodex(cfg)
+	rolo.AddLocalFS(dir, fileFS)
+	rolo.SetRootNode(&rootNode)
+	require.NoError(t, rolo.IndexTheRolodex())
+
+	rootPath := rolo.GetRootIndex().GetSpecAbsolutePath()
+	typesPath, _ := filepath.Abs(filepath.Join(dir, "types.yaml"))
+	oldRoot, types := rolo.GetRootIndex(), rolo.GetIndexes()[0]
+
+	require.NoError(t, rolo.ReindexFile(rootPath, []byte(`openapi: 3.1.0
+components:
+  schemas:
+    Bar:
+      $ref: 'types.yaml#/Bar'`)))
+
+	// the root document has a new node and index, the other files keep theirs.
+	assert.NotSame(t, oldRoot, rolo.GetRootIndex())
+	assert.NotSame(t, &rootNode, rolo.GetRootNode())
+	assert.Same(t, rolo.GetRootIndex().GetRootNode(), rolo.GetRootNode())
+	assert.Equal(t, rootPath, rolo.GetRootIndex().GetSpecAbsolutePath())
+	assert.Equal(t, []*SpecIndex{types}, rolo.GetIndexes())
+
+	refs := rolo.GetRootIndex().GetMappedReferences()
+	assert.NotNil(t, refs[typesPath+"#/Bar"])
+	assert.Nil(t, refs[typesPath+"#/Foo"])
+
+	// content that can't be parsed leaves the root document as it is.
+	current := rolo.GetRootIndex()
+	assert.Error(t, rolo.ReindexFile(rootPath, []byte("openapi: [")))
+	assert.Same(t, current, rolo.GetRootIndex())
+}
+
+// cachedSchema stands in for a low-level schema used as a key of the high-level schema cache.
+type cachedSchema struct {
+	root *yaml.Node
+}
+
+func (c *cachedSchema) GetRootNode() *yaml.Node {
+	return c.root
+}
+
+// findMapValue returns the key and value of key in mapping node n.
+func findMapValue(n *yaml.Node, key string) (*yaml.Node, string) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i], n.Content[i+1].Value
+		}
+	}
+	return nil, ""
+}
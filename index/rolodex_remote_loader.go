@@ -4,6 +4,7 @@
 package index
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -12,6 +13,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -61,6 +63,9 @@ type RemoteFile struct {
 	index         *SpecIndex
 	parsed        *yaml.Node
 	offset        int64
+
+	// contentLock guards data, parsed, index and lastModified, which are replaced when the file is re-indexed.
+	contentLock sync.RWMutex
 }
 
 // GetFileName returns the name of the file.
@@ -70,11 +75,15 @@ func (f *RemoteFile) GetFileName() string {
 
 // GetContent returns the content of the file as a string.
 func (f *RemoteFile) GetContent() string {
+	f.contentLock.RLock()
+	defer f.contentLock.RUnlock()
 	return string(f.data)
 }
 
 // GetContentAsYAMLNode returns the content of the file as a yaml.Node.
 func (f *RemoteFile) GetContentAsYAMLNode() (*yaml.Node, error) {
+	f.contentLock.Lock()
+	defer f.contentLock.Unlock()
 	if f.parsed != nil {
 		return f.parsed, nil
 	}
@@ -103,6 +112,8 @@ func (f *RemoteFile) GetFileExtension() FileExtension {
 
 // GetLastModified returns the last modified time of the file.
 func (f *RemoteFile) GetLastModified() time.Time {
+	f.contentLock.RLock()
+	defer f.contentLock.RUnlock()
 	return f.lastModified
 }
 
@@ -125,6 +136,8 @@ func (f *RemoteFile) Name() string {
 
 // Size returns the size of the file.
 func (f *RemoteFile) Size() int64 {
+	f.contentLock.RLock()
+	defer f.contentLock.RUnlock()
 	return int64(len(f.data))
 }
 
@@ -135,7 +148,7 @@ func (f *RemoteFile) Mode() fs.FileMode {
 
 // ModTime returns the modification time of the file.
 func (f *RemoteFile) ModTime() time.Time {
-	return f.lastModified
+	return f.GetLastModified()
 }
 
 // IsDir returns true if the file is a directory.
@@ -162,38 +175,68 @@ func (f *RemoteFile) Stat() (fs.FileInfo, error) {
 
 // Read reads the file. Makes it compatible with io.Reader.
 func (f *RemoteFile) Read(b []byte) (int, error) {
-	if f.offset >= int64(len(f.data)) {
+	f.contentLock.RLock()
+	data := f.data
+	f.contentLock.RUnlock()
+	if f.offset >= int64(len(data)) {
 		return 0, io.EOF
 	}
 	if f.offset < 0 {
 		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrInvalid}
 	}
-	n := copy(b, f.data[f.offset:])
+	n := copy(b, data[f.offset:])
 	f.offset += int64(n)
 	return n, nil
 }
 
 // Index indexes the file and returns a *SpecIndex, any errors are returned as well.
 func (f *RemoteFile) Index(config *SpecIndexConfig) (*SpecIndex, error) {
-	if f.index != nil {
-		return f.index, nil
+	f.contentLock.RLock()
+	existing, content := f.index, f.data
+	f.contentLock.RUnlock()
+	if existing != nil {
+		return existing, nil
 	}
-	content := f.data
 
 	// first, we must parse the content of the file
-	info, err := datamodel.ExtractSpecInfoWithDocumentCheckSync(content, true)
+	info, err := extractSpecInfo(content, true, config)
 	if err != nil {
 		return nil, err
 	}
 
 	index := NewSpecIndexWithConfig(info.RootNode, config)
 	index.specAbsolutePath = config.SpecAbsolutePath
+
+	f.contentLock.Lock()
+	defer f.contentLock.Unlock()
+	if f.index == nil {
+		f.index = index
+	}
+	return f.index, nil
+}
+
+// reindex replaces the content of the file with data, and creates a new (unbuilt) index for it using config. The file
+// is left as it was if data can't be parsed.
+func (f *RemoteFile) reindex(data []byte, config *SpecIndexConfig) (*SpecIndex, error) {
+	info, err := extractSpecInfo(data, true, config)
+	if err != nil {
+		return nil, err
+	}
+	index := NewSpecIndexWithConfig(info.RootNode, config)
+	index.specAbsolutePath = config.SpecAbsolutePath
+	f.contentLock.Lock()
+	f.data = data
+	f.parsed = nil
 	f.index = index
+	f.lastModified = time.Now()
+	f.contentLock.Unlock()
 	return index, nil
 }
 
 // GetIndex returns the index for the file.
 func (f *RemoteFile) GetIndex() *SpecIndex {
+	f.contentLock.RLock()
+	defer f.contentLock.RUnlock()
 	return f.index
 }
 
@@ -222,17 +265,49 @@ func NewRemoteFSWithConfig(specIndexConfig *SpecIndexConfig) (*RemoteFS, error)
 	if specIndexConfig.RemoteURLHandler != nil {
 		rfs.RemoteHandlerFunc = specIndexConfig.RemoteURLHandler
 	} else {
-		// default http client
-		client := &http.Client{
-			Timeout: time.Second * 120,
+		client := specIndexConfig.HTTPClient
+		if client == nil {
+			// default http client
+			client = &http.Client{
+				Timeout: time.Second * 120,
+			}
+		}
+		// redirects are checked against the allowed and denied hosts as well, so an allowed host can't redirect
+		// the lookup somewhere it's not allowed to go. The client supplied is copied, not changed.
+		checked := *client
+		checkRedirect := client.CheckRedirect
+		checked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if err := rfs.checkRemoteHost(req.URL); err != nil {
+				return err
+			}
+			if checkRedirect != nil {
+				return checkRedirect(req, via)
+			}
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return nil
 		}
+		client = &checked
 		rfs.RemoteHandlerFunc = func(url string) (*http.Response, error) {
-			return client.Get(url)
+			return contextGet(specIndexConfig.Context, client, url)
 		}
 	}
 	return rfs, nil
 }
 
+// contextGet performs a GET request using the supplied client, the request is bound to the context if there is one.
+func contextGet(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
 // NewRemoteFSWithRootURL creates a new RemoteFS using the supplied root URL.
 func NewRemoteFSWithRootURL(rootURL string) (*RemoteFS, error) {
 	remoteRootURL, err := url.Parse(rootURL)
@@ -323,9 +398,11 @@ func (i *RemoteFS) Open(remoteURL string) (fs.File, error) {
 		return wait.file, nil
 	}
 
+	// if there is no extension, the content is sniffed once it has been fetched, to work out if it's JSON or YAML.
 	fileExt := ExtractFileType(remoteParsedURL.Path)
+	sniff := fileExt == UNSUPPORTED && filepath.Ext(remoteParsedURL.Path) == ""
 
-	if fileExt == UNSUPPORTED {
+	if fileExt == UNSUPPORTED && !sniff {
 		i.remoteErrors = append(i.remoteErrors, fs.ErrInvalid)
 		if i.logger != nil {
 			i.logger.Warn("[rolodex remote loader] unsupported file in reference will be ignored", "file", remoteURL, "remoteURL", remoteParsedURL.String())
@@ -356,64 +433,56 @@ func (i *RemoteFS) Open(remoteURL string) (fs.File, error) {
 		return nil, nil // not a remote file, nothing wrong with that - just we can't keep looking here partner.
 	}
 
-	i.logger.Debug("[rolodex remote loader] loading remote file", "file", remoteURL, "remoteURL", remoteParsedURL.String())
-
-	response, clientErr := i.RemoteHandlerFunc(remoteParsedURL.String())
-	if clientErr != nil {
-
-		i.remoteErrors = append(i.remoteErrors, clientErr)
-		// remove from processing
+	if hostErr := i.checkRemoteHost(remoteParsedURL); hostErr != nil {
+		i.remoteErrors = append(i.remoteErrors, hostErr)
 		processingWaiter.done = true
 		i.ProcessingFiles.Delete(remoteParsedURL.Path)
-		if response != nil {
-			i.logger.Error("client error", "error", clientErr, "status", response.StatusCode)
-		} else {
-			i.logger.Error("client error", "error", clientErr.Error())
-		}
-		return nil, clientErr
+		i.logger.Error("[rolodex remote loader] remote host refused", "file", remoteURL, "error", hostErr.Error())
+		return nil, hostErr
 	}
-	if response == nil {
-		// remove from processing
-		processingWaiter.done = true
-		i.ProcessingFiles.Delete(remoteParsedURL.Path)
-		return nil, fmt.Errorf("empty response from remote URL: %s", remoteParsedURL.String())
-	}
-	responseBytes, readError := io.ReadAll(response.Body)
-	if readError != nil {
 
-		// remove from processing
-		processingWaiter.done = true
-		i.ProcessingFiles.Delete(remoteParsedURL.Path)
+	var responseBytes []byte
+	var lastModifiedTime time.Time
+	var cached bool
 
-		return nil, fmt.Errorf("error reading bytes from remote file '%s': [%s]",
-			remoteParsedURL.String(), readError.Error())
+	// check the cache before going to the network.
+	if i.indexConfig != nil && i.indexConfig.RemoteCache != nil {
+		responseBytes, cached = i.indexConfig.RemoteCache.Get(remoteParsedURL.String())
+		if cached {
+			i.logger.Debug("[rolodex remote loader] remote file loaded from cache", "file", remoteURL,
+				"remoteURL", remoteParsedURL.String())
+			lastModifiedTime = time.Now()
+		}
 	}
 
-	if response.StatusCode >= 400 {
-
-		// remove from processing
-		processingWaiter.done = true
-		i.ProcessingFiles.Delete(remoteParsedURL.Path)
+	if !cached {
+		var fetchErr error
+		responseBytes, lastModifiedTime, fetchErr = i.fetchRemoteFile(remoteURL, remoteParsedURL)
+		if fetchErr != nil {
+			// remove from processing
+			processingWaiter.done = true
+			i.ProcessingFiles.Delete(remoteParsedURL.Path)
+			return nil, fetchErr
+		}
+		if i.indexConfig != nil && i.indexConfig.RemoteCache != nil {
+			i.indexConfig.RemoteCache.Set(remoteParsedURL.String(), responseBytes)
+		}
+	}
 
-		i.logger.Error("unable to fetch remote document",
-			"file", remoteParsedURL.Path, "status", response.StatusCode, "resp", string(responseBytes))
-		return nil, fmt.Errorf("unable to fetch remote document '%s' (error %d)", remoteParsedURL.String(),
-			response.StatusCode)
+	if sniff {
+		if fileExt = SniffFileType(responseBytes); fileExt == UNSUPPORTED {
+			i.remoteErrors = append(i.remoteErrors, fs.ErrInvalid)
+			if i.logger != nil {
+				i.logger.Warn("[rolodex remote loader] unsupported file in reference will be ignored", "file", remoteURL, "remoteURL", remoteParsedURL.String())
+			}
+			processingWaiter.done = true
+			i.ProcessingFiles.Delete(remoteParsedURL.Path)
+			return nil, &fs.PathError{Op: "open", Path: remoteURL, Err: fs.ErrInvalid}
+		}
 	}
 
 	absolutePath := remoteParsedURL.Path
 
-	// extract last modified from response
-	lastModified := response.Header.Get("Last-Modified")
-
-	// parse the last modified date into a time object
-	lastModifiedTime, parseErr := time.Parse(time.RFC1123, lastModified)
-
-	if parseErr != nil {
-		// can't extract last modified, so use now
-		lastModifiedTime = time.Now()
-	}
-
 	filename := filepath.Base(remoteParsedURL.Path)
 
 	remoteFile := &RemoteFile{
@@ -436,6 +505,7 @@ func (i *RemoteFS) Open(remoteURL string) (fs.File, error) {
 		copiedCfg.BaseURL = newBaseURL
 	}
 	copiedCfg.SpecAbsolutePath = remoteParsedURL.String()
+	copiedCfg.Logger = i.logger
 
 	if len(remoteFile.data) > 0 {
 		i.logger.Debug("[rolodex remote loaded] successfully loaded file", "file", absolutePath)
@@ -464,3 +534,135 @@ func (i *RemoteFS) Open(remoteURL string) (fs.File, error) {
 	}
 	return remoteFile, errors.Join(i.remoteErrors...)
 }
+
+// Fetch retrieves the content of a remote file without indexing it, or adding it to the file system. The remote host
+// restrictions, the document size limit and the remote cache of the index configuration are all respected.
+func (i *RemoteFS) Fetch(remoteURL string) ([]byte, error) {
+	remoteParsedURL, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	if hostErr := i.checkRemoteHost(remoteParsedURL); hostErr != nil {
+		i.logger.Error("[rolodex remote loader] remote host refused", "file", remoteURL, "error", hostErr.Error())
+		return nil, hostErr
+	}
+	if i.indexConfig != nil && i.indexConfig.RemoteCache != nil {
+		if cached, ok := i.indexConfig.RemoteCache.Get(remoteParsedURL.String()); ok {
+			return cached, nil
+		}
+	}
+	responseBytes, _, fetchErr := i.fetchRemoteFile(remoteURL, remoteParsedURL)
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	if i.indexConfig != nil && i.indexConfig.RemoteCache != nil {
+		i.indexConfig.RemoteCache.Set(remoteParsedURL.String(), responseBytes)
+	}
+	return responseBytes, nil
+}
+
+// fetchRemoteFile fetches a remote file using the RemoteHandlerFunc, returning the bytes and last modified time.
+func (i *RemoteFS) fetchRemoteFile(remoteURL string, remoteParsedURL *url.URL) (data []byte, modified time.Time, err error) {
+	started := time.Now()
+	notifyResolution(i.indexConfig, ResolutionEvent{Type: ResolutionFetchingRemote,
+		Reference: remoteParsedURL.String(), Time: started})
+	defer func() {
+		notifyResolution(i.indexConfig, ResolutionEvent{Type: ResolutionFetchedRemote,
+			Reference: remoteParsedURL.String(), Duration: time.Since(started), Error: err})
+	}()
+
+	if i.RemoteHandlerFunc == nil {
+		return nil, time.Time{}, fmt.Errorf("unable to fetch remote file '%s', no remote handler is configured",
+			remoteParsedURL.String())
+	}
+	i.logger.Debug("[rolodex remote loader] loading remote file", "file", remoteURL, "remoteURL", remoteParsedURL.String())
+
+	response, clientErr := i.RemoteHandlerFunc(remoteParsedURL.String())
+	if clientErr != nil {
+
+		i.remoteErrors = append(i.remoteErrors, clientErr)
+		if response != nil {
+			i.logger.Error("client error", "error", clientErr, "status", response.StatusCode)
+		} else {
+			i.logger.Error("client error", "error", clientErr.Error())
+		}
+		return nil, time.Time{}, clientErr
+	}
+	if response == nil {
+		return nil, time.Time{}, fmt.Errorf("empty response from remote URL: %s", remoteParsedURL.String())
+	}
+	var maxBytes int64
+	if i.indexConfig != nil {
+		maxBytes = i.indexConfig.MaxDocumentBytes
+	}
+	if sizeErr := datamodel.CheckDocumentSize(response.ContentLength, maxBytes); sizeErr != nil {
+		_ = response.Body.Close()
+		return nil, time.Time{}, fmt.Errorf("unable to read remote file '%s': %w", remoteParsedURL.String(), sizeErr)
+	}
+
+	// never read more than one byte past the limit, the content length header cannot be trusted.
+	body := io.Reader(response.Body)
+	if maxBytes > 0 {
+		body = io.LimitReader(response.Body, maxBytes+1)
+	}
+	responseBytes, readError := io.ReadAll(body)
+	if readError != nil {
+		return nil, time.Time{}, fmt.Errorf("error reading bytes from remote file '%s': [%s]",
+			remoteParsedURL.String(), readError.Error())
+	}
+	if sizeErr := datamodel.CheckDocumentSize(int64(len(responseBytes)), maxBytes); sizeErr != nil {
+		return nil, time.Time{}, fmt.Errorf("unable to read remote file '%s': %w", remoteParsedURL.String(), sizeErr)
+	}
+
+	if response.StatusCode >= 400 {
+		i.logger.Error("unable to fetch remote document",
+			"file", remoteParsedURL.Path, "status", response.StatusCode, "resp", string(responseBytes))
+		return nil, time.Time{}, fmt.Errorf("unable to fetch remote document '%s' (error %d)", remoteParsedURL.String(),
+			response.StatusCode)
+	}
+
+	// extract last modified from response
+	lastModified := response.Header.Get("Last-Modified")
+
+	// parse the last modified date into a time object
+	lastModifiedTime, parseErr := time.Parse(time.RFC1123, lastModified)
+
+	if parseErr != nil {
+		// can't extract last modified, so use now
+		lastModifiedTime = time.Now()
+	}
+	return responseBytes, lastModifiedTime, nil
+}
+
+// checkRemoteHost will return an error if the host of the URL is denied, or is not allowed, by the index configuration.
+func (i *RemoteFS) checkRemoteHost(remoteURL *url.URL) error {
+	if i.indexConfig == nil {
+		return nil
+	}
+	host := strings.ToLower(remoteURL.Hostname())
+	for _, denied := range i.indexConfig.DeniedRemoteHosts {
+		if matchRemoteHost(denied, host) {
+			return fmt.Errorf("remote lookup for '%s' is not allowed, host '%s' is denied", remoteURL.String(), host)
+		}
+	}
+	if len(i.indexConfig.AllowedRemoteHosts) == 0 {
+		return nil
+	}
+	for _, allowed := range i.indexConfig.AllowedRemoteHosts {
+		if matchRemoteHost(allowed, host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("remote lookup for '%s' is not allowed, host '%s' is not in the allowed remote hosts",
+		remoteURL.String(), host)
+}
+
+// matchRemoteHost returns true if the host matches the pattern, patterns may contain wildcards (e.g. '*.example.com').
+func matchRemoteHost(pattern, host string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == host {
+		return true
+	}
+	matched, err := path.Match(pattern, host)
+	return err == nil && matched
+}
@@ -8,13 +8,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -244,6 +247,29 @@ func TestRemoteFile_Index_BadContent(t *testing.T) {
 	assert.Error(t, y)
 }
 
+func TestRemoteFile_Reindex_Concurrent(t *testing.T) {
+	rf := &RemoteFile{data: []byte("good: data"), fullPath: "https://pb33f.io/good.yaml"}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := rf.reindex([]byte("better: data"), &SpecIndexConfig{})
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = rf.GetContent()
+			_ = rf.GetIndex()
+			_ = rf.ModTime()
+			_, _ = rf.GetContentAsYAMLNode()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, "better: data", rf.GetContent())
+	assert.NotNil(t, rf.GetIndex())
+}
+
 func TestRemoteFS_NoConfig(t *testing.T) {
 	x, y := NewRemoteFSWithConfig(nil)
 	assert.Nil(t, x)
@@ -442,3 +468,240 @@ func TestNewRemoteFS_BadURL(t *testing.T) {
 	assert.Nil(t, x)
 	assert.Error(t, y)
 }
+
+func TestNewRemoteFS_RemoteCache(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		hits++
+		_, _ = rw.Write([]byte(`type: string`))
+	}))
+	defer server.Close()
+
+	cache := NewRemoteCache(time.Minute)
+	for i := 0; i < 3; i++ {
+		cfg := CreateOpenAPIIndexConfig()
+		cfg.RemoteCache = cache
+		remoteFS, _ := NewRemoteFSWithConfig(cfg)
+		remoteFS.RemoteHandlerFunc = test_httpClient.Get
+
+		file, err := remoteFS.Open(server.URL + "/schemas/string.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, "type: string", file.(*RemoteFile).GetContent())
+	}
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 1, cache.Len())
+
+	data, ok := cache.Get(server.URL + "/schemas/string.yaml")
+	assert.True(t, ok)
+	assert.Equal(t, "type: string", string(data))
+}
+
+func TestNewRemoteFS_RemoteCache_ErrorNotCached(t *testing.T) {
+	server := test_buildServer()
+	defer server.Close()
+
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.RemoteCache = NewRemoteCache(time.Minute)
+	remoteFS, _ := NewRemoteFSWithConfig(cfg)
+	remoteFS.RemoteHandlerFunc = test_httpClient.Get
+
+	_, err := remoteFS.Open(server.URL + "/bad.yaml")
+	assert.Error(t, err)
+	assert.Equal(t, 0, cfg.RemoteCache.(*MemoryRemoteCache).Len())
+}
+
+type test_headerTransport struct {
+	header, value string
+}
+
+func (h *test_headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(h.header, h.value)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewRemoteFS_HTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer pizza" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = rw.Write([]byte(`type: string`))
+	}))
+	defer server.Close()
+
+	cfg := CreateOpenAPIIndexConfig()
+	remoteFS, _ := NewRemoteFSWithConfig(cfg)
+	_, err := remoteFS.Open(server.URL + "/secure.yaml")
+	assert.Error(t, err)
+
+	cfg = CreateOpenAPIIndexConfig()
+	cfg.HTTPClient = &http.Client{Transport: &test_headerTransport{header: "Authorization", value: "Bearer pizza"}}
+	remoteFS, _ = NewRemoteFSWithConfig(cfg)
+	file, err := remoteFS.Open(server.URL + "/secure.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "type: string", file.(*RemoteFile).GetContent())
+}
+
+func TestNewRemoteFS_HTTPClient_HandlerTakesPrecedence(t *testing.T) {
+	var handlerUsed bool
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.HTTPClient = &http.Client{Transport: &test_headerTransport{header: "X-Pizza", value: "cheese"}}
+	cfg.RemoteURLHandler = func(url string) (*http.Response, error) {
+		handlerUsed = true
+		return nil, errors.New("no pizza")
+	}
+	remoteFS, _ := NewRemoteFSWithConfig(cfg)
+	_, err := remoteFS.Open("https://pb33f.io/pizza.yaml")
+	assert.EqualError(t, err, "no pizza")
+	assert.True(t, handlerUsed)
+}
+
+func TestNewRemoteFS_AllowedRemoteHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(`type: string`))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.AllowedRemoteHosts = []string{"*.pb33f.io", serverURL.Hostname()}
+	remoteFS, _ := NewRemoteFSWithConfig(cfg)
+	remoteFS.RemoteHandlerFunc = test_httpClient.Get
+
+	file, err := remoteFS.Open(server.URL + "/allowed.yaml")
+	assert.NoError(t, err)
+	assert.NotNil(t, file)
+
+	file, err = remoteFS.Open("https://evil.example.com/passwords.yaml")
+	assert.Nil(t, file)
+	assert.EqualError(t, err, "remote lookup for 'https://evil.example.com/passwords.yaml' is not allowed, "+
+		"host 'evil.example.com' is not in the allowed remote hosts")
+	assert.Len(t, remoteFS.GetErrors(), 1)
+}
+
+func TestNewRemoteFS_DeniedRemoteHosts(t *testing.T) {
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.AllowedRemoteHosts = []string{"*.example.com"}
+	cfg.DeniedRemoteHosts = []string{"*.internal.example.com", "169.254.169.254"}
+	var fetched []string
+	cfg.RemoteURLHandler = func(u string) (*http.Response, error) {
+		fetched = append(fetched, u)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("type: string"))}, nil
+	}
+	remoteFS, _ := NewRemoteFSWithConfig(cfg)
+
+	_, err := remoteFS.Open("https://api.internal.example.com/secret.yaml")
+	assert.EqualError(t, err, "remote lookup for 'https://api.internal.example.com/secret.yaml' is not allowed, "+
+		"host 'api.internal.example.com' is denied")
+
+	_, err = remoteFS.Open("http://169.254.169.254:80/latest/meta-data.yaml")
+	assert.Error(t, err)
+
+	_, _ = remoteFS.Open("https://Public.Example.com/schema.yaml")
+	assert.Len(t, fetched, 1)
+	assert.Equal(t, "https://Public.Example.com/schema.yaml", fetched[0])
+}
+
+func TestNewRemoteFS_DeniedRemoteHostRedirect(t *testing.T) {
+	deniedHit := false
+	denied := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		deniedHit = true
+		_, _ = rw.Write([]byte(`type: string`))
+	}))
+	defer denied.Close()
+	deniedURL, _ := url.Parse(denied.URL)
+
+	allowed := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.Redirect(rw, req, "http://localhost:"+deniedURL.Port()+"/secret.yaml", http.StatusFound)
+	}))
+	defer allowed.Close()
+	allowedURL, _ := url.Parse(allowed.URL)
+
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.AllowedRemoteHosts = []string{allowedURL.Hostname()}
+	cfg.DeniedRemoteHosts = []string{"localhost"}
+	remoteFS, _ := NewRemoteFSWithConfig(cfg)
+
+	file, err := remoteFS.Open(allowed.URL + "/redirect.yaml")
+	assert.Nil(t, file)
+	assert.ErrorContains(t, err, "host 'localhost' is denied")
+	assert.False(t, deniedHit)
+
+	// a client supplied by the caller keeps its own redirect check.
+	checked := 0
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		checked++
+		return nil
+	}}
+	cfg.DeniedRemoteHosts = nil
+	cfg.AllowedRemoteHosts = []string{allowedURL.Hostname(), "localhost"}
+	cfg.HTTPClient = client
+	remoteFS, _ = NewRemoteFSWithConfig(cfg)
+
+	file, err = remoteFS.Open(allowed.URL + "/redirect.yaml")
+	assert.NoError(t, err)
+	assert.NotNil(t, file)
+	assert.True(t, deniedHit)
+	assert.Equal(t, 1, checked)
+}
+
+func TestMatchRemoteHost(t *testing.T) {
+	assert.True(t, matchRemoteHost("pb33f.io", "pb33f.io"))
+	assert.True(t, matchRemoteHost("*.pb33f.io", "api.pb33f.io"))
+	assert.True(t, matchRemoteHost("*.PB33F.io", "api.pb33f.io"))
+	assert.False(t, matchRemoteHost("*.pb33f.io", "pb33f.io"))
+	assert.False(t, matchRemoteHost("pb33f.io", "evilpb33f.io"))
+	assert.False(t, matchRemoteHost("[", "pb33f.io"))
+}
+
+func TestNewRemoteFS_MaxDocumentBytes(t *testing.T) {
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.MaxDocumentBytes = 64
+	cfg.RemoteURLHandler = func(u string) (*http.Response, error) {
+		body := "type: string"
+		if strings.Contains(u, "big") {
+			body = "description: " + strings.Repeat("x", 128)
+		}
+		// no content length, so the limit must be enforced while reading.
+		return &http.Response{StatusCode: http.StatusOK, ContentLength: -1,
+			Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+	remoteFS, _ := NewRemoteFSWithConfig(cfg)
+
+	file, err := remoteFS.Open("https://pb33f.io/small.yaml")
+	assert.NoError(t, err)
+	assert.NotNil(t, file)
+
+	file, err = remoteFS.Open("https://pb33f.io/big.yaml")
+	assert.Nil(t, file)
+	assert.ErrorIs(t, err, datamodel.ErrDocumentTooLarge)
+}
+
+func TestNewRemoteFS_SniffNoExtension(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/openapi":
+			_, _ = rw.Write([]byte(`{"components": {"schemas": {"Pet": {"type": "object"}}}}`))
+		case "/schemas":
+			_, _ = rw.Write([]byte("components:\n  schemas:\n    Pet:\n      type: object"))
+		default:
+			_, _ = rw.Write([]byte("this is not a specification"))
+		}
+	}))
+	defer server.Close()
+
+	rfs, _ := NewRemoteFSWithConfig(CreateOpenAPIIndexConfig())
+
+	f, err := rfs.Open(server.URL + "/openapi")
+	assert.NoError(t, err)
+	assert.Equal(t, JSON, f.(*RemoteFile).GetFileExtension())
+
+	f, err = rfs.Open(server.URL + "/schemas")
+	assert.NoError(t, err)
+	assert.Equal(t, YAML, f.(*RemoteFile).GetFileExtension())
+
+	f, err = rfs.Open(server.URL + "/readme")
+	assert.Nil(t, f)
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
@@ -1826,3 +1826,169 @@ func TestHumanFileSize(t *testing.T) {
 	assert.Equal(t, "1 MB", HumanFileSize(1024*1024))
 
 }
+
+func TestRolodex_LocalFS_Logger(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "pizza.yaml"), []byte("openapi: 3.1.0"), 0o644)
+
+	var buf strings.Builder
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	cf := CreateOpenAPIIndexConfig()
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: dir,
+		IndexConfig:   cf,
+		Logger:        logger,
+	})
+	assert.NoError(t, err)
+
+	// the rolodex has no logger of its own, the local file system keeps its logger.
+	rolo := NewRolodex(cf)
+	rolo.AddLocalFS(dir, fileFS)
+	assert.Same(t, logger, fileFS.logger)
+
+	f, err := rolo.Open(filepath.Join(dir, "pizza.yaml"))
+	assert.NoError(t, err)
+	assert.Same(t, logger, f.GetIndex().GetLogger())
+	assert.Contains(t, buf.String(), "pizza.yaml")
+
+	// a local file system without a logger uses the logger of the rolodex.
+	rolodexLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cf = CreateOpenAPIIndexConfig()
+	cf.Logger = rolodexLogger
+	fileFS, _ = NewLocalFSWithConfig(&LocalFSConfig{BaseDirectory: dir, IndexConfig: cf})
+	rolo = NewRolodex(cf)
+	rolo.AddLocalFS(dir, fileFS)
+	assert.Same(t, rolodexLogger, fileFS.logger)
+}
+
+func TestRolodex_RemoteFS_Logger(t *testing.T) {
+	rolodexLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	remoteLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	remoteFS, _ := NewRemoteFSWithConfig(&SpecIndexConfig{Logger: remoteLogger})
+	rolo := NewRolodex(&SpecIndexConfig{Logger: rolodexLogger})
+	rolo.AddRemoteFS("https://pb33f.io", remoteFS)
+	assert.Same(t, remoteLogger, remoteFS.logger)
+
+	remoteFS, _ = NewRemoteFSWithConfig(&SpecIndexConfig{})
+	rolo.AddRemoteFS("https://pb33f.io", remoteFS)
+	assert.Same(t, rolodexLogger, remoteFS.logger)
+}
+
+func TestRolodex_EntryPointDirectory_SiblingTraversal(t *testing.T) {
+
+	tmp := t.TempDir()
+	api := filepath.Join(tmp, "specs", "api")
+	shared := filepath.Join(tmp, "specs", "shared")
+	_ = os.MkdirAll(api, 0o755)
+	_ = os.MkdirAll(shared, 0o755)
+
+	entry := filepath.Join(api, "openapi.yaml")
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      $ref: '../shared/types.yaml#/components/schemas/Pet'`
+	_ = os.WriteFile(entry, []byte(spec), 0o644)
+	_ = os.WriteFile(filepath.Join(shared, "types.yaml"), []byte(`components:
+  schemas:
+    Pet:
+      type: object`), 0o644)
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(spec), &rootNode)
+
+	// the entry point is the spec file itself, so relative references are resolved from the directory it lives in.
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: entry,
+		IndexConfig:   CreateOpenAPIIndexConfig(),
+	})
+	assert.NoError(t, err)
+
+	cf := CreateOpenAPIIndexConfig()
+	cf.BasePath = entry
+	rolo := NewRolodex(cf)
+	rolo.AddLocalFS(entry, fileFS)
+	rolo.SetRootNode(&rootNode)
+	assert.NoError(t, rolo.IndexTheRolodex())
+	assert.Empty(t, rolo.GetCaughtErrors())
+	assert.Equal(t, entry, rolo.GetRootIndex().GetSpecAbsolutePath())
+	assert.Len(t, rolo.GetRootIndex().GetAllReferences(), 1)
+	assert.Empty(t, rolo.GetRootIndex().GetReferenceIndexErrors())
+
+	f, err := rolo.Open("../shared/types.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(shared, "types.yaml"), f.GetFullPath())
+
+	// the same traversal works when the base directory is the entry point directory.
+	dirFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: api,
+		IndexConfig:   CreateOpenAPIIndexConfig(),
+	})
+	assert.NoError(t, err)
+	lf, err := dirFS.Open("../shared/types.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(shared, "types.yaml"), lf.(*LocalFile).GetFullPath())
+}
+
+func TestRolodex_EntryPointDirectory_NestedRoot(t *testing.T) {
+
+	tmp := t.TempDir()
+	api := filepath.Join(tmp, "specs", "api")
+	shared := filepath.Join(tmp, "specs", "shared")
+	_ = os.MkdirAll(api, 0o755)
+	_ = os.MkdirAll(shared, 0o755)
+
+	entry := filepath.Join(api, "openapi.yaml")
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      $ref: '../shared/types.yaml#/components/schemas/Pet'`
+	_ = os.WriteFile(entry, []byte(spec), 0o644)
+	_ = os.WriteFile(filepath.Join(shared, "types.yaml"), []byte(`components:
+  schemas:
+    Pet:
+      type: object`), 0o644)
+
+	// the base directory is the project, the root document is nested in it.
+	cf := CreateOpenAPIIndexConfig()
+	cf.BasePath = tmp
+	cf.SpecFilePath = filepath.Join("specs", "api", "openapi.yaml")
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: tmp,
+		IndexConfig:   cf,
+	})
+	assert.NoError(t, err)
+	lf, err := fileFS.Open("../shared/types.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(shared, "types.yaml"), lf.(*LocalFile).GetFullPath())
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(spec), &rootNode)
+	rolo := NewRolodex(cf)
+	rolo.AddLocalFS(tmp, fileFS)
+	rolo.SetRootNode(&rootNode)
+	assert.NoError(t, rolo.IndexTheRolodex())
+	assert.Empty(t, rolo.GetCaughtErrors())
+	assert.Equal(t, entry, rolo.GetRootIndex().GetSpecAbsolutePath())
+	assert.Len(t, rolo.GetRootIndex().GetAllReferences(), 1)
+	assert.Empty(t, rolo.GetRootIndex().GetReferenceIndexErrors())
+
+	f, err := rolo.Open("../shared/types.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(shared, "types.yaml"), f.GetFullPath())
+
+	// the root document can also be located by the absolute path of the index.
+	absCf := CreateOpenAPIIndexConfig()
+	absCf.SpecAbsolutePath = entry
+	absFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: tmp,
+		IndexConfig:   absCf,
+	})
+	assert.NoError(t, err)
+	lf, err = absFS.Open("../shared/types.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(shared, "types.yaml"), lf.(*LocalFile).GetFullPath())
+}
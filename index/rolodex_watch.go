@@ -0,0 +1,152 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// DefaultWatchInterval is how often Watch checks the loaded files for changes, unless SetWatchInterval is used.
+const DefaultWatchInterval = time.Second
+
+// RolodexChangeType is the kind of change sent by Watch.
+type RolodexChangeType int
+
+const (
+	// RolodexFileModified is sent when a file has been written to (or has re-appeared after being removed).
+	RolodexFileModified RolodexChangeType = iota
+
+	// RolodexFileRemoved is sent when a file no longer exists.
+	RolodexFileRemoved
+)
+
+// RolodexChange is a change to a file loaded by the rolodex, sent by Watch.
+type RolodexChange struct {
+	// Type is the kind of change.
+	Type RolodexChangeType
+
+	// Path is the absolute path of the file.
+	Path string
+
+	// ModTime is the modification time of the file (zero when the file has been removed).
+	ModTime time.Time
+
+	// Reindexed is true when the file has been re-indexed with its new content (see SetReindexOnChange).
+	Reindexed bool
+
+	// Error is set when the changed file could not be read or re-indexed.
+	Error error
+}
+
+// SetWatchInterval sets how often Watch checks the loaded files for changes (DefaultWatchInterval by default).
+func (r *Rolodex) SetWatchInterval(interval time.Duration) {
+	r.watchInterval = interval
+}
+
+// SetReindexOnChange will make Watch re-index every file that has been modified (see ReindexFile), before the change
+// is sent. Re-indexing happens on the goroutine running Watch, see Watch for what is safe to read while it runs.
+func (r *Rolodex) SetReindexOnChange(reindex bool) {
+	r.reindexOnChange = reindex
+}
+
+// watchedFile is the state of a file the last time Watch checked it.
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	removed bool
+}
+
+// Watch polls every local file loaded by the rolodex (files loaded from an fs.FS that isn't the operating system's
+// file system can't be watched), and sends a RolodexChange to events when one is modified or removed. The root
+// document is watched too, when it was read from a local file (the absolute path of the root index is known). Files
+// loaded after watching has started are watched as soon as they are found. Remote files are not watched.
+//
+// Watch blocks until ctx is cancelled, and then returns nil. An error is returned if the rolodex has not been
+// indexed yet. SetWatchInterval and SetReindexOnChange must be called before Watch is.
+//
+// When re-indexing on change, the indexes, nodes and references of the rolodex are replaced while Watch runs, and
+// nothing built from them (like a document model) is rebuilt. It is not safe to read the rolodex, its indexes or a
+// model built from them while a change is being re-indexed, so readers should only do so (or rebuild the model)
+// after receiving the change from events, and before receiving the next one.
+func (r *Rolodex) Watch(ctx context.Context, events chan<- RolodexChange) error {
+	if !r.indexed {
+		return errors.New("rolodex has not been indexed, cannot watch files")
+	}
+	interval := r.watchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	watched := make(map[string]*watchedFile)
+	r.watchLocalFiles(watched)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for _, change := range r.watchLocalFiles(watched) {
+				if change.Type == RolodexFileModified && r.reindexOnChange {
+					data, err := os.ReadFile(change.Path)
+					if err == nil {
+						err = r.ReindexFile(change.Path, data)
+					}
+					change.Reindexed = err == nil
+					change.Error = err
+				}
+				select {
+				case events <- change:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// watchLocalFiles checks every loaded local file against its state in watched, updating it, and returns the changes.
+// Files that are not in watched yet are added without a change.
+func (r *Rolodex) watchLocalFiles(watched map[string]*watchedFile) []RolodexChange {
+	var paths []string
+	for _, v := range r.localFS {
+		if l, ok := v.(*LocalFS); ok {
+			l.Files.Range(func(key, _ any) bool {
+				paths = append(paths, key.(string))
+				return true
+			})
+		}
+	}
+	if root := r.GetRootIndex(); root != nil {
+		if p := root.GetSpecAbsolutePath(); filepath.IsAbs(p) && !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+
+	var changes []RolodexChange
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		state, seen := watched[path]
+		if !seen {
+			if err == nil {
+				watched[path] = &watchedFile{modTime: info.ModTime(), size: info.Size()}
+			}
+			continue
+		}
+		switch {
+		case err != nil && !state.removed:
+			state.removed = true
+			changes = append(changes, RolodexChange{Type: RolodexFileRemoved, Path: path})
+		case err == nil && (state.removed || !info.ModTime().Equal(state.modTime) || info.Size() != state.size):
+			*state = watchedFile{modTime: info.ModTime(), size: info.Size()}
+			changes = append(changes, RolodexChange{Type: RolodexFileModified, Path: path, ModTime: info.ModTime()})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRolodex_Watch(t *testing.T) {
+	dir := t.TempDir()
+	typesPath, _ := filepath.Abs(filepath.Join(dir, "types.yaml"))
+	require.NoError(t, os.WriteFile(typesPath, []byte(`Foo:
+  type: string`), 0o644))
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(`openapi: 3.1.0
+components:
+  schemas:
+    Foo:
+      $ref: 'types.yaml#/Foo'`), &rootNode)
+
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.BasePath = dir
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: dir,
+		IndexConfig:   cfg,
+		DirFS:         os.DirFS(dir),
+	})
+	require.NoError(t, err)
+
+	rolo := NewRolodex(cfg)
+	rolo.AddLocalFS(dir, fileFS)
+	rolo.SetRootNode(&rootNode)
+	rolo.SetWatchInterval(10 * time.Millisecond)
+	rolo.SetReindexOnChange(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan RolodexChange)
+	assert.Error(t, rolo.Watch(ctx, events))
+	require.NoError(t, rolo.IndexTheRolodex())
+
+	done := make(chan error)
+	go func() {
+		done <- rolo.Watch(ctx, events)
+	}()
+	next := func() RolodexChange {
+		select {
+		case change := <-events:
+			return change
+		case <-time.After(5 * time.Second):
+			t.Fatal("no change was sent")
+			return RolodexChange{}
+		}
+	}
+
+	// wait for the first check before changing the file.
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(typesPath, []byte(`Foo:
+  type: integer`), 0o644))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(typesPath, later, later))
+
+	change := next()
+	assert.Equal(t, RolodexFileModified, change.Type)
+	assert.Equal(t, typesPath, change.Path)
+	assert.True(t, change.ModTime.Equal(later))
+	assert.True(t, change.Reindexed)
+	assert.NoError(t, change.Error)
+
+	found, _ := rolo.GetRootIndex().SearchIndexForReference(typesPath + "#/Foo")
+	require.NotNil(t, found)
+	_, v := findMapValue(found.Node, "type")
+	assert.Equal(t, "integer", v)
+
+	require.NoError(t, os.Remove(typesPath))
+	change = next()
+	assert.Equal(t, RolodexFileRemoved, change.Type)
+	assert.Equal(t, typesPath, change.Path)
+	assert.False(t, change.Reindexed)
+
+	cancel()
+	select {
+	case err = <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watching did not stop")
+	}
+}
+
+func TestRolodex_Watch_Root(t *testing.T) {
+	dir := t.TempDir()
+	rootPath, _ := filepath.Abs(filepath.Join(dir, "openapi.yaml"))
+	spec := []byte(`openapi: 3.1.0
+info:
+  title: pets`)
+	require.NoError(t, os.WriteFile(rootPath, spec, 0o644))
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal(spec, &rootNode)
+
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.BasePath = rootPath
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: dir,
+		IndexConfig:   cfg,
+		DirFS:         os.DirFS(dir),
+	})
+	require.NoError(t, err)
+
+	rolo := NewRolodex(cfg)
+	rolo.AddLocalFS(dir, fileFS)
+	rolo.SetRootNode(&rootNode)
+	rolo.SetWatchInterval(10 * time.Millisecond)
+	rolo.SetReindexOnChange(true)
+	require.NoError(t, rolo.IndexTheRolodex())
+	require.Equal(t, rootPath, rolo.GetRootIndex().GetSpecAbsolutePath())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan RolodexChange)
+	go func() {
+		_ = rolo.Watch(ctx, events)
+	}()
+
+	// wait for the first check before changing the file.
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(rootPath, []byte(`openapi: 3.1.0
+info:
+  title: cats`), 0o644))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(rootPath, later, later))
+
+	select {
+	case change := <-events:
+		assert.Equal(t, RolodexFileModified, change.Type)
+		assert.Equal(t, rootPath, change.Path)
+		assert.True(t, change.Reindexed)
+		assert.NoError(t, change.Error)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change was sent")
+	}
+	_, title := findMapValue(rolo.GetRootNode().Content[0].Content[3], "title")
+	assert.Equal(t, "cats", title)
+}
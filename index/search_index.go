@@ -18,6 +18,38 @@ const (
 	FoundIndexKey  ContextKey = "foundIndex"
 )
 
+// IsLazyRemoteReference returns true if the index is configured with LazyRemoteResolution, and ref (as found in the
+// document at base, or the root document of the index if base is empty) points to a remote document, other than
+// the one that has been indexed. Lazy references are not fetched until they are searched for.
+func (index *SpecIndex) IsLazyRemoteReference(ref, base string) bool {
+	if index == nil || index.config == nil || !index.config.LazyRemoteResolution {
+		return false
+	}
+	file, _, _ := strings.Cut(ref, "#")
+	if file == "" {
+		return false
+	}
+	if !strings.HasPrefix(file, "http") {
+		if base == "" {
+			base = index.specAbsolutePath
+		}
+		if !strings.HasPrefix(base, "http") {
+			return false
+		}
+		b, err := url.Parse(base)
+		if err != nil {
+			return false
+		}
+		r, err := url.Parse(file)
+		if err != nil {
+			return false
+		}
+		file = b.ResolveReference(r).String()
+	}
+	indexed, _, _ := strings.Cut(index.specAbsolutePath, "#")
+	return file != indexed
+}
+
 func (index *SpecIndex) SearchIndexForReferenceByReference(fullRef *Reference) (*Reference, *SpecIndex) {
 	r, idx, _ := index.SearchIndexForReferenceByReferenceWithContext(context.Background(), fullRef)
 	return r, idx
@@ -34,3 +34,31 @@ func TestSpecIndex_SearchIndexForReferenceWithContext(t *testing.T) {
 	ref, _, _ := idx.SearchIndexForReferenceWithContext(context.Background(), "#/components/schemas/Pet")
 	assert.NotNil(t, ref)
 }
+
+func TestSpecIndex_IsLazyRemoteReference(t *testing.T) {
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(`openapi: 3.1.0`), &rootNode)
+
+	c := CreateOpenAPIIndexConfig()
+	c.LazyRemoteResolution = true
+	idx := NewSpecIndexWithConfig(&rootNode, c)
+
+	assert.True(t, idx.IsLazyRemoteReference("https://pb33f.io/pizza.yaml#/components/schemas/Pizza", ""))
+	assert.True(t, idx.IsLazyRemoteReference("https://pb33f.io/pizza.yaml", ""))
+	assert.False(t, idx.IsLazyRemoteReference("#/components/schemas/Pizza", ""))
+	assert.False(t, idx.IsLazyRemoteReference("pizza.yaml#/components/schemas/Pizza", ""))
+
+	// relative to a remote document.
+	assert.True(t, idx.IsLazyRemoteReference("pizza.yaml", "https://pb33f.io/specs/openapi.yaml"))
+	assert.False(t, idx.IsLazyRemoteReference("#/components/schemas/Pizza", "https://pb33f.io/specs/openapi.yaml"))
+
+	// the indexed document is never lazy.
+	c.SpecAbsolutePath = "https://pb33f.io/specs/openapi.yaml"
+	idx = NewSpecIndexWithConfig(&rootNode, c)
+	assert.False(t, idx.IsLazyRemoteReference("https://pb33f.io/specs/openapi.yaml#/components/schemas/Pizza", ""))
+	assert.False(t, idx.IsLazyRemoteReference("openapi.yaml#/components/schemas/Pizza", ""))
+	assert.True(t, idx.IsLazyRemoteReference("toppings.yaml#/components/schemas/Cheese", ""))
+
+	c.LazyRemoteResolution = false
+	assert.False(t, idx.IsLazyRemoteReference("https://pb33f.io/pizza.yaml", ""))
+}
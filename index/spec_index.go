@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -63,6 +64,29 @@ func NewSpecIndex(rootNode *yaml.Node) *SpecIndex {
 	return createNewIndex(rootNode, index, false)
 }
 
+// contextErr returns the error of the configured Context, if it has been cancelled or its deadline has passed.
+func (index *SpecIndex) contextErr() error {
+	if index.config == nil || index.config.Context == nil {
+		return nil
+	}
+	return index.config.Context.Err()
+}
+
+// cancelled checks if the configured Context has been cancelled. If it has, the context error is recorded
+// as a reference error and true is returned.
+func (index *SpecIndex) cancelled() bool {
+	err := index.contextErr()
+	if err == nil {
+		return false
+	}
+	index.errorLock.Lock()
+	if !slices.Contains(index.refErrors, err) {
+		index.refErrors = append(index.refErrors, err)
+	}
+	index.errorLock.Unlock()
+	return true
+}
+
 func createNewIndex(rootNode *yaml.Node, index *SpecIndex, avoidBuildOut bool) *SpecIndex {
 	// there is no node! return an empty index.
 	if rootNode == nil {
@@ -73,9 +97,15 @@ func createNewIndex(rootNode *yaml.Node, index *SpecIndex, avoidBuildOut bool) *
 	go index.MapNodes(rootNode) // this can run async.
 
 	index.cache = new(sync.Map)
+	index.schemaCache = new(sync.Map)
+	index.highSchemaCache = new(sync.Map)
 
 	// boot index.
 	results := index.ExtractRefs(index.root.Content[0], index.root, []string{}, 0, false, "")
+	if index.cancelled() {
+		<-index.nodeMapCompleted
+		return index
+	}
 
 	// map poly refs
 	poly := make([]*Reference, len(index.polymorphicRefs))
@@ -88,6 +118,11 @@ func createNewIndex(rootNode *yaml.Node, index *SpecIndex, avoidBuildOut bool) *
 	// pull out references
 	index.ExtractComponentsFromRefs(results)
 	index.ExtractComponentsFromRefs(poly)
+	index.ExtractDynamicRefs()
+	if index.cancelled() {
+		<-index.nodeMapCompleted
+		return index
+	}
 
 	index.ExtractExternalDocuments(index.root)
 	index.GetPathCount()
@@ -104,7 +139,7 @@ func createNewIndex(rootNode *yaml.Node, index *SpecIndex, avoidBuildOut bool) *
 // useful for looking up things, the count operations are all run in parallel and then the final calculations are run
 // the index is ready.
 func (index *SpecIndex) BuildIndex() {
-	if index.built {
+	if index.built || index.cancelled() {
 		return
 	}
 	countFuncs := []func() int{
@@ -402,6 +437,40 @@ func (index *SpecIndex) GetAllSequencedReferences() []*Reference {
 	return index.rawSequencedRefs
 }
 
+// GetAllReferencesWithLocations will return every reference found in the spec (in sequence, not de-duplicated),
+// along with the file, line and column of its value. References that could not be resolved are included, with
+// Resolved set to false. If the index is the root index of a rolodex, the references found by every other index of
+// the rolodex are included too, after the references of the root.
+func (index *SpecIndex) GetAllReferencesWithLocations() []RefLocation {
+	indexes := []*SpecIndex{index}
+	if index.rolodex != nil && index.rolodex.GetRootIndex() == index {
+		for _, idx := range index.rolodex.GetIndexes() {
+			if idx != index {
+				indexes = append(indexes, idx)
+			}
+		}
+	}
+	var locations []RefLocation
+	for _, idx := range indexes {
+		for _, ref := range idx.rawSequencedRefs {
+			loc := RefLocation{
+				FullDefinition: ref.FullDefinition,
+				File:           idx.specAbsolutePath,
+			}
+			if ref.KeyNode != nil {
+				loc.Reference = ref.KeyNode.Value
+				loc.Line = ref.KeyNode.Line
+				loc.Column = ref.KeyNode.Column
+			}
+			idx.refLock.Lock()
+			loc.Resolved = idx.allMappedRefs[ref.FullDefinition] != nil
+			idx.refLock.Unlock()
+			locations = append(locations, loc)
+		}
+	}
+	return locations
+}
+
 // GetSchemasNode will return the schema's node found in the spec
 func (index *SpecIndex) GetSchemasNode() *yaml.Node {
 	return index.schemasNode
@@ -492,6 +561,11 @@ func (index *SpecIndex) checkPolymorphicNode(name string) (bool, string) {
 
 // GetPathCount will return the number of paths found in the spec
 func (index *SpecIndex) GetPathCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
+	if index.merged {
+		return index.pathCount
+	}
 	if index.root == nil {
 		return -1
 	}
@@ -545,6 +619,8 @@ func (index *SpecIndex) ExtractExternalDocuments(node *yaml.Node) []*Reference {
 
 // GetGlobalTagsCount will return the number of tags found in the top level 'tags' node of the document.
 func (index *SpecIndex) GetGlobalTagsCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
 	if index.root == nil {
 		return -1
 	}
@@ -588,6 +664,8 @@ func (index *SpecIndex) GetGlobalTagsCount() int {
 
 // GetOperationTagsCount will return the number of operation tags found (tags referenced in operations)
 func (index *SpecIndex) GetOperationTagsCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
 	if index.root == nil {
 		return -1
 	}
@@ -616,6 +694,8 @@ func (index *SpecIndex) GetOperationTagsCount() int {
 
 // GetTotalTagsCount will return the number of global and operation tags found that are unique.
 func (index *SpecIndex) GetTotalTagsCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
 	if index.root == nil {
 		return -1
 	}
@@ -649,6 +729,8 @@ func (index *SpecIndex) GetTotalTagsCount() int {
 
 // GetGlobalCallbacksCount for each response of each operation method, multiple callbacks can be defined
 func (index *SpecIndex) GetGlobalCallbacksCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
 	if index.root == nil {
 		return -1
 	}
@@ -695,6 +777,8 @@ func (index *SpecIndex) GetGlobalCallbacksCount() int {
 
 // GetGlobalLinksCount for each response of each operation method, multiple callbacks can be defined
 func (index *SpecIndex) GetGlobalLinksCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
 	if index.root == nil {
 		return -1
 	}
@@ -746,6 +830,11 @@ func (index *SpecIndex) GetRawReferenceCount() int {
 
 // GetComponentSchemaCount will return the number of schemas located in the 'components' or 'definitions' node.
 func (index *SpecIndex) GetComponentSchemaCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
+	if index.merged {
+		return index.schemaCount
+	}
 	if index.root == nil || len(index.root.Content) == 0 {
 		return -1
 	}
@@ -918,6 +1007,8 @@ func (index *SpecIndex) GetComponentSchemaCount() int {
 
 // GetComponentParameterCount returns the number of parameter components defined
 func (index *SpecIndex) GetComponentParameterCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
 	if index.root == nil {
 		return -1
 	}
@@ -955,6 +1046,11 @@ func (index *SpecIndex) GetComponentParameterCount() int {
 
 // GetOperationCount returns the number of operations (for all paths) located in the document
 func (index *SpecIndex) GetOperationCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
+	if index.merged {
+		return index.operationCount
+	}
 	if index.root == nil {
 		return -1
 	}
@@ -1022,6 +1118,8 @@ func (index *SpecIndex) GetOperationCount() int {
 // this method looks in top level (path level) and inside each operation (get, post etc.). Parameters can
 // be hiding within multiple places.
 func (index *SpecIndex) GetOperationsParameterCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
 	if index.root == nil {
 		return -1
 	}
@@ -1214,6 +1312,8 @@ func (index *SpecIndex) GetOperationsParameterCount() int {
 
 // GetInlineDuplicateParamCount returns the number of inline duplicate parameters (operation params)
 func (index *SpecIndex) GetInlineDuplicateParamCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
 	if index.componentsInlineParamDuplicateCount > 0 {
 		return index.componentsInlineParamDuplicateCount
 	}
@@ -1224,6 +1324,8 @@ func (index *SpecIndex) GetInlineDuplicateParamCount() int {
 
 // GetInlineUniqueParamCount returns the number of unique inline parameters (operation params)
 func (index *SpecIndex) GetInlineUniqueParamCount() int {
+	index.countLock.Lock()
+	defer index.countLock.Unlock()
 	return index.countUniqueInlineDuplicates()
 }
 
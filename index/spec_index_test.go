@@ -5,6 +5,7 @@ package index
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"log/slog"
@@ -1801,3 +1802,125 @@ components:
 	schemas := index.GetAllReferences()
 	assert.Equal(t, 0, len(schemas))
 }
+
+func TestSpecIndex_ConcurrentReads(t *testing.T) {
+	specs := []string{"../test_specs/burgershop.openapi.yaml", "../test_specs/petstorev3.json", "../test_specs/first.yaml"}
+	for _, spec := range specs {
+		data, err := os.ReadFile(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rootNode yaml.Node
+		_ = yaml.Unmarshal(data, &rootNode)
+		idx := NewSpecIndexWithConfig(&rootNode, CreateOpenAPIIndexConfig())
+
+		getters := []func(){
+			func() { idx.GetPathCount() },
+			func() { idx.GetOperationCount() },
+			func() { idx.GetComponentSchemaCount() },
+			func() { idx.GetComponentParameterCount() },
+			func() { idx.GetOperationsParameterCount() },
+			func() { idx.GetInlineDuplicateParamCount() },
+			func() { idx.GetInlineUniqueParamCount() },
+			func() { idx.GetGlobalTagsCount() },
+			func() { idx.GetOperationTagsCount() },
+			func() { idx.GetTotalTagsCount() },
+			func() { idx.GetGlobalCallbacksCount() },
+			func() { idx.GetGlobalLinksCount() },
+			func() { idx.GetRawReferenceCount() },
+			func() { idx.GetAllDescriptionsCount() },
+			func() { idx.GetAllSummariesCount() },
+			func() { idx.GetAllSchemas() },
+			func() { idx.GetAllCombinedReferences() },
+			func() { idx.GetAllComponentSchemas() },
+			func() { idx.GetAllParametersFromOperations() },
+			func() { idx.GetAllPaths() },
+			func() { idx.GetOperationTags() },
+			func() { idx.GetAllSecuritySchemes() },
+			func() { idx.GetAllRequestBodies() },
+			func() { idx.GetAllResponses() },
+			func() { idx.GetMappedReferences() },
+			func() { idx.GetMappedReferencesSequenced() },
+			func() { idx.GetReferenceIndexErrors() },
+			func() { idx.GetOperationParametersIndexErrors() },
+			func() { idx.FindComponent("#/components/schemas/Pet") },
+			func() { idx.FindComponent("#/components/schemas/Burger") },
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			for _, get := range getters {
+				wg.Add(1)
+				go func(get func()) {
+					defer wg.Done()
+					get()
+				}(get)
+			}
+		}
+		wg.Wait()
+	}
+}
+
+func TestSpecIndex_CancelledContext(t *testing.T) {
+	petstore, _ := os.ReadFile("../test_specs/petstorev3.json")
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal(petstore, &rootNode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	config := CreateOpenAPIIndexConfig()
+	config.Context = ctx
+	idx := NewSpecIndexWithConfig(&rootNode, config)
+
+	assert.Len(t, idx.GetAllReferences(), 0)
+	assert.Contains(t, idx.GetReferenceIndexErrors(), context.Canceled)
+
+	resolver := NewResolver(idx)
+	errs := resolver.CheckForCircularReferences()
+	assert.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0].ErrorRef, context.Canceled)
+}
+
+func TestSpecIndex_GetAllReferencesWithLocations(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        "404":
+          $ref: '#/components/responses/Missing'
+components:
+  schemas:
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+    Cat:
+      type: object`
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(spec), &rootNode)
+	idx := NewSpecIndexWithConfig(&rootNode, CreateOpenAPIIndexConfig())
+
+	locations := idx.GetAllReferencesWithLocations()
+	assert.Len(t, locations, 3)
+
+	assert.Equal(t, "#/components/schemas/Pet", locations[0].Reference)
+	assert.Equal(t, 10, locations[0].Line)
+	assert.Equal(t, 23, locations[0].Column)
+	assert.True(t, locations[0].Resolved)
+
+	assert.Equal(t, "#/components/responses/Missing", locations[1].Reference)
+	assert.Equal(t, 12, locations[1].Line)
+	assert.Equal(t, 17, locations[1].Column)
+	assert.False(t, locations[1].Resolved)
+
+	assert.Equal(t, "#/components/schemas/Cat", locations[2].Reference)
+	assert.Equal(t, 17, locations[2].Line)
+	assert.True(t, locations[2].Resolved)
+}
@@ -1,8 +1,12 @@
 package json
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/pb33f/libopenapi/orderedmap"
 	"gopkg.in/yaml.v3"
@@ -12,7 +16,7 @@ import (
 //
 // NOTE: The limitation is this won't work with YAML that is not compatible with JSON, ie yaml with anchors or complex map keys
 func YAMLNodeToJSON(node *yaml.Node, indentation string) ([]byte, error) {
-	v, err := handleYAMLNode(node)
+	v, err := handleYAMLNode(node, false)
 	if err != nil {
 		return nil, err
 	}
@@ -20,16 +24,158 @@ func YAMLNodeToJSON(node *yaml.Node, indentation string) ([]byte, error) {
 	return json.MarshalIndent(v, "", indentation)
 }
 
-func handleYAMLNode(node *yaml.Node) (any, error) {
+// Options control how YAMLNodeToJSONWithOptions renders JSON.
+type Options struct {
+	// Indent is the number of spaces used to indent each level of nested JSON. When zero, the JSON is compact.
+	Indent int
+
+	// EscapeHTML escapes the characters <, > and & in strings, so the JSON can be embedded in HTML.
+	EscapeHTML bool
+}
+
+// YAMLNodeToJSONWithOptions converts yaml/json stored in a yaml.Node to json ordered matching the original yaml/json,
+// rendered using opts. Numbers are rendered as they are written in the node (so 1.0 stays 1.0), as long as they are
+// valid JSON numbers.
+//
+// NOTE: The limitation is this won't work with YAML that is not compatible with JSON, ie yaml with anchors or complex map keys
+func YAMLNodeToJSONWithOptions(node *yaml.Node, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := YAMLNodeToJSONTo(&buf, node, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// YAMLNodeToJSONTo converts yaml/json stored in a yaml.Node to json in the same way as YAMLNodeToJSONWithOptions, but
+// writes the json to w while the node is walked, rather than returning it as a byte slice. No other copy of the
+// document is made, each level of the node is encoded as it is reached. If an error is returned, part of the json
+// may already have been written to w.
+func YAMLNodeToJSONTo(w io.Writer, node *yaml.Node, opts Options) error {
+	if opts.Indent < 0 {
+		return fmt.Errorf("indent cannot be negative (%d)", opts.Indent)
+	}
+	enc := &encoder{
+		w:          bufio.NewWriter(w),
+		indent:     strings.Repeat(" ", opts.Indent),
+		escapeHTML: opts.EscapeHTML,
+	}
+	if err := enc.encodeNode(node, 0); err != nil {
+		return err
+	}
+	return enc.w.Flush()
+}
+
+// encoder writes yaml nodes to w as JSON, keeping the order of objects. Each level is indented with indent, unless
+// it's empty, in which case the JSON is compact.
+type encoder struct {
+	w          *bufio.Writer
+	indent     string
+	escapeHTML bool
+}
+
+// newline starts a new line indented depth levels, when the JSON isn't compact.
+func (e *encoder) newline(depth int) {
+	if e.indent == "" {
+		return
+	}
+	e.w.WriteByte('\n')
+	for i := 0; i < depth; i++ {
+		e.w.WriteString(e.indent)
+	}
+}
+
+// encodeNode writes node, nested depth levels deep. Mappings and sequences are decoded one level at a time (in the
+// same way as handleYAMLNode), so only the nodes of the level being written are copied.
+func (e *encoder) encodeNode(node *yaml.Node, depth int) error {
 	switch node.Kind {
 	case yaml.DocumentNode:
-		return handleYAMLNode(node.Content[0])
+		return e.encodeNode(node.Content[0], depth)
+	case yaml.MappingNode:
+		m := orderedmap.New[string, yaml.Node]()
+		if err := node.Decode(m); err != nil {
+			return err
+		}
+		if orderedmap.Len(m) == 0 {
+			e.w.WriteString("{}")
+			return nil
+		}
+		e.w.WriteByte('{')
+		for pair, first := orderedmap.First(m), true; pair != nil; pair, first = pair.Next(), false {
+			if !first {
+				e.w.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			if err := e.encodeScalar(pair.Key()); err != nil {
+				return err
+			}
+			e.w.WriteByte(':')
+			if e.indent != "" {
+				e.w.WriteByte(' ')
+			}
+			n := pair.Value()
+			if err := e.encodeNode(&n, depth+1); err != nil {
+				return err
+			}
+		}
+		e.newline(depth)
+		e.w.WriteByte('}')
+	case yaml.SequenceNode:
+		var s []yaml.Node
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+		if len(s) == 0 {
+			e.w.WriteString("[]")
+			return nil
+		}
+		e.w.WriteByte('[')
+		for i := range s {
+			if i > 0 {
+				e.w.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			if err := e.encodeNode(&s[i], depth+1); err != nil {
+				return err
+			}
+		}
+		e.newline(depth)
+		e.w.WriteByte(']')
+	case yaml.ScalarNode:
+		v, err := handleScalarNode(node, true)
+		if err != nil {
+			return err
+		}
+		return e.encodeScalar(v)
+	case yaml.AliasNode:
+		panic("currently unsupported")
+	default:
+		return fmt.Errorf("unknown node kind: %v", node.Kind)
+	}
+	return nil
+}
+
+// encodeScalar writes the scalar value v.
+func (e *encoder) encodeScalar(v any) error {
+	var scalar bytes.Buffer
+	enc := json.NewEncoder(&scalar)
+	enc.SetEscapeHTML(e.escapeHTML)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	e.w.Write(bytes.TrimSuffix(scalar.Bytes(), []byte("\n")))
+	return nil
+}
+
+func handleYAMLNode(node *yaml.Node, keepNumbers bool) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return handleYAMLNode(node.Content[0], keepNumbers)
 	case yaml.SequenceNode:
-		return handleSequenceNode(node)
+		return handleSequenceNode(node, keepNumbers)
 	case yaml.MappingNode:
-		return handleMappingNode(node)
+		return handleMappingNode(node, keepNumbers)
 	case yaml.ScalarNode:
-		return handleScalarNode(node)
+		return handleScalarNode(node, keepNumbers)
 	case yaml.AliasNode:
 		panic("currently unsupported")
 	default:
@@ -37,7 +183,7 @@ func handleYAMLNode(node *yaml.Node) (any, error) {
 	}
 }
 
-func handleMappingNode(node *yaml.Node) (any, error) {
+func handleMappingNode(node *yaml.Node, keepNumbers bool) (any, error) {
 	m := orderedmap.New[string, yaml.Node]()
 
 	if err := node.Decode(m); err != nil {
@@ -47,7 +193,7 @@ func handleMappingNode(node *yaml.Node) (any, error) {
 	v := orderedmap.New[string, any]()
 	for pair := orderedmap.First(m); pair != nil; pair = pair.Next() {
 		n := pair.Value()
-		vv, err := handleYAMLNode(&n)
+		vv, err := handleYAMLNode(&n, keepNumbers)
 		if err != nil {
 			return nil, err
 		}
@@ -58,7 +204,7 @@ func handleMappingNode(node *yaml.Node) (any, error) {
 	return v, nil
 }
 
-func handleSequenceNode(node *yaml.Node) (any, error) {
+func handleSequenceNode(node *yaml.Node, keepNumbers bool) (any, error) {
 	var s []yaml.Node
 
 	if err := node.Decode(&s); err != nil {
@@ -67,7 +213,7 @@ func handleSequenceNode(node *yaml.Node) (any, error) {
 
 	v := make([]any, len(s))
 	for i, n := range s {
-		vv, err := handleYAMLNode(&n)
+		vv, err := handleYAMLNode(&n, keepNumbers)
 		if err != nil {
 			return nil, err
 		}
@@ -78,7 +224,14 @@ func handleSequenceNode(node *yaml.Node) (any, error) {
 	return v, nil
 }
 
-func handleScalarNode(node *yaml.Node) (any, error) {
+func handleScalarNode(node *yaml.Node, keepNumbers bool) (any, error) {
+	// numbers keep the way they are written, if that's valid JSON.
+	if tag := node.ShortTag(); keepNumbers && (tag == "!!int" || tag == "!!float") {
+		if n := node.Value; n != "" && strings.ContainsRune("-0123456789", rune(n[0])) && json.Valid([]byte(n)) {
+			return json.Number(n), nil
+		}
+	}
+
 	var v any
 
 	if err := node.Decode(&v); err != nil {
@@ -1,6 +1,8 @@
 package json_test
 
 import (
+	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/pb33f/libopenapi/json"
@@ -85,3 +87,82 @@ func TestYAMLNodeToJSON_FromJSON(t *testing.T) {
 
 	assert.Equal(t, j, string(o))
 }
+
+func TestYAMLNodeToJSONWithOptions(t *testing.T) {
+	y := `root:
+  html: <b>&</b>
+  float: 1.0
+  exp: 1e3
+  int: 42
+  negative: -0.50
+  octal: 0o17
+  list:
+    - 1.10
+    - true
+    - null`
+
+	var v yaml.Node
+	err := yaml.Unmarshal([]byte(y), &v)
+	require.NoError(t, err)
+
+	j, err := json.YAMLNodeToJSONWithOptions(&v, json.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"root":{"html":"<b>&</b>","float":1.0,"exp":1e3,"int":42,"negative":-0.50,"octal":15,`+
+		`"list":[1.10,true,null]}}`, string(j))
+
+	j, err = json.YAMLNodeToJSONWithOptions(&v, json.Options{Indent: 2, EscapeHTML: true})
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "root": {
+    "html": "\u003cb\u003e\u0026\u003c/b\u003e",
+    "float": 1.0,
+    "exp": 1e3,
+    "int": 42,
+    "negative": -0.50,
+    "octal": 15,
+    "list": [
+      1.10,
+      true,
+      null
+    ]
+  }
+}`, string(j))
+
+	_, err = json.YAMLNodeToJSONWithOptions(&v, json.Options{Indent: -1})
+	assert.Error(t, err)
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestYAMLNodeToJSONTo(t *testing.T) {
+	var v yaml.Node
+	err := yaml.Unmarshal([]byte(`root: {empty: {}, none: [], list: [{a: 1}]}`), &v)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, json.YAMLNodeToJSONTo(&buf, &v, json.Options{Indent: 1}))
+	assert.Equal(t, `{
+ "root": {
+  "empty": {},
+  "none": [],
+  "list": [
+   {
+    "a": 1
+   }
+  ]
+ }
+}`, buf.String())
+
+	assert.EqualError(t, json.YAMLNodeToJSONTo(errWriter{}, &v, json.Options{}), "write failed")
+	assert.Error(t, json.YAMLNodeToJSONTo(&buf, &v, json.Options{Indent: -1}))
+
+	// nodes that can't be decoded are found as the node is walked.
+	var bad yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`root: {list: [!!int nope]}`), &bad))
+	buf.Reset()
+	assert.Error(t, json.YAMLNodeToJSONTo(&buf, &bad, json.Options{}))
+}
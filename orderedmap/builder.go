@@ -79,12 +79,13 @@ func (o *Map[K, V]) ToYamlNode(n NodeBuilder, l any) *yaml.Node {
 		}
 
 		n.AddYAMLNode(p, &nodes.NodeEntry{
-			Tag:      ks,
-			Key:      ks,
-			Line:     i,
-			Value:    pair.Value(),
-			KeyStyle: keyStyle,
-			LowValue: lv,
+			Tag:        ks,
+			Key:        ks,
+			Line:       i,
+			Value:      pair.Value(),
+			KeyStyle:   keyStyle,
+			LowValue:   lv,
+			LowKeyNode: keyNode,
 		})
 		i++
 	}
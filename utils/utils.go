@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -279,6 +280,45 @@ func FindKeyNodeTop(key string, nodes []*yaml.Node) (keyNode *yaml.Node, valueNo
 	return nil, nil
 }
 
+// RemoveKeyNodes is a non-recursive removal of keys (and their values) from a mapping node, will not look at content.
+// Returns true if any keys were removed.
+func RemoveKeyNodes(node *yaml.Node, keys ...string) bool {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return false
+	}
+	removed := false
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if slices.Contains(keys, node.Content[i].Value) {
+			removed = true
+			continue
+		}
+		content = append(content, node.Content[i], node.Content[i+1])
+	}
+	node.Content = content
+	return removed
+}
+
+// RemoveKeyNodesWithPrefix is a non-recursive removal of keys (and their values) that start with any of the prefixes
+// from a mapping node, will not look at content. Returns the keys that were removed.
+func RemoveKeyNodesWithPrefix(node *yaml.Node, prefixes ...string) []string {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var removed []string
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if slices.ContainsFunc(prefixes, func(p string) bool { return strings.HasPrefix(key, p) }) {
+			removed = append(removed, key)
+			continue
+		}
+		content = append(content, node.Content[i], node.Content[i+1])
+	}
+	node.Content = content
+	return removed
+}
+
 // FindKeyNode is a non-recursive search of a *yaml.Node Content for a child node with a key.
 // Returns the key and value
 func FindKeyNode(key string, nodes []*yaml.Node) (keyNode *yaml.Node, valueNode *yaml.Node) {
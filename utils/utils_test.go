@@ -1182,3 +1182,32 @@ func TestNodeMerge_NoNodes(t *testing.T) {
 	n := NodeMerge(nil)
 	assert.Nil(t, n)
 }
+
+func TestRemoveKeyNodes(t *testing.T) {
+	var node yaml.Node
+	_ = yaml.Unmarshal([]byte(`description: pizza
+example: cheese
+type: string
+examples: [cheese]`), &node)
+
+	assert.True(t, RemoveKeyNodes(node.Content[0], "example", "examples"))
+	assert.Len(t, node.Content[0].Content, 4)
+	_, v := FindKeyNodeTop("type", node.Content[0].Content)
+	assert.Equal(t, "string", v.Value)
+
+	assert.False(t, RemoveKeyNodes(node.Content[0], "example"))
+	assert.False(t, RemoveKeyNodes(nil, "example"))
+	assert.False(t, RemoveKeyNodes(node.Content[0].Content[1], "example"))
+}
+
+func TestRemoveKeyNodesWithPrefix(t *testing.T) {
+	var node yaml.Node
+	_ = yaml.Unmarshal([]byte(`x-internal-owner: burgers
+x-logo: logo.png
+type: string`), &node)
+
+	assert.Equal(t, []string{"x-internal-owner"}, RemoveKeyNodesWithPrefix(node.Content[0], "x-internal-"))
+	assert.Equal(t, []string{"x-logo"}, RemoveKeyNodesWithPrefix(node.Content[0], "x-"))
+	assert.Len(t, node.Content[0].Content, 2)
+	assert.Nil(t, RemoveKeyNodesWithPrefix(nil, "x-"))
+}
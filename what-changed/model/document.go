@@ -142,6 +142,28 @@ func (d *DocumentChanges) TotalBreakingChanges() int {
 	return c
 }
 
+// BreakingChanges returns only the changes made in the Document that are classified as breaking, useful for gating
+// a release on a pipeline. An empty slice (never nil) is returned when there are no breaking changes.
+//
+// Breaking changes are classified by each model as the comparison is made. The most common are:
+//   - removing a path, an operation, a response code or a component.
+//   - adding a required parameter or property, or making an optional one required.
+//   - removing an enum value, changing a type or format.
+//   - tightening a constraint (minimum, maximum, minLength, maxLength, pattern etc.)
+//   - changing a parameter name, or where it lives ('in').
+//
+// Every Change has a 'Breaking' flag, so the exact rules can be found (and are tested) alongside each comparison
+// function.
+func (d *DocumentChanges) BreakingChanges() []*Change {
+	breaking := make([]*Change, 0)
+	for _, c := range d.GetAllChanges() {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
 // CompareDocuments will compare any two OpenAPI documents (either Swagger or OpenAPI) and return a pointer to
 // DocumentChanges that outlines everything that was found to have changed.
 func CompareDocuments(l, r any) *DocumentChanges {
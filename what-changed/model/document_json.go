@@ -0,0 +1,165 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ChangeJSON is the shape of a single change, when DocumentChanges are rendered as JSON. The field names are
+// snake_case and are stable, consumers can depend on them.
+//
+//   - type: the kind of change, one of 'modified', 'property_added', 'property_removed', 'object_added'
+//     or 'object_removed'.
+//   - path: a JSON pointer to the changed object in the change tree (for example '/paths/pathItems/~1pets/get').
+//   - property: the name of the property that was changed.
+//   - original / new: the original and new values, empty if the property was added or removed.
+//   - breaking: true if the change is a breaking one.
+//   - original_line, original_column, new_line, new_column: the position of the value in each document,
+//     zero if the value does not exist in that document.
+type ChangeJSON struct {
+	Type           string `json:"type"`
+	Path           string `json:"path"`
+	Property       string `json:"property"`
+	Original       string `json:"original"`
+	New            string `json:"new"`
+	Breaking       bool   `json:"breaking"`
+	OriginalLine   int    `json:"original_line"`
+	OriginalColumn int    `json:"original_column"`
+	NewLine        int    `json:"new_line"`
+	NewColumn      int    `json:"new_column"`
+}
+
+// DocumentChangesJSON holds the fields added to DocumentChanges when it's rendered as JSON. Every change is listed
+// in 'all_changes' (in the order of the document), so there is no need to walk the tree of changes.
+type DocumentChangesJSON struct {
+	TotalChanges    int           `json:"total_changes"`
+	BreakingChanges int           `json:"breaking_changes"`
+	AllChanges      []*ChangeJSON `json:"all_changes"`
+}
+
+// documentChangesTree has the fields of DocumentChanges, but not its methods, so it renders as the tree of changes.
+type documentChangesTree DocumentChanges
+
+// MarshalJSON renders DocumentChanges as JSON. The tree of changes is rendered as it always has been (using the json
+// tags of every change type), and the fields of DocumentChangesJSON are added alongside it, with a flat and stable
+// shape (see ChangeJSON) designed to be consumed by tools that are not written in Go, like dashboards or pull request
+// comments.
+func (d *DocumentChanges) MarshalJSON() ([]byte, error) {
+	flat := &DocumentChangesJSON{AllChanges: make([]*ChangeJSON, 0)}
+	if d != nil {
+		flat.TotalChanges = d.TotalChanges()
+		flat.BreakingChanges = d.TotalBreakingChanges()
+		collectChangesJSON(reflect.ValueOf(*d), "", &flat.AllChanges)
+	}
+	return json.Marshal(struct {
+		*documentChangesTree
+		*DocumentChangesJSON
+	}{(*documentChangesTree)(d), flat})
+}
+
+var changePointerType = reflect.TypeOf(&Change{})
+
+// collectChangesJSON walks a tree of changes, and collects every change with the path to the object it was made to.
+// Segments of the path are taken from the json tags of the change tree, and the keys of any maps.
+func collectChangesJSON(v reflect.Value, path string, out *[]*ChangeJSON) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			collectChangesJSON(v.Elem(), path, out)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if f.Anonymous {
+				collectChangesJSON(v.Field(i), path, out) // *PropertyChanges
+				continue
+			}
+			tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if tag == "-" {
+				continue
+			}
+			if tag == "" {
+				tag = f.Name
+			}
+			if tag == "changes" && f.Type == reflect.TypeOf([]*Change{}) {
+				tag = "" // property changes belong to the object that owns them.
+			}
+			collectChangesJSON(v.Field(i), joinChangePath(path, tag), out)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if v.Type().Elem() == changePointerType {
+				if c, ok := v.Index(i).Interface().(*Change); ok && c != nil {
+					*out = append(*out, newChangeJSON(c, path))
+				}
+				continue
+			}
+			collectChangesJSON(v.Index(i), path, out)
+		}
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			collectChangesJSON(v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key())), joinChangePath(path, k), out)
+		}
+	}
+}
+
+func joinChangePath(path, segment string) string {
+	if segment == "" {
+		return path
+	}
+	segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1")
+	return path + "/" + segment
+}
+
+func newChangeJSON(c *Change, path string) *ChangeJSON {
+	cj := &ChangeJSON{
+		Path:     path,
+		Property: c.Property,
+		Original: c.Original,
+		New:      c.New,
+		Breaking: c.Breaking,
+	}
+	if cj.Path == "" {
+		cj.Path = "/"
+	}
+	switch c.ChangeType {
+	case Modified:
+		cj.Type = "modified"
+	case PropertyAdded:
+		cj.Type = "property_added"
+	case PropertyRemoved:
+		cj.Type = "property_removed"
+	case ObjectAdded:
+		cj.Type = "object_added"
+	case ObjectRemoved:
+		cj.Type = "object_removed"
+	}
+	if c.Context != nil {
+		cj.OriginalLine = derefInt(c.Context.OriginalLine)
+		cj.OriginalColumn = derefInt(c.Context.OriginalColumn)
+		cj.NewLine = derefInt(c.Context.NewLine)
+		cj.NewColumn = derefInt(c.Context.NewColumn)
+	}
+	return cj
+}
+
+func derefInt(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
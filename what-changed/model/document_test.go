@@ -4,6 +4,8 @@
 package model
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/pb33f/libopenapi/datamodel"
@@ -1181,3 +1183,150 @@ func TestDocumentChanges_Nil(t *testing.T) {
 	assert.Equal(t, 0, dc.TotalBreakingChanges())
 	assert.Nil(t, dc.GetAllChanges())
 }
+
+func TestDocumentChanges_BreakingChanges(t *testing.T) {
+	base := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+                  status:
+                    type: string
+                    enum: [available, sold]
+  /owners:
+    get:
+      responses:
+        "200":
+          description: ok`
+
+	tests := []struct {
+		name     string
+		old, new string
+		breaking int
+	}{
+		{"removed endpoint", "  /owners:\n    get:\n      responses:\n        \"200\":\n          description: ok", "", 1},
+		{"tightened constraint", "maximum: 100", "maximum: 50", 1},
+		{"new required field", "required: [name]", "required: [name, status]", 1},
+		{"removed enum value", "enum: [available, sold]", "enum: [available]", 1},
+		{"type change", "type: integer", "type: string", 1},
+		{"description change", "description: ok", "description: fine", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := strings.Replace(base, tt.old, tt.new, 1)
+			assert.NotEqual(t, base, updated)
+
+			siLeft, _ := datamodel.ExtractSpecInfo([]byte(base))
+			siRight, _ := datamodel.ExtractSpecInfo([]byte(updated))
+			lDoc, _ := v3.CreateDocumentFromConfig(siLeft, datamodel.NewDocumentConfiguration())
+			rDoc, _ := v3.CreateDocumentFromConfig(siRight, datamodel.NewDocumentConfiguration())
+
+			changes := CompareDocuments(lDoc, rDoc)
+			breaking := changes.BreakingChanges()
+			assert.NotNil(t, breaking)
+			assert.Len(t, breaking, tt.breaking)
+			assert.Equal(t, changes.TotalBreakingChanges(), len(breaking))
+		})
+	}
+}
+
+func TestDocumentChanges_BreakingChanges_None(t *testing.T) {
+	var changes *DocumentChanges
+	assert.NotNil(t, changes.BreakingChanges())
+	assert.Empty(t, changes.BreakingChanges())
+}
+
+func TestDocumentChanges_MarshalJSON(t *testing.T) {
+	left := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100`
+
+	right := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 50`
+
+	siLeft, _ := datamodel.ExtractSpecInfo([]byte(left))
+	siRight, _ := datamodel.ExtractSpecInfo([]byte(right))
+	lDoc, _ := v3.CreateDocumentFromConfig(siLeft, datamodel.NewDocumentConfiguration())
+	rDoc, _ := v3.CreateDocumentFromConfig(siRight, datamodel.NewDocumentConfiguration())
+
+	changes := CompareDocuments(lDoc, rDoc)
+	bits, err := json.Marshal(changes)
+	assert.NoError(t, err)
+
+	// the tree of changes is rendered as it always has been.
+	var rendered map[string]any
+	assert.NoError(t, json.Unmarshal(bits, &rendered))
+	tree, err := json.Marshal((*documentChangesTree)(changes))
+	assert.NoError(t, err)
+	var expected map[string]any
+	assert.NoError(t, json.Unmarshal(tree, &expected))
+	assert.Contains(t, expected, "paths")
+	for key, value := range expected {
+		assert.Equal(t, value, rendered[key], key)
+	}
+
+	flat := map[string]any{
+		"total_changes":    rendered["total_changes"],
+		"breaking_changes": rendered["breaking_changes"],
+		"all_changes":      rendered["all_changes"],
+	}
+	bits, _ = json.Marshal(flat)
+	assert.JSONEq(t, `{
+  "total_changes": 1,
+  "breaking_changes": 1,
+  "all_changes": [
+    {
+      "type": "modified",
+      "path": "/paths/pathItems/~1pets/get/parameters/schemas",
+      "property": "maximum",
+      "original": "100",
+      "new": "50",
+      "breaking": true,
+      "original_line": 10,
+      "original_column": 22,
+      "new_line": 10,
+      "new_column": 22
+    }
+  ]
+}`, string(bits))
+
+	var empty *DocumentChanges
+	bits, err = json.Marshal(empty)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(bits))
+	bits, err = empty.MarshalJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"total_changes": 0, "breaking_changes": 0, "all_changes": []}`, string(bits))
+}